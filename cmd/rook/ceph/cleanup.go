@@ -36,6 +36,7 @@ var (
 	sanitizeMethod     string
 	sanitizeDataSource string
 	sanitizeIteration  int32
+	sanitizeOSDIDs     []int
 )
 
 var cleanUpCmd = &cobra.Command{
@@ -51,6 +52,7 @@ func init() {
 	cleanUpCmd.Flags().StringVar(&sanitizeMethod, "sanitize-method", string(cephv1.SanitizeMethodQuick), "sanitize method to use (metadata or data)")
 	cleanUpCmd.Flags().StringVar(&sanitizeDataSource, "sanitize-data-source", string(cephv1.SanitizeDataSourceZero), "data source to sanitize the disk (zero or random)")
 	cleanUpCmd.Flags().Int32Var(&sanitizeIteration, "sanitize-iteration", 1, "overwrite N times the disk")
+	cleanUpCmd.Flags().IntSliceVar(&sanitizeOSDIDs, "sanitize-osd-ids", nil, "sanitize only the disks of the given osds")
 	flags.SetFlagsFromEnv(cleanUpCmd.Flags(), rook.RookEnvVarPrefix)
 	cleanUpCmd.RunE = startCleanUp
 }
@@ -80,8 +82,12 @@ func startCleanUp(cmd *cobra.Command, args []string) error {
 		},
 	)
 
-	// Start OSD wipe process
-	s.StartSanitizeDisks()
+	// Start OSD wipe process, only the disks of the removed OSDs are wiped when the OSDs are given
+	if len(sanitizeOSDIDs) > 0 {
+		s.StartSanitizeOSDDisks(sanitizeOSDIDs)
+	} else {
+		s.StartSanitizeDisks()
+	}
 
 	return nil
 }
@@ -55,6 +55,14 @@ var osdRemoveCmd = &cobra.Command{
 	Use:   "remove",
 	Short: "Removes a set of OSDs from the cluster",
 }
+var osdRotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key",
+	Short: "Rotates the dm-crypt key of an encrypted OSD on PVC",
+}
+var osdGetKeyCmd = &cobra.Command{
+	Use:   "get-key",
+	Short: "Writes the dm-crypt key of an encrypted OSD stored in a KMS to a file",
+}
 
 var (
 	osdDataDeviceFilter     string
@@ -70,6 +78,10 @@ var (
 	lvBackedPV              bool
 	driveGroups             string
 	osdIDsToRemove          string
+	encryptionKeyName       string
+	encryptionKeyPath       string
+	encryptedBlockPaths     string
+	removeOldEncryptionKey  bool
 )
 
 func addOSDFlags(command *cobra.Command) {
@@ -100,11 +112,23 @@ func addOSDFlags(command *cobra.Command) {
 	// flags for removing OSDs that are unhealthy or otherwise should be purged from the cluster
 	osdRemoveCmd.Flags().StringVar(&osdIDsToRemove, "osd-ids", "", "OSD IDs to remove from the cluster")
 
+	// flags for rotating the encryption key of an OSD
+	osdRotateKeyCmd.Flags().StringVar(&encryptionKeyName, "key-name", "", "name of the secret or KMS key holding the encryption key")
+	osdRotateKeyCmd.Flags().StringVar(&encryptionKeyPath, "key-path", "", "file the encryption keys are written to while cryptsetup runs")
+	osdRotateKeyCmd.Flags().StringVar(&encryptedBlockPaths, "block-paths", "", "comma separated list of the encrypted block devices of the OSD")
+	osdRotateKeyCmd.Flags().BoolVar(&removeOldEncryptionKey, "remove-old-key", false, "remove the keyslot of the old key once the operator stored the new key, without a KMS only")
+
+	// flags for fetching the encryption key of an OSD from the KMS
+	osdGetKeyCmd.Flags().StringVar(&encryptionKeyName, "key-name", "", "name of the encryption key in the KMS")
+	osdGetKeyCmd.Flags().StringVar(&encryptionKeyPath, "key-path", "", "file the encryption key is written to")
+
 	// add the subcommands to the parent osd command
 	osdCmd.AddCommand(osdConfigCmd,
 		provisionCmd,
 		osdStartCmd,
-		osdRemoveCmd)
+		osdRemoveCmd,
+		osdRotateKeyCmd,
+		osdGetKeyCmd)
 }
 
 func addOSDConfigFlags(command *cobra.Command) {
@@ -128,11 +152,15 @@ func init() {
 	flags.SetFlagsFromEnv(provisionCmd.Flags(), rook.RookEnvVarPrefix)
 	flags.SetFlagsFromEnv(osdStartCmd.Flags(), rook.RookEnvVarPrefix)
 	flags.SetFlagsFromEnv(osdRemoveCmd.Flags(), rook.RookEnvVarPrefix)
+	flags.SetFlagsFromEnv(osdRotateKeyCmd.Flags(), rook.RookEnvVarPrefix)
+	flags.SetFlagsFromEnv(osdGetKeyCmd.Flags(), rook.RookEnvVarPrefix)
 
 	osdConfigCmd.RunE = writeOSDConfig
 	provisionCmd.RunE = prepareOSD
 	osdStartCmd.RunE = startOSD
 	osdRemoveCmd.RunE = removeOSDs
+	osdRotateKeyCmd.RunE = rotateOSDEncryptionKey
+	osdGetKeyCmd.RunE = getOSDEncryptionKey
 }
 
 // Start the osd daemon if provisioned by ceph-volume
@@ -272,6 +300,46 @@ func removeOSDs(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Replace the encryption key of an OSD with a new one without stopping the OSD
+func rotateOSDEncryptionKey(cmd *cobra.Command, args []string) error {
+	required := []string{"key-name", "key-path", "block-paths"}
+	if err := flags.VerifyRequiredFlags(osdRotateKeyCmd, required); err != nil {
+		return err
+	}
+
+	rook.SetLogLevel()
+	rook.LogStartupInfo(osdRotateKeyCmd.Flags())
+
+	context := createContext()
+
+	var err error
+	if removeOldEncryptionKey {
+		err = osddaemon.RemoveOldEncryptionKey(context, encryptionKeyPath, strings.Split(encryptedBlockPaths, ","))
+	} else {
+		err = osddaemon.RotateEncryptionKey(context, encryptionKeyName, encryptionKeyPath, strings.Split(encryptedBlockPaths, ","))
+	}
+	if err != nil {
+		rook.TerminateFatal(err)
+	}
+	return nil
+}
+
+// Fetch the encryption key of an OSD from the KMS before the encrypted block is opened
+func getOSDEncryptionKey(cmd *cobra.Command, args []string) error {
+	required := []string{"key-name", "key-path"}
+	if err := flags.VerifyRequiredFlags(osdGetKeyCmd, required); err != nil {
+		return err
+	}
+
+	rook.SetLogLevel()
+	rook.LogStartupInfo(osdGetKeyCmd.Flags())
+
+	if err := osddaemon.WriteKMSEncryptionKey(encryptionKeyName, encryptionKeyPath); err != nil {
+		rook.TerminateFatal(err)
+	}
+	return nil
+}
+
 func commonOSDInit(cmd *cobra.Command) {
 	rook.SetLogLevel()
 	rook.LogStartupInfo(cmd.Flags())
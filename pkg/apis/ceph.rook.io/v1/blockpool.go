@@ -31,3 +31,8 @@ func (p *PoolSpec) IsCompressionEnabled() bool {
 func (p *ReplicatedSpec) IsTargetRatioEnabled() bool {
 	return p.TargetSizeRatio != 0
 }
+
+// IsEnabled returns whether any quota is set on the pool
+func (q *QuotaSpec) IsEnabled() bool {
+	return q.MaxBytes != nil || q.MaxObjects != nil
+}
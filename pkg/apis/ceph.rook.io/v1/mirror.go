@@ -20,3 +20,13 @@ package v1
 func (m *RBDMirroringPeerSpec) HasPeers() bool {
 	return len(m.SecretNames) != 0
 }
+
+// HasPeers returns whether the filesystem has mirror peers to add
+func (m *FSMirroringSpec) HasPeers() bool {
+	return m.Peers != nil && len(m.Peers.SecretNames) != 0
+}
+
+// HasPeers returns whether the pool has mirror peers to add
+func (m *MirroringSpec) HasPeers() bool {
+	return m.Peers != nil && len(m.Peers.SecretNames) != 0
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"strings"
+)
+
+const (
+	// DefaultZoneFailureDomainLabel is the default node label of the zones of the mons
+	DefaultZoneFailureDomainLabel = "topology.kubernetes.io/zone"
+	// DefaultStretchSubFailureDomain is the failure domain within a zone of a stretch cluster
+	DefaultStretchSubFailureDomain = "host"
+	// StretchClusterMonCount is the number of mons of a stretch cluster, two per data zone and one in the arbiter zone
+	StretchClusterMonCount = 5
+)
+
+// IsStretchCluster returns whether the mons are stretched across zones
+func (m *MonSpec) IsStretchCluster() bool {
+	return m.StretchCluster != nil && len(m.StretchCluster.Zones) > 0
+}
+
+// IsZoneAware returns whether the mons are spread across the zones of a node label, outside of a stretch cluster
+func (m *MonSpec) IsZoneAware() bool {
+	return !m.IsStretchCluster() && (len(m.Zones) > 0 || m.FailureDomainLabel != "")
+}
+
+// GetFailureDomainLabel returns the node label of the zones of the mons
+func (m *MonSpec) GetFailureDomainLabel() string {
+	if m.IsStretchCluster() {
+		return m.StretchCluster.GetFailureDomainLabel()
+	}
+	if m.FailureDomainLabel == "" {
+		return DefaultZoneFailureDomainLabel
+	}
+	return m.FailureDomainLabel
+}
+
+// GetFailureDomainLabel returns the node label of the zones
+func (s *StretchClusterSpec) GetFailureDomainLabel() string {
+	if s.FailureDomainLabel == "" {
+		return DefaultZoneFailureDomainLabel
+	}
+	return s.FailureDomainLabel
+}
+
+// FailureDomainKey returns the CRUSH bucket type of the zones, e.g. "zone" for "topology.kubernetes.io/zone"
+func (s *StretchClusterSpec) FailureDomainKey() string {
+	label := s.GetFailureDomainLabel()
+	return label[strings.LastIndex(label, "/")+1:]
+}
+
+// GetSubFailureDomain returns the failure domain within a zone
+func (s *StretchClusterSpec) GetSubFailureDomain() string {
+	if s.SubFailureDomain == "" {
+		return DefaultStretchSubFailureDomain
+	}
+	return s.SubFailureDomain
+}
+
+// ArbiterZone returns the name of the arbiter zone
+func (s *StretchClusterSpec) ArbiterZone() string {
+	for _, zone := range s.Zones {
+		if zone.Arbiter {
+			return zone.Name
+		}
+	}
+	return ""
+}
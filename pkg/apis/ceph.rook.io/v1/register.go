@@ -62,6 +62,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CephBlockPoolList{},
 		&CephFilesystem{},
 		&CephFilesystemList{},
+		&CephFilesystemMirror{},
+		&CephFilesystemMirrorList{},
+		&CephFilesystemSubVolumeGroup{},
+		&CephFilesystemSubVolumeGroupList{},
 		&CephNFS{},
 		&CephNFSList{},
 		&CephObjectStore{},
@@ -74,6 +78,14 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CephObjectZoneGroupList{},
 		&CephObjectZone{},
 		&CephObjectZoneList{},
+		&CephBucketTopic{},
+		&CephBucketTopicList{},
+		&CephBucketNotification{},
+		&CephBucketNotificationList{},
+		&CephObjectRole{},
+		&CephObjectRoleList{},
+		&CephOSDRemoval{},
+		&CephOSDRemovalList{},
 		&CephRBDMirror{},
 		&CephRBDMirrorList{},
 	)
@@ -21,6 +21,7 @@ import (
 
 	rookv1 "github.com/rook/rook/pkg/apis/rook.io/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -44,6 +45,17 @@ type CephCluster struct {
 type CephClusterHealthCheckSpec struct {
 	DaemonHealth  DaemonHealthSpec                     `json:"daemonHealth,omitempty"`
 	LivenessProbe map[rookv1.KeyType]*rookv1.ProbeSpec `json:"livenessProbe,omitempty"`
+	// Capacity is the thresholds of the used capacity alerts
+	Capacity CapacityThresholdSpec `json:"capacity,omitempty"`
+}
+
+// CapacityThresholdSpec is the percentages of used capacity of the cluster, of a device class or of a pool
+// above which the operator reports it as near full or full
+type CapacityThresholdSpec struct {
+	// NearFullPercent is the near full threshold, 75 by default
+	NearFullPercent int `json:"nearFullPercent,omitempty"`
+	// FullPercent is the full threshold, 85 by default
+	FullPercent int `json:"fullPercent,omitempty"`
 }
 
 type DaemonHealthSpec struct {
@@ -129,6 +141,13 @@ type ClusterSpec struct {
 
 	// Internal daemon healthchecks and liveness probe
 	HealthCheck CephClusterHealthCheckSpec `json:"healthCheck"`
+
+	// Security represents security settings
+	Security SecuritySpec `json:"security,omitempty"`
+
+	// CephConfig is the ceph config options to set in the centralized mon config database by section,
+	// such as "global", "osd" or "osd.3". The options are applied live and removed when they leave the spec.
+	CephConfig map[string]map[string]string `json:"cephConfig,omitempty"`
 }
 
 // VersionSpec represents the settings for the Ceph version that Rook is orchestrating.
@@ -186,13 +205,14 @@ type MonitoringSpec struct {
 }
 
 type ClusterStatus struct {
-	State       ClusterState    `json:"state,omitempty"`
-	Phase       ConditionType   `json:"phase,omitempty"`
-	Message     string          `json:"message,omitempty"`
-	Conditions  []Condition     `json:"conditions,omitempty"`
-	CephStatus  *CephStatus     `json:"ceph,omitempty"`
-	CephStorage *CephStorage    `json:"storage,omitempty"`
-	CephVersion *ClusterVersion `json:"version,omitempty"`
+	State       ClusterState       `json:"state,omitempty"`
+	Phase       ConditionType      `json:"phase,omitempty"`
+	Message     string             `json:"message,omitempty"`
+	Conditions  []Condition        `json:"conditions,omitempty"`
+	CephStatus  *CephStatus        `json:"ceph,omitempty"`
+	CephStorage *CephStorage       `json:"storage,omitempty"`
+	CephVersion *ClusterVersion    `json:"version,omitempty"`
+	KeyRotation *KeyRotationStatus `json:"keyRotation,omitempty"`
 }
 
 type CephStatus struct {
@@ -201,10 +221,71 @@ type CephStatus struct {
 	LastChecked    string                       `json:"lastChecked,omitempty"`
 	LastChanged    string                       `json:"lastChanged,omitempty"`
 	PreviousHealth string                       `json:"previousHealth,omitempty"`
+	Capacity       *Capacity                    `json:"capacity,omitempty"`
+}
+
+// Capacity is the raw capacity of the cluster and its breakdown by device class and by pool
+type Capacity struct {
+	TotalBytes     uint64 `json:"bytesTotal,omitempty"`
+	UsedBytes      uint64 `json:"bytesUsed,omitempty"`
+	AvailableBytes uint64 `json:"bytesAvailable,omitempty"`
+	UsedPercent    int    `json:"usedPercent"`
+	LastUpdated    string `json:"lastUpdated,omitempty"`
+	// DeviceClasses is the raw capacity of the OSDs of each device class
+	DeviceClasses []DeviceClassCapacity `json:"deviceClasses,omitempty"`
+	// Pools is the usage of each pool, the available bytes are the data the pool can still store
+	Pools []PoolCapacity `json:"pools,omitempty"`
+}
+
+type DeviceClassCapacity struct {
+	Name           string `json:"name"`
+	TotalBytes     uint64 `json:"bytesTotal,omitempty"`
+	UsedBytes      uint64 `json:"bytesUsed,omitempty"`
+	AvailableBytes uint64 `json:"bytesAvailable,omitempty"`
+	UsedPercent    int    `json:"usedPercent"`
+}
+
+type PoolCapacity struct {
+	Name           string `json:"name"`
+	StoredBytes    uint64 `json:"bytesStored,omitempty"`
+	UsedBytes      uint64 `json:"bytesUsed,omitempty"`
+	AvailableBytes uint64 `json:"bytesAvailable,omitempty"`
+	Objects        uint64 `json:"objects,omitempty"`
+	UsedPercent    int    `json:"usedPercent"`
 }
 
 type CephStorage struct {
 	DeviceClasses []DeviceClasses `json:"deviceClasses,omitempty"`
+	OSDs          []OSDStatus     `json:"osds,omitempty"`
+}
+
+// OSDStatus is the status of an OSD of the cluster
+type OSDStatus struct {
+	ID int `json:"id"`
+	// Host is the node running the OSD
+	Host string `json:"host,omitempty"`
+	// DevicePath is the path of the block device or logical volume of the OSD
+	DevicePath string `json:"devicePath,omitempty"`
+	// DeviceClass is the CRUSH device class of the OSD
+	DeviceClass string `json:"deviceClass,omitempty"`
+	// Store is the object store backend of the OSD
+	Store string `json:"store,omitempty"`
+	// Encrypted is true if the OSD device is encrypted with dm-crypt
+	Encrypted bool `json:"encrypted,omitempty"`
+	// Location is the CRUSH location of the OSD
+	Location string `json:"location,omitempty"`
+	// Up is true if the OSD is up in the OSD map
+	Up bool `json:"up"`
+	// In is true if the OSD is in the OSD map
+	In bool `json:"in"`
+	// LastError is the last failure of the provisioning of the node or PVC of the OSD
+	LastError string `json:"lastError,omitempty"`
+	// PVCName is the name of the data PVC of an OSD on PVC
+	PVCName string `json:"pvcName,omitempty"`
+	// PVCSize is the capacity of the data PVC of an OSD on PVC
+	PVCSize string `json:"pvcSize,omitempty"`
+	// SizeBytes is the size of the OSD reported by Ceph, which follows the PVC once the OSD is expanded
+	SizeBytes uint64 `json:"sizeBytes,omitempty"`
 }
 
 type DeviceClasses struct {
@@ -216,6 +297,20 @@ type ClusterVersion struct {
 	Version string `json:"version,omitempty"`
 }
 
+// KeyRotationStatus is the status of the dm-crypt key rotation of the encrypted OSDs on PVC
+type KeyRotationStatus struct {
+	OSDs []OSDKeyRotationStatus `json:"osds,omitempty"`
+}
+
+// OSDKeyRotationStatus is the status of the key rotation of a single OSD
+type OSDKeyRotationStatus struct {
+	ID           int    `json:"id"`
+	PVCName      string `json:"pvcName,omitempty"`
+	Phase        string `json:"phase,omitempty"`
+	LastRotation string `json:"lastRotation,omitempty"`
+	Message      string `json:"message,omitempty"`
+}
+
 type CephHealthMessage struct {
 	Severity string `json:"severity"`
 	Message  string `json:"message"`
@@ -242,6 +337,19 @@ const (
 	ConditionFailure     ConditionType = "Failure"
 	ConditionUpgrading   ConditionType = "Upgrading"
 	ConditionDeleting    ConditionType = "Deleting"
+
+	// ConditionMetadataSynced reports whether the metadata of an object zone is in sync with the master zone
+	ConditionMetadataSynced ConditionType = "MetadataSynced"
+	// ConditionDataSynced reports whether the data of an object zone is in sync with its source zones
+	ConditionDataSynced ConditionType = "DataSynced"
+	// ConditionZonesSynced reports whether all the zones of an object realm are in sync
+	ConditionZonesSynced ConditionType = "ZonesSynced"
+	// ConditionCephConfigApplied reports whether all the ceph config options of the cluster spec are applied
+	ConditionCephConfigApplied ConditionType = "CephConfigApplied"
+	// ConditionNearFull reports whether the used capacity of the cluster, a device class or a pool is above
+	// the near full threshold
+	ConditionNearFull ConditionType = "NearFull"
+
 	// DefaultFailureDomain for PoolSpec
 	DefaultFailureDomain = "host"
 )
@@ -261,6 +369,40 @@ type MonSpec struct {
 	Count                int                       `json:"count,omitempty"`
 	AllowMultiplePerNode bool                      `json:"allowMultiplePerNode,omitempty"`
 	VolumeClaimTemplate  *v1.PersistentVolumeClaim `json:"volumeClaimTemplate,omitempty"`
+	// StretchCluster is the stretch cluster specification, the mons are spread across two data zones and an arbiter zone
+	StretchCluster *StretchClusterSpec `json:"stretchCluster,omitempty"`
+	// FailureDomainLabel is the node label across which the mons are spread, defaults to "topology.kubernetes.io/zone"
+	// when zones are specified
+	FailureDomainLabel string `json:"failureDomainLabel,omitempty"`
+	// Zones are the zones across which the mons are spread, the zones are discovered from the node labels if not specified
+	Zones []MonZoneSpec `json:"zones,omitempty"`
+}
+
+// MonZoneSpec represents the specification of a zone of the mons
+type MonZoneSpec struct {
+	// Name is the name of the zone, the value of the failure domain label of its nodes
+	Name string `json:"name,omitempty"`
+}
+
+// StretchClusterSpec represents the specification of a stretched Ceph Cluster
+type StretchClusterSpec struct {
+	// FailureDomainLabel is the node label of the zones, defaults to "topology.kubernetes.io/zone"
+	FailureDomainLabel string `json:"failureDomainLabel,omitempty"`
+
+	// SubFailureDomain is the failure domain within a zone, defaults to "host"
+	SubFailureDomain string `json:"subFailureDomain,omitempty"`
+
+	// Zones is the list of zones, two data zones and an arbiter zone
+	Zones []StretchClusterZoneSpec `json:"zones,omitempty"`
+}
+
+// StretchClusterZoneSpec represents the specification of a stretched zone in a Ceph Cluster
+type StretchClusterZoneSpec struct {
+	// Name is the name of the zone, the value of the failure domain label of its nodes
+	Name string `json:"name,omitempty"`
+
+	// Arbiter determines if the zone contains the tiebreaker mon and no data
+	Arbiter bool `json:"arbiter,omitempty"`
 }
 
 // MgrSpec represents options to configure a ceph mgr
@@ -279,6 +421,30 @@ type ExternalSpec struct {
 	Enable bool `json:"enable"`
 }
 
+// SecuritySpec is security spec to include various security items such as kms
+type SecuritySpec struct {
+	// KeyManagementService is the main Key Management option
+	KeyManagementService KeyManagementServiceSpec `json:"kms,omitempty"`
+	// KeyRotation is the policy for the periodic rotation of the dm-crypt keys of the encrypted OSDs on PVC
+	KeyRotation KeyRotationSpec `json:"keyRotation,omitempty"`
+}
+
+// KeyManagementServiceSpec represent various details of the KMS server
+type KeyManagementServiceSpec struct {
+	// ConnectionDetails contains the KMS connection details (address, port etc)
+	ConnectionDetails map[string]string `json:"connectionDetails,omitempty"`
+	// TokenSecretName is the kubernetes secret containing the KMS token
+	TokenSecretName string `json:"tokenSecretName,omitempty"`
+}
+
+// KeyRotationSpec represents the policy to rotate the dm-crypt keys of the encrypted OSDs on PVC
+type KeyRotationSpec struct {
+	// Enabled turns on the periodic rotation of the keys
+	Enabled bool `json:"enabled,omitempty"`
+	// Period is the time between two rotations of the key of an OSD, e.g. "2160h" for 90 days
+	Period string `json:"period,omitempty"`
+}
+
 // CrashCollectorSpec represents options to configure the crash controller
 type CrashCollectorSpec struct {
 	Disable bool `json:"disable"`
@@ -333,6 +499,18 @@ type PoolSpec struct {
 
 	// The mirroring statusCheck
 	StatusCheck MirrorHealthCheckSpec `json:"statusCheck"`
+
+	// The quota settings
+	Quotas QuotaSpec `json:"quotas,omitempty"`
+}
+
+// QuotaSpec represents the spec for quotas in a pool
+type QuotaSpec struct {
+	// MaxBytes represents the quota in bytes, e.g. "10Gi". Zero removes the quota.
+	MaxBytes *resource.Quantity `json:"maxBytes,omitempty"`
+
+	// MaxObjects represents the quota in objects, e.g. "100k". Zero removes the quota.
+	MaxObjects *resource.Quantity `json:"maxObjects,omitempty"`
 }
 
 type MirrorHealthCheckSpec struct {
@@ -353,6 +531,22 @@ type MirroringStatusSpec struct {
 	LastChecked string      `json:"lastChecked,omitempty"`
 	LastChanged string      `json:"lastChanged,omitempty"`
 	Details     string      `json:"details,omitempty"`
+	// ImageStates is the number of mirrored images of the pool in each mirroring state
+	ImageStates map[string]int `json:"imageStates,omitempty"`
+	// FailedImages is the mirroring status of the images in error, only the first ones are listed
+	FailedImages []ImageMirroringStatusSpec `json:"failedImages,omitempty"`
+	// MaxReplicationLagSeconds is the largest replication lag of the images mirrored with snapshots
+	MaxReplicationLagSeconds *int64 `json:"maxReplicationLagSeconds,omitempty"`
+}
+
+// ImageMirroringStatusSpec is the mirroring status of an image
+type ImageMirroringStatusSpec struct {
+	Name        string `json:"name,omitempty"`
+	State       string `json:"state,omitempty"`
+	Description string `json:"description,omitempty"`
+	LastUpdate  string `json:"lastUpdate,omitempty"`
+	// ReplicationLagSeconds is how far the last replicated snapshot is behind the primary, only set for snapshot-based mirroring
+	ReplicationLagSeconds *int64 `json:"replicationLagSeconds,omitempty"`
 }
 
 type SummarySpec map[string]interface{}
@@ -367,6 +561,8 @@ type MirroringInfoSpec struct {
 
 type Status struct {
 	Phase string `json:"phase,omitempty"`
+	// Message explains the phase, e.g. why the resource failed to reconcile
+	Message string `json:"message,omitempty"`
 }
 
 // ReplicatedSpec represents the spec for replication in a pool
@@ -388,6 +584,15 @@ type MirroringSpec struct {
 
 	// Mode is the mirroring mode: either "pool" or "image"
 	Mode string `json:"mode,omitempty"`
+
+	// SnapshotSchedules is the scheduling of the snapshots of the images mirrored with snapshots
+	SnapshotSchedules []SnapshotScheduleSpec `json:"snapshotSchedules,omitempty"`
+
+	// Peers represents the peers of the pool
+	Peers *MirroringPeerSpec `json:"peers,omitempty"`
+
+	// Direction is the mirroring direction of the peers: either "rx-only" or "rx-tx"
+	Direction string `json:"direction,omitempty"`
 }
 
 // ErasureCodeSpec represents the spec for erasure code in a pool
@@ -409,8 +614,8 @@ type ErasureCodedSpec struct {
 type CephFilesystem struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              FilesystemSpec `json:"spec"`
-	Status            *Status        `json:"status"`
+	Spec              FilesystemSpec        `json:"spec"`
+	Status            *CephFilesystemStatus `json:"status"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -434,6 +639,128 @@ type FilesystemSpec struct {
 
 	// The mds pod info
 	MetadataServer MetadataServerSpec `json:"metadataServer"`
+
+	// The mirroring settings
+	Mirroring *FSMirroringSpec `json:"mirroring,omitempty"`
+
+	// The mirroring statusCheck
+	StatusCheck MirrorHealthCheckSpec `json:"statusCheck,omitempty"`
+}
+
+// FSMirroringSpec represents the setting for a mirrored filesystem
+type FSMirroringSpec struct {
+	// Enabled whether this filesystem is mirrored or not
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Peers represents the peers spec
+	Peers *MirroringPeerSpec `json:"peers,omitempty"`
+
+	// Directories are the paths of the filesystem mirrored to the peers
+	Directories []string `json:"directories,omitempty"`
+
+	// SnapshotSchedules is the scheduling of snapshots of the mirrored directories
+	SnapshotSchedules []SnapshotScheduleSpec `json:"snapshotSchedules,omitempty"`
+
+	// SnapshotRetention is the retention policy of the scheduled snapshots
+	SnapshotRetention []SnapshotScheduleRetentionSpec `json:"snapshotRetention,omitempty"`
+}
+
+// MirroringPeerSpec represents the specification of a mirror peer
+type MirroringPeerSpec struct {
+	// SecretNames represents the Kubernetes Secret names holding the bootstrap tokens of the peers
+	SecretNames []string `json:"secretNames,omitempty"`
+}
+
+// SnapshotScheduleSpec represents the snapshot scheduling settings of a directory or of a pool
+type SnapshotScheduleSpec struct {
+	// Path is the path to snapshot, defaults to the root of the filesystem. Only used by filesystems.
+	Path string `json:"path,omitempty"`
+
+	// Interval represents the periodicity of the snapshot, e.g. "1h" or "1d"
+	Interval string `json:"interval,omitempty"`
+
+	// StartTime indicates when to start the snapshot, e.g. "2020-11-01T00:00:00"
+	StartTime string `json:"startTime,omitempty"`
+}
+
+// SnapshotScheduleRetentionSpec is a retention policy of the snapshots of a directory
+type SnapshotScheduleRetentionSpec struct {
+	// Path is the path the retention applies to, defaults to the root of the filesystem
+	Path string `json:"path,omitempty"`
+
+	// Duration represents the retention, e.g. "24h" keeps the last 24 hourly snapshots
+	Duration string `json:"duration,omitempty"`
+}
+
+// CephFilesystemStatus represents the status of a Ceph Filesystem
+type CephFilesystemStatus struct {
+	Phase string `json:"phase,omitempty"`
+
+	// Info contains the name of the bootstrap peer Secret of a mirrored filesystem
+	Info map[string]string `json:"info,omitempty"`
+
+	// MirroringStatus is the status of the mirrored directories
+	MirroringStatus *FilesystemMirroringStatus `json:"mirroringStatus,omitempty"`
+}
+
+// FilesystemMirroringStatus is the mirroring status of a filesystem
+type FilesystemMirroringStatus struct {
+	// Peers is the status of the peers the filesystem is mirrored to
+	Peers []FilesystemMirrorPeerStatus `json:"peers,omitempty"`
+
+	// Directories is the sync status of each mirrored directory to each peer
+	Directories []FilesystemMirrorDirectoryStatus `json:"directories,omitempty"`
+
+	// LastChecked is the last time the status was checked
+	LastChecked string `json:"lastChecked,omitempty"`
+
+	// Details contains potential status errors
+	Details string `json:"details,omitempty"`
+}
+
+// FilesystemMirrorPeerStatus is the status of a peer the filesystem is mirrored to
+type FilesystemMirrorPeerStatus struct {
+	// UUID is the ID of the peer in the filesystem mirroring configuration
+	UUID string `json:"uuid"`
+
+	// Remote is the remote filesystem, e.g. "client.mirror_remote@site-b:myfs"
+	Remote string `json:"remote,omitempty"`
+
+	// FailureCount is the number of failed directory syncs to the peer
+	FailureCount int `json:"failureCount"`
+
+	// RecoveryCount is the number of directories that recovered from a failed sync
+	RecoveryCount int `json:"recoveryCount"`
+}
+
+// FilesystemMirrorDirectoryStatus is the sync status of a mirrored directory to a peer
+type FilesystemMirrorDirectoryStatus struct {
+	// Path is the mirrored directory
+	Path string `json:"path"`
+
+	// PeerUUID is the peer the directory is synced to
+	PeerUUID string `json:"peerUUID,omitempty"`
+
+	// State is the sync state of the directory, e.g. "idle", "syncing" or "failed"
+	State string `json:"state,omitempty"`
+
+	// FailureReason is the reason of the last failed sync, if any
+	FailureReason string `json:"failureReason,omitempty"`
+
+	// LastSyncedSnapshot is the name of the last snapshot synced to the peer
+	LastSyncedSnapshot string `json:"lastSyncedSnapshot,omitempty"`
+
+	// LastSyncDuration is the duration of the last snapshot sync, e.g. "1.5s"
+	LastSyncDuration string `json:"lastSyncDuration,omitempty"`
+
+	// SnapshotsSynced is the number of snapshots synced to the peer
+	SnapshotsSynced int `json:"snapshotsSynced,omitempty"`
+
+	// SnapshotsDeleted is the number of snapshots deleted on the peer
+	SnapshotsDeleted int `json:"snapshotsDeleted,omitempty"`
+
+	// SnapshotsRenamed is the number of snapshots renamed on the peer
+	SnapshotsRenamed int `json:"snapshotsRenamed,omitempty"`
 }
 
 type MetadataServerSpec struct {
@@ -464,6 +791,100 @@ type MetadataServerSpec struct {
 // +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// CephFilesystemMirror is the Ceph Filesystem Mirror object definition
+type CephFilesystemMirror struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              FilesystemMirroringSpec `json:"spec"`
+	Status            *Status                 `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephFilesystemMirrorList is a list of CephFilesystemMirror
+type CephFilesystemMirrorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephFilesystemMirror `json:"items"`
+}
+
+// FilesystemMirroringSpec is the filesystem mirroring specification
+type FilesystemMirroringSpec struct {
+	// The affinity to place the cephfs-mirror pod (default is to place on any available node)
+	Placement rookv1.Placement `json:"placement,omitempty"`
+
+	// The annotations-related configuration to add/set on each Pod related object.
+	Annotations rookv1.Annotations `json:"annotations,omitempty"`
+
+	// The labels-related configuration to add/set on each Pod related object.
+	Labels rookv1.Labels `json:"labels,omitempty"`
+
+	// The resource requirements for the cephfs-mirror pod
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// PriorityClassName sets priority class on the cephfs-mirror pod
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephFilesystemSubVolumeGroup represents a Ceph Filesystem SubVolumeGroup
+type CephFilesystemSubVolumeGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              FilesystemSubVolumeGroupSpec        `json:"spec"`
+	Status            *CephFilesystemSubVolumeGroupStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephFilesystemSubVolumeGroupList represents a list of Ceph Filesystem SubVolumeGroups
+type CephFilesystemSubVolumeGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephFilesystemSubVolumeGroup `json:"items"`
+}
+
+// FilesystemSubVolumeGroupSpec represents the spec of a Filesystem SubVolumeGroup
+type FilesystemSubVolumeGroupSpec struct {
+	// FilesystemName is the name of the CephFilesystem in the same namespace the group is created in
+	FilesystemName string `json:"filesystemName"`
+
+	// Quota is the maximum size of the group, e.g. "100Gi". Zero removes the quota.
+	Quota *resource.Quantity `json:"quota,omitempty"`
+
+	// DataPoolName is the data pool of the filesystem the group is placed in, defaults to the first data pool
+	DataPoolName string `json:"dataPoolName,omitempty"`
+
+	// Pinning pins the group to the MDS ranks
+	Pinning SubVolumeGroupPinningSpec `json:"pinning,omitempty"`
+}
+
+// SubVolumeGroupPinningSpec represents the pinning policy of a SubVolumeGroup, only one policy can be set.
+// See https://docs.ceph.com/docs/master/cephfs/fs-volumes/#pinning-subvolumes-and-subvolume-groups
+type SubVolumeGroupPinningSpec struct {
+	// Export pins the group to the given MDS rank
+	Export *int `json:"export,omitempty"`
+
+	// Distributed spreads the subdirectories of the group across the MDS ranks, 0 disables it
+	Distributed *int `json:"distributed,omitempty"`
+
+	// Random pins each subdirectory of the group to a random MDS rank with the given probability
+	Random *float64 `json:"random,omitempty"`
+}
+
+// CephFilesystemSubVolumeGroupStatus represents the status of a Filesystem SubVolumeGroup
+type CephFilesystemSubVolumeGroupStatus struct {
+	Phase ConditionType     `json:"phase,omitempty"`
+	Info  map[string]string `json:"info,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 type CephObjectStore struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
@@ -487,17 +908,134 @@ type ObjectStoreSpec struct {
 	// The data pool settings
 	DataPool PoolSpec `json:"dataPool"`
 
+	// StorageClasses are the additional S3 storage classes of the default placement target, the objects
+	// of the STANDARD storage class being stored in the DataPool
+	// +optional
+	StorageClasses []ObjectStorageClassSpec `json:"storageClasses,omitempty"`
+
+	// PlacementTargets are the additional placement targets the buckets can be created in
+	// +optional
+	PlacementTargets []PlacementTargetSpec `json:"placementTargets,omitempty"`
+
 	// Preserve pools on object store deletion
 	PreservePoolsOnDelete bool `json:"preservePoolsOnDelete"`
 
 	// The rgw pod info
 	Gateway GatewaySpec `json:"gateway"`
 
+	// GatewayGroups are the additional named groups of gateways of the object store, each group with its own
+	// deployments and service
+	// +optional
+	GatewayGroups []GatewayGroupSpec `json:"gatewayGroups,omitempty"`
+
 	// The multisite info
 	Zone ZoneSpec `json:"zone,omitempty"`
 
 	// The rgw Bucket healthchecks and liveness probe
 	HealthCheck BucketHealthCheckSpec `json:"healthCheck"`
+
+	// Auth authenticates the S3 users against external identity services besides the local rgw users
+	// +optional
+	Auth ObjectStoreAuthSpec `json:"auth,omitempty"`
+
+	// Usage collects the usage of the buckets and users of the object store
+	// +optional
+	Usage *ObjectStoreUsageSpec `json:"usage,omitempty"`
+}
+
+// ObjectStoreUsageSpec represents the periodic collection of the usage of the buckets and users of the object store,
+// the usage is exported as Prometheus metrics by the operator
+type ObjectStoreUsageSpec struct {
+	// Interval between the collections of the usage, "5m" by default and at least "1m"
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// UpdateStatus reports the usage of the users in the status of their CephObjectStoreUser, and the usage of the
+	// buckets in the annotations of their ObjectBucketClaim
+	// +optional
+	UpdateStatus bool `json:"updateStatus,omitempty"`
+}
+
+// ObjectStoreAuthSpec represents the external identity services the S3 users are authenticated against
+type ObjectStoreAuthSpec struct {
+	// Keystone authenticates the users with OpenStack Keystone
+	// +optional
+	Keystone *KeystoneSpec `json:"keystone,omitempty"`
+	// LDAP authenticates the users with an LDAP directory
+	// +optional
+	LDAP *LDAPSpec `json:"ldap,omitempty"`
+	// STS issues temporary credentials to the Kubernetes service accounts allowed by a CephObjectRole
+	// +optional
+	STS *STSSpec `json:"sts,omitempty"`
+}
+
+// STSSpec represents the security token service of the object store, the service account tokens are exchanged
+// for temporary credentials with AssumeRoleWithWebIdentity
+type STSSpec struct {
+	// Issuer is the URL of the OIDC issuer of the service account tokens, "https://kubernetes.default.svc" by default
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+	// Audiences are the audiences the service account tokens must be issued for, "sts.amazonaws.com" by default
+	// +optional
+	Audiences []string `json:"audiences,omitempty"`
+	// Thumbprints are the SHA-1 thumbprints of the certificate of the issuer, the thumbprint of the
+	// Kubernetes CA by default
+	// +optional
+	Thumbprints []string `json:"thumbprints,omitempty"`
+}
+
+// KeystoneSpec represents the OpenStack Keystone server the users are authenticated with, using the identity API v3
+type KeystoneSpec struct {
+	// URL of the Keystone server
+	URL string `json:"url"`
+	// ServiceUserSecretName is the name of the secret with the credentials of the Keystone admin user in the
+	// "OS_USERNAME", "OS_PASSWORD", "OS_PROJECT_NAME" and "OS_USER_DOMAIN_NAME" keys
+	ServiceUserSecretName string `json:"serviceUserSecretName"`
+	// AcceptedRoles are the Keystone roles the users must have one of
+	AcceptedRoles []string `json:"acceptedRoles"`
+	// ImplicitTenants creates the users in a tenant named after their Keystone project: "true", "false", "s3" or "swift"
+	// +optional
+	ImplicitTenants string `json:"implicitTenants,omitempty"`
+	// TokenCacheSize is the maximum number of Keystone tokens cached by the gateways
+	// +optional
+	TokenCacheSize *int `json:"tokenCacheSize,omitempty"`
+}
+
+// LDAPSpec represents the LDAP directory the users are authenticated with
+type LDAPSpec struct {
+	// URI of the LDAP server, e.g. "ldaps://ldap.example.com:636"
+	URI string `json:"uri"`
+	// BindSecretName is the name of the secret with the DN and the password of the user searching the directory
+	// in the "binddn" and "password" keys
+	BindSecretName string `json:"bindSecretName"`
+	// SearchDN is the base DN the users are searched in
+	SearchDN string `json:"searchDN"`
+	// DNAttribute is the attribute of the user entries matching the S3 access key, "uid" by default
+	// +optional
+	DNAttribute string `json:"dnAttribute,omitempty"`
+	// SearchFilter restricts the users allowed to authenticate, e.g. "(memberOf=cn=s3,ou=groups,dc=example,dc=com)"
+	// +optional
+	SearchFilter string `json:"searchFilter,omitempty"`
+}
+
+// PlacementTargetSpec represents a placement target of the object store, the buckets created in the
+// placement store their indexes in the metadata pools of the object store
+type PlacementTargetSpec struct {
+	// Name of the placement target
+	Name string `json:"name"`
+	// DataPool stores the objects of the STANDARD storage class of the placement target
+	DataPool PoolSpec `json:"dataPool"`
+	// StorageClasses are the additional S3 storage classes of the placement target
+	// +optional
+	StorageClasses []ObjectStorageClassSpec `json:"storageClasses,omitempty"`
+}
+
+// ObjectStorageClassSpec represents an S3 storage class of a placement target
+type ObjectStorageClassSpec struct {
+	// Name of the storage class, in upper case by convention, e.g. "COLD"
+	Name string `json:"name"`
+	// DataPool stores the objects of the storage class
+	DataPool PoolSpec `json:"dataPool"`
 }
 
 type BucketHealthCheckSpec struct {
@@ -544,6 +1082,24 @@ type GatewaySpec struct {
 
 	// ExternalRgwEndpoints points to external rgw endpoint(s)
 	ExternalRgwEndpoints []v1.EndpointAddress `json:"externalRgwEndpoints,omitempty"`
+
+	// ServiceType is the type of the rgw service, ClusterIP by default
+	// +optional
+	ServiceType v1.ServiceType `json:"serviceType,omitempty"`
+
+	// ServiceAnnotations are the annotations of the rgw service, e.g. to configure a load balancer
+	// +optional
+	ServiceAnnotations rookv1.Annotations `json:"serviceAnnotations,omitempty"`
+}
+
+// GatewayGroupSpec represents a named group of gateways of the object store. The gateways of the group
+// are named rook-ceph-rgw-<store>-<group>-<id> and served by the rook-ceph-rgw-<store>-<group> service.
+type GatewayGroupSpec struct {
+	// Name of the group, unique in the object store
+	Name string `json:"name"`
+
+	// The settings of the gateways of the group, the settings of the gateway of the store are not inherited
+	GatewaySpec `json:",inline"`
 }
 
 type ZoneSpec struct {
@@ -579,6 +1135,46 @@ type CephObjectStoreUser struct {
 type ObjectStoreUserStatus struct {
 	Phase string            `json:"phase,omitempty"`
 	Info  map[string]string `json:"info"`
+	// KeyRotation is the state of the rotation of the user keys
+	// +optional
+	KeyRotation *ObjectUserKeyRotationStatus `json:"keyRotation,omitempty"`
+	// Usage is the usage of the buckets of the user, reported when the usage collection of the store updates the status
+	// +optional
+	Usage *ObjectUsageStatus `json:"usage,omitempty"`
+}
+
+// ObjectUsageStatus represents the usage of the buckets owned by an object store user
+type ObjectUsageStatus struct {
+	// Size is the size of the objects in bytes
+	Size uint64 `json:"size"`
+	// Objects is the number of objects
+	Objects uint64 `json:"objects"`
+	// Ops is the number of operations of the user in the usage log
+	Ops uint64 `json:"ops"`
+	// SuccessfulOps is the number of successful operations of the user in the usage log
+	SuccessfulOps uint64 `json:"successfulOps"`
+	// BytesSent is the number of bytes sent to the user in the usage log
+	BytesSent uint64 `json:"bytesSent"`
+	// BytesReceived is the number of bytes received from the user in the usage log
+	BytesReceived uint64 `json:"bytesReceived"`
+	// LastCollected is the time of the collection of the usage
+	LastCollected string `json:"lastCollected,omitempty"`
+}
+
+// ObjectUserKeyRotationStatus represents the state of the rotation of the object store user keys
+type ObjectUserKeyRotationStatus struct {
+	// Trigger is the last rotation trigger handled
+	Trigger string `json:"trigger,omitempty"`
+	// AccessKey is the access key stored in the user secret
+	AccessKey string `json:"accessKey,omitempty"`
+	// RetiringKeys are the previous keys of the user, removed at the end of their grace period
+	RetiringKeys []RetiringObjectUserKey `json:"retiringKeys,omitempty"`
+}
+
+// RetiringObjectUserKey is a previous key of an object store user waiting to be removed
+type RetiringObjectUserKey struct {
+	AccessKey  string      `json:"accessKey"`
+	RetireTime metav1.Time `json:"retireTime"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -595,6 +1191,47 @@ type ObjectStoreUserSpec struct {
 	Store string `json:"store,omitempty"`
 	//The display name for the ceph users
 	DisplayName string `json:"displayName,omitempty"`
+	// Quotas of the user, the quotas are not managed if not set
+	// +optional
+	Quotas *ObjectUserQuotaSpec `json:"quotas,omitempty"`
+	// Capabilities of the user on the admin APIs, the capabilities are not managed if not set
+	// +optional
+	Capabilities *ObjectUserCapSpec `json:"capabilities,omitempty"`
+	// KeyRotation rotates the access and secret keys of the user
+	// +optional
+	KeyRotation *ObjectUserKeyRotationSpec `json:"keyRotation,omitempty"`
+}
+
+// ObjectUserQuotaSpec represents the quotas of an object store user
+type ObjectUserQuotaSpec struct {
+	// MaxBuckets represents the maximum number of buckets the user can own
+	MaxBuckets *int `json:"maxBuckets,omitempty"`
+	// MaxSize represents the maximum size of the objects of the user, e.g. "10Gi". Not limited if not set.
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// MaxObjects represents the maximum number of objects of the user. Not limited if not set.
+	MaxObjects *int64 `json:"maxObjects,omitempty"`
+}
+
+// ObjectUserCapSpec represents the capabilities of an object store user on the admin APIs,
+// each capability is one of "read", "write", "*" or empty for no access
+type ObjectUserCapSpec struct {
+	// Users is the capability on the users admin API
+	Users string `json:"users,omitempty"`
+	// Buckets is the capability on the buckets admin API
+	Buckets string `json:"buckets,omitempty"`
+	// Usage is the capability on the usage admin API
+	Usage string `json:"usage,omitempty"`
+	// Metadata is the capability on the metadata admin API
+	Metadata string `json:"metadata,omitempty"`
+}
+
+// ObjectUserKeyRotationSpec represents the rotation of the object store user keys
+type ObjectUserKeyRotationSpec struct {
+	// Trigger generates a new key pair each time its value changes, e.g. the date of the rotation
+	Trigger string `json:"trigger,omitempty"`
+	// GracePeriod is how long the previous keys remain valid after a rotation, e.g. "24h".
+	// The previous keys are removed right away if not set.
+	GracePeriod string `json:"gracePeriod,omitempty"`
 }
 
 // +genclient
@@ -604,8 +1241,8 @@ type ObjectStoreUserSpec struct {
 type CephObjectRealm struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              ObjectRealmSpec `json:"spec"`
-	Status            *Status         `json:"status"`
+	Spec              ObjectRealmSpec    `json:"spec"`
+	Status            *ObjectRealmStatus `json:"status"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -625,6 +1262,24 @@ type PullSpec struct {
 	Endpoint string `json:"endpoint"`
 }
 
+// ObjectRealmStatus represents the status of an ObjectRealm
+type ObjectRealmStatus struct {
+	Phase string `json:"phase,omitempty"`
+	// Zones is the sync summary of the zones of the realm managed by this cluster
+	Zones      []ObjectRealmZoneStatus `json:"zones,omitempty"`
+	Conditions []Condition             `json:"conditions,omitempty"`
+}
+
+// ObjectRealmZoneStatus is the sync summary of a zone of the realm
+type ObjectRealmZoneStatus struct {
+	Name      string `json:"name"`
+	ZoneGroup string `json:"zoneGroup"`
+	// CaughtUp is whether the metadata and the data of the zone are in sync with the other zones
+	CaughtUp bool `json:"caughtUp"`
+	// LagSeconds is the age of the oldest change not applied yet to the zone
+	LagSeconds int64 `json:"lagSeconds,omitempty"`
+}
+
 // +genclient
 // +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -648,6 +1303,10 @@ type CephObjectZoneGroupList struct {
 type ObjectZoneGroupSpec struct {
 	//The display name for the ceph users
 	Realm string `json:"realm"`
+
+	// Master promotes the zone group to the master zone group of the realm, e.g. when the master zone group is lost.
+	// The promotion is refused while the current master zone group answers on one of its endpoints.
+	Master bool `json:"master,omitempty"`
 }
 
 // +genclient
@@ -657,8 +1316,8 @@ type ObjectZoneGroupSpec struct {
 type CephObjectZone struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              ObjectZoneSpec `json:"spec"`
-	Status            *Status        `json:"status"`
+	Spec              ObjectZoneSpec    `json:"spec"`
+	Status            *ObjectZoneStatus `json:"status"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -679,6 +1338,249 @@ type ObjectZoneSpec struct {
 
 	// The data pool settings
 	DataPool PoolSpec `json:"dataPool"`
+
+	// Master promotes the zone to the master zone of the zone group, e.g. when the master zone is lost.
+	// The promotion is refused while the current master zone answers on one of its endpoints.
+	Master bool `json:"master,omitempty"`
+}
+
+// ObjectZoneStatus represents the status of an ObjectZone
+type ObjectZoneStatus struct {
+	Phase string `json:"phase,omitempty"`
+	// SyncStatus is the multisite sync status of the zone, refreshed periodically
+	SyncStatus *ObjectZoneSyncStatus `json:"syncStatus,omitempty"`
+	Conditions []Condition           `json:"conditions,omitempty"`
+}
+
+// ObjectZoneSyncStatus is the multisite sync status of a zone as reported by radosgw-admin
+type ObjectZoneSyncStatus struct {
+	// LastChecked is when the sync status was collected
+	LastChecked metav1.Time `json:"lastChecked,omitempty"`
+	// Metadata is the sync of the metadata from the master zone
+	Metadata ObjectSyncProgress `json:"metadata"`
+	// DataSources is the sync of the data from each source zone
+	DataSources []ObjectDataSyncSource `json:"dataSources,omitempty"`
+}
+
+// ObjectSyncProgress is the progress of the metadata or data sync of a zone
+type ObjectSyncProgress struct {
+	// State is the sync state, e.g. "syncing" or "no sync (zone is master)"
+	State    string `json:"state,omitempty"`
+	CaughtUp bool   `json:"caughtUp"`
+	// ShardsBehind is the number of log shards with changes not applied yet
+	ShardsBehind int `json:"shardsBehind,omitempty"`
+	// RecoveringShards is the number of log shards recovering from errors
+	RecoveringShards int `json:"recoveringShards,omitempty"`
+	// OldestChange is the time of the oldest change not applied yet
+	OldestChange *metav1.Time `json:"oldestChange,omitempty"`
+	// LagSeconds is the age of the oldest change not applied yet
+	LagSeconds int64 `json:"lagSeconds,omitempty"`
+}
+
+// ObjectDataSyncSource is the data sync of a zone from a source zone
+type ObjectDataSyncSource struct {
+	Zone               string `json:"zone"`
+	ObjectSyncProgress `json:",inline"`
+	// BucketsBehind is the number of buckets with changes from the source zone not applied yet
+	BucketsBehind int `json:"bucketsBehind,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephBucketTopic represents a topic the bucket notifications of an object store are pushed to
+type CephBucketTopic struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              BucketTopicSpec    `json:"spec"`
+	Status            *BucketTopicStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephBucketTopicList represents a list of Ceph bucket topics
+type CephBucketTopicList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephBucketTopic `json:"items"`
+}
+
+// BucketTopicSpec represents the spec of a bucket topic
+type BucketTopicSpec struct {
+	// ObjectStoreName is the name of the object store the topic is created in
+	ObjectStoreName string `json:"objectStoreName"`
+	// OpaqueData is sent along with every notification of the topic
+	OpaqueData string `json:"opaqueData,omitempty"`
+	// Persistent stores the notifications until they are acknowledged by the endpoint
+	Persistent bool `json:"persistent,omitempty"`
+	// Endpoint is where the notifications are pushed to
+	Endpoint TopicEndpointSpec `json:"endpoint"`
+}
+
+// TopicEndpointSpec represents the endpoint of a bucket topic, only one type of endpoint can be set
+type TopicEndpointSpec struct {
+	HTTP  *HTTPEndpointSpec  `json:"http,omitempty"`
+	AMQP  *AMQPEndpointSpec  `json:"amqp,omitempty"`
+	Kafka *KafkaEndpointSpec `json:"kafka,omitempty"`
+	// AuthSecretName is the name of a secret with the "username" and "password" to authenticate to the endpoint
+	AuthSecretName string `json:"authSecretName,omitempty"`
+}
+
+// HTTPEndpointSpec represents an HTTP endpoint of a bucket topic
+type HTTPEndpointSpec struct {
+	// URI of the endpoint, in the form of http[s]://<fqdn>[:<port>][/<resource>]
+	URI string `json:"uri"`
+	// DisableVerifySSL skips the verification of the server certificate
+	DisableVerifySSL bool `json:"disableVerifySSL,omitempty"`
+}
+
+// AMQPEndpointSpec represents an AMQP endpoint of a bucket topic
+type AMQPEndpointSpec struct {
+	// URI of the endpoint, in the form of amqp[s]://<fqdn>[:<port>][/<vhost>]
+	URI string `json:"uri"`
+	// Exchange is the AMQP exchange the notifications are routed through
+	Exchange string `json:"exchange"`
+	// AckLevel is when the notification is considered delivered: "none", "broker" or "routable"
+	AckLevel string `json:"ackLevel,omitempty"`
+	// DisableVerifySSL skips the verification of the server certificate
+	DisableVerifySSL bool `json:"disableVerifySSL,omitempty"`
+}
+
+// KafkaEndpointSpec represents a Kafka endpoint of a bucket topic
+type KafkaEndpointSpec struct {
+	// URI of the endpoint, in the form of kafka://<fqdn>[:<port>], the topic of the notifications is the name of the bucket topic
+	URI string `json:"uri"`
+	// UseSSL connects to the broker with SSL
+	UseSSL bool `json:"useSSL,omitempty"`
+	// AckLevel is when the notification is considered delivered: "none" or "broker"
+	AckLevel string `json:"ackLevel,omitempty"`
+	// DisableVerifySSL skips the verification of the server certificate
+	DisableVerifySSL bool `json:"disableVerifySSL,omitempty"`
+}
+
+// BucketTopicStatus represents the status of a bucket topic
+type BucketTopicStatus struct {
+	Phase string `json:"phase,omitempty"`
+	// ARN is the Amazon Resource Name of the topic, used by the bucket notifications
+	ARN string `json:"arn,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephBucketNotification represents a notification of the bucket events to a bucket topic
+type CephBucketNotification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              BucketNotificationSpec    `json:"spec"`
+	Status            *BucketNotificationStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephBucketNotificationList represents a list of Ceph bucket notifications
+type CephBucketNotificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephBucketNotification `json:"items"`
+}
+
+// BucketNotificationEvent is a type of bucket event, for example "s3:ObjectCreated:*"
+type BucketNotificationEvent string
+
+// BucketNotificationSpec represents the spec of a bucket notification
+type BucketNotificationSpec struct {
+	// Topic is the name of the CephBucketTopic the events are pushed to
+	Topic string `json:"topic"`
+	// Events are the bucket events notified, all the events are notified if empty
+	Events []BucketNotificationEvent `json:"events,omitempty"`
+	// Filter restricts the objects the events are notified for
+	Filter *NotificationFilterSpec `json:"filter,omitempty"`
+}
+
+// NotificationFilterSpec represents the filters of a bucket notification
+type NotificationFilterSpec struct {
+	// KeyFilters match the name of the objects
+	KeyFilters []NotificationKeyFilterRule `json:"keyFilters,omitempty"`
+}
+
+// NotificationKeyFilterRule represents a filter on the name of the objects
+type NotificationKeyFilterRule struct {
+	// Name of the filter: "prefix", "suffix" or "regex"
+	Name string `json:"name"`
+	// Value to match the object name with
+	Value string `json:"value"`
+}
+
+// BucketNotificationStatus represents the status of a bucket notification
+type BucketNotificationStatus struct {
+	Phase string `json:"phase,omitempty"`
+	// ObjectBucketClaims are the "<namespace>/<name>" of the claims whose bucket the notification is attached to
+	ObjectBucketClaims []string `json:"objectBucketClaims,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephObjectRole represents a role of an object store the Kubernetes service accounts can assume
+type CephObjectRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              ObjectRoleSpec    `json:"spec"`
+	Status            *ObjectRoleStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephObjectRoleList represents a list of Ceph object roles
+type CephObjectRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephObjectRole `json:"items"`
+}
+
+// ObjectRoleSpec represents the spec of an object role
+type ObjectRoleSpec struct {
+	// ObjectStoreName is the name of the object store the role is created in, STS must be enabled on the store
+	ObjectStoreName string `json:"objectStoreName"`
+	// ServiceAccounts are the service accounts allowed to assume the role
+	ServiceAccounts []ObjectRoleServiceAccount `json:"serviceAccounts"`
+	// Policies are the permissions of the role
+	Policies []ObjectRolePolicySpec `json:"policies"`
+}
+
+// ObjectRoleServiceAccount represents a service account allowed to assume an object role
+type ObjectRoleServiceAccount struct {
+	// Namespace of the service account
+	Namespace string `json:"namespace"`
+	// Name of the service account
+	Name string `json:"name"`
+}
+
+// ObjectRolePolicySpec represents a policy of an object role
+type ObjectRolePolicySpec struct {
+	// Name of the policy, unique in the role
+	Name string `json:"name"`
+	// Effect of the policy, "Allow" or "Deny". Defaults to "Allow"
+	// +optional
+	Effect string `json:"effect,omitempty"`
+	// Actions are the "s3:*" actions of the policy, a lenient list of the object and bucket actions by default
+	// +optional
+	Actions []string `json:"actions,omitempty"`
+	// Buckets are the names of the buckets the policy applies to, along with their objects. "*" for all the buckets
+	Buckets []string `json:"buckets"`
+}
+
+// ObjectRoleStatus represents the status of an object role
+type ObjectRoleStatus struct {
+	Phase string `json:"phase,omitempty"`
+	// ARN is the Amazon Resource Name of the role, assumed with AssumeRoleWithWebIdentity
+	ARN string `json:"arn,omitempty"`
+	// Message explains the phase, e.g. why the role failed validation
+	Message string `json:"message,omitempty"`
 }
 
 // +genclient
@@ -809,6 +1711,82 @@ type SanitizeDisksSpec struct {
 // +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// CephOSDRemoval represents a request to remove OSDs from a Ceph cluster
+type CephOSDRemoval struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              OSDRemovalSpec        `json:"spec"`
+	Status            *CephOSDRemovalStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephOSDRemovalList represents a list of OSD removal requests
+type CephOSDRemovalList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephOSDRemoval `json:"items"`
+}
+
+// OSDRemovalSpec represents the spec of an OSD removal request
+type OSDRemovalSpec struct {
+	// OSDIDs are the IDs of the OSDs to remove
+	OSDIDs []int `json:"osdIDs"`
+
+	// ForceRemoval removes the OSDs without waiting for their data to be safe on the other OSDs
+	ForceRemoval bool `json:"forceRemoval,omitempty"`
+
+	// PreservePVC keeps the PVC of an OSD on PVC after the OSD is removed
+	PreservePVC bool `json:"preservePVC,omitempty"`
+
+	// SanitizeDisks wipes the devices of the OSDs on nodes after they are removed
+	SanitizeDisks *SanitizeDisksSpec `json:"sanitizeDisks,omitempty"`
+
+	// Replace destroys the OSDs on the PVCs of a storageClassDeviceSet while keeping their IDs, the OSDs are
+	// re-created with the same IDs on new PVCs
+	Replace bool `json:"replace,omitempty"`
+}
+
+// OSDRemovalPhase is the phase of the removal of an OSD
+type OSDRemovalPhase string
+
+const (
+	// OSDRemovalPending means the removal of the OSD did not start
+	OSDRemovalPending OSDRemovalPhase = "Pending"
+	// OSDRemovalDraining means the OSD is out and its data is moved to the other OSDs
+	OSDRemovalDraining OSDRemovalPhase = "Draining"
+	// OSDRemovalSanitizing means the OSD is purged and its device is wiped
+	OSDRemovalSanitizing OSDRemovalPhase = "Sanitizing"
+	// OSDRemovalReplacing means the OSD is destroyed and waits to be re-created on a new PVC
+	OSDRemovalReplacing OSDRemovalPhase = "Replacing"
+	// OSDRemovalCompleted means the OSD is removed
+	OSDRemovalCompleted OSDRemovalPhase = "Completed"
+	// OSDRemovalFailed means the OSD cannot be removed
+	OSDRemovalFailed OSDRemovalPhase = "Failed"
+)
+
+// CephOSDRemovalStatus represents the status of an OSD removal request
+type CephOSDRemovalStatus struct {
+	Phase ConditionType      `json:"phase,omitempty"`
+	OSDs  []OSDRemovalStatus `json:"osds,omitempty"`
+}
+
+// OSDRemovalStatus represents the progress of the removal of an OSD
+type OSDRemovalStatus struct {
+	ID         int             `json:"id"`
+	Phase      OSDRemovalPhase `json:"phase"`
+	Message    string          `json:"message,omitempty"`
+	Host       string          `json:"host,omitempty"`
+	LastUpdate string          `json:"lastUpdate,omitempty"`
+	PVCName    string          `json:"pvcName,omitempty"`
+	// DeviceSetPVCID is the ID of the data PVC of a replaced OSD in its storageClassDeviceSet
+	DeviceSetPVCID string `json:"deviceSetPVCID,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 type CephRBDMirror struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
@@ -63,6 +63,14 @@ func ValidatePoolSpecs(ps PoolSpec) error {
 			return errors.New("invalid create: erasurecoded.codingchunks needs minimum value of 1")
 		}
 	}
+
+	// Check if the quotas are positive
+	if ps.Quotas.MaxBytes != nil && ps.Quotas.MaxBytes.Sign() < 0 {
+		return errors.New("invalid create: quotas.maxBytes cannot be negative")
+	}
+	if ps.Quotas.MaxObjects != nil && ps.Quotas.MaxObjects.Sign() < 0 {
+		return errors.New("invalid create: quotas.maxObjects cannot be negative")
+	}
 	return nil
 }
 
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -61,6 +62,12 @@ func TestValidatePoolSpec(t *testing.T) {
 	p.Spec.ErasureCoded.DataChunks = 1
 	err = ValidatePoolSpecs(p.Spec)
 	assert.Error(t, err)
+
+	p.Spec.ErasureCoded.DataChunks = 2
+	maxBytes := resource.MustParse("-1Gi")
+	p.Spec.Quotas.MaxBytes = &maxBytes
+	err = ValidatePoolSpecs(p.Spec)
+	assert.Error(t, err)
 }
 
 func TestCephBlockPoolValidateUpdate(t *testing.T) {
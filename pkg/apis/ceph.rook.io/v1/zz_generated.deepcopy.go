@@ -27,6 +27,22 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMQPEndpointSpec) DeepCopyInto(out *AMQPEndpointSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMQPEndpointSpec.
+func (in *AMQPEndpointSpec) DeepCopy() *AMQPEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AMQPEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BucketHealthCheckSpec) DeepCopyInto(out *BucketHealthCheckSpec) {
 	*out = *in
@@ -49,6 +65,53 @@ func (in *BucketHealthCheckSpec) DeepCopy() *BucketHealthCheckSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketNotificationSpec) DeepCopyInto(out *BucketNotificationSpec) {
+	*out = *in
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]BucketNotificationEvent, len(*in))
+		copy(*out, *in)
+	}
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(NotificationFilterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketNotificationSpec.
+func (in *BucketNotificationSpec) DeepCopy() *BucketNotificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketNotificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketNotificationStatus) DeepCopyInto(out *BucketNotificationStatus) {
+	*out = *in
+	if in.ObjectBucketClaims != nil {
+		in, out := &in.ObjectBucketClaims, &out.ObjectBucketClaims
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketNotificationStatus.
+func (in *BucketNotificationStatus) DeepCopy() *BucketNotificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketNotificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BucketStatus) DeepCopyInto(out *BucketStatus) {
 	*out = *in
@@ -65,6 +128,81 @@ func (in *BucketStatus) DeepCopy() *BucketStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketTopicSpec) DeepCopyInto(out *BucketTopicSpec) {
+	*out = *in
+	in.Endpoint.DeepCopyInto(&out.Endpoint)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketTopicSpec.
+func (in *BucketTopicSpec) DeepCopy() *BucketTopicSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketTopicSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketTopicStatus) DeepCopyInto(out *BucketTopicStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketTopicStatus.
+func (in *BucketTopicStatus) DeepCopy() *BucketTopicStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketTopicStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Capacity) DeepCopyInto(out *Capacity) {
+	*out = *in
+	if in.DeviceClasses != nil {
+		in, out := &in.DeviceClasses, &out.DeviceClasses
+		*out = make([]DeviceClassCapacity, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]PoolCapacity, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Capacity.
+func (in *Capacity) DeepCopy() *Capacity {
+	if in == nil {
+		return nil
+	}
+	out := new(Capacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityThresholdSpec) DeepCopyInto(out *CapacityThresholdSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityThresholdSpec.
+func (in *CapacityThresholdSpec) DeepCopy() *CapacityThresholdSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityThresholdSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephBlockPool) DeepCopyInto(out *CephBlockPool) {
 	*out = *in
@@ -163,6 +301,136 @@ func (in *CephBlockPoolStatus) DeepCopy() *CephBlockPoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephBucketNotification) DeepCopyInto(out *CephBucketNotification) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(BucketNotificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephBucketNotification.
+func (in *CephBucketNotification) DeepCopy() *CephBucketNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(CephBucketNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephBucketNotification) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephBucketNotificationList) DeepCopyInto(out *CephBucketNotificationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephBucketNotification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephBucketNotificationList.
+func (in *CephBucketNotificationList) DeepCopy() *CephBucketNotificationList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephBucketNotificationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephBucketNotificationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephBucketTopic) DeepCopyInto(out *CephBucketTopic) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(BucketTopicStatus)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephBucketTopic.
+func (in *CephBucketTopic) DeepCopy() *CephBucketTopic {
+	if in == nil {
+		return nil
+	}
+	out := new(CephBucketTopic)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephBucketTopic) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephBucketTopicList) DeepCopyInto(out *CephBucketTopicList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephBucketTopic, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephBucketTopicList.
+func (in *CephBucketTopicList) DeepCopy() *CephBucketTopicList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephBucketTopicList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephBucketTopicList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephClient) DeepCopyInto(out *CephClient) {
 	*out = *in
@@ -270,6 +538,7 @@ func (in *CephClusterHealthCheckSpec) DeepCopyInto(out *CephClusterHealthCheckSp
 			(*out)[key] = outVal
 		}
 	}
+	out.Capacity = in.Capacity
 	return
 }
 
@@ -324,8 +593,8 @@ func (in *CephFilesystem) DeepCopyInto(out *CephFilesystem) {
 	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(Status)
-		**out = **in
+		*out = new(CephFilesystemStatus)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -382,23 +651,7 @@ func (in *CephFilesystemList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephHealthMessage) DeepCopyInto(out *CephHealthMessage) {
-	*out = *in
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephHealthMessage.
-func (in *CephHealthMessage) DeepCopy() *CephHealthMessage {
-	if in == nil {
-		return nil
-	}
-	out := new(CephHealthMessage)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephNFS) DeepCopyInto(out *CephNFS) {
+func (in *CephFilesystemMirror) DeepCopyInto(out *CephFilesystemMirror) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -411,18 +664,18 @@ func (in *CephNFS) DeepCopyInto(out *CephNFS) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephNFS.
-func (in *CephNFS) DeepCopy() *CephNFS {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemMirror.
+func (in *CephFilesystemMirror) DeepCopy() *CephFilesystemMirror {
 	if in == nil {
 		return nil
 	}
-	out := new(CephNFS)
+	out := new(CephFilesystemMirror)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephNFS) DeepCopyObject() runtime.Object {
+func (in *CephFilesystemMirror) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -430,13 +683,13 @@ func (in *CephNFS) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephNFSList) DeepCopyInto(out *CephNFSList) {
+func (in *CephFilesystemMirrorList) DeepCopyInto(out *CephFilesystemMirrorList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CephNFS, len(*in))
+		*out = make([]CephFilesystemMirror, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -444,18 +697,18 @@ func (in *CephNFSList) DeepCopyInto(out *CephNFSList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephNFSList.
-func (in *CephNFSList) DeepCopy() *CephNFSList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemMirrorList.
+func (in *CephFilesystemMirrorList) DeepCopy() *CephFilesystemMirrorList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephNFSList)
+	out := new(CephFilesystemMirrorList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephNFSList) DeepCopyObject() runtime.Object {
+func (in *CephFilesystemMirrorList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -463,31 +716,59 @@ func (in *CephNFSList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectRealm) DeepCopyInto(out *CephObjectRealm) {
+func (in *CephFilesystemStatus) DeepCopyInto(out *CephFilesystemStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
-		*out = new(Status)
-		**out = **in
+	if in.Info != nil {
+		in, out := &in.Info, &out.Info
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MirroringStatus != nil {
+		in, out := &in.MirroringStatus, &out.MirroringStatus
+		*out = new(FilesystemMirroringStatus)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectRealm.
-func (in *CephObjectRealm) DeepCopy() *CephObjectRealm {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemStatus.
+func (in *CephFilesystemStatus) DeepCopy() *CephFilesystemStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectRealm)
+	out := new(CephFilesystemStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectRealm) DeepCopyObject() runtime.Object {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephFilesystemSubVolumeGroup) DeepCopyInto(out *CephFilesystemSubVolumeGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(CephFilesystemSubVolumeGroupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemSubVolumeGroup.
+func (in *CephFilesystemSubVolumeGroup) DeepCopy() *CephFilesystemSubVolumeGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(CephFilesystemSubVolumeGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephFilesystemSubVolumeGroup) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -495,13 +776,13 @@ func (in *CephObjectRealm) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectRealmList) DeepCopyInto(out *CephObjectRealmList) {
+func (in *CephFilesystemSubVolumeGroupList) DeepCopyInto(out *CephFilesystemSubVolumeGroupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CephObjectRealm, len(*in))
+		*out = make([]CephFilesystemSubVolumeGroup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -509,18 +790,18 @@ func (in *CephObjectRealmList) DeepCopyInto(out *CephObjectRealmList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectRealmList.
-func (in *CephObjectRealmList) DeepCopy() *CephObjectRealmList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemSubVolumeGroupList.
+func (in *CephFilesystemSubVolumeGroupList) DeepCopy() *CephFilesystemSubVolumeGroupList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectRealmList)
+	out := new(CephFilesystemSubVolumeGroupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectRealmList) DeepCopyObject() runtime.Object {
+func (in *CephFilesystemSubVolumeGroupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -528,31 +809,70 @@ func (in *CephObjectRealmList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectStore) DeepCopyInto(out *CephObjectStore) {
+func (in *CephFilesystemSubVolumeGroupStatus) DeepCopyInto(out *CephFilesystemSubVolumeGroupStatus) {
+	*out = *in
+	if in.Info != nil {
+		in, out := &in.Info, &out.Info
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemSubVolumeGroupStatus.
+func (in *CephFilesystemSubVolumeGroupStatus) DeepCopy() *CephFilesystemSubVolumeGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephFilesystemSubVolumeGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephHealthMessage) DeepCopyInto(out *CephHealthMessage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephHealthMessage.
+func (in *CephHealthMessage) DeepCopy() *CephHealthMessage {
+	if in == nil {
+		return nil
+	}
+	out := new(CephHealthMessage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephNFS) DeepCopyInto(out *CephNFS) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(ObjectStoreStatus)
-		(*in).DeepCopyInto(*out)
+		*out = new(Status)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStore.
-func (in *CephObjectStore) DeepCopy() *CephObjectStore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephNFS.
+func (in *CephNFS) DeepCopy() *CephNFS {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectStore)
+	out := new(CephNFS)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectStore) DeepCopyObject() runtime.Object {
+func (in *CephNFS) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -560,13 +880,13 @@ func (in *CephObjectStore) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectStoreList) DeepCopyInto(out *CephObjectStoreList) {
+func (in *CephNFSList) DeepCopyInto(out *CephNFSList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CephObjectStore, len(*in))
+		*out = make([]CephNFS, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -574,18 +894,18 @@ func (in *CephObjectStoreList) DeepCopyInto(out *CephObjectStoreList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStoreList.
-func (in *CephObjectStoreList) DeepCopy() *CephObjectStoreList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephNFSList.
+func (in *CephNFSList) DeepCopy() *CephNFSList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectStoreList)
+	out := new(CephNFSList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectStoreList) DeepCopyObject() runtime.Object {
+func (in *CephNFSList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -593,31 +913,31 @@ func (in *CephObjectStoreList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectStoreUser) DeepCopyInto(out *CephObjectStoreUser) {
+func (in *CephOSDRemoval) DeepCopyInto(out *CephOSDRemoval) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(ObjectStoreUserStatus)
+		*out = new(CephOSDRemovalStatus)
 		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStoreUser.
-func (in *CephObjectStoreUser) DeepCopy() *CephObjectStoreUser {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOSDRemoval.
+func (in *CephOSDRemoval) DeepCopy() *CephOSDRemoval {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectStoreUser)
+	out := new(CephOSDRemoval)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectStoreUser) DeepCopyObject() runtime.Object {
+func (in *CephOSDRemoval) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -625,13 +945,13 @@ func (in *CephObjectStoreUser) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectStoreUserList) DeepCopyInto(out *CephObjectStoreUserList) {
+func (in *CephOSDRemovalList) DeepCopyInto(out *CephOSDRemovalList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CephObjectStoreUser, len(*in))
+		*out = make([]CephOSDRemoval, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -639,18 +959,18 @@ func (in *CephObjectStoreUserList) DeepCopyInto(out *CephObjectStoreUserList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStoreUserList.
-func (in *CephObjectStoreUserList) DeepCopy() *CephObjectStoreUserList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOSDRemovalList.
+func (in *CephOSDRemovalList) DeepCopy() *CephOSDRemovalList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectStoreUserList)
+	out := new(CephOSDRemovalList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectStoreUserList) DeepCopyObject() runtime.Object {
+func (in *CephOSDRemovalList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -658,31 +978,52 @@ func (in *CephObjectStoreUserList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectZone) DeepCopyInto(out *CephObjectZone) {
+func (in *CephOSDRemovalStatus) DeepCopyInto(out *CephOSDRemovalStatus) {
+	*out = *in
+	if in.OSDs != nil {
+		in, out := &in.OSDs, &out.OSDs
+		*out = make([]OSDRemovalStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOSDRemovalStatus.
+func (in *CephOSDRemovalStatus) DeepCopy() *CephOSDRemovalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephOSDRemovalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephObjectRealm) DeepCopyInto(out *CephObjectRealm) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	out.Spec = in.Spec
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(Status)
-		**out = **in
+		*out = new(ObjectRealmStatus)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZone.
-func (in *CephObjectZone) DeepCopy() *CephObjectZone {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectRealm.
+func (in *CephObjectRealm) DeepCopy() *CephObjectRealm {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectZone)
+	out := new(CephObjectRealm)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectZone) DeepCopyObject() runtime.Object {
+func (in *CephObjectRealm) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -690,31 +1031,32 @@ func (in *CephObjectZone) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectZoneGroup) DeepCopyInto(out *CephObjectZoneGroup) {
+func (in *CephObjectRealmList) DeepCopyInto(out *CephObjectRealmList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
-		*out = new(Status)
-		**out = **in
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephObjectRealm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZoneGroup.
-func (in *CephObjectZoneGroup) DeepCopy() *CephObjectZoneGroup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectRealmList.
+func (in *CephObjectRealmList) DeepCopy() *CephObjectRealmList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectZoneGroup)
+	out := new(CephObjectRealmList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectZoneGroup) DeepCopyObject() runtime.Object {
+func (in *CephObjectRealmList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -722,32 +1064,31 @@ func (in *CephObjectZoneGroup) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectZoneGroupList) DeepCopyInto(out *CephObjectZoneGroupList) {
+func (in *CephObjectRole) DeepCopyInto(out *CephObjectRole) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]CephObjectZoneGroup, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(ObjectRoleStatus)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZoneGroupList.
-func (in *CephObjectZoneGroupList) DeepCopy() *CephObjectZoneGroupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectRole.
+func (in *CephObjectRole) DeepCopy() *CephObjectRole {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectZoneGroupList)
+	out := new(CephObjectRole)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectZoneGroupList) DeepCopyObject() runtime.Object {
+func (in *CephObjectRole) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -755,13 +1096,13 @@ func (in *CephObjectZoneGroupList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephObjectZoneList) DeepCopyInto(out *CephObjectZoneList) {
+func (in *CephObjectRoleList) DeepCopyInto(out *CephObjectRoleList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CephObjectZone, len(*in))
+		*out = make([]CephObjectRole, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -769,18 +1110,18 @@ func (in *CephObjectZoneList) DeepCopyInto(out *CephObjectZoneList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZoneList.
-func (in *CephObjectZoneList) DeepCopy() *CephObjectZoneList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectRoleList.
+func (in *CephObjectRoleList) DeepCopy() *CephObjectRoleList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephObjectZoneList)
+	out := new(CephObjectRoleList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephObjectZoneList) DeepCopyObject() runtime.Object {
+func (in *CephObjectRoleList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -788,31 +1129,31 @@ func (in *CephObjectZoneList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephRBDMirror) DeepCopyInto(out *CephRBDMirror) {
+func (in *CephObjectStore) DeepCopyInto(out *CephObjectStore) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(Status)
-		**out = **in
+		*out = new(ObjectStoreStatus)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephRBDMirror.
-func (in *CephRBDMirror) DeepCopy() *CephRBDMirror {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStore.
+func (in *CephObjectStore) DeepCopy() *CephObjectStore {
 	if in == nil {
 		return nil
 	}
-	out := new(CephRBDMirror)
+	out := new(CephObjectStore)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephRBDMirror) DeepCopyObject() runtime.Object {
+func (in *CephObjectStore) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -820,13 +1161,13 @@ func (in *CephRBDMirror) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephRBDMirrorList) DeepCopyInto(out *CephRBDMirrorList) {
+func (in *CephObjectStoreList) DeepCopyInto(out *CephObjectStoreList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CephRBDMirror, len(*in))
+		*out = make([]CephObjectStore, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -834,18 +1175,18 @@ func (in *CephRBDMirrorList) DeepCopyInto(out *CephRBDMirrorList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephRBDMirrorList.
-func (in *CephRBDMirrorList) DeepCopy() *CephRBDMirrorList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStoreList.
+func (in *CephObjectStoreList) DeepCopy() *CephObjectStoreList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephRBDMirrorList)
+	out := new(CephObjectStoreList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CephRBDMirrorList) DeepCopyObject() runtime.Object {
+func (in *CephObjectStoreList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -853,909 +1194,2216 @@ func (in *CephRBDMirrorList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephStatus) DeepCopyInto(out *CephStatus) {
+func (in *CephObjectStoreUser) DeepCopyInto(out *CephObjectStoreUser) {
 	*out = *in
-	if in.Details != nil {
-		in, out := &in.Details, &out.Details
-		*out = make(map[string]CephHealthMessage, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(ObjectStoreUserStatus)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephStatus.
-func (in *CephStatus) DeepCopy() *CephStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStoreUser.
+func (in *CephObjectStoreUser) DeepCopy() *CephObjectStoreUser {
 	if in == nil {
 		return nil
 	}
-	out := new(CephStatus)
+	out := new(CephObjectStoreUser)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectStoreUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephStorage) DeepCopyInto(out *CephStorage) {
+func (in *CephObjectStoreUserList) DeepCopyInto(out *CephObjectStoreUserList) {
 	*out = *in
-	if in.DeviceClasses != nil {
-		in, out := &in.DeviceClasses, &out.DeviceClasses
-		*out = make([]DeviceClasses, len(*in))
-		copy(*out, *in)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephObjectStoreUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephStorage.
-func (in *CephStorage) DeepCopy() *CephStorage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectStoreUserList.
+func (in *CephObjectStoreUserList) DeepCopy() *CephObjectStoreUserList {
 	if in == nil {
 		return nil
 	}
-	out := new(CephStorage)
+	out := new(CephObjectStoreUserList)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CephVersionSpec) DeepCopyInto(out *CephVersionSpec) {
-	*out = *in
-	return
-}
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectStoreUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephObjectZone) DeepCopyInto(out *CephObjectZone) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(ObjectZoneStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZone.
+func (in *CephObjectZone) DeepCopy() *CephObjectZone {
+	if in == nil {
+		return nil
+	}
+	out := new(CephObjectZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectZone) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephObjectZoneGroup) DeepCopyInto(out *CephObjectZoneGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(Status)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZoneGroup.
+func (in *CephObjectZoneGroup) DeepCopy() *CephObjectZoneGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(CephObjectZoneGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectZoneGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephObjectZoneGroupList) DeepCopyInto(out *CephObjectZoneGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephObjectZoneGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZoneGroupList.
+func (in *CephObjectZoneGroupList) DeepCopy() *CephObjectZoneGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephObjectZoneGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectZoneGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephObjectZoneList) DeepCopyInto(out *CephObjectZoneList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephObjectZone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephObjectZoneList.
+func (in *CephObjectZoneList) DeepCopy() *CephObjectZoneList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephObjectZoneList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephObjectZoneList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephRBDMirror) DeepCopyInto(out *CephRBDMirror) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(Status)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephRBDMirror.
+func (in *CephRBDMirror) DeepCopy() *CephRBDMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(CephRBDMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephRBDMirror) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephRBDMirrorList) DeepCopyInto(out *CephRBDMirrorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephRBDMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephRBDMirrorList.
+func (in *CephRBDMirrorList) DeepCopy() *CephRBDMirrorList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephRBDMirrorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephRBDMirrorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephStatus) DeepCopyInto(out *CephStatus) {
+	*out = *in
+	if in.Details != nil {
+		in, out := &in.Details, &out.Details
+		*out = make(map[string]CephHealthMessage, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(Capacity)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephStatus.
+func (in *CephStatus) DeepCopy() *CephStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephStorage) DeepCopyInto(out *CephStorage) {
+	*out = *in
+	if in.DeviceClasses != nil {
+		in, out := &in.DeviceClasses, &out.DeviceClasses
+		*out = make([]DeviceClasses, len(*in))
+		copy(*out, *in)
+	}
+	if in.OSDs != nil {
+		in, out := &in.OSDs, &out.OSDs
+		*out = make([]OSDStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephStorage.
+func (in *CephStorage) DeepCopy() *CephStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(CephStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephVersionSpec) DeepCopyInto(out *CephVersionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephVersionSpec.
+func (in *CephVersionSpec) DeepCopy() *CephVersionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CephVersionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupPolicySpec) DeepCopyInto(out *CleanupPolicySpec) {
+	*out = *in
+	out.SanitizeDisks = in.SanitizeDisks
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupPolicySpec.
+func (in *CleanupPolicySpec) DeepCopy() *CleanupPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientSpec) DeepCopyInto(out *ClientSpec) {
+	*out = *in
+	if in.Caps != nil {
+		in, out := &in.Caps, &out.Caps
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientSpec.
+func (in *ClientSpec) DeepCopy() *ClientSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
+	*out = *in
+	out.CephVersion = in.CephVersion
+	if in.DriveGroups != nil {
+		in, out := &in.DriveGroups, &out.DriveGroups
+		*out = make(DriveGroupsSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(rookiov1.AnnotationsSpec, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(rookiov1.Annotations, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(rookiov1.LabelsSpec, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(rookiov1.Labels, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = make(rookiov1.PlacementSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	in.Network.DeepCopyInto(&out.Network)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(rookiov1.ResourceSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.PriorityClassNames != nil {
+		in, out := &in.PriorityClassNames, &out.PriorityClassNames
+		*out = make(rookiov1.PriorityClassNamesSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.DisruptionManagement = in.DisruptionManagement
+	in.Mon.DeepCopyInto(&out.Mon)
+	out.CrashCollector = in.CrashCollector
+	out.Dashboard = in.Dashboard
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	out.External = in.External
+	in.Mgr.DeepCopyInto(&out.Mgr)
+	out.CleanupPolicy = in.CleanupPolicy
+	in.HealthCheck.DeepCopyInto(&out.HealthCheck)
+	in.Security.DeepCopyInto(&out.Security)
+	if in.CephConfig != nil {
+		in, out := &in.CephConfig, &out.CephConfig
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
+func (in *ClusterSpec) DeepCopy() *ClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CephStatus != nil {
+		in, out := &in.CephStatus, &out.CephStatus
+		*out = new(CephStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CephStorage != nil {
+		in, out := &in.CephStorage, &out.CephStorage
+		*out = new(CephStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CephVersion != nil {
+		in, out := &in.CephVersion, &out.CephVersion
+		*out = new(ClusterVersion)
+		**out = **in
+	}
+	if in.KeyRotation != nil {
+		in, out := &in.KeyRotation, &out.KeyRotation
+		*out = new(KeyRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
+func (in *ClusterStatus) DeepCopy() *ClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterVersion) DeepCopyInto(out *ClusterVersion) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterVersion.
+func (in *ClusterVersion) DeepCopy() *ClusterVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrashCollectorSpec) DeepCopyInto(out *CrashCollectorSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrashCollectorSpec.
+func (in *CrashCollectorSpec) DeepCopy() *CrashCollectorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CrashCollectorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonHealthSpec) DeepCopyInto(out *DaemonHealthSpec) {
+	*out = *in
+	out.Status = in.Status
+	out.Monitor = in.Monitor
+	out.ObjectStorageDaemon = in.ObjectStorageDaemon
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonHealthSpec.
+func (in *DaemonHealthSpec) DeepCopy() *DaemonHealthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonHealthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardSpec) DeepCopyInto(out *DashboardSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardSpec.
+func (in *DashboardSpec) DeepCopy() *DashboardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClassCapacity) DeepCopyInto(out *DeviceClassCapacity) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClassCapacity.
+func (in *DeviceClassCapacity) DeepCopy() *DeviceClassCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClassCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClasses) DeepCopyInto(out *DeviceClasses) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClasses.
+func (in *DeviceClasses) DeepCopy() *DeviceClasses {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClasses)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionManagementSpec) DeepCopyInto(out *DisruptionManagementSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionManagementSpec.
+func (in *DisruptionManagementSpec) DeepCopy() *DisruptionManagementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionManagementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriveGroup) DeepCopyInto(out *DriveGroup) {
+	*out = *in
+	out.Spec = in.Spec.DeepCopy()
+	in.Placement.DeepCopyInto(&out.Placement)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriveGroup.
+func (in *DriveGroup) DeepCopy() *DriveGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(DriveGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in DriveGroupSpec) DeepCopyInto(out *DriveGroupSpec) {
+	{
+		in := &in
+		*out = in.DeepCopy()
+		return
+	}
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in DriveGroupsSpec) DeepCopyInto(out *DriveGroupsSpec) {
+	{
+		in := &in
+		*out = make(DriveGroupsSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+		return
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriveGroupsSpec.
+func (in DriveGroupsSpec) DeepCopy() DriveGroupsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DriveGroupsSpec)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErasureCodedSpec) DeepCopyInto(out *ErasureCodedSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErasureCodedSpec.
+func (in *ErasureCodedSpec) DeepCopy() *ErasureCodedSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ErasureCodedSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSpec) DeepCopyInto(out *ExternalSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSpec.
+func (in *ExternalSpec) DeepCopy() *ExternalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FSMirroringSpec) DeepCopyInto(out *FSMirroringSpec) {
+	*out = *in
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = new(MirroringPeerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Directories != nil {
+		in, out := &in.Directories, &out.Directories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SnapshotSchedules != nil {
+		in, out := &in.SnapshotSchedules, &out.SnapshotSchedules
+		*out = make([]SnapshotScheduleSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.SnapshotRetention != nil {
+		in, out := &in.SnapshotRetention, &out.SnapshotRetention
+		*out = make([]SnapshotScheduleRetentionSpec, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FSMirroringSpec.
+func (in *FSMirroringSpec) DeepCopy() *FSMirroringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FSMirroringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemMirrorDirectoryStatus) DeepCopyInto(out *FilesystemMirrorDirectoryStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemMirrorDirectoryStatus.
+func (in *FilesystemMirrorDirectoryStatus) DeepCopy() *FilesystemMirrorDirectoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemMirrorDirectoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemMirrorPeerStatus) DeepCopyInto(out *FilesystemMirrorPeerStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemMirrorPeerStatus.
+func (in *FilesystemMirrorPeerStatus) DeepCopy() *FilesystemMirrorPeerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemMirrorPeerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemMirroringSpec) DeepCopyInto(out *FilesystemMirroringSpec) {
+	*out = *in
+	in.Placement.DeepCopyInto(&out.Placement)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(rookiov1.Annotations, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(rookiov1.Labels, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemMirroringSpec.
+func (in *FilesystemMirroringSpec) DeepCopy() *FilesystemMirroringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemMirroringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemMirroringStatus) DeepCopyInto(out *FilesystemMirroringStatus) {
+	*out = *in
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = make([]FilesystemMirrorPeerStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Directories != nil {
+		in, out := &in.Directories, &out.Directories
+		*out = make([]FilesystemMirrorDirectoryStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemMirroringStatus.
+func (in *FilesystemMirroringStatus) DeepCopy() *FilesystemMirroringStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemMirroringStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemSpec) DeepCopyInto(out *FilesystemSpec) {
+	*out = *in
+	in.MetadataPool.DeepCopyInto(&out.MetadataPool)
+	if in.DataPools != nil {
+		in, out := &in.DataPools, &out.DataPools
+		*out = make([]PoolSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.MetadataServer.DeepCopyInto(&out.MetadataServer)
+	if in.Mirroring != nil {
+		in, out := &in.Mirroring, &out.Mirroring
+		*out = new(FSMirroringSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	out.StatusCheck = in.StatusCheck
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemSpec.
+func (in *FilesystemSpec) DeepCopy() *FilesystemSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemSubVolumeGroupSpec) DeepCopyInto(out *FilesystemSubVolumeGroupSpec) {
+	*out = *in
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	in.Pinning.DeepCopyInto(&out.Pinning)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemSubVolumeGroupSpec.
+func (in *FilesystemSubVolumeGroupSpec) DeepCopy() *FilesystemSubVolumeGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemSubVolumeGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GaneshaRADOSSpec) DeepCopyInto(out *GaneshaRADOSSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GaneshaRADOSSpec.
+func (in *GaneshaRADOSSpec) DeepCopy() *GaneshaRADOSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GaneshaRADOSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GaneshaServerSpec) DeepCopyInto(out *GaneshaServerSpec) {
+	*out = *in
+	in.Placement.DeepCopyInto(&out.Placement)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(rookiov1.Annotations, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(rookiov1.Labels, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GaneshaServerSpec.
+func (in *GaneshaServerSpec) DeepCopy() *GaneshaServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GaneshaServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayGroupSpec) DeepCopyInto(out *GatewayGroupSpec) {
+	*out = *in
+	in.GatewaySpec.DeepCopyInto(&out.GatewaySpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayGroupSpec.
+func (in *GatewayGroupSpec) DeepCopy() *GatewayGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
+	*out = *in
+	in.Placement.DeepCopyInto(&out.Placement)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(rookiov1.Annotations, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(rookiov1.Labels, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ExternalRgwEndpoints != nil {
+		in, out := &in.ExternalRgwEndpoints, &out.ExternalRgwEndpoints
+		*out = make([]corev1.EndpointAddress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(rookiov1.Annotations, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySpec.
+func (in *GatewaySpec) DeepCopy() *GatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPEndpointSpec) DeepCopyInto(out *HTTPEndpointSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPEndpointSpec.
+func (in *HTTPEndpointSpec) DeepCopy() *HTTPEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheckSpec) DeepCopyInto(out *HealthCheckSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckSpec.
+func (in *HealthCheckSpec) DeepCopy() *HealthCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageMirroringStatusSpec) DeepCopyInto(out *ImageMirroringStatusSpec) {
+	*out = *in
+	if in.ReplicationLagSeconds != nil {
+		in, out := &in.ReplicationLagSeconds, &out.ReplicationLagSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMirroringStatusSpec.
+func (in *ImageMirroringStatusSpec) DeepCopy() *ImageMirroringStatusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageMirroringStatusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaEndpointSpec) DeepCopyInto(out *KafkaEndpointSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaEndpointSpec.
+func (in *KafkaEndpointSpec) DeepCopy() *KafkaEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyManagementServiceSpec) DeepCopyInto(out *KeyManagementServiceSpec) {
+	*out = *in
+	if in.ConnectionDetails != nil {
+		in, out := &in.ConnectionDetails, &out.ConnectionDetails
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyManagementServiceSpec.
+func (in *KeyManagementServiceSpec) DeepCopy() *KeyManagementServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyManagementServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyRotationSpec) DeepCopyInto(out *KeyRotationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyRotationSpec.
+func (in *KeyRotationSpec) DeepCopy() *KeyRotationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyRotationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyRotationStatus) DeepCopyInto(out *KeyRotationStatus) {
+	*out = *in
+	if in.OSDs != nil {
+		in, out := &in.OSDs, &out.OSDs
+		*out = make([]OSDKeyRotationStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyRotationStatus.
+func (in *KeyRotationStatus) DeepCopy() *KeyRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeystoneSpec) DeepCopyInto(out *KeystoneSpec) {
+	*out = *in
+	if in.AcceptedRoles != nil {
+		in, out := &in.AcceptedRoles, &out.AcceptedRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TokenCacheSize != nil {
+		in, out := &in.TokenCacheSize, &out.TokenCacheSize
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeystoneSpec.
+func (in *KeystoneSpec) DeepCopy() *KeystoneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeystoneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPSpec) DeepCopyInto(out *LDAPSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPSpec.
+func (in *LDAPSpec) DeepCopy() *LDAPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataServerSpec) DeepCopyInto(out *MetadataServerSpec) {
+	*out = *in
+	in.Placement.DeepCopyInto(&out.Placement)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(rookiov1.Annotations, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(rookiov1.Labels, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataServerSpec.
+func (in *MetadataServerSpec) DeepCopy() *MetadataServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MgrSpec) DeepCopyInto(out *MgrSpec) {
+	*out = *in
+	if in.Modules != nil {
+		in, out := &in.Modules, &out.Modules
+		*out = make([]Module, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MgrSpec.
+func (in *MgrSpec) DeepCopy() *MgrSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MgrSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirrorHealthCheckSpec) DeepCopyInto(out *MirrorHealthCheckSpec) {
+	*out = *in
+	out.Mirror = in.Mirror
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorHealthCheckSpec.
+func (in *MirrorHealthCheckSpec) DeepCopy() *MirrorHealthCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MirrorHealthCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirroringInfoSpec) DeepCopyInto(out *MirroringInfoSpec) {
+	*out = *in
+	out.Summary = in.Summary.DeepCopy()
+	return
+}
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephVersionSpec.
-func (in *CephVersionSpec) DeepCopy() *CephVersionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringInfoSpec.
+func (in *MirroringInfoSpec) DeepCopy() *MirroringInfoSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CephVersionSpec)
+	out := new(MirroringInfoSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CleanupPolicySpec) DeepCopyInto(out *CleanupPolicySpec) {
+func (in *MirroringPeerSpec) DeepCopyInto(out *MirroringPeerSpec) {
 	*out = *in
-	out.SanitizeDisks = in.SanitizeDisks
+	if in.SecretNames != nil {
+		in, out := &in.SecretNames, &out.SecretNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupPolicySpec.
-func (in *CleanupPolicySpec) DeepCopy() *CleanupPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringPeerSpec.
+func (in *MirroringPeerSpec) DeepCopy() *MirroringPeerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CleanupPolicySpec)
+	out := new(MirroringPeerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClientSpec) DeepCopyInto(out *ClientSpec) {
+func (in *MirroringSpec) DeepCopyInto(out *MirroringSpec) {
 	*out = *in
-	if in.Caps != nil {
-		in, out := &in.Caps, &out.Caps
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.SnapshotSchedules != nil {
+		in, out := &in.SnapshotSchedules, &out.SnapshotSchedules
+		*out = make([]SnapshotScheduleSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = new(MirroringPeerSpec)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientSpec.
-func (in *ClientSpec) DeepCopy() *ClientSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringSpec.
+func (in *MirroringSpec) DeepCopy() *MirroringSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClientSpec)
+	out := new(MirroringSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
+func (in *MirroringStatusSpec) DeepCopyInto(out *MirroringStatusSpec) {
 	*out = *in
-	out.CephVersion = in.CephVersion
-	if in.DriveGroups != nil {
-		in, out := &in.DriveGroups, &out.DriveGroups
-		*out = make(DriveGroupsSpec, len(*in))
+	out.Summary = in.Summary.DeepCopy()
+	if in.ImageStates != nil {
+		in, out := &in.ImageStates, &out.ImageStates
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FailedImages != nil {
+		in, out := &in.FailedImages, &out.FailedImages
+		*out = make([]ImageMirroringStatusSpec, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	in.Storage.DeepCopyInto(&out.Storage)
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(rookiov1.AnnotationsSpec, len(*in))
-		for key, val := range *in {
-			var outVal map[string]string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make(rookiov1.Annotations, len(*in))
-				for key, val := range *in {
-					(*out)[key] = val
-				}
-			}
-			(*out)[key] = outVal
-		}
+	if in.MaxReplicationLagSeconds != nil {
+		in, out := &in.MaxReplicationLagSeconds, &out.MaxReplicationLagSeconds
+		*out = new(int64)
+		**out = **in
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(rookiov1.LabelsSpec, len(*in))
-		for key, val := range *in {
-			var outVal map[string]string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make(rookiov1.Labels, len(*in))
-				for key, val := range *in {
-					(*out)[key] = val
-				}
-			}
-			(*out)[key] = outVal
-		}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringStatusSpec.
+func (in *MirroringStatusSpec) DeepCopy() *MirroringStatusSpec {
+	if in == nil {
+		return nil
 	}
-	if in.Placement != nil {
-		in, out := &in.Placement, &out.Placement
-		*out = make(rookiov1.PlacementSpec, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
+	out := new(MirroringStatusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Module) DeepCopyInto(out *Module) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Module.
+func (in *Module) DeepCopy() *Module {
+	if in == nil {
+		return nil
 	}
-	in.Network.DeepCopyInto(&out.Network)
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = make(rookiov1.ResourceSpec, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
+	out := new(Module)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonSpec) DeepCopyInto(out *MonSpec) {
+	*out = *in
+	if in.VolumeClaimTemplate != nil {
+		in, out := &in.VolumeClaimTemplate, &out.VolumeClaimTemplate
+		*out = new(corev1.PersistentVolumeClaim)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.PriorityClassNames != nil {
-		in, out := &in.PriorityClassNames, &out.PriorityClassNames
-		*out = make(rookiov1.PriorityClassNamesSpec, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.StretchCluster != nil {
+		in, out := &in.StretchCluster, &out.StretchCluster
+		*out = new(StretchClusterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]MonZoneSpec, len(*in))
+		copy(*out, *in)
 	}
-	out.DisruptionManagement = in.DisruptionManagement
-	in.Mon.DeepCopyInto(&out.Mon)
-	out.CrashCollector = in.CrashCollector
-	out.Dashboard = in.Dashboard
-	in.Monitoring.DeepCopyInto(&out.Monitoring)
-	out.External = in.External
-	in.Mgr.DeepCopyInto(&out.Mgr)
-	out.CleanupPolicy = in.CleanupPolicy
-	in.HealthCheck.DeepCopyInto(&out.HealthCheck)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
-func (in *ClusterSpec) DeepCopy() *ClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonSpec.
+func (in *MonSpec) DeepCopy() *MonSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterSpec)
+	out := new(MonSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
+func (in *MonZoneSpec) DeepCopyInto(out *MonZoneSpec) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonZoneSpec.
+func (in *MonZoneSpec) DeepCopy() *MonZoneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonZoneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+	*out = *in
+	if in.ExternalMgrEndpoints != nil {
+		in, out := &in.ExternalMgrEndpoints, &out.ExternalMgrEndpoints
+		*out = make([]corev1.EndpointAddress, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.CephStatus != nil {
-		in, out := &in.CephStatus, &out.CephStatus
-		*out = new(CephStatus)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.CephStorage != nil {
-		in, out := &in.CephStorage, &out.CephStorage
-		*out = new(CephStorage)
-		(*in).DeepCopyInto(*out)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
+func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
+	if in == nil {
+		return nil
 	}
-	if in.CephVersion != nil {
-		in, out := &in.CephVersion, &out.CephVersion
-		*out = new(ClusterVersion)
-		**out = **in
+	out := new(MonitoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NFSGaneshaSpec) DeepCopyInto(out *NFSGaneshaSpec) {
+	*out = *in
+	out.RADOS = in.RADOS
+	in.Server.DeepCopyInto(&out.Server)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NFSGaneshaSpec.
+func (in *NFSGaneshaSpec) DeepCopy() *NFSGaneshaSpec {
+	if in == nil {
+		return nil
 	}
+	out := new(NFSGaneshaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
+	*out = *in
+	in.NetworkSpec.DeepCopyInto(&out.NetworkSpec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
-func (in *ClusterStatus) DeepCopy() *ClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
+func (in *NetworkSpec) DeepCopy() *NetworkSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterStatus)
+	out := new(NetworkSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterVersion) DeepCopyInto(out *ClusterVersion) {
+func (in *NotificationFilterSpec) DeepCopyInto(out *NotificationFilterSpec) {
 	*out = *in
+	if in.KeyFilters != nil {
+		in, out := &in.KeyFilters, &out.KeyFilters
+		*out = make([]NotificationKeyFilterRule, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterVersion.
-func (in *ClusterVersion) DeepCopy() *ClusterVersion {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationFilterSpec.
+func (in *NotificationFilterSpec) DeepCopy() *NotificationFilterSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterVersion)
+	out := new(NotificationFilterSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Condition) DeepCopyInto(out *Condition) {
+func (in *NotificationKeyFilterRule) DeepCopyInto(out *NotificationKeyFilterRule) {
 	*out = *in
-	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
-func (in *Condition) DeepCopy() *Condition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationKeyFilterRule.
+func (in *NotificationKeyFilterRule) DeepCopy() *NotificationKeyFilterRule {
 	if in == nil {
 		return nil
 	}
-	out := new(Condition)
+	out := new(NotificationKeyFilterRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CrashCollectorSpec) DeepCopyInto(out *CrashCollectorSpec) {
+func (in *OSDKeyRotationStatus) DeepCopyInto(out *OSDKeyRotationStatus) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrashCollectorSpec.
-func (in *CrashCollectorSpec) DeepCopy() *CrashCollectorSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDKeyRotationStatus.
+func (in *OSDKeyRotationStatus) DeepCopy() *OSDKeyRotationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CrashCollectorSpec)
+	out := new(OSDKeyRotationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DaemonHealthSpec) DeepCopyInto(out *DaemonHealthSpec) {
+func (in *OSDRemovalSpec) DeepCopyInto(out *OSDRemovalSpec) {
 	*out = *in
-	out.Status = in.Status
-	out.Monitor = in.Monitor
-	out.ObjectStorageDaemon = in.ObjectStorageDaemon
+	if in.OSDIDs != nil {
+		in, out := &in.OSDIDs, &out.OSDIDs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.SanitizeDisks != nil {
+		in, out := &in.SanitizeDisks, &out.SanitizeDisks
+		*out = new(SanitizeDisksSpec)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonHealthSpec.
-func (in *DaemonHealthSpec) DeepCopy() *DaemonHealthSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDRemovalSpec.
+func (in *OSDRemovalSpec) DeepCopy() *OSDRemovalSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DaemonHealthSpec)
+	out := new(OSDRemovalSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DashboardSpec) DeepCopyInto(out *DashboardSpec) {
+func (in *OSDRemovalStatus) DeepCopyInto(out *OSDRemovalStatus) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardSpec.
-func (in *DashboardSpec) DeepCopy() *DashboardSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDRemovalStatus.
+func (in *OSDRemovalStatus) DeepCopy() *OSDRemovalStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DashboardSpec)
+	out := new(OSDRemovalStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DeviceClasses) DeepCopyInto(out *DeviceClasses) {
+func (in *OSDStatus) DeepCopyInto(out *OSDStatus) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClasses.
-func (in *DeviceClasses) DeepCopy() *DeviceClasses {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDStatus.
+func (in *OSDStatus) DeepCopy() *OSDStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DeviceClasses)
+	out := new(OSDStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DisruptionManagementSpec) DeepCopyInto(out *DisruptionManagementSpec) {
+func (in *ObjectDataSyncSource) DeepCopyInto(out *ObjectDataSyncSource) {
 	*out = *in
+	in.ObjectSyncProgress.DeepCopyInto(&out.ObjectSyncProgress)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionManagementSpec.
-func (in *DisruptionManagementSpec) DeepCopy() *DisruptionManagementSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectDataSyncSource.
+func (in *ObjectDataSyncSource) DeepCopy() *ObjectDataSyncSource {
 	if in == nil {
 		return nil
 	}
-	out := new(DisruptionManagementSpec)
+	out := new(ObjectDataSyncSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DriveGroup) DeepCopyInto(out *DriveGroup) {
+func (in *ObjectRealmSpec) DeepCopyInto(out *ObjectRealmSpec) {
 	*out = *in
-	out.Spec = in.Spec.DeepCopy()
-	in.Placement.DeepCopyInto(&out.Placement)
+	out.Pull = in.Pull
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriveGroup.
-func (in *DriveGroup) DeepCopy() *DriveGroup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRealmSpec.
+func (in *ObjectRealmSpec) DeepCopy() *ObjectRealmSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DriveGroup)
+	out := new(ObjectRealmSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in DriveGroupSpec) DeepCopyInto(out *DriveGroupSpec) {
-	{
-		in := &in
-		*out = in.DeepCopy()
-		return
+func (in *ObjectRealmStatus) DeepCopyInto(out *ObjectRealmStatus) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]ObjectRealmZoneStatus, len(*in))
+		copy(*out, *in)
 	}
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in DriveGroupsSpec) DeepCopyInto(out *DriveGroupsSpec) {
-	{
-		in := &in
-		*out = make(DriveGroupsSpec, len(*in))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
-		return
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriveGroupsSpec.
-func (in DriveGroupsSpec) DeepCopy() DriveGroupsSpec {
-	if in == nil {
-		return nil
 	}
-	out := new(DriveGroupsSpec)
-	in.DeepCopyInto(out)
-	return *out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ErasureCodedSpec) DeepCopyInto(out *ErasureCodedSpec) {
-	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErasureCodedSpec.
-func (in *ErasureCodedSpec) DeepCopy() *ErasureCodedSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRealmStatus.
+func (in *ObjectRealmStatus) DeepCopy() *ObjectRealmStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ErasureCodedSpec)
+	out := new(ObjectRealmStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExternalSpec) DeepCopyInto(out *ExternalSpec) {
+func (in *ObjectRealmZoneStatus) DeepCopyInto(out *ObjectRealmZoneStatus) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSpec.
-func (in *ExternalSpec) DeepCopy() *ExternalSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRealmZoneStatus.
+func (in *ObjectRealmZoneStatus) DeepCopy() *ObjectRealmZoneStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ExternalSpec)
+	out := new(ObjectRealmZoneStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FilesystemSpec) DeepCopyInto(out *FilesystemSpec) {
+func (in *ObjectRolePolicySpec) DeepCopyInto(out *ObjectRolePolicySpec) {
 	*out = *in
-	in.MetadataPool.DeepCopyInto(&out.MetadataPool)
-	if in.DataPools != nil {
-		in, out := &in.DataPools, &out.DataPools
-		*out = make([]PoolSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	in.MetadataServer.DeepCopyInto(&out.MetadataServer)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemSpec.
-func (in *FilesystemSpec) DeepCopy() *FilesystemSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRolePolicySpec.
+func (in *ObjectRolePolicySpec) DeepCopy() *ObjectRolePolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(FilesystemSpec)
+	out := new(ObjectRolePolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GaneshaRADOSSpec) DeepCopyInto(out *GaneshaRADOSSpec) {
+func (in *ObjectRoleServiceAccount) DeepCopyInto(out *ObjectRoleServiceAccount) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GaneshaRADOSSpec.
-func (in *GaneshaRADOSSpec) DeepCopy() *GaneshaRADOSSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRoleServiceAccount.
+func (in *ObjectRoleServiceAccount) DeepCopy() *ObjectRoleServiceAccount {
 	if in == nil {
 		return nil
 	}
-	out := new(GaneshaRADOSSpec)
+	out := new(ObjectRoleServiceAccount)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GaneshaServerSpec) DeepCopyInto(out *GaneshaServerSpec) {
+func (in *ObjectRoleSpec) DeepCopyInto(out *ObjectRoleSpec) {
 	*out = *in
-	in.Placement.DeepCopyInto(&out.Placement)
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(rookiov1.Annotations, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]ObjectRoleServiceAccount, len(*in))
+		copy(*out, *in)
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(rookiov1.Labels, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]ObjectRolePolicySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GaneshaServerSpec.
-func (in *GaneshaServerSpec) DeepCopy() *GaneshaServerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRoleSpec.
+func (in *ObjectRoleSpec) DeepCopy() *ObjectRoleSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GaneshaServerSpec)
+	out := new(ObjectRoleSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
+func (in *ObjectRoleStatus) DeepCopyInto(out *ObjectRoleStatus) {
 	*out = *in
-	in.Placement.DeepCopyInto(&out.Placement)
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(rookiov1.Annotations, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(rookiov1.Labels, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.ExternalRgwEndpoints != nil {
-		in, out := &in.ExternalRgwEndpoints, &out.ExternalRgwEndpoints
-		*out = make([]corev1.EndpointAddress, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySpec.
-func (in *GatewaySpec) DeepCopy() *GatewaySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRoleStatus.
+func (in *ObjectRoleStatus) DeepCopy() *ObjectRoleStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GatewaySpec)
+	out := new(ObjectRoleStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealthCheckSpec) DeepCopyInto(out *HealthCheckSpec) {
+func (in *ObjectStorageClassSpec) DeepCopyInto(out *ObjectStorageClassSpec) {
 	*out = *in
+	in.DataPool.DeepCopyInto(&out.DataPool)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckSpec.
-func (in *HealthCheckSpec) DeepCopy() *HealthCheckSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStorageClassSpec.
+func (in *ObjectStorageClassSpec) DeepCopy() *ObjectStorageClassSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HealthCheckSpec)
+	out := new(ObjectStorageClassSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MetadataServerSpec) DeepCopyInto(out *MetadataServerSpec) {
+func (in *ObjectStoreAuthSpec) DeepCopyInto(out *ObjectStoreAuthSpec) {
 	*out = *in
-	in.Placement.DeepCopyInto(&out.Placement)
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(rookiov1.Annotations, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Keystone != nil {
+		in, out := &in.Keystone, &out.Keystone
+		*out = new(KeystoneSpec)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(rookiov1.Labels, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAPSpec)
+		**out = **in
+	}
+	if in.STS != nil {
+		in, out := &in.STS, &out.STS
+		*out = new(STSSpec)
+		(*in).DeepCopyInto(*out)
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataServerSpec.
-func (in *MetadataServerSpec) DeepCopy() *MetadataServerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreAuthSpec.
+func (in *ObjectStoreAuthSpec) DeepCopy() *ObjectStoreAuthSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MetadataServerSpec)
+	out := new(ObjectStoreAuthSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MgrSpec) DeepCopyInto(out *MgrSpec) {
+func (in *ObjectStoreSpec) DeepCopyInto(out *ObjectStoreSpec) {
 	*out = *in
-	if in.Modules != nil {
-		in, out := &in.Modules, &out.Modules
-		*out = make([]Module, len(*in))
-		copy(*out, *in)
+	in.MetadataPool.DeepCopyInto(&out.MetadataPool)
+	in.DataPool.DeepCopyInto(&out.DataPool)
+	if in.StorageClasses != nil {
+		in, out := &in.StorageClasses, &out.StorageClasses
+		*out = make([]ObjectStorageClassSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PlacementTargets != nil {
+		in, out := &in.PlacementTargets, &out.PlacementTargets
+		*out = make([]PlacementTargetSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Gateway.DeepCopyInto(&out.Gateway)
+	if in.GatewayGroups != nil {
+		in, out := &in.GatewayGroups, &out.GatewayGroups
+		*out = make([]GatewayGroupSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Zone = in.Zone
+	in.HealthCheck.DeepCopyInto(&out.HealthCheck)
+	in.Auth.DeepCopyInto(&out.Auth)
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(ObjectStoreUsageSpec)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MgrSpec.
-func (in *MgrSpec) DeepCopy() *MgrSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreSpec.
+func (in *ObjectStoreSpec) DeepCopy() *ObjectStoreSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MgrSpec)
+	out := new(ObjectStoreSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MirrorHealthCheckSpec) DeepCopyInto(out *MirrorHealthCheckSpec) {
+func (in *ObjectStoreStatus) DeepCopyInto(out *ObjectStoreStatus) {
 	*out = *in
-	out.Mirror = in.Mirror
+	if in.BucketStatus != nil {
+		in, out := &in.BucketStatus, &out.BucketStatus
+		*out = new(BucketStatus)
+		**out = **in
+	}
+	if in.Info != nil {
+		in, out := &in.Info, &out.Info
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorHealthCheckSpec.
-func (in *MirrorHealthCheckSpec) DeepCopy() *MirrorHealthCheckSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreStatus.
+func (in *ObjectStoreStatus) DeepCopy() *ObjectStoreStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MirrorHealthCheckSpec)
+	out := new(ObjectStoreStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MirroringInfoSpec) DeepCopyInto(out *MirroringInfoSpec) {
+func (in *ObjectStoreUsageSpec) DeepCopyInto(out *ObjectStoreUsageSpec) {
 	*out = *in
-	out.Summary = in.Summary.DeepCopy()
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringInfoSpec.
-func (in *MirroringInfoSpec) DeepCopy() *MirroringInfoSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreUsageSpec.
+func (in *ObjectStoreUsageSpec) DeepCopy() *ObjectStoreUsageSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MirroringInfoSpec)
+	out := new(ObjectStoreUsageSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MirroringSpec) DeepCopyInto(out *MirroringSpec) {
+func (in *ObjectStoreUserSpec) DeepCopyInto(out *ObjectStoreUserSpec) {
 	*out = *in
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = new(ObjectUserQuotaSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(ObjectUserCapSpec)
+		**out = **in
+	}
+	if in.KeyRotation != nil {
+		in, out := &in.KeyRotation, &out.KeyRotation
+		*out = new(ObjectUserKeyRotationSpec)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringSpec.
-func (in *MirroringSpec) DeepCopy() *MirroringSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreUserSpec.
+func (in *ObjectStoreUserSpec) DeepCopy() *ObjectStoreUserSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MirroringSpec)
+	out := new(ObjectStoreUserSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MirroringStatusSpec) DeepCopyInto(out *MirroringStatusSpec) {
+func (in *ObjectStoreUserStatus) DeepCopyInto(out *ObjectStoreUserStatus) {
 	*out = *in
-	out.Summary = in.Summary.DeepCopy()
+	if in.Info != nil {
+		in, out := &in.Info, &out.Info
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.KeyRotation != nil {
+		in, out := &in.KeyRotation, &out.KeyRotation
+		*out = new(ObjectUserKeyRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(ObjectUsageStatus)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringStatusSpec.
-func (in *MirroringStatusSpec) DeepCopy() *MirroringStatusSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreUserStatus.
+func (in *ObjectStoreUserStatus) DeepCopy() *ObjectStoreUserStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MirroringStatusSpec)
+	out := new(ObjectStoreUserStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Module) DeepCopyInto(out *Module) {
+func (in *ObjectSyncProgress) DeepCopyInto(out *ObjectSyncProgress) {
 	*out = *in
+	if in.OldestChange != nil {
+		in, out := &in.OldestChange, &out.OldestChange
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Module.
-func (in *Module) DeepCopy() *Module {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSyncProgress.
+func (in *ObjectSyncProgress) DeepCopy() *ObjectSyncProgress {
 	if in == nil {
 		return nil
 	}
-	out := new(Module)
+	out := new(ObjectSyncProgress)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonSpec) DeepCopyInto(out *MonSpec) {
-	*out = *in
-	if in.VolumeClaimTemplate != nil {
-		in, out := &in.VolumeClaimTemplate, &out.VolumeClaimTemplate
-		*out = new(corev1.PersistentVolumeClaim)
-		(*in).DeepCopyInto(*out)
-	}
+func (in *ObjectUsageStatus) DeepCopyInto(out *ObjectUsageStatus) {
+	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonSpec.
-func (in *MonSpec) DeepCopy() *MonSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectUsageStatus.
+func (in *ObjectUsageStatus) DeepCopy() *ObjectUsageStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MonSpec)
+	out := new(ObjectUsageStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+func (in *ObjectUserCapSpec) DeepCopyInto(out *ObjectUserCapSpec) {
 	*out = *in
-	if in.ExternalMgrEndpoints != nil {
-		in, out := &in.ExternalMgrEndpoints, &out.ExternalMgrEndpoints
-		*out = make([]corev1.EndpointAddress, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
-func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectUserCapSpec.
+func (in *ObjectUserCapSpec) DeepCopy() *ObjectUserCapSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MonitoringSpec)
+	out := new(ObjectUserCapSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NFSGaneshaSpec) DeepCopyInto(out *NFSGaneshaSpec) {
+func (in *ObjectUserKeyRotationSpec) DeepCopyInto(out *ObjectUserKeyRotationSpec) {
 	*out = *in
-	out.RADOS = in.RADOS
-	in.Server.DeepCopyInto(&out.Server)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NFSGaneshaSpec.
-func (in *NFSGaneshaSpec) DeepCopy() *NFSGaneshaSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectUserKeyRotationSpec.
+func (in *ObjectUserKeyRotationSpec) DeepCopy() *ObjectUserKeyRotationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NFSGaneshaSpec)
+	out := new(ObjectUserKeyRotationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
+func (in *ObjectUserKeyRotationStatus) DeepCopyInto(out *ObjectUserKeyRotationStatus) {
 	*out = *in
-	in.NetworkSpec.DeepCopyInto(&out.NetworkSpec)
+	if in.RetiringKeys != nil {
+		in, out := &in.RetiringKeys, &out.RetiringKeys
+		*out = make([]RetiringObjectUserKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
-func (in *NetworkSpec) DeepCopy() *NetworkSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectUserKeyRotationStatus.
+func (in *ObjectUserKeyRotationStatus) DeepCopy() *ObjectUserKeyRotationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkSpec)
+	out := new(ObjectUserKeyRotationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectRealmSpec) DeepCopyInto(out *ObjectRealmSpec) {
+func (in *ObjectUserQuotaSpec) DeepCopyInto(out *ObjectUserQuotaSpec) {
 	*out = *in
-	out.Pull = in.Pull
+	if in.MaxBuckets != nil {
+		in, out := &in.MaxBuckets, &out.MaxBuckets
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxObjects != nil {
+		in, out := &in.MaxObjects, &out.MaxObjects
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRealmSpec.
-func (in *ObjectRealmSpec) DeepCopy() *ObjectRealmSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectUserQuotaSpec.
+func (in *ObjectUserQuotaSpec) DeepCopy() *ObjectUserQuotaSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectRealmSpec)
+	out := new(ObjectUserQuotaSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectStoreSpec) DeepCopyInto(out *ObjectStoreSpec) {
+func (in *ObjectZoneGroupSpec) DeepCopyInto(out *ObjectZoneGroupSpec) {
 	*out = *in
-	in.MetadataPool.DeepCopyInto(&out.MetadataPool)
-	in.DataPool.DeepCopyInto(&out.DataPool)
-	in.Gateway.DeepCopyInto(&out.Gateway)
-	out.Zone = in.Zone
-	in.HealthCheck.DeepCopyInto(&out.HealthCheck)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreSpec.
-func (in *ObjectStoreSpec) DeepCopy() *ObjectStoreSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectZoneGroupSpec.
+func (in *ObjectZoneGroupSpec) DeepCopy() *ObjectZoneGroupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectStoreSpec)
+	out := new(ObjectZoneGroupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectStoreStatus) DeepCopyInto(out *ObjectStoreStatus) {
+func (in *ObjectZoneSpec) DeepCopyInto(out *ObjectZoneSpec) {
 	*out = *in
-	if in.BucketStatus != nil {
-		in, out := &in.BucketStatus, &out.BucketStatus
-		*out = new(BucketStatus)
-		**out = **in
-	}
-	if in.Info != nil {
-		in, out := &in.Info, &out.Info
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	in.MetadataPool.DeepCopyInto(&out.MetadataPool)
+	in.DataPool.DeepCopyInto(&out.DataPool)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreStatus.
-func (in *ObjectStoreStatus) DeepCopy() *ObjectStoreStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectZoneSpec.
+func (in *ObjectZoneSpec) DeepCopy() *ObjectZoneSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectStoreStatus)
+	out := new(ObjectZoneSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectStoreUserSpec) DeepCopyInto(out *ObjectStoreUserSpec) {
+func (in *ObjectZoneStatus) DeepCopyInto(out *ObjectZoneStatus) {
 	*out = *in
+	if in.SyncStatus != nil {
+		in, out := &in.SyncStatus, &out.SyncStatus
+		*out = new(ObjectZoneSyncStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreUserSpec.
-func (in *ObjectStoreUserSpec) DeepCopy() *ObjectStoreUserSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectZoneStatus.
+func (in *ObjectZoneStatus) DeepCopy() *ObjectZoneStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectStoreUserSpec)
+	out := new(ObjectZoneStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectStoreUserStatus) DeepCopyInto(out *ObjectStoreUserStatus) {
+func (in *ObjectZoneSyncStatus) DeepCopyInto(out *ObjectZoneSyncStatus) {
 	*out = *in
-	if in.Info != nil {
-		in, out := &in.Info, &out.Info
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	in.LastChecked.DeepCopyInto(&out.LastChecked)
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	if in.DataSources != nil {
+		in, out := &in.DataSources, &out.DataSources
+		*out = make([]ObjectDataSyncSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreUserStatus.
-func (in *ObjectStoreUserStatus) DeepCopy() *ObjectStoreUserStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectZoneSyncStatus.
+func (in *ObjectZoneSyncStatus) DeepCopy() *ObjectZoneSyncStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectStoreUserStatus)
+	out := new(ObjectZoneSyncStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectZoneGroupSpec) DeepCopyInto(out *ObjectZoneGroupSpec) {
+func (in *PlacementTargetSpec) DeepCopyInto(out *PlacementTargetSpec) {
 	*out = *in
+	in.DataPool.DeepCopyInto(&out.DataPool)
+	if in.StorageClasses != nil {
+		in, out := &in.StorageClasses, &out.StorageClasses
+		*out = make([]ObjectStorageClassSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectZoneGroupSpec.
-func (in *ObjectZoneGroupSpec) DeepCopy() *ObjectZoneGroupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementTargetSpec.
+func (in *PlacementTargetSpec) DeepCopy() *PlacementTargetSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectZoneGroupSpec)
+	out := new(PlacementTargetSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectZoneSpec) DeepCopyInto(out *ObjectZoneSpec) {
+func (in *PoolCapacity) DeepCopyInto(out *PoolCapacity) {
 	*out = *in
-	in.MetadataPool.DeepCopyInto(&out.MetadataPool)
-	in.DataPool.DeepCopyInto(&out.DataPool)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectZoneSpec.
-func (in *ObjectZoneSpec) DeepCopy() *ObjectZoneSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolCapacity.
+func (in *PoolCapacity) DeepCopy() *PoolCapacity {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectZoneSpec)
+	out := new(PoolCapacity)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1772,8 +3420,9 @@ func (in *PoolSpec) DeepCopyInto(out *PoolSpec) {
 			(*out)[key] = val
 		}
 	}
-	out.Mirroring = in.Mirroring
+	in.Mirroring.DeepCopyInto(&out.Mirroring)
 	out.StatusCheck = in.StatusCheck
+	in.Quotas.DeepCopyInto(&out.Quotas)
 	return
 }
 
@@ -1803,6 +3452,32 @@ func (in *PullSpec) DeepCopy() *PullSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaSpec) DeepCopyInto(out *QuotaSpec) {
+	*out = *in
+	if in.MaxBytes != nil {
+		in, out := &in.MaxBytes, &out.MaxBytes
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxObjects != nil {
+		in, out := &in.MaxObjects, &out.MaxObjects
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaSpec.
+func (in *QuotaSpec) DeepCopy() *QuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RBDMirroringPeerSpec) DeepCopyInto(out *RBDMirroringPeerSpec) {
 	*out = *in
@@ -1873,6 +3548,49 @@ func (in *ReplicatedSpec) DeepCopy() *ReplicatedSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetiringObjectUserKey) DeepCopyInto(out *RetiringObjectUserKey) {
+	*out = *in
+	in.RetireTime.DeepCopyInto(&out.RetireTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetiringObjectUserKey.
+func (in *RetiringObjectUserKey) DeepCopy() *RetiringObjectUserKey {
+	if in == nil {
+		return nil
+	}
+	out := new(RetiringObjectUserKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *STSSpec) DeepCopyInto(out *STSSpec) {
+	*out = *in
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Thumbprints != nil {
+		in, out := &in.Thumbprints, &out.Thumbprints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new STSSpec.
+func (in *STSSpec) DeepCopy() *STSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(STSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SanitizeDisksSpec) DeepCopyInto(out *SanitizeDisksSpec) {
 	*out = *in
@@ -1889,6 +3607,56 @@ func (in *SanitizeDisksSpec) DeepCopy() *SanitizeDisksSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecuritySpec) DeepCopyInto(out *SecuritySpec) {
+	*out = *in
+	in.KeyManagementService.DeepCopyInto(&out.KeyManagementService)
+	out.KeyRotation = in.KeyRotation
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecuritySpec.
+func (in *SecuritySpec) DeepCopy() *SecuritySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecuritySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotScheduleRetentionSpec) DeepCopyInto(out *SnapshotScheduleRetentionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotScheduleRetentionSpec.
+func (in *SnapshotScheduleRetentionSpec) DeepCopy() *SnapshotScheduleRetentionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotScheduleRetentionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotScheduleSpec) DeepCopyInto(out *SnapshotScheduleSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotScheduleSpec.
+func (in *SnapshotScheduleSpec) DeepCopy() *SnapshotScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Status) DeepCopyInto(out *Status) {
 	*out = *in
@@ -1905,6 +3673,74 @@ func (in *Status) DeepCopy() *Status {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StretchClusterSpec) DeepCopyInto(out *StretchClusterSpec) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]StretchClusterZoneSpec, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StretchClusterSpec.
+func (in *StretchClusterSpec) DeepCopy() *StretchClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StretchClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StretchClusterZoneSpec) DeepCopyInto(out *StretchClusterZoneSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StretchClusterZoneSpec.
+func (in *StretchClusterZoneSpec) DeepCopy() *StretchClusterZoneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StretchClusterZoneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubVolumeGroupPinningSpec) DeepCopyInto(out *SubVolumeGroupPinningSpec) {
+	*out = *in
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(int)
+		**out = **in
+	}
+	if in.Distributed != nil {
+		in, out := &in.Distributed, &out.Distributed
+		*out = new(int)
+		**out = **in
+	}
+	if in.Random != nil {
+		in, out := &in.Random, &out.Random
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubVolumeGroupPinningSpec.
+func (in *SubVolumeGroupPinningSpec) DeepCopy() *SubVolumeGroupPinningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SubVolumeGroupPinningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in SummarySpec) DeepCopyInto(out *SummarySpec) {
 	{
@@ -1914,6 +3750,37 @@ func (in SummarySpec) DeepCopyInto(out *SummarySpec) {
 	}
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopicEndpointSpec) DeepCopyInto(out *TopicEndpointSpec) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPEndpointSpec)
+		**out = **in
+	}
+	if in.AMQP != nil {
+		in, out := &in.AMQP, &out.AMQP
+		*out = new(AMQPEndpointSpec)
+		**out = **in
+	}
+	if in.Kafka != nil {
+		in, out := &in.Kafka, &out.Kafka
+		*out = new(KafkaEndpointSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopicEndpointSpec.
+func (in *TopicEndpointSpec) DeepCopy() *TopicEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TopicEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ZoneSpec) DeepCopyInto(out *ZoneSpec) {
 	*out = *in
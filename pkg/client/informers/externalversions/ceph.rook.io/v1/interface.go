@@ -26,16 +26,28 @@ import (
 type Interface interface {
 	// CephBlockPools returns a CephBlockPoolInformer.
 	CephBlockPools() CephBlockPoolInformer
+	// CephBucketNotifications returns a CephBucketNotificationInformer.
+	CephBucketNotifications() CephBucketNotificationInformer
+	// CephBucketTopics returns a CephBucketTopicInformer.
+	CephBucketTopics() CephBucketTopicInformer
 	// CephClients returns a CephClientInformer.
 	CephClients() CephClientInformer
 	// CephClusters returns a CephClusterInformer.
 	CephClusters() CephClusterInformer
 	// CephFilesystems returns a CephFilesystemInformer.
 	CephFilesystems() CephFilesystemInformer
+	// CephFilesystemMirrors returns a CephFilesystemMirrorInformer.
+	CephFilesystemMirrors() CephFilesystemMirrorInformer
+	// CephFilesystemSubVolumeGroups returns a CephFilesystemSubVolumeGroupInformer.
+	CephFilesystemSubVolumeGroups() CephFilesystemSubVolumeGroupInformer
 	// CephNFSes returns a CephNFSInformer.
 	CephNFSes() CephNFSInformer
+	// CephOSDRemovals returns a CephOSDRemovalInformer.
+	CephOSDRemovals() CephOSDRemovalInformer
 	// CephObjectRealms returns a CephObjectRealmInformer.
 	CephObjectRealms() CephObjectRealmInformer
+	// CephObjectRoles returns a CephObjectRoleInformer.
+	CephObjectRoles() CephObjectRoleInformer
 	// CephObjectStores returns a CephObjectStoreInformer.
 	CephObjectStores() CephObjectStoreInformer
 	// CephObjectStoreUsers returns a CephObjectStoreUserInformer.
@@ -64,6 +76,16 @@ func (v *version) CephBlockPools() CephBlockPoolInformer {
 	return &cephBlockPoolInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CephBucketNotifications returns a CephBucketNotificationInformer.
+func (v *version) CephBucketNotifications() CephBucketNotificationInformer {
+	return &cephBucketNotificationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// CephBucketTopics returns a CephBucketTopicInformer.
+func (v *version) CephBucketTopics() CephBucketTopicInformer {
+	return &cephBucketTopicInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CephClients returns a CephClientInformer.
 func (v *version) CephClients() CephClientInformer {
 	return &cephClientInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -79,16 +101,36 @@ func (v *version) CephFilesystems() CephFilesystemInformer {
 	return &cephFilesystemInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CephFilesystemMirrors returns a CephFilesystemMirrorInformer.
+func (v *version) CephFilesystemMirrors() CephFilesystemMirrorInformer {
+	return &cephFilesystemMirrorInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// CephFilesystemSubVolumeGroups returns a CephFilesystemSubVolumeGroupInformer.
+func (v *version) CephFilesystemSubVolumeGroups() CephFilesystemSubVolumeGroupInformer {
+	return &cephFilesystemSubVolumeGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CephNFSes returns a CephNFSInformer.
 func (v *version) CephNFSes() CephNFSInformer {
 	return &cephNFSInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CephOSDRemovals returns a CephOSDRemovalInformer.
+func (v *version) CephOSDRemovals() CephOSDRemovalInformer {
+	return &cephOSDRemovalInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CephObjectRealms returns a CephObjectRealmInformer.
 func (v *version) CephObjectRealms() CephObjectRealmInformer {
 	return &cephObjectRealmInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CephObjectRoles returns a CephObjectRoleInformer.
+func (v *version) CephObjectRoles() CephObjectRoleInformer {
+	return &cephObjectRoleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CephObjectStores returns a CephObjectStoreInformer.
 func (v *version) CephObjectStores() CephObjectStoreInformer {
 	return &cephObjectStoreInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
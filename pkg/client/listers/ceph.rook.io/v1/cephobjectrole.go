@@ -0,0 +1,94 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CephObjectRoleLister helps list CephObjectRoles.
+type CephObjectRoleLister interface {
+	// List lists all CephObjectRoles in the indexer.
+	List(selector labels.Selector) (ret []*v1.CephObjectRole, err error)
+	// CephObjectRoles returns an object that can list and get CephObjectRoles.
+	CephObjectRoles(namespace string) CephObjectRoleNamespaceLister
+	CephObjectRoleListerExpansion
+}
+
+// cephObjectRoleLister implements the CephObjectRoleLister interface.
+type cephObjectRoleLister struct {
+	indexer cache.Indexer
+}
+
+// NewCephObjectRoleLister returns a new CephObjectRoleLister.
+func NewCephObjectRoleLister(indexer cache.Indexer) CephObjectRoleLister {
+	return &cephObjectRoleLister{indexer: indexer}
+}
+
+// List lists all CephObjectRoles in the indexer.
+func (s *cephObjectRoleLister) List(selector labels.Selector) (ret []*v1.CephObjectRole, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CephObjectRole))
+	})
+	return ret, err
+}
+
+// CephObjectRoles returns an object that can list and get CephObjectRoles.
+func (s *cephObjectRoleLister) CephObjectRoles(namespace string) CephObjectRoleNamespaceLister {
+	return cephObjectRoleNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// CephObjectRoleNamespaceLister helps list and get CephObjectRoles.
+type CephObjectRoleNamespaceLister interface {
+	// List lists all CephObjectRoles in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1.CephObjectRole, err error)
+	// Get retrieves the CephObjectRole from the indexer for a given namespace and name.
+	Get(name string) (*v1.CephObjectRole, error)
+	CephObjectRoleNamespaceListerExpansion
+}
+
+// cephObjectRoleNamespaceLister implements the CephObjectRoleNamespaceLister
+// interface.
+type cephObjectRoleNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all CephObjectRoles in the indexer for a given namespace.
+func (s cephObjectRoleNamespaceLister) List(selector labels.Selector) (ret []*v1.CephObjectRole, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CephObjectRole))
+	})
+	return ret, err
+}
+
+// Get retrieves the CephObjectRole from the indexer for a given namespace and name.
+func (s cephObjectRoleNamespaceLister) Get(name string) (*v1.CephObjectRole, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("cephobjectrole"), name)
+	}
+	return obj.(*v1.CephObjectRole), nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CephOSDRemovalLister helps list CephOSDRemovals.
+type CephOSDRemovalLister interface {
+	// List lists all CephOSDRemovals in the indexer.
+	List(selector labels.Selector) (ret []*v1.CephOSDRemoval, err error)
+	// CephOSDRemovals returns an object that can list and get CephOSDRemovals.
+	CephOSDRemovals(namespace string) CephOSDRemovalNamespaceLister
+	CephOSDRemovalListerExpansion
+}
+
+// cephOSDRemovalLister implements the CephOSDRemovalLister interface.
+type cephOSDRemovalLister struct {
+	indexer cache.Indexer
+}
+
+// NewCephOSDRemovalLister returns a new CephOSDRemovalLister.
+func NewCephOSDRemovalLister(indexer cache.Indexer) CephOSDRemovalLister {
+	return &cephOSDRemovalLister{indexer: indexer}
+}
+
+// List lists all CephOSDRemovals in the indexer.
+func (s *cephOSDRemovalLister) List(selector labels.Selector) (ret []*v1.CephOSDRemoval, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CephOSDRemoval))
+	})
+	return ret, err
+}
+
+// CephOSDRemovals returns an object that can list and get CephOSDRemovals.
+func (s *cephOSDRemovalLister) CephOSDRemovals(namespace string) CephOSDRemovalNamespaceLister {
+	return cephOSDRemovalNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// CephOSDRemovalNamespaceLister helps list and get CephOSDRemovals.
+type CephOSDRemovalNamespaceLister interface {
+	// List lists all CephOSDRemovals in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1.CephOSDRemoval, err error)
+	// Get retrieves the CephOSDRemoval from the indexer for a given namespace and name.
+	Get(name string) (*v1.CephOSDRemoval, error)
+	CephOSDRemovalNamespaceListerExpansion
+}
+
+// cephOSDRemovalNamespaceLister implements the CephOSDRemovalNamespaceLister
+// interface.
+type cephOSDRemovalNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all CephOSDRemovals in the indexer for a given namespace.
+func (s cephOSDRemovalNamespaceLister) List(selector labels.Selector) (ret []*v1.CephOSDRemoval, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CephOSDRemoval))
+	})
+	return ret, err
+}
+
+// Get retrieves the CephOSDRemoval from the indexer for a given namespace and name.
+func (s cephOSDRemovalNamespaceLister) Get(name string) (*v1.CephOSDRemoval, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("cephosdremoval"), name)
+	}
+	return obj.(*v1.CephOSDRemoval), nil
+}
@@ -26,6 +26,22 @@ type CephBlockPoolListerExpansion interface{}
 // CephBlockPoolNamespaceLister.
 type CephBlockPoolNamespaceListerExpansion interface{}
 
+// CephBucketNotificationListerExpansion allows custom methods to be added to
+// CephBucketNotificationLister.
+type CephBucketNotificationListerExpansion interface{}
+
+// CephBucketNotificationNamespaceListerExpansion allows custom methods to be added to
+// CephBucketNotificationNamespaceLister.
+type CephBucketNotificationNamespaceListerExpansion interface{}
+
+// CephBucketTopicListerExpansion allows custom methods to be added to
+// CephBucketTopicLister.
+type CephBucketTopicListerExpansion interface{}
+
+// CephBucketTopicNamespaceListerExpansion allows custom methods to be added to
+// CephBucketTopicNamespaceLister.
+type CephBucketTopicNamespaceListerExpansion interface{}
+
 // CephClientListerExpansion allows custom methods to be added to
 // CephClientLister.
 type CephClientListerExpansion interface{}
@@ -50,6 +66,22 @@ type CephFilesystemListerExpansion interface{}
 // CephFilesystemNamespaceLister.
 type CephFilesystemNamespaceListerExpansion interface{}
 
+// CephFilesystemMirrorListerExpansion allows custom methods to be added to
+// CephFilesystemMirrorLister.
+type CephFilesystemMirrorListerExpansion interface{}
+
+// CephFilesystemMirrorNamespaceListerExpansion allows custom methods to be added to
+// CephFilesystemMirrorNamespaceLister.
+type CephFilesystemMirrorNamespaceListerExpansion interface{}
+
+// CephFilesystemSubVolumeGroupListerExpansion allows custom methods to be added to
+// CephFilesystemSubVolumeGroupLister.
+type CephFilesystemSubVolumeGroupListerExpansion interface{}
+
+// CephFilesystemSubVolumeGroupNamespaceListerExpansion allows custom methods to be added to
+// CephFilesystemSubVolumeGroupNamespaceLister.
+type CephFilesystemSubVolumeGroupNamespaceListerExpansion interface{}
+
 // CephNFSListerExpansion allows custom methods to be added to
 // CephNFSLister.
 type CephNFSListerExpansion interface{}
@@ -58,6 +90,14 @@ type CephNFSListerExpansion interface{}
 // CephNFSNamespaceLister.
 type CephNFSNamespaceListerExpansion interface{}
 
+// CephOSDRemovalListerExpansion allows custom methods to be added to
+// CephOSDRemovalLister.
+type CephOSDRemovalListerExpansion interface{}
+
+// CephOSDRemovalNamespaceListerExpansion allows custom methods to be added to
+// CephOSDRemovalNamespaceLister.
+type CephOSDRemovalNamespaceListerExpansion interface{}
+
 // CephObjectRealmListerExpansion allows custom methods to be added to
 // CephObjectRealmLister.
 type CephObjectRealmListerExpansion interface{}
@@ -66,6 +106,14 @@ type CephObjectRealmListerExpansion interface{}
 // CephObjectRealmNamespaceLister.
 type CephObjectRealmNamespaceListerExpansion interface{}
 
+// CephObjectRoleListerExpansion allows custom methods to be added to
+// CephObjectRoleLister.
+type CephObjectRoleListerExpansion interface{}
+
+// CephObjectRoleNamespaceListerExpansion allows custom methods to be added to
+// CephObjectRoleNamespaceLister.
+type CephObjectRoleNamespaceListerExpansion interface{}
+
 // CephObjectStoreListerExpansion allows custom methods to be added to
 // CephObjectStoreLister.
 type CephObjectStoreListerExpansion interface{}
@@ -57,13 +57,27 @@ func NewDiskSanitizer(context *clusterd.Context, clusterInfo *client.ClusterInfo
 
 // StartSanitizeDisks main entrypoint of the cleanup package
 func (s *DiskSanitizer) StartSanitizeDisks() {
+	s.sanitizeOSDDisks(nil)
+}
+
+// StartSanitizeOSDDisks wipes the disks of the given OSDs only, e.g. after the OSDs are removed
+func (s *DiskSanitizer) StartSanitizeOSDDisks(osdIDs []int) {
+	if len(osdIDs) == 0 {
+		logger.Info("no osd to sanitize")
+		return
+	}
+	s.sanitizeOSDDisks(osdIDs)
+}
+
+// sanitizeOSDDisks wipes the disks of the OSDs, or all the OSDs of the cluster if no OSD is given
+func (s *DiskSanitizer) sanitizeOSDDisks(osdIDs []int) {
 	// LVM based OSDs
 	osdLVMList, err := osd.GetCephVolumeLVMOSDs(s.context, s.clusterInfo, s.clusterInfo.FSID, "", false, false)
 	if err != nil {
 		logger.Errorf("failed to list lvm osd(s). %v", err)
 	} else {
 		// Start the sanitizing sequence
-		s.sanitizeLVMDisk(osdLVMList)
+		s.sanitizeLVMDisk(filterOSDs(osdLVMList, osdIDs))
 	}
 
 	// Raw based OSDs
@@ -72,8 +86,26 @@ func (s *DiskSanitizer) StartSanitizeDisks() {
 		logger.Errorf("failed to list raw osd(s). %v", err)
 	} else {
 		// Start the sanitizing sequence
-		s.sanitizeRawDisk(osdRawList)
+		s.sanitizeRawDisk(filterOSDs(osdRawList, osdIDs))
+	}
+}
+
+// filterOSDs returns the OSDs with the given IDs, or all the OSDs if no ID is given
+func filterOSDs(osds []oposd.OSDInfo, osdIDs []int) []oposd.OSDInfo {
+	if len(osdIDs) == 0 {
+		return osds
+	}
+
+	filtered := []oposd.OSDInfo{}
+	for _, osd := range osds {
+		for _, id := range osdIDs {
+			if osd.ID == id {
+				filtered = append(filtered, osd)
+				break
+			}
+		}
 	}
+	return filtered
 }
 
 func (s *DiskSanitizer) sanitizeRawDisk(osdRawList []oposd.OSDInfo) {
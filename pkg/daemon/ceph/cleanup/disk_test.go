@@ -23,6 +23,7 @@ import (
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
+	oposd "github.com/rook/rook/pkg/operator/ceph/cluster/osd"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -66,3 +67,11 @@ func TestBuildShredArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterOSDs(t *testing.T) {
+	osds := []oposd.OSDInfo{{ID: 0}, {ID: 1}, {ID: 2}}
+
+	assert.Equal(t, osds, filterOSDs(osds, nil))
+	assert.Equal(t, []oposd.OSDInfo{{ID: 0}, {ID: 2}}, filterOSDs(osds, []int{2, 0}))
+	assert.Equal(t, 0, len(filterOSDs(osds, []int{3})))
+}
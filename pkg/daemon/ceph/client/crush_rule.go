@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+const (
+	// StretchCrushRuleName is the name of the CRUSH rule of a stretch cluster
+	StretchCrushRuleName = "default_stretch_cluster_rule"
+
+	// two replicas are placed in each of the two data zones
+	stretchCrushRuleTemplate = `
+rule %s {
+	id %d
+	type replicated
+	min_size 1
+	max_size 10
+	step take %s
+	step choose firstn 0 type %s
+	step chooseleaf firstn 2 type %s
+	step emit
+}
+`
+)
+
+// CreateStretchCrushRule creates the CRUSH rule of a stretch cluster, which places two replicas in each zone
+func CreateStretchCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, ruleName, crushRoot, failureDomain, subFailureDomain string) error {
+	crushMap, err := GetCrushMap(context, clusterInfo)
+	if err != nil {
+		return err
+	}
+	ruleID := 0
+	for _, rule := range crushMap.Rules {
+		if rule.Name == ruleName {
+			logger.Debugf("crush rule %q already exists", ruleName)
+			return nil
+		}
+		if rule.ID >= ruleID {
+			ruleID = rule.ID + 1
+		}
+	}
+
+	// Such a rule cannot be created with the "osd crush rule" commands, it is added to the decompiled CRUSH map
+	dir, err := ioutil.TempDir("", "crushmap")
+	if err != nil {
+		return errors.Wrap(err, "failed to create crush map directory")
+	}
+	defer os.RemoveAll(dir)
+	compiledMap := path.Join(dir, "crushmap")
+	decompiledMap := path.Join(dir, "crushmap.txt")
+
+	cmd := NewCephCommand(context, clusterInfo, []string{"osd", "getcrushmap"})
+	cmd.JsonOutput = false
+	buf, err := cmd.Run()
+	if err != nil {
+		return errors.Wrap(err, "failed to get crush map")
+	}
+	if err := ioutil.WriteFile(compiledMap, buf, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write crush map to %q", compiledMap)
+	}
+
+	output, err := context.Executor.ExecuteCommandWithOutput(CrushTool, "-d", compiledMap, "-o", decompiledMap)
+	if err != nil {
+		return errors.Wrapf(err, "failed to decompile crush map. %s", output)
+	}
+	decompiled, err := ioutil.ReadFile(decompiledMap)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read decompiled crush map %q", decompiledMap)
+	}
+
+	rule := fmt.Sprintf(stretchCrushRuleTemplate, ruleName, ruleID, crushRoot, failureDomain, subFailureDomain)
+	if err := ioutil.WriteFile(decompiledMap, append(decompiled, []byte(rule)...), 0600); err != nil {
+		return errors.Wrapf(err, "failed to write decompiled crush map to %q", decompiledMap)
+	}
+
+	output, err = context.Executor.ExecuteCommandWithOutput(CrushTool, "-c", decompiledMap, "-o", compiledMap)
+	if err != nil {
+		return errors.Wrapf(err, "failed to compile crush map. %s", output)
+	}
+
+	logger.Infof("creating crush rule %q", ruleName)
+	buf, err = NewCephCommand(context, clusterInfo, []string{"osd", "setcrushmap", "-i", compiledMap}).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set crush map with rule %q. %s", ruleName, string(buf))
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateStretchCrushRule(t *testing.T) {
+	setCrushMap := false
+	decompiled := ""
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "osd" {
+			if args[1] == "crush" && args[2] == "dump" {
+				return testCrushMap, nil
+			}
+			if args[1] == "getcrushmap" {
+				return "compiled", nil
+			}
+			if args[1] == "setcrushmap" {
+				setCrushMap = true
+				return "", nil
+			}
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if command != CrushTool {
+			return "", errors.Errorf("unexpected command %q", command)
+		}
+		if args[0] == "-d" {
+			return "", ioutil.WriteFile(args[3], []byte("# begin crush map\n"), 0600)
+		}
+		if args[0] == "-c" {
+			buf, err := ioutil.ReadFile(args[1])
+			decompiled = string(buf)
+			return "", err
+		}
+		return "", errors.Errorf("unexpected crushtool args %q", args)
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	// an existing rule is not created again
+	err := CreateStretchCrushRule(context, AdminClusterInfo("mycluster"), "replicated_ruleset", "default", "zone", "host")
+	assert.NoError(t, err)
+	assert.False(t, setCrushMap)
+
+	err = CreateStretchCrushRule(context, AdminClusterInfo("mycluster"), StretchCrushRuleName, "default", "zone", "host")
+	assert.NoError(t, err)
+	assert.True(t, setCrushMap)
+	assert.True(t, strings.HasPrefix(decompiled, "# begin crush map\n"))
+	assert.Contains(t, decompiled, "rule default_stretch_cluster_rule {")
+	assert.Contains(t, decompiled, "id 2\n")
+	assert.Contains(t, decompiled, "step take default\n")
+	assert.Contains(t, decompiled, "step choose firstn 0 type zone\n")
+	assert.Contains(t, decompiled, "step chooseleaf firstn 2 type host\n")
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+const (
+	// FilesystemMirrorPeerUser is the user the remote peers use to connect to the filesystem
+	FilesystemMirrorPeerUser = "client.mirror_remote"
+)
+
+// FilesystemMirrorBootstrapToken is the bootstrap token returned by 'ceph fs snapshot mirror peer_bootstrap create'
+type FilesystemMirrorBootstrapToken struct {
+	Token string `json:"token"`
+}
+
+var retentionRegexp = regexp.MustCompile(`\d+[a-zA-Z]`)
+
+// FilesystemMirrorInfo is the mirroring configuration returned by 'ceph fs get' for a mirrored filesystem
+type FilesystemMirrorInfo struct {
+	Peers []FilesystemMirrorPeer `json:"peers"`
+}
+
+// FilesystemMirrorDaemonStatus is the status of a cephfs-mirror daemon returned by 'ceph fs snapshot mirror daemon status'
+type FilesystemMirrorDaemonStatus struct {
+	DaemonID    int                                `json:"daemon_id"`
+	Filesystems []FilesystemMirrorDaemonFilesystem `json:"filesystems"`
+}
+
+// FilesystemMirrorDaemonFilesystem is a filesystem synced by a cephfs-mirror daemon
+type FilesystemMirrorDaemonFilesystem struct {
+	FilesystemID   int                    `json:"filesystem_id"`
+	Name           string                 `json:"name"`
+	DirectoryCount int                    `json:"directory_count"`
+	Peers          []FilesystemMirrorPeer `json:"peers"`
+}
+
+// FilesystemMirrorPeer is a peer a filesystem is mirrored to
+type FilesystemMirrorPeer struct {
+	UUID   string `json:"uuid"`
+	Remote struct {
+		ClientName  string `json:"client_name"`
+		ClusterName string `json:"cluster_name"`
+		FSName      string `json:"fs_name"`
+	} `json:"remote"`
+	Stats struct {
+		FailureCount  int `json:"failure_count"`
+		RecoveryCount int `json:"recovery_count"`
+	} `json:"stats"`
+}
+
+// FilesystemSnapshotSchedule is a snapshot schedule listed by 'ceph fs snap-schedule list'
+type FilesystemSnapshotSchedule struct {
+	Path     string
+	Interval string
+	// Retention is the retention of the path, e.g. ["24h", "7d"]
+	Retention []string
+}
+
+// EnableFilesystemSnapshotMirror enables snapshot mirroring on a filesystem
+func EnableFilesystemSnapshotMirror(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string) error {
+	logger.Infof("enabling snapshot mirroring for filesystem %q", fsName)
+
+	// Build command
+	args := []string{"fs", "snapshot", "mirror", "enable", fsName}
+	cmd := NewCephCommand(context, clusterInfo, args)
+
+	// Run command
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable snapshot mirroring for filesystem %q. %s", fsName, output)
+	}
+
+	return nil
+}
+
+// DisableFilesystemSnapshotMirror disables snapshot mirroring on a filesystem
+func DisableFilesystemSnapshotMirror(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string) error {
+	logger.Infof("disabling snapshot mirroring for filesystem %q", fsName)
+
+	// Build command
+	args := []string{"fs", "snapshot", "mirror", "disable", fsName}
+	cmd := NewCephCommand(context, clusterInfo, args)
+
+	// Run command
+	output, err := cmd.Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.EINVAL) {
+			logger.Debugf("snapshot mirroring is already disabled for filesystem %q", fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to disable snapshot mirroring for filesystem %q. %s", fsName, output)
+	}
+
+	return nil
+}
+
+// ImportFilesystemMirrorBootstrapPeer add a mirror peer in the cephfs-mirror configuration
+func ImportFilesystemMirrorBootstrapPeer(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string, token []byte) error {
+	logger.Infof("add cephfs-mirror bootstrap peer token for filesystem %q", fsName)
+
+	// Build command
+	args := []string{"fs", "snapshot", "mirror", "peer_bootstrap", "import", fsName, string(token)}
+	cmd := NewCephCommand(context, clusterInfo, args)
+
+	// Run command
+	output, err := cmd.Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.EEXIST) {
+			logger.Debugf("cephfs-mirror peer already added to filesystem %q", fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to add cephfs-mirror peer token for filesystem %q. %s", fsName, output)
+	}
+
+	return nil
+}
+
+// CreateFilesystemMirrorBootstrapPeer creates a bootstrap token remote clusters can import to add this filesystem as a peer
+func CreateFilesystemMirrorBootstrapPeer(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string) ([]byte, error) {
+	logger.Infof("create cephfs-mirror bootstrap peer token for filesystem %q", fsName)
+
+	// The remote peers connect with this user
+	args := []string{"fs", "authorize", fsName, FilesystemMirrorPeerUser, "/", "rwps"}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to authorize cephfs-mirror peer user for filesystem %q. %s", fsName, output)
+	}
+
+	// Build command
+	args = []string{"fs", "snapshot", "mirror", "peer_bootstrap", "create", fsName, FilesystemMirrorPeerUser, fmt.Sprintf("%s-%s", clusterInfo.FSID, clusterInfo.Namespace)}
+	cmd := NewCephCommand(context, clusterInfo, args)
+
+	// Run command
+	output, err = cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create cephfs-mirror peer token for filesystem %q. %s", fsName, output)
+	}
+
+	var token FilesystemMirrorBootstrapToken
+	if err := json.Unmarshal(output, &token); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal cephfs-mirror peer token. %s", string(output))
+	}
+
+	return []byte(token.Token), nil
+}
+
+// AddFilesystemMirrorDirectory configures a directory of the filesystem to be mirrored to the peers
+func AddFilesystemMirrorDirectory(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path string) error {
+	logger.Infof("adding directory %q of filesystem %q to snapshot mirroring", path, fsName)
+	args := []string{"fs", "snapshot", "mirror", "add", fsName, path}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.EEXIST) {
+			logger.Debugf("directory %q of filesystem %q is already mirrored", path, fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to add directory %q of filesystem %q to snapshot mirroring. %s", path, fsName, output)
+	}
+
+	return nil
+}
+
+// GetFilesystemMirrorDaemonStatus returns the status of the cephfs-mirror daemons, with the peers of each filesystem they sync
+func GetFilesystemMirrorDaemonStatus(context *clusterd.Context, clusterInfo *ClusterInfo) ([]FilesystemMirrorDaemonStatus, error) {
+	args := []string{"fs", "snapshot", "mirror", "daemon", "status"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cephfs-mirror daemon status")
+	}
+
+	var status []FilesystemMirrorDaemonStatus
+	if err := json.Unmarshal(buf, &status); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal cephfs-mirror daemon status. %s", string(buf))
+	}
+
+	return status, nil
+}
+
+// AddFilesystemSnapshotSchedule schedules periodic snapshots of a path of the filesystem
+func AddFilesystemSnapshotSchedule(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path, interval, startTime string) error {
+	logger.Infof("adding snapshot schedule %q on path %q of filesystem %q", interval, path, fsName)
+	args := []string{"fs", "snap-schedule", "add", path, interval}
+	if startTime != "" {
+		args = append(args, startTime)
+	}
+	args = append(args, "--fs", fsName)
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.EEXIST) {
+			logger.Debugf("snapshot schedule %q on path %q of filesystem %q already exists", interval, path, fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to add snapshot schedule %q on path %q of filesystem %q. %s", interval, path, fsName, output)
+	}
+
+	return nil
+}
+
+// AddFilesystemSnapshotRetention sets the retention policy of the scheduled snapshots of a path of the filesystem
+func AddFilesystemSnapshotRetention(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path, duration string) error {
+	logger.Infof("adding snapshot retention %q on path %q of filesystem %q", duration, path, fsName)
+	args := []string{"fs", "snap-schedule", "retention", "add", path, duration, "--fs", fsName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to add snapshot retention %q on path %q of filesystem %q. %s", duration, path, fsName, output)
+	}
+
+	return nil
+}
+
+// ListFilesystemSnapshotSchedules returns the snapshot schedules of all the paths of the filesystem
+func ListFilesystemSnapshotSchedules(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string) ([]FilesystemSnapshotSchedule, error) {
+	args := []string{"fs", "snap-schedule", "list", "/", "--recursive", "--fs", fsName}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	// The json output does not tell the path of each schedule
+	cmd.JsonOutput = false
+	buf, err := cmd.Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to list snapshot schedules of filesystem %q. %s", fsName, string(buf))
+	}
+
+	return parseFilesystemSnapshotSchedules(string(buf)), nil
+}
+
+// parseFilesystemSnapshotSchedules parses the "<path> <interval> <retention>" lines of the schedules
+func parseFilesystemSnapshotSchedules(output string) []FilesystemSnapshotSchedule {
+	var schedules []FilesystemSnapshotSchedule
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		schedule := FilesystemSnapshotSchedule{Path: fields[0], Interval: fields[1]}
+		if len(fields) > 2 {
+			schedule.Retention = SplitFilesystemSnapshotRetention(fields[2])
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules
+}
+
+// SplitFilesystemSnapshotRetention splits a retention into its periods, e.g. "24h7d" into ["24h", "7d"]
+func SplitFilesystemSnapshotRetention(retention string) []string {
+	return retentionRegexp.FindAllString(retention, -1)
+}
+
+// RemoveFilesystemSnapshotSchedule removes the snapshot schedule of a path of the filesystem
+func RemoveFilesystemSnapshotSchedule(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path, interval string) error {
+	logger.Infof("removing snapshot schedule %q on path %q of filesystem %q", interval, path, fsName)
+	args := []string{"fs", "snap-schedule", "remove", path, interval, "--fs", fsName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			logger.Debugf("snapshot schedule %q on path %q of filesystem %q is already removed", interval, path, fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to remove snapshot schedule %q on path %q of filesystem %q. %s", interval, path, fsName, output)
+	}
+
+	return nil
+}
+
+// RemoveFilesystemSnapshotRetention removes a retention policy of the scheduled snapshots of a path of the filesystem
+func RemoveFilesystemSnapshotRetention(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path, duration string) error {
+	logger.Infof("removing snapshot retention %q on path %q of filesystem %q", duration, path, fsName)
+	args := []string{"fs", "snap-schedule", "retention", "remove", path, duration, "--fs", fsName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove snapshot retention %q on path %q of filesystem %q. %s", duration, path, fsName, output)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilesystemMirroring(t *testing.T) {
+	var lastArgs []string
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutputFile = func(command, outfile string, args ...string) (string, error) {
+		lastArgs = args
+		if args[0] != "fs" {
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		}
+		if args[1] == "snapshot" {
+			switch args[3] {
+			case "peer_bootstrap":
+				if args[4] == "create" {
+					return `{"token": "eyJmc2lkIjogIjgxNDFlMjc"}`, nil
+				}
+			case "daemon":
+				return `[{"daemon_id": 4115, "filesystems": [{"filesystem_id": 1, "name": "myfs", "directory_count": 1,
+					"peers": [{"uuid": "a2dc7784-e7a1-4723-b103-03ee8d8768f8", "remote": {"client_name": "client.mirror_remote", "cluster_name": "site-b", "fs_name": "backup"},
+					"stats": {"failure_count": 1, "recovery_count": 0}}]}]}]`, nil
+			}
+		}
+		if args[1] == "snap-schedule" && args[2] == "list" {
+			return "/ 1h 24h7d\n/volumes/csi 1d \n", nil
+		}
+		return "", nil
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminClusterInfo("mycluster")
+
+	err := EnableFilesystemSnapshotMirror(context, clusterInfo, "myfs")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fs", "snapshot", "mirror", "enable", "myfs"}, lastArgs[:5])
+
+	token, err := CreateFilesystemMirrorBootstrapPeer(context, clusterInfo, "myfs")
+	assert.NoError(t, err)
+	assert.Equal(t, "eyJmc2lkIjogIjgxNDFlMjc", string(token))
+
+	err = ImportFilesystemMirrorBootstrapPeer(context, clusterInfo, "myfs", token)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"peer_bootstrap", "import", "myfs", "eyJmc2lkIjogIjgxNDFlMjc"}, lastArgs[3:7])
+
+	err = AddFilesystemMirrorDirectory(context, clusterInfo, "myfs", "/volumes/csi")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"add", "myfs", "/volumes/csi"}, lastArgs[3:6])
+
+	daemons, err := GetFilesystemMirrorDaemonStatus(context, clusterInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fs", "snapshot", "mirror", "daemon", "status"}, lastArgs[:5])
+	assert.Equal(t, 1, len(daemons))
+	assert.Equal(t, "myfs", daemons[0].Filesystems[0].Name)
+	assert.Equal(t, 1, daemons[0].Filesystems[0].FilesystemID)
+	peer := daemons[0].Filesystems[0].Peers[0]
+	assert.Equal(t, "a2dc7784-e7a1-4723-b103-03ee8d8768f8", peer.UUID)
+	assert.Equal(t, "site-b", peer.Remote.ClusterName)
+	assert.Equal(t, 1, peer.Stats.FailureCount)
+
+	err = DisableFilesystemSnapshotMirror(context, clusterInfo, "myfs")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fs", "snapshot", "mirror", "disable", "myfs"}, lastArgs[:5])
+
+	err = AddFilesystemSnapshotSchedule(context, clusterInfo, "myfs", "/", "1h", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fs", "snap-schedule", "add", "/", "1h", "--fs", "myfs"}, lastArgs[:7])
+	err = AddFilesystemSnapshotSchedule(context, clusterInfo, "myfs", "/", "1d", "2020-11-01T00:00:00")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1d", "2020-11-01T00:00:00", "--fs", "myfs"}, lastArgs[4:8])
+
+	err = AddFilesystemSnapshotRetention(context, clusterInfo, "myfs", "/", "24h")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"retention", "add", "/", "24h", "--fs", "myfs"}, lastArgs[2:8])
+
+	schedules, err := ListFilesystemSnapshotSchedules(context, clusterInfo, "myfs")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fs", "snap-schedule", "list", "/", "--recursive", "--fs", "myfs"}, lastArgs[:7])
+	assert.Equal(t, []FilesystemSnapshotSchedule{
+		{Path: "/", Interval: "1h", Retention: []string{"24h", "7d"}},
+		{Path: "/volumes/csi", Interval: "1d"},
+	}, schedules)
+
+	err = RemoveFilesystemSnapshotSchedule(context, clusterInfo, "myfs", "/volumes/csi", "1d")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fs", "snap-schedule", "remove", "/volumes/csi", "1d", "--fs", "myfs"}, lastArgs[:7])
+
+	err = RemoveFilesystemSnapshotRetention(context, clusterInfo, "myfs", "/", "7d")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"retention", "remove", "/", "7d", "--fs", "myfs"}, lastArgs[2:8])
+}
@@ -21,6 +21,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -35,6 +38,40 @@ type PoolMirroringStatus struct {
 		ImageHealth  string      `json:"image_health"`
 		States       interface{} `json:"states"`
 	} `json:"summary"`
+	Images []ImageMirroringStatus `json:"images"`
+}
+
+// ImageMirroringStatus is the mirroring status of an image, as reported by the verbose pool status
+type ImageMirroringStatus struct {
+	Name        string                 `json:"name"`
+	GlobalID    string                 `json:"global_id"`
+	State       string                 `json:"state"`
+	Description string                 `json:"description"`
+	LastUpdate  string                 `json:"last_update"`
+	PeerSites   []PeerSiteMirrorStatus `json:"peer_sites"`
+}
+
+// PeerSiteMirrorStatus is the mirroring status of an image on a peer site
+type PeerSiteMirrorStatus struct {
+	SiteName    string `json:"site_name"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	LastUpdate  string `json:"last_update"`
+}
+
+// imageMirroringErrorState is the suffix of the state of an image failing to be mirrored, e.g. "up+error"
+const imageMirroringErrorState = "+error"
+
+// replayStatus is the replay progress embedded in the description of a replaying image
+type replayStatus struct {
+	LocalSnapshotTimestamp  int64 `json:"local_snapshot_timestamp"`
+	RemoteSnapshotTimestamp int64 `json:"remote_snapshot_timestamp"`
+}
+
+// SnapshotScheduleStatus is a snapshot schedule of a pool
+type SnapshotScheduleStatus struct {
+	Interval  string `json:"interval"`
+	StartTime string `json:"start_time"`
 }
 
 // PoolMirroringInfo is the mirroring info of a given pool
@@ -127,7 +164,7 @@ func GetPoolMirroringStatus(context *clusterd.Context, clusterInfo *ClusterInfo,
 	logger.Debugf("retrieving mirroring pool %q status", poolName)
 
 	// Build command
-	args := []string{"mirror", "pool", "status", poolName}
+	args := []string{"mirror", "pool", "status", poolName, "--verbose"}
 	cmd := NewRBDCommand(context, clusterInfo, args)
 	cmd.JsonOutput = true
 
@@ -168,3 +205,216 @@ func GetPoolMirroringInfo(context *clusterd.Context, clusterInfo *ClusterInfo, p
 
 	return &poolMirroringInfo, nil
 }
+
+// ReplicationLag returns the replication lag in seconds of a snapshot-based mirrored image.
+// The lag is read from the replay status of the local image or of its peers, whichever is the largest.
+// The second return value is false if the lag cannot be determined, e.g. for journal-based mirroring.
+func (s *ImageMirroringStatus) ReplicationLag() (int64, bool) {
+	lag, found := replicationLagFromDescription(s.Description)
+	for _, peer := range s.PeerSites {
+		if peerLag, ok := replicationLagFromDescription(peer.Description); ok {
+			if !found || peerLag > lag {
+				lag = peerLag
+			}
+			found = true
+		}
+	}
+
+	return lag, found
+}
+
+// IsFailed returns whether the mirroring of the image is in error, locally or on a peer site
+func (s *ImageMirroringStatus) IsFailed() bool {
+	if strings.HasSuffix(s.State, imageMirroringErrorState) {
+		return true
+	}
+	for _, peer := range s.PeerSites {
+		if strings.HasSuffix(peer.State, imageMirroringErrorState) {
+			return true
+		}
+	}
+	return false
+}
+
+// replicationLagFromDescription parses a description like 'replaying, {"local_snapshot_timestamp":1606153205,...}'
+func replicationLagFromDescription(description string) (int64, bool) {
+	i := strings.Index(description, "{")
+	if i < 0 {
+		return 0, false
+	}
+
+	var status replayStatus
+	if err := json.Unmarshal([]byte(description[i:]), &status); err != nil {
+		logger.Debugf("failed to parse image replay status %q. %v", description, err)
+		return 0, false
+	}
+	if status.RemoteSnapshotTimestamp == 0 {
+		return 0, false
+	}
+
+	lag := status.RemoteSnapshotTimestamp - status.LocalSnapshotTimestamp
+	if lag < 0 {
+		lag = 0
+	}
+	return lag, true
+}
+
+// ListSnapshotSchedules lists the snapshot schedules of a pool
+func ListSnapshotSchedules(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) ([]SnapshotScheduleStatus, error) {
+	logger.Debugf("retrieving snapshot schedules of pool %q", poolName)
+
+	// Build command
+	args := []string{"mirror", "snapshot", "schedule", "ls", "--pool", poolName}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+
+	// Run command
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve snapshot schedules of pool %q. %s", poolName, string(buf))
+	}
+
+	// Unmarshal JSON into Go struct
+	var schedules []SnapshotScheduleStatus
+	if err := json.Unmarshal(buf, &schedules); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal snapshot schedules response")
+	}
+
+	return schedules, nil
+}
+
+// AddSnapshotSchedule adds a snapshot schedule to the mirrored images of a pool
+func AddSnapshotSchedule(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, interval, startTime string) error {
+	logger.Infof("adding snapshot schedule every %q to pool %q", interval, poolName)
+
+	args := []string{"mirror", "snapshot", "schedule", "add", "--pool", poolName, interval}
+	if startTime != "" {
+		args = append(args, startTime)
+	}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+
+	// Run command
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to add snapshot schedule every %q to pool %q. %s", interval, poolName, output)
+	}
+
+	return nil
+}
+
+// RemoveSnapshotSchedule removes a snapshot schedule from a pool
+func RemoveSnapshotSchedule(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, interval, startTime string) error {
+	logger.Infof("removing snapshot schedule every %q from pool %q", interval, poolName)
+
+	args := []string{"mirror", "snapshot", "schedule", "remove", "--pool", poolName, interval}
+	if startTime != "" {
+		args = append(args, startTime)
+	}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+
+	// Run command
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove snapshot schedule every %q from pool %q. %s", interval, poolName, output)
+	}
+
+	return nil
+}
+
+// ReconcileSnapshotSchedules adds the snapshot schedules of the pool spec that are missing and removes the ones
+// that are not in the spec anymore
+func ReconcileSnapshotSchedules(context *clusterd.Context, clusterInfo *ClusterInfo, pool cephv1.PoolSpec, poolName string) error {
+	current, err := ListSnapshotSchedules(context, clusterInfo, poolName)
+	if err != nil {
+		return err
+	}
+
+	for _, schedule := range current {
+		if !hasSnapshotSchedule(pool.Mirroring.SnapshotSchedules, schedule.Interval, schedule.StartTime) {
+			err := RemoveSnapshotSchedule(context, clusterInfo, poolName, schedule.Interval, schedule.StartTime)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, schedule := range pool.Mirroring.SnapshotSchedules {
+		if !isScheduled(current, schedule.Interval, schedule.StartTime) {
+			err := AddSnapshotSchedule(context, clusterInfo, poolName, schedule.Interval, schedule.StartTime)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func hasSnapshotSchedule(schedules []cephv1.SnapshotScheduleSpec, interval, startTime string) bool {
+	key := newSnapshotScheduleKey(interval, startTime)
+	for _, schedule := range schedules {
+		if newSnapshotScheduleKey(schedule.Interval, schedule.StartTime) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func isScheduled(schedules []SnapshotScheduleStatus, interval, startTime string) bool {
+	key := newSnapshotScheduleKey(interval, startTime)
+	for _, schedule := range schedules {
+		if newSnapshotScheduleKey(schedule.Interval, schedule.StartTime) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotScheduleKey compares the snapshot schedules of the spec with the ones listed by Ceph, which normalizes them,
+// e.g. an interval of "24h" is listed as "1d" and the start time is converted to UTC
+type snapshotScheduleKey struct {
+	interval  string
+	startTime string
+}
+
+// the layouts of the start times, with or without seconds, time zone and date
+var snapshotScheduleStartTimeLayouts = []string{
+	"15:04Z07:00", "15:04:05Z07:00", "15:04-0700", "15:04:05-0700", "15:04", "15:04:05",
+	"2006-01-02T15:04:05Z07:00", "2006-01-02T15:04:05", "2006-01-02 15:04:05Z07:00", "2006-01-02 15:04:05",
+}
+
+// newSnapshotScheduleKey returns the key of a snapshot schedule, the values that cannot be parsed are compared as is
+func newSnapshotScheduleKey(interval, startTime string) snapshotScheduleKey {
+	key := snapshotScheduleKey{interval: interval, startTime: startTime}
+	if minutes, err := snapshotScheduleIntervalMinutes(interval); err == nil {
+		key.interval = fmt.Sprintf("%dm", minutes)
+	}
+	if startTime == "" {
+		return key
+	}
+	for _, layout := range snapshotScheduleStartTimeLayouts {
+		if t, err := time.Parse(layout, startTime); err == nil {
+			// the start time of a schedule without time zone is in UTC
+			key.startTime = t.UTC().Format("15:04")
+			break
+		}
+	}
+	return key
+}
+
+// snapshotScheduleIntervalMinutes parses a snapshot schedule interval in days, hours or minutes, e.g. "1d", "12h"
+func snapshotScheduleIntervalMinutes(interval string) (int, error) {
+	unitMinutes := map[byte]int{'d': 24 * 60, 'h': 60, 'm': 1}
+	if len(interval) < 2 {
+		return 0, errors.Errorf("invalid interval %q", interval)
+	}
+	minutes, ok := unitMinutes[interval[len(interval)-1]]
+	if !ok {
+		return 0, errors.Errorf("invalid unit of interval %q", interval)
+	}
+	count, err := strconv.Atoi(interval[:len(interval)-1])
+	if err != nil || count <= 0 {
+		return 0, errors.Errorf("invalid interval %q", interval)
+	}
+	return count * minutes, nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package client
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -29,6 +30,7 @@ import (
 var (
 	bootstrapPeerToken = `eyJmc2lkIjoiYzZiMDg3ZjItNzgyOS00ZGJiLWJjZmMtNTNkYzM0ZTBiMzVkIiwiY2xpZW50X2lkIjoicmJkLW1pcnJvci1wZWVyIiwia2V5IjoiQVFBV1lsWmZVQ1Q2RGhBQVBtVnAwbGtubDA5YVZWS3lyRVV1NEE9PSIsIm1vbl9ob3N0IjoiW3YyOjE5Mi4xNjguMTExLjEwOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTA6Njc4OV0sW3YyOjE5Mi4xNjguMTExLjEyOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTI6Njc4OV0sW3YyOjE5Mi4xNjguMTExLjExOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTE6Njc4OV0ifQ==`
 	mirrorStatus       = `{"summary":{"health":"WARNING","daemon_health":"OK","image_health":"WARNING","states":{"starting_replay":1,"replaying":1}}}`
+	mirrorStatusImages = `{"summary":{"health":"OK","daemon_health":"OK","image_health":"OK","states":{"replaying":2}},"images":[{"name":"img1","global_id":"5d1a0bd1-7c18-4a66-8c2b-5b8d1d3a9a5e","state":"up+stopped","description":"local image is primary","last_update":"2020-11-23 17:40:05","peer_sites":[{"site_name":"site-b","state":"up+replaying","description":"replaying, {\"bytes_per_second\":0.0,\"bytes_per_snapshot\":0.0,\"local_snapshot_timestamp\":1606153205,\"remote_snapshot_timestamp\":1606153505,\"replay_state\":\"idle\"}","last_update":"2020-11-23 17:40:05"}]},{"name":"img2","global_id":"b0d3f3e5-1f34-4e3c-9c0b-9d2f0e0d6c1a","state":"up+replaying","description":"replaying, {\"bytes_per_second\":0.0,\"entries_behind_primary\":0}","last_update":"2020-11-23 17:40:05","peer_sites":[]}]}`
 	mirrorInfo         = `{"mode":"image","site_name":"39074576-5884-4ef3-8a4d-8a0c5ed33031","peers":[{"uuid":"4a6983c0-3c9d-40f5-b2a9-2334a4659827","direction":"rx-tx","site_name":"ocs","mirror_uuid":"","client_name":"client.rbd-mirror-peer"}]}`
 )
 
@@ -94,6 +96,114 @@ func TestGetPoolMirroringStatus(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "WARNING", poolMirrorStatus.Summary.Health)
 	assert.Equal(t, "OK", poolMirrorStatus.Summary.DaemonHealth)
+	assert.Empty(t, poolMirrorStatus.Images)
+
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" {
+			assert.Equal(t, "--verbose", args[4])
+			return mirrorStatusImages, nil
+		}
+		return "", errors.New("unknown command")
+	}
+	poolMirrorStatus, err = GetPoolMirroringStatus(context, AdminClusterInfo("mycluster"), pool)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(poolMirrorStatus.Images))
+	assert.Equal(t, "img1", poolMirrorStatus.Images[0].Name)
+	assert.Equal(t, "up+stopped", poolMirrorStatus.Images[0].State)
+	assert.Equal(t, "site-b", poolMirrorStatus.Images[0].PeerSites[0].SiteName)
+}
+
+func TestReplicationLag(t *testing.T) {
+	// snapshot-based mirroring, the lag is reported by the peer
+	status := ImageMirroringStatus{
+		Description: "local image is primary",
+		PeerSites: []PeerSiteMirrorStatus{
+			{Description: `replaying, {"local_snapshot_timestamp":1606153205,"remote_snapshot_timestamp":1606153505,"replay_state":"idle"}`},
+		},
+	}
+	lag, ok := status.ReplicationLag()
+	assert.True(t, ok)
+	assert.Equal(t, int64(300), lag)
+
+	// snapshot-based mirroring, the lag is reported by the local image
+	status = ImageMirroringStatus{Description: `replaying, {"local_snapshot_timestamp":1606153205,"remote_snapshot_timestamp":1606153265}`}
+	lag, ok = status.ReplicationLag()
+	assert.True(t, ok)
+	assert.Equal(t, int64(60), lag)
+
+	// journal-based mirroring
+	status = ImageMirroringStatus{Description: `replaying, {"bytes_per_second":0.0,"entries_behind_primary":0}`}
+	_, ok = status.ReplicationLag()
+	assert.False(t, ok)
+
+	// no replay status
+	status = ImageMirroringStatus{Description: "local image is primary"}
+	_, ok = status.ReplicationLag()
+	assert.False(t, ok)
+}
+
+func TestReconcileSnapshotSchedules(t *testing.T) {
+	pool := "pool-test"
+	poolSpec := cephv1.PoolSpec{Mirroring: cephv1.MirroringSpec{Mode: "image", SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Interval: "24h", StartTime: "14:00:00-05:00"}, {Interval: "1h"}}}}
+	added := []string{}
+	removed := []string{}
+	schedules := `[{"interval":"1h","start_time":""},{"interval":"30m","start_time":""}]`
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" && args[1] == "snapshot" && args[2] == "schedule" {
+			assert.Equal(t, "--pool", args[4])
+			assert.Equal(t, pool, args[5])
+			switch args[3] {
+			case "ls":
+				return schedules, nil
+			case "add":
+				added = append(added, args[6])
+				if args[6] == "24h" {
+					assert.Equal(t, "14:00:00-05:00", args[7])
+				}
+				return "", nil
+			case "remove":
+				removed = append(removed, args[6])
+				assert.True(t, strings.HasPrefix(args[7], "--"))
+				return "", nil
+			}
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := ReconcileSnapshotSchedules(context, AdminClusterInfo("mycluster"), poolSpec, pool)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"24h"}, added)
+	assert.Equal(t, []string{"30m"}, removed)
+
+	// the schedules normalized by ceph match the spec
+	schedules = `[{"interval":"1d","start_time":"19:00:00"},{"interval":"60m","start_time":""}]`
+	added = []string{}
+	removed = []string{}
+	err = ReconcileSnapshotSchedules(context, AdminClusterInfo("mycluster"), poolSpec, pool)
+	assert.NoError(t, err)
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+
+	// failure to list the schedules
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		return "", errors.New("failed")
+	}
+	err = ReconcileSnapshotSchedules(context, AdminClusterInfo("mycluster"), poolSpec, pool)
+	assert.Error(t, err)
+}
+
+func TestNewSnapshotScheduleKey(t *testing.T) {
+	assert.Equal(t, newSnapshotScheduleKey("1d", ""), newSnapshotScheduleKey("24h", ""))
+	assert.Equal(t, newSnapshotScheduleKey("1h", ""), newSnapshotScheduleKey("60m", ""))
+	assert.NotEqual(t, newSnapshotScheduleKey("1h", ""), newSnapshotScheduleKey("30m", ""))
+	assert.Equal(t, newSnapshotScheduleKey("1d", "19:00:00"), newSnapshotScheduleKey("24h", "14:00:00-05:00"))
+	assert.Equal(t, newSnapshotScheduleKey("1d", "19:00"), newSnapshotScheduleKey("1d", "19:00:00Z"))
+	assert.NotEqual(t, newSnapshotScheduleKey("1d", "14:00"), newSnapshotScheduleKey("1d", "14:00:00-05:00"))
+
+	// the values that cannot be parsed are compared as is
+	assert.Equal(t, snapshotScheduleKey{interval: "1w", startTime: "noon"}, newSnapshotScheduleKey("1w", "noon"))
 }
 
 func TestImportRBDMirrorBootstrapPeer(t *testing.T) {
@@ -17,6 +17,7 @@ package client
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
@@ -27,7 +28,9 @@ import (
 type MonStatusResponse struct {
 	Quorum []int `json:"quorum"`
 	MonMap struct {
-		Mons []MonMapEntry `json:"mons"`
+		Mons          []MonMapEntry `json:"mons"`
+		StretchMode   bool          `json:"stretch_mode"`
+		TiebreakerMon string        `json:"tiebreaker_mon"`
 	} `json:"monmap"`
 }
 
@@ -66,3 +69,46 @@ func GetMonQuorumStatus(context *clusterd.Context, clusterInfo *ClusterInfo) (Mo
 
 	return resp, nil
 }
+
+// SetMonStretchZone sets the location of a mon in the CRUSH hierarchy of a stretch cluster
+func SetMonStretchZone(context *clusterd.Context, clusterInfo *ClusterInfo, monName, crushType, zone string) error {
+	logger.Infof("setting mon %q location to %s=%s", monName, crushType, zone)
+	args := []string{"mon", "set_location", monName, fmt.Sprintf("%s=%s", crushType, zone)}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set mon %q location. %s", monName, string(buf))
+	}
+
+	return nil
+}
+
+// EnableStretchMode enables the stretch mode of the mons with the given tiebreaker mon, CRUSH rule and zone type
+func EnableStretchMode(context *clusterd.Context, clusterInfo *ClusterInfo, tiebreaker, crushRule, crushType string) error {
+	// the stretch mode requires the connectivity election strategy
+	args := []string{"mon", "set", "election_strategy", "connectivity"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the connectivity election strategy. %s", string(buf))
+	}
+
+	logger.Infof("enabling stretch mode with tiebreaker mon %q", tiebreaker)
+	args = []string{"mon", "enable_stretch_mode", tiebreaker, crushRule, crushType}
+	buf, err = NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable stretch mode. %s", string(buf))
+	}
+
+	return nil
+}
+
+// SetNewTiebreaker sets the tiebreaker mon of a cluster in stretch mode
+func SetNewTiebreaker(context *clusterd.Context, clusterInfo *ClusterInfo, monName string) error {
+	logger.Infof("setting new tiebreaker mon %q", monName)
+	args := []string{"mon", "set_new_tiebreaker", monName}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set new tiebreaker mon %q. %s", monName, string(buf))
+	}
+
+	return nil
+}
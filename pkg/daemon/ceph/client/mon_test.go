@@ -19,6 +19,9 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -61,3 +64,29 @@ func TestCephArgs(t *testing.T) {
 	assert.Equal(t, "--name=client.admin", args[3])
 	assert.Equal(t, "--keyring=/var/lib/rook/a/client.admin.keyring", args[4])
 }
+
+func TestEnableStretchMode(t *testing.T) {
+	commands := [][]string{}
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		commands = append(commands, args[:5])
+		return "", nil
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := SetMonStretchZone(context, AdminClusterInfo("mycluster"), "a", "zone", "z1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mon", "set_location", "a", "zone=z1"}, commands[0][:4])
+
+	err = EnableStretchMode(context, AdminClusterInfo("mycluster"), "e", StretchCrushRuleName, "zone")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(commands))
+	assert.Equal(t, []string{"mon", "set", "election_strategy", "connectivity"}, commands[1][:4])
+	assert.Equal(t, []string{"mon", "enable_stretch_mode", "e", StretchCrushRuleName, "zone"}, commands[2])
+
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		return "", errors.New("failed")
+	}
+	err = EnableStretchMode(context, AdminClusterInfo("mycluster"), "e", StretchCrushRuleName, "zone")
+	assert.Error(t, err)
+}
@@ -17,6 +17,7 @@ package client
 
 import (
 	"encoding/json"
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -252,6 +253,45 @@ func OSDOut(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int) (str
 	return string(buf), err
 }
 
+// OSDOkToStop checks that stopping an OSD does not make placement groups unavailable
+func OSDOkToStop(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int) error {
+	args := []string{"osd", "ok-to-stop", strconv.Itoa(osdID)}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "osd.%d is not ok to stop. %s", osdID, string(buf))
+	}
+	return nil
+}
+
+// PurgeOSD removes an OSD from the CRUSH map, the auth keys and the OSD map. Unless forced, the OSD must be safe to destroy.
+func PurgeOSD(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int, force bool) error {
+	args := []string{"osd", "purge", fmt.Sprintf("osd.%d", osdID), "--yes-i-really-mean-it"}
+	if force {
+		args = append(args, "--force")
+	}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to purge osd.%d. %s", osdID, string(buf))
+	}
+	logger.Infof("purged osd.%d", osdID)
+	return nil
+}
+
+// DestroyOSD removes the auth keys of an OSD and marks it destroyed in the OSD map, keeping its ID and CRUSH weight so
+// that a new OSD can be created with the same ID. Unless forced, the OSD must be safe to destroy.
+func DestroyOSD(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int, force bool) error {
+	args := []string{"osd", "destroy", fmt.Sprintf("osd.%d", osdID), "--yes-i-really-mean-it"}
+	if force {
+		args = append(args, "--force")
+	}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to destroy osd.%d. %s", osdID, string(buf))
+	}
+	logger.Infof("destroyed osd.%d", osdID)
+	return nil
+}
+
 func OsdSafeToDestroy(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int) (bool, error) {
 	args := []string{"osd", "safe-to-destroy", strconv.Itoa(osdID)}
 	cmd := NewCephCommand(context, clusterInfo, args)
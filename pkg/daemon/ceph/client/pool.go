@@ -55,11 +55,14 @@ type CephStoragePoolDetails struct {
 }
 
 type CephStoragePoolStats struct {
-	Pools []struct {
+	Stats        CephStorageStats            `json:"stats"`
+	StatsByClass map[string]CephStorageStats `json:"stats_by_class"`
+	Pools        []struct {
 		Name  string `json:"name"`
 		ID    int    `json:"id"`
 		Stats struct {
 			BytesUsed    float64 `json:"bytes_used"`
+			Stored       float64 `json:"stored"`
 			RawBytesUsed float64 `json:"raw_bytes_used"`
 			MaxAvail     float64 `json:"max_avail"`
 			Objects      float64 `json:"objects"`
@@ -68,10 +71,18 @@ type CephStoragePoolStats struct {
 			ReadBytes    float64 `json:"rd_bytes"`
 			WriteIO      float64 `json:"wr"`
 			WriteBytes   float64 `json:"wr_bytes"`
+			PercentUsed  float64 `json:"percent_used"`
 		} `json:"stats"`
 	} `json:"pools"`
 }
 
+// CephStorageStats is the raw capacity of the cluster or of a device class
+type CephStorageStats struct {
+	TotalBytes        uint64 `json:"total_bytes"`
+	TotalAvailBytes   uint64 `json:"total_avail_bytes"`
+	TotalUsedRawBytes uint64 `json:"total_used_raw_bytes"`
+}
+
 type PoolStatistics struct {
 	Images struct {
 		Count            int `json:"count"`
@@ -264,6 +275,14 @@ func setCommonPoolProperties(context *clusterd.Context, clusterInfo *ClusterInfo
 		}
 	}
 
+	// Set the quotas of the spec, a quota of zero removes it. The quotas not in the spec are left untouched.
+	if pool.Quotas.IsEnabled() {
+		err := SetPoolQuota(context, clusterInfo, poolName, pool.Quotas)
+		if err != nil {
+			return errors.Wrapf(err, "failed to set quotas for pool %q", poolName)
+		}
+	}
+
 	// If the pool is mirrored, let's enable mirroring
 	// we don't need to check if the pool is erasure coded or not, mirroring will still work, it will simply be slow
 	if pool.Mirroring.Enabled {
@@ -271,6 +290,14 @@ func setCommonPoolProperties(context *clusterd.Context, clusterInfo *ClusterInfo
 		if err != nil {
 			return errors.Wrapf(err, "failed to enable mirroring for pool %q", poolName)
 		}
+
+		// Snapshot schedules only apply to the images mirrored with snapshots
+		if pool.Mirroring.Mode == "image" {
+			err = ReconcileSnapshotSchedules(context, clusterInfo, pool, poolName)
+			if err != nil {
+				return errors.Wrapf(err, "failed to reconcile snapshot schedules for pool %q", poolName)
+			}
+		}
 	}
 
 	return nil
@@ -364,6 +391,32 @@ func SetPoolProperty(context *clusterd.Context, clusterInfo *ClusterInfo, name,
 	return nil
 }
 
+// SetPoolQuota sets the max bytes and max objects quotas of a pool that are set in the spec, a quota of zero is removed
+func SetPoolQuota(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string, quotas cephv1.QuotaSpec) error {
+	if quotas.MaxBytes != nil {
+		if err := setPoolQuota(context, clusterInfo, poolName, "max_bytes", quotas.MaxBytes.Value()); err != nil {
+			return err
+		}
+	}
+	if quotas.MaxObjects != nil {
+		if err := setPoolQuota(context, clusterInfo, poolName, "max_objects", quotas.MaxObjects.Value()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setPoolQuota(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, quotaName string, quotaValue int64) error {
+	value := strconv.FormatInt(quotaValue, 10)
+	args := []string{"osd", "pool", "set-quota", poolName, quotaName, value}
+	logger.Debugf("setting quota %q to %q on pool %q", quotaName, value, poolName)
+	_, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set quota %q on pool %q", quotaName, poolName)
+	}
+	return nil
+}
+
 // SetPoolReplicatedSizeProperty sets the replica size of a pool
 func SetPoolReplicatedSizeProperty(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, size string) error {
 	propName := "size"
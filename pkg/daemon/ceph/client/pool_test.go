@@ -23,6 +23,7 @@ import (
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/rook/rook/pkg/clusterd"
 )
@@ -267,3 +268,33 @@ func TestSetPoolReplicatedSizeProperty(t *testing.T) {
 	err = SetPoolReplicatedSizeProperty(context, AdminClusterInfo("mycluster"), poolName, "1")
 	assert.NoError(t, err)
 }
+
+func TestSetPoolQuota(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	quotas := map[string]string{}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[2] == "set-quota" {
+			assert.Equal(t, "mypool", args[3])
+			quotas[args[4]] = args[5]
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	maxBytes := resource.MustParse("10Gi")
+	err := SetPoolQuota(context, AdminClusterInfo("mycluster"), "mypool", cephv1.QuotaSpec{MaxBytes: &maxBytes})
+	assert.NoError(t, err)
+	assert.Equal(t, "10737418240", quotas["max_bytes"])
+	// the unset quota is left untouched
+	assert.NotContains(t, quotas, "max_objects")
+
+	// a quota of zero is removed
+	maxObjects := resource.MustParse("100k")
+	zero := resource.MustParse("0")
+	err = SetPoolQuota(context, AdminClusterInfo("mycluster"), "mypool", cephv1.QuotaSpec{MaxBytes: &zero, MaxObjects: &maxObjects})
+	assert.NoError(t, err)
+	assert.Equal(t, "0", quotas["max_bytes"])
+	assert.Equal(t, "100000", quotas["max_objects"])
+}
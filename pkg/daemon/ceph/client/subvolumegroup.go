@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+// SubVolume is a representation of the json structure returned by 'ceph fs subvolume ls'
+type SubVolume struct {
+	Name string `json:"name"`
+}
+
+// CreateSubVolumeGroup creates a subvolume group in a filesystem, nothing is done if the group already exists
+func CreateSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, groupName, dataPoolName string) error {
+	logger.Infof("creating subvolume group %q in filesystem %q", groupName, fsName)
+	args := []string{"fs", "subvolumegroup", "create", fsName, groupName}
+	if dataPoolName != "" {
+		args = append(args, "--pool_layout", dataPoolName)
+	}
+	_, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to create subvolume group %q in filesystem %q", groupName, fsName)
+	}
+
+	return nil
+}
+
+// ResizeSubVolumeGroup sets the quota of a subvolume group, a size of zero removes the quota
+func ResizeSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, groupName string, size int64) error {
+	newSize := "inf"
+	if size > 0 {
+		newSize = strconv.FormatInt(size, 10)
+	}
+	logger.Infof("resizing subvolume group %q in filesystem %q to %q", groupName, fsName, newSize)
+	args := []string{"fs", "subvolumegroup", "resize", fsName, groupName, newSize}
+	_, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to resize subvolume group %q in filesystem %q", groupName, fsName)
+	}
+
+	return nil
+}
+
+// PinSubVolumeGroup sets the pinning policy of a subvolume group, the type is "export", "distributed" or "random"
+func PinSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, groupName, pinType, setting string) error {
+	logger.Infof("setting %s pin of subvolume group %q in filesystem %q to %q", pinType, groupName, fsName, setting)
+	args := []string{"fs", "subvolumegroup", "pin", fsName, groupName, pinType, setting}
+	_, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set %s pin of subvolume group %q in filesystem %q", pinType, groupName, fsName)
+	}
+
+	return nil
+}
+
+// GetSubVolumeGroupPath returns the path of a subvolume group in the filesystem
+func GetSubVolumeGroupPath(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, groupName string) (string, error) {
+	args := []string{"fs", "subvolumegroup", "getpath", fsName, groupName}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get path of subvolume group %q in filesystem %q", groupName, fsName)
+	}
+
+	return strings.TrimSpace(string(buf)), nil
+}
+
+// ListSubVolumes lists the subvolumes of a subvolume group
+func ListSubVolumes(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, groupName string) ([]SubVolume, error) {
+	args := []string{"fs", "subvolume", "ls", fsName, "--group_name", groupName}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list subvolumes of subvolume group %q in filesystem %q", groupName, fsName)
+	}
+
+	var subVolumes []SubVolume
+	if err := json.Unmarshal(buf, &subVolumes); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(buf))
+	}
+
+	return subVolumes, nil
+}
+
+// DeleteSubVolumeGroup removes a subvolume group from a filesystem, ceph refuses to remove a group with subvolumes
+func DeleteSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, groupName string) error {
+	logger.Infof("deleting subvolume group %q in filesystem %q", groupName, fsName)
+	args := []string{"fs", "subvolumegroup", "rm", fsName, groupName}
+	_, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete subvolume group %q in filesystem %q", groupName, fsName)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubVolumeGroup(t *testing.T) {
+	var lastArgs []string
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutputFile = func(command, outfile string, args ...string) (string, error) {
+		lastArgs = args
+		if args[0] != "fs" {
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		}
+		switch args[2] {
+		case "getpath":
+			return "/volumes/csi\n", nil
+		case "ls":
+			return `[{"name":"csi-vol-1"},{"name":"csi-vol-2"}]`, nil
+		}
+		return "", nil
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminClusterInfo("mycluster")
+
+	err := CreateSubVolumeGroup(context, clusterInfo, "myfs", "csi", "myfs-data0")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fs", "subvolumegroup", "create", "myfs", "csi", "--pool_layout", "myfs-data0"}, lastArgs[:7])
+
+	err = ResizeSubVolumeGroup(context, clusterInfo, "myfs", "csi", 1024)
+	assert.NoError(t, err)
+	assert.Equal(t, "1024", lastArgs[5])
+	err = ResizeSubVolumeGroup(context, clusterInfo, "myfs", "csi", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "inf", lastArgs[5])
+
+	err = PinSubVolumeGroup(context, clusterInfo, "myfs", "csi", "export", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pin", "myfs", "csi", "export", "1"}, lastArgs[2:7])
+
+	path, err := GetSubVolumeGroupPath(context, clusterInfo, "myfs", "csi")
+	assert.NoError(t, err)
+	assert.Equal(t, "/volumes/csi", path)
+
+	subVolumes, err := ListSubVolumes(context, clusterInfo, "myfs", "csi")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(subVolumes))
+	assert.Equal(t, "csi-vol-1", subVolumes[0].Name)
+	assert.Equal(t, []string{"subvolume", "ls", "myfs", "--group_name", "csi"}, lastArgs[1:6])
+}
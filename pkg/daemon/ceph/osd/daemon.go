@@ -30,6 +30,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
 	oposd "github.com/rook/rook/pkg/operator/ceph/cluster/osd"
 	"github.com/rook/rook/pkg/util/sys"
 )
@@ -215,6 +216,13 @@ func Provision(context *clusterd.Context, agent *OsdAgent, crushLocation string)
 		return errors.Wrap(err, "failed to generate ceph config")
 	}
 
+	// With a KMS the encryption key is not passed by the operator so fetch it before running ceph-volume
+	if agent.pvcBacked && isEncrypted && kms.IsEnabledFromEnv() {
+		if err := setKMSEncryptionKey(os.Getenv(oposd.EncryptionKeyNameEnvVarName)); err != nil {
+			return errors.Wrap(err, "failed to set encryption key")
+		}
+	}
+
 	logger.Infof("discovering hardware")
 
 	var err error
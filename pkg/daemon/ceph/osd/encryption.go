@@ -17,12 +17,19 @@ limitations under the License.
 package osd
 
 import (
+	"io/ioutil"
+	"os"
+
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
+	oposd "github.com/rook/rook/pkg/operator/ceph/cluster/osd"
 )
 
 const (
 	cryptsetupBinary = "cryptsetup"
+	// the old key of an OSD is saved in the KMS under the name of its key with this suffix until its keyslots are removed
+	oldEncryptionKeyNameSuffix = "-old"
 )
 
 func closeEncryptedDevice(context *clusterd.Context, dmName string) error {
@@ -35,3 +42,234 @@ func closeEncryptedDevice(context *clusterd.Context, dmName string) error {
 	logger.Info(cryptsetupOut)
 	return nil
 }
+
+// setKMSEncryptionKey fetches the dm-crypt key of the OSD from the KMS, or generates and stores it on the
+// first run, then exposes it to ceph-volume
+func setKMSEncryptionKey(keyName string) error {
+	if keyName == "" {
+		return errors.Errorf("failed to get encryption key from kms, %q is not set", oposd.EncryptionKeyNameEnvVarName)
+	}
+
+	km, err := kms.NewKeyManager(kms.ConnectionDetailsFromEnv())
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to kms")
+	}
+
+	key, err := km.GetSecret(keyName)
+	if err == kms.ErrKeyNotFound {
+		logger.Infof("generating encryption key %q and storing it in the kms", keyName)
+		key, err = oposd.GenerateDmCryptKey()
+		if err != nil {
+			return errors.Wrapf(err, "failed to generate encryption key %q", keyName)
+		}
+		if err := km.PutSecret(keyName, key); err != nil {
+			return errors.Wrapf(err, "failed to store encryption key %q in the kms", keyName)
+		}
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to get encryption key %q from the kms", keyName)
+	}
+
+	return os.Setenv(oposd.CephVolumeEncryptedKeyEnvVarName, key)
+}
+
+// WriteKMSEncryptionKey fetches the dm-crypt key of an OSD from the KMS and writes it where cryptsetup reads it
+func WriteKMSEncryptionKey(keyName, keyPath string) error {
+	km, err := kms.NewKeyManager(kms.ConnectionDetailsFromEnv())
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to kms")
+	}
+
+	key, err := km.GetSecret(keyName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get encryption key %q from the kms", keyName)
+	}
+
+	if err := ioutil.WriteFile(keyPath, []byte(key), 0400); err != nil {
+		return errors.Wrapf(err, "failed to write encryption key to %q", keyPath)
+	}
+
+	logger.Infof("wrote encryption key %q to %q", keyName, keyPath)
+	return nil
+}
+
+// RotateEncryptionKey replaces the dm-crypt key of an encrypted OSD in three steps, each of them can be retried: a keyslot
+// with a new key is added to each of the block devices, the new key is stored, then the keyslot of the old key is removed.
+// The OSD keeps running. Without a KMS the keys are stored in Kubernetes Secrets by the operator, which mounts the old and
+// the new key and runs the job once to add the new keyslots and once more to remove the old ones after storing the new key.
+func RotateEncryptionKey(context *clusterd.Context, keyName, keyPath string, blockPaths []string) error {
+	if !kms.IsEnabledFromEnv() {
+		return addMountedEncryptionKey(context, keyPath, keyPath+oposd.NewEncryptionKeyFileSuffix, blockPaths)
+	}
+
+	km, err := kms.NewKeyManager(kms.ConnectionDetailsFromEnv())
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to kms")
+	}
+	return rotateKMSEncryptionKey(context, km, keyName, keyPath, blockPaths)
+}
+
+// RemoveOldEncryptionKey removes the keyslot of the old key mounted by the operator once the new key is stored
+func RemoveOldEncryptionKey(context *clusterd.Context, keyPath string, blockPaths []string) error {
+	return removeOldEncryptionKey(context, keyPath, keyPath+oposd.NewEncryptionKeyFileSuffix, blockPaths)
+}
+
+// rotateKMSEncryptionKey rotates a key stored in the KMS. The old key is saved in the KMS before the new key replaces
+// it and deleted once its keyslots are removed, a retry after the new key was stored only removes the old keyslots.
+func rotateKMSEncryptionKey(context *clusterd.Context, km kms.KeyManager, keyName, keyPath string, blockPaths []string) error {
+	oldKeyName := keyName + oldEncryptionKeyNameSuffix
+	currentKey, err := km.GetSecret(keyName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get encryption key %q", keyName)
+	}
+	oldKey, err := km.GetSecret(oldKeyName)
+	if err != nil && err != kms.ErrKeyNotFound {
+		return errors.Wrapf(err, "failed to get old encryption key %q", oldKeyName)
+	}
+
+	// cryptsetup reads the keys from files, they live in a memory backed volume
+	newKeyPath := keyPath + oposd.NewEncryptionKeyFileSuffix
+	defer os.Remove(keyPath)
+	defer os.Remove(newKeyPath)
+
+	// the old key is only saved until its keyslots are removed, if it is still the current key the previous
+	// rotation stopped before storing its new key and the rotation starts over
+	if oldKey == "" || oldKey == currentKey {
+		newKey, err := oposd.GenerateDmCryptKey()
+		if err != nil {
+			return errors.Wrapf(err, "failed to generate new encryption key %q", keyName)
+		}
+		if err := writeEncryptionKeys(keyPath, currentKey, newKeyPath, newKey); err != nil {
+			return err
+		}
+		if err := addEncryptionKeys(context, keyPath, newKeyPath, blockPaths); err != nil {
+			return err
+		}
+
+		if err := km.PutSecret(oldKeyName, currentKey); err != nil {
+			removeNewEncryptionKeys(context, newKeyPath, blockPaths)
+			return errors.Wrapf(err, "failed to save old encryption key %q", oldKeyName)
+		}
+		if err := km.PutSecret(keyName, newKey); err != nil {
+			removeNewEncryptionKeys(context, newKeyPath, blockPaths)
+			return errors.Wrapf(err, "failed to store new encryption key %q", keyName)
+		}
+		logger.Infof("stored new encryption key %q", keyName)
+		oldKey, currentKey = currentKey, newKey
+	} else {
+		logger.Infof("new encryption key %q is already stored, removing the old keyslots", keyName)
+	}
+
+	// from now on the new key opens the devices, a failure is retried with the saved old key
+	if err := writeEncryptionKeys(keyPath, oldKey, newKeyPath, currentKey); err != nil {
+		return err
+	}
+	if err := removeOldEncryptionKey(context, keyPath, newKeyPath, blockPaths); err != nil {
+		return errors.Wrapf(err, "new encryption key %q is in use but the old keyslots could not be removed", keyName)
+	}
+	if err := km.DeleteSecret(oldKeyName); err != nil {
+		return errors.Wrapf(err, "failed to delete old encryption key %q", oldKeyName)
+	}
+
+	logger.Infof("successfully rotated encryption key %q", keyName)
+	return nil
+}
+
+func writeEncryptionKeys(keyPath, key, newKeyPath, newKey string) error {
+	if err := ioutil.WriteFile(keyPath, []byte(key), 0400); err != nil {
+		return errors.Wrapf(err, "failed to write encryption key to %q", keyPath)
+	}
+	if err := ioutil.WriteFile(newKeyPath, []byte(newKey), 0400); err != nil {
+		return errors.Wrapf(err, "failed to write new encryption key to %q", newKeyPath)
+	}
+	return nil
+}
+
+// addEncryptionKeys adds a keyslot with the new key to each of the devices, the new keyslots are dropped on failure
+// so that the old key remains the only one opening the devices
+func addEncryptionKeys(context *clusterd.Context, keyPath, newKeyPath string, blockPaths []string) error {
+	for i, blockPath := range blockPaths {
+		if err := addEncryptionKey(context, blockPath, keyPath, newKeyPath); err != nil {
+			removeNewEncryptionKeys(context, newKeyPath, blockPaths[:i])
+			return err
+		}
+	}
+	return nil
+}
+
+func removeNewEncryptionKeys(context *clusterd.Context, newKeyPath string, blockPaths []string) {
+	for _, blockPath := range blockPaths {
+		if err := removeEncryptionKey(context, blockPath, newKeyPath); err != nil {
+			logger.Errorf("failed to remove new keyslot of %q. %v", blockPath, err)
+		}
+	}
+}
+
+// addMountedEncryptionKey adds a keyslot with the new key mounted by the operator to each of the block devices. The
+// operator stores the new key once the job succeeded, so the step can be retried with the same keys: a device already
+// opened by the new key is not given another keyslot.
+func addMountedEncryptionKey(context *clusterd.Context, keyPath, newKeyPath string, blockPaths []string) error {
+	for _, blockPath := range blockPaths {
+		if isEncryptionKeyValid(context, blockPath, newKeyPath) {
+			logger.Infof("new encryption key already opens encrypted device %q", blockPath)
+			continue
+		}
+		if err := addEncryptionKey(context, blockPath, keyPath, newKeyPath); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("successfully added new encryption key")
+	return nil
+}
+
+// removeOldEncryptionKey removes the keyslot of the old key from each of the block devices. A device is only modified
+// if the new key opens it, and a device the old key does not open anymore was already handled by a previous attempt.
+func removeOldEncryptionKey(context *clusterd.Context, oldKeyPath, keyPath string, blockPaths []string) error {
+	for _, blockPath := range blockPaths {
+		if !isEncryptionKeyValid(context, blockPath, keyPath) {
+			return errors.Errorf("refusing to remove old keyslot of encrypted device %q, the new key does not open it", blockPath)
+		}
+	}
+
+	for _, blockPath := range blockPaths {
+		if !isEncryptionKeyValid(context, blockPath, oldKeyPath) {
+			logger.Infof("old encryption key already removed from encrypted device %q", blockPath)
+			continue
+		}
+		if err := removeEncryptionKey(context, blockPath, oldKeyPath); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("successfully removed old encryption key")
+	return nil
+}
+
+// isEncryptionKeyValid returns whether the key opens the encrypted device
+func isEncryptionKeyValid(context *clusterd.Context, blockPath, keyPath string) bool {
+	args := []string{"--verbose", "luksOpen", "--test-passphrase", "--key-file", keyPath, blockPath}
+	_, err := context.Executor.ExecuteCommandWithCombinedOutput(cryptsetupBinary, args...)
+	return err == nil
+}
+
+func addEncryptionKey(context *clusterd.Context, blockPath, keyPath, newKeyPath string) error {
+	args := []string{"--verbose", "luksAddKey", "--key-file", keyPath, blockPath, newKeyPath}
+	cryptsetupOut, err := context.Executor.ExecuteCommandWithCombinedOutput(cryptsetupBinary, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to add new keyslot to encrypted device %q. %s", blockPath, cryptsetupOut)
+	}
+
+	logger.Info(cryptsetupOut)
+	return nil
+}
+
+func removeEncryptionKey(context *clusterd.Context, blockPath, keyPath string) error {
+	args := []string{"--verbose", "luksRemoveKey", blockPath, keyPath}
+	cryptsetupOut, err := context.Executor.ExecuteCommandWithCombinedOutput(cryptsetupBinary, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove keyslot from encrypted device %q. %s", blockPath, cryptsetupOut)
+	}
+
+	logger.Info(cryptsetupOut)
+	return nil
+}
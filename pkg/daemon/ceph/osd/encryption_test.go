@@ -17,10 +17,15 @@ limitations under the License.
 package osd
 
 import (
+	"io/ioutil"
+	"os"
+	"path"
 	"testing"
 
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
+	oposd "github.com/rook/rook/pkg/operator/ceph/cluster/osd"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -40,3 +45,151 @@ func TestCloseEncryptedDevice(t *testing.T) {
 	err := closeEncryptedDevice(context, "/dev/mapper/ceph-43e9efed-0676-4731-b75a-a4c42ece1bb1-xvdbr-block-dmcrypt")
 	assert.NoError(t, err)
 }
+
+func TestRotateEncryptionKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotate")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	keyPath := path.Join(dir, "luks_key")
+	newKeyPath := keyPath + oposd.NewEncryptionKeyFileSuffix
+	// the keys are mounted by the operator
+	assert.NoError(t, ioutil.WriteFile(keyPath, []byte("old"), 0400))
+	assert.NoError(t, ioutil.WriteFile(newKeyPath, []byte("new"), 0400))
+
+	// the keys each keyslot of the device is opened with
+	keyslots := map[string][]string{"/set1-data-0": {"old"}, "/set1-metadata-0": {"old"}}
+	failAdd := false
+	failRemoval := false
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithCombinedOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("%s %v", command, args)
+		if command != "cryptsetup" {
+			return "", errors.Errorf("unknown command %s %s", command, args)
+		}
+		switch args[1] {
+		case "luksOpen":
+			key, _ := ioutil.ReadFile(args[4])
+			for _, k := range keyslots[args[5]] {
+				if k == string(key) {
+					return "Key slot 0 unlocked.", nil
+				}
+			}
+			return "", errors.New("no key available with this passphrase")
+		case "luksAddKey":
+			if failAdd {
+				return "", errors.New("failed")
+			}
+			key, _ := ioutil.ReadFile(args[3])
+			newKey, _ := ioutil.ReadFile(args[5])
+			assert.Contains(t, keyslots[args[4]], string(key))
+			keyslots[args[4]] = append(keyslots[args[4]], string(newKey))
+			return "Key slot 1 created.", nil
+		case "luksRemoveKey":
+			if failRemoval {
+				return "", errors.New("failed")
+			}
+			key, _ := ioutil.ReadFile(args[3])
+			for i, k := range keyslots[args[2]] {
+				if k == string(key) {
+					keyslots[args[2]] = append(keyslots[args[2]][:i], keyslots[args[2]][i+1:]...)
+				}
+			}
+			return "Key slot 0 removed.", nil
+		}
+		return "", errors.Errorf("unknown command %s %s", command, args)
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	// the new key cannot be added, the old key still opens the devices
+	failAdd = true
+	err = RotateEncryptionKey(context, "rook-ceph-osd-encryption-key-set1-data-0", keyPath, []string{"/set1-data-0", "/set1-metadata-0"})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"old"}, keyslots["/set1-data-0"])
+
+	// the step is retried after the first device was given the new key, the old keyslots are kept
+	failAdd = false
+	keyslots["/set1-data-0"] = []string{"old", "new"}
+	err = RotateEncryptionKey(context, "rook-ceph-osd-encryption-key-set1-data-0", keyPath, []string{"/set1-data-0", "/set1-metadata-0"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"old", "new"}, keyslots["/set1-data-0"])
+	assert.Equal(t, []string{"old", "new"}, keyslots["/set1-metadata-0"])
+
+	// the old keyslots are removed once the operator stored the new key
+	failRemoval = true
+	err = RemoveOldEncryptionKey(context, keyPath, []string{"/set1-data-0", "/set1-metadata-0"})
+	assert.Error(t, err)
+	failRemoval = false
+	err = RemoveOldEncryptionKey(context, keyPath, []string{"/set1-data-0", "/set1-metadata-0"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"new"}, keyslots["/set1-data-0"])
+	assert.Equal(t, []string{"new"}, keyslots["/set1-metadata-0"])
+
+	// a device the new key does not open keeps its old keyslot
+	keyslots["/set1-data-0"] = []string{"old"}
+	err = RemoveOldEncryptionKey(context, keyPath, []string{"/set1-data-0", "/set1-metadata-0"})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"old"}, keyslots["/set1-data-0"])
+
+	// the mounted keys are left in place
+	_, err = os.Stat(keyPath)
+	assert.NoError(t, err)
+
+	//
+	// with a KMS the job stores the new key itself
+	//
+	km := &fakeKeyManager{secrets: map[string]string{"key": "old"}}
+	keyslots = map[string][]string{"/set1-data-0": {"old"}}
+	kmsKeyPath := path.Join(dir, "kms_key")
+
+	// the old keyslot cannot be removed, the old key is saved in the kms
+	failRemoval = true
+	err = rotateKMSEncryptionKey(context, km, "key", kmsKeyPath, []string{"/set1-data-0"})
+	assert.Error(t, err)
+	newKey := km.secrets["key"]
+	assert.NotEqual(t, "old", newKey)
+	assert.Equal(t, "old", km.secrets["key-old"])
+	assert.Equal(t, []string{"old", newKey}, keyslots["/set1-data-0"])
+
+	// the retry only removes the old keyslot
+	failRemoval = false
+	err = rotateKMSEncryptionKey(context, km, "key", kmsKeyPath, []string{"/set1-data-0"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"key": newKey}, km.secrets)
+	assert.Equal(t, []string{newKey}, keyslots["/set1-data-0"])
+
+	// the new key cannot be stored, its keyslot is removed
+	km.failPut = true
+	err = rotateKMSEncryptionKey(context, km, "key", kmsKeyPath, []string{"/set1-data-0"})
+	assert.Error(t, err)
+	assert.Equal(t, map[string]string{"key": newKey}, km.secrets)
+	assert.Equal(t, []string{newKey}, keyslots["/set1-data-0"])
+
+	// the keys are not left behind
+	_, err = os.Stat(kmsKeyPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+type fakeKeyManager struct {
+	secrets map[string]string
+	failPut bool
+}
+
+func (f *fakeKeyManager) GetSecret(name string) (string, error) {
+	if value, ok := f.secrets[name]; ok {
+		return value, nil
+	}
+	return "", kms.ErrKeyNotFound
+}
+
+func (f *fakeKeyManager) PutSecret(name, value string) error {
+	if f.failPut {
+		return errors.New("failed")
+	}
+	f.secrets[name] = value
+	return nil
+}
+
+func (f *fakeKeyManager) DeleteSecret(name string) error {
+	delete(f.secrets, name)
+	return nil
+}
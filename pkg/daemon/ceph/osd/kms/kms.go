@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kms stores and retrieves the dm-crypt keys of encrypted OSDs in an external Key Management System
+package kms
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ProviderKey is the connection details key selecting the KMS implementation
+	ProviderKey = "KMS_PROVIDER"
+	// TokenSecretKey is the key of the KMS token in the Secret referenced by the KMS spec
+	// #nosec G101 since this is not leaking any hardcoded credentials, it's just the secret key name
+	TokenSecretKey = "token"
+)
+
+var (
+	logger = capnslog.NewPackageLogger("github.com/rook/rook", "op-kms")
+
+	// ErrKeyNotFound is returned when the KMS does not hold the requested key
+	ErrKeyNotFound = errors.New("key not found in the kms")
+)
+
+// KeyManager is implemented by each Key Management System able to hold the OSD dm-crypt keys
+type KeyManager interface {
+	// GetSecret returns the value of the secret, or ErrKeyNotFound if it does not exist
+	GetSecret(name string) (string, error)
+	// PutSecret stores the value of the secret, overwriting any existing value
+	PutSecret(name, value string) error
+	// DeleteSecret removes the secret from the KMS
+	DeleteSecret(name string) error
+}
+
+// NewKeyManager returns the key manager selected by the KMS_PROVIDER of the connection details
+func NewKeyManager(connectionDetails map[string]string) (KeyManager, error) {
+	provider := connectionDetails[ProviderKey]
+	switch provider {
+	case VaultProvider:
+		return newVault(connectionDetails)
+	case "":
+		return nil, errors.Errorf("no kms provider set, %q must be part of the connection details", ProviderKey)
+	default:
+		return nil, errors.Errorf("unsupported kms provider %q", provider)
+	}
+}
+
+// IsEnabledFromEnv returns whether the operator passed KMS connection details to this pod
+func IsEnabledFromEnv() bool {
+	return os.Getenv(ProviderKey) != ""
+}
+
+// ConnectionDetailsFromEnv rebuilds the KMS connection details the operator passed to this pod with EnvVars()
+func ConnectionDetailsFromEnv() map[string]string {
+	details := map[string]string{}
+	for _, env := range os.Environ() {
+		kv := strings.SplitN(env, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if kv[0] == ProviderKey || strings.HasPrefix(kv[0], vaultEnvPrefix) {
+			details[kv[0]] = kv[1]
+		}
+	}
+
+	return details
+}
+
+// EnvVars returns the environment variables carrying the KMS connection details into a pod
+func EnvVars(kmsSpec cephv1.KeyManagementServiceSpec) []v1.EnvVar {
+	// Sort the keys so the pod spec does not change between reconciles
+	keys := make([]string, 0, len(kmsSpec.ConnectionDetails))
+	for k := range kmsSpec.ConnectionDetails {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	envs := []v1.EnvVar{}
+	for _, k := range keys {
+		envs = append(envs, v1.EnvVar{Name: k, Value: kmsSpec.ConnectionDetails[k]})
+	}
+
+	if kmsSpec.IsTokenAuthEnabled() {
+		envs = append(envs, v1.EnvVar{
+			Name: vaultTokenKey,
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: kmsSpec.TokenSecretName},
+					Key:                  TokenSecretKey,
+				},
+			},
+		})
+	}
+
+	return envs
+}
+
+// ValidateConnectionDetails validates the KMS settings of the cluster spec before they are handed to the OSD pods
+func ValidateConnectionDetails(clientset kubernetes.Interface, namespace string, kmsSpec *cephv1.KeyManagementServiceSpec) error {
+	provider := kmsSpec.ConnectionDetails[ProviderKey]
+	switch provider {
+	case VaultProvider:
+		if err := validateVaultConnectionDetails(kmsSpec); err != nil {
+			return err
+		}
+	case "":
+		return errors.Errorf("failed to validate kms config, %q is not set", ProviderKey)
+	default:
+		return errors.Errorf("failed to validate kms config, unsupported kms provider %q", provider)
+	}
+
+	if kmsSpec.IsTokenAuthEnabled() {
+		s, err := clientset.CoreV1().Secrets(namespace).Get(kmsSpec.TokenSecretName, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch kms token secret %q", kmsSpec.TokenSecretName)
+		}
+		if len(s.Data[TokenSecretKey]) == 0 {
+			return errors.Errorf("failed to read kms token, key %q is empty in secret %q", TokenSecretKey, kmsSpec.TokenSecretName)
+		}
+	}
+
+	return nil
+}
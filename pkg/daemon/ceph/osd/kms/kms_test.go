@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kms
+
+import (
+	"os"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEnvVars(t *testing.T) {
+	kmsSpec := cephv1.KeyManagementServiceSpec{
+		ConnectionDetails: map[string]string{
+			vaultAddressKey: "https://vault.default.svc:8200",
+			ProviderKey:     VaultProvider,
+		},
+		TokenSecretName: "vault-token",
+	}
+
+	envs := EnvVars(kmsSpec)
+	assert.Equal(t, 3, len(envs))
+	assert.Equal(t, ProviderKey, envs[0].Name)
+	assert.Equal(t, vaultAddressKey, envs[1].Name)
+	assert.Equal(t, vaultTokenKey, envs[2].Name)
+	assert.Equal(t, "vault-token", envs[2].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, TokenSecretKey, envs[2].ValueFrom.SecretKeyRef.Key)
+
+	// the variables are read back in the pod
+	for _, env := range envs[:2] {
+		os.Setenv(env.Name, env.Value)
+		defer os.Unsetenv(env.Name)
+	}
+	os.Setenv(vaultTokenKey, "s.root")
+	defer os.Unsetenv(vaultTokenKey)
+	assert.True(t, IsEnabledFromEnv())
+	details := ConnectionDetailsFromEnv()
+	assert.Equal(t, VaultProvider, details[ProviderKey])
+	assert.Equal(t, "https://vault.default.svc:8200", details[vaultAddressKey])
+	assert.Equal(t, "s.root", details[vaultTokenKey])
+}
+
+func TestValidateConnectionDetails(t *testing.T) {
+	clientset := test.New(t, 1)
+	kmsSpec := &cephv1.KeyManagementServiceSpec{ConnectionDetails: map[string]string{vaultAddressKey: "https://vault.default.svc:8200"}}
+
+	// no provider
+	err := ValidateConnectionDetails(clientset, "ns", kmsSpec)
+	assert.Error(t, err)
+
+	// unknown provider
+	kmsSpec.ConnectionDetails[ProviderKey] = "foo"
+	err = ValidateConnectionDetails(clientset, "ns", kmsSpec)
+	assert.Error(t, err)
+
+	// the token secret does not exist
+	kmsSpec.ConnectionDetails[ProviderKey] = VaultProvider
+	kmsSpec.TokenSecretName = "vault-token"
+	err = ValidateConnectionDetails(clientset, "ns", kmsSpec)
+	assert.Error(t, err)
+
+	s := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault-token", Namespace: "ns"},
+		Data:       map[string][]byte{TokenSecretKey: []byte("s.root")},
+	}
+	_, err = clientset.CoreV1().Secrets("ns").Create(s)
+	assert.NoError(t, err)
+	err = ValidateConnectionDetails(clientset, "ns", kmsSpec)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kms
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+)
+
+const (
+	// VaultProvider is the name of the HashiCorp Vault KMS provider
+	VaultProvider = "vault"
+
+	vaultEnvPrefix             = "VAULT_"
+	vaultAddressKey            = "VAULT_ADDR"
+	vaultBackendPathKey        = "VAULT_BACKEND_PATH"
+	vaultBackendKey            = "VAULT_BACKEND"
+	vaultNamespaceKey          = "VAULT_NAMESPACE"
+	vaultAuthMethodKey         = "VAULT_AUTH_METHOD"
+	vaultAuthKubernetesRoleKey = "VAULT_AUTH_KUBERNETES_ROLE"
+	vaultAuthMountPathKey      = "VAULT_AUTH_MOUNT_PATH"
+	vaultSkipVerifyKey         = "VAULT_SKIP_VERIFY"
+	vaultTLSServerNameKey      = "VAULT_TLS_SERVER_NAME"
+	// #nosec G101 since this is not leaking any hardcoded credentials, it's just the variable name
+	vaultTokenKey = "VAULT_TOKEN"
+
+	vaultAuthMethodToken      = "token"
+	vaultAuthMethodKubernetes = "kubernetes"
+	vaultKVv1                 = "v1"
+	vaultKVv2                 = "v2"
+	defaultVaultBackendPath   = "secret"
+	defaultVaultAuthMountPath = "kubernetes"
+	vaultRequestTimeout       = 30 * time.Second
+)
+
+var (
+	// serviceAccountTokenPath is where the pod service account token is mounted, used by the kubernetes auth method
+	// #nosec G101 since this is not leaking any hardcoded credentials, it's just the token path
+	serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// vault stores the keys in a HashiCorp Vault KV secret engine (version 1 or 2)
+type vault struct {
+	client        *http.Client
+	address       string
+	backendPath   string
+	kvVersion     string
+	namespace     string
+	authMethod    string
+	role          string
+	authMountPath string
+	token         string
+}
+
+type vaultKVResponse struct {
+	Data json.RawMessage `json:"data"`
+}
+
+type vaultKVv2Data struct {
+	Data map[string]string `json:"data"`
+}
+
+type vaultLoginResponse struct {
+	Auth struct {
+		ClientToken string `json:"client_token"`
+	} `json:"auth"`
+}
+
+func newVault(connectionDetails map[string]string) (*vault, error) {
+	v := &vault{
+		address:       strings.TrimSuffix(connectionDetails[vaultAddressKey], "/"),
+		backendPath:   strings.Trim(connectionDetails[vaultBackendPathKey], "/"),
+		kvVersion:     connectionDetails[vaultBackendKey],
+		namespace:     connectionDetails[vaultNamespaceKey],
+		authMethod:    connectionDetails[vaultAuthMethodKey],
+		role:          connectionDetails[vaultAuthKubernetesRoleKey],
+		authMountPath: strings.Trim(connectionDetails[vaultAuthMountPathKey], "/"),
+		token:         connectionDetails[vaultTokenKey],
+	}
+	if v.address == "" {
+		return nil, errors.Errorf("failed to configure vault, %q is not set", vaultAddressKey)
+	}
+	if v.backendPath == "" {
+		v.backendPath = defaultVaultBackendPath
+	}
+	if v.kvVersion == "" {
+		v.kvVersion = vaultKVv2
+	}
+	if v.authMethod == "" {
+		v.authMethod = vaultAuthMethodToken
+	}
+	if v.authMountPath == "" {
+		v.authMountPath = defaultVaultAuthMountPath
+	}
+
+	tlsConfig := &tls.Config{ServerName: connectionDetails[vaultTLSServerNameKey]}
+	if skip, ok := connectionDetails[vaultSkipVerifyKey]; ok {
+		skipVerify, err := strconv.ParseBool(skip)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %q", vaultSkipVerifyKey)
+		}
+		// #nosec G402 the user explicitly asked to skip the verification
+		tlsConfig.InsecureSkipVerify = skipVerify
+	}
+	v.client = &http.Client{
+		Timeout:   vaultRequestTimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+	}
+
+	if err := v.authenticate(); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func validateVaultConnectionDetails(kmsSpec *cephv1.KeyManagementServiceSpec) error {
+	details := kmsSpec.ConnectionDetails
+	if details[vaultAddressKey] == "" {
+		return errors.Errorf("failed to validate vault connection details, %q is not set", vaultAddressKey)
+	}
+	if kvVersion := details[vaultBackendKey]; kvVersion != "" && kvVersion != vaultKVv1 && kvVersion != vaultKVv2 {
+		return errors.Errorf("failed to validate vault connection details, unsupported %q %q. must be %q or %q", vaultBackendKey, kvVersion, vaultKVv1, vaultKVv2)
+	}
+
+	switch details[vaultAuthMethodKey] {
+	case "", vaultAuthMethodToken:
+		if !kmsSpec.IsTokenAuthEnabled() {
+			return errors.New("failed to validate vault connection details, tokenSecretName must be set with the token auth method")
+		}
+	case vaultAuthMethodKubernetes:
+		if details[vaultAuthKubernetesRoleKey] == "" {
+			return errors.Errorf("failed to validate vault connection details, %q must be set with the kubernetes auth method", vaultAuthKubernetesRoleKey)
+		}
+	default:
+		return errors.Errorf("failed to validate vault connection details, unsupported %q %q", vaultAuthMethodKey, details[vaultAuthMethodKey])
+	}
+
+	return nil
+}
+
+// authenticate ensures the client has a token, logging in with the pod service account when requested
+func (v *vault) authenticate() error {
+	switch v.authMethod {
+	case vaultAuthMethodToken:
+		if v.token == "" {
+			return errors.Errorf("failed to authenticate to vault, %q is not set", vaultTokenKey)
+		}
+		return nil
+	case vaultAuthMethodKubernetes:
+		jwt, err := ioutil.ReadFile(filepath.Clean(serviceAccountTokenPath))
+		if err != nil {
+			return errors.Wrap(err, "failed to read service account token for vault kubernetes auth")
+		}
+		body := map[string]string{"role": v.role, "jwt": strings.TrimSpace(string(jwt))}
+		out, err := v.do(http.MethodPost, fmt.Sprintf("auth/%s/login", v.authMountPath), body)
+		if err != nil {
+			return errors.Wrapf(err, "failed to log in to vault with role %q", v.role)
+		}
+		var login vaultLoginResponse
+		if err := json.Unmarshal(out, &login); err != nil {
+			return errors.Wrap(err, "failed to unmarshal vault login response")
+		}
+		if login.Auth.ClientToken == "" {
+			return errors.New("failed to log in to vault, no client token returned")
+		}
+		v.token = login.Auth.ClientToken
+		logger.Infof("logged in to vault with kubernetes role %q", v.role)
+		return nil
+	default:
+		return errors.Errorf("unsupported vault auth method %q", v.authMethod)
+	}
+}
+
+func (v *vault) dataPath(name string) string {
+	if v.kvVersion == vaultKVv1 {
+		return fmt.Sprintf("%s/%s", v.backendPath, name)
+	}
+	return fmt.Sprintf("%s/data/%s", v.backendPath, name)
+}
+
+// GetSecret returns the value of the secret stored in vault
+func (v *vault) GetSecret(name string) (string, error) {
+	out, err := v.do(http.MethodGet, v.dataPath(name), nil)
+	if err == ErrKeyNotFound {
+		return "", err
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get secret %q from vault", name)
+	}
+
+	var resp vaultKVResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal vault secret %q", name)
+	}
+
+	data := map[string]string{}
+	if v.kvVersion == vaultKVv1 {
+		err = json.Unmarshal(resp.Data, &data)
+	} else {
+		var kv2 vaultKVv2Data
+		err = json.Unmarshal(resp.Data, &kv2)
+		data = kv2.Data
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal vault secret %q data", name)
+	}
+
+	value, ok := data[name]
+	if !ok {
+		return "", ErrKeyNotFound
+	}
+	return value, nil
+}
+
+// PutSecret stores the value of the secret in vault
+func (v *vault) PutSecret(name, value string) error {
+	var body interface{} = map[string]string{name: value}
+	if v.kvVersion == vaultKVv2 {
+		body = map[string]interface{}{"data": body}
+	}
+
+	if _, err := v.do(http.MethodPost, v.dataPath(name), body); err != nil {
+		return errors.Wrapf(err, "failed to put secret %q in vault", name)
+	}
+	return nil
+}
+
+// DeleteSecret removes the secret from vault. With KV version 2 all the versions of the secret are destroyed.
+func (v *vault) DeleteSecret(name string) error {
+	p := v.dataPath(name)
+	if v.kvVersion == vaultKVv2 {
+		p = fmt.Sprintf("%s/metadata/%s", v.backendPath, name)
+	}
+
+	if _, err := v.do(http.MethodDelete, p, nil); err != nil && err != ErrKeyNotFound {
+		return errors.Wrapf(err, "failed to delete secret %q from vault", name)
+	}
+	return nil
+}
+
+// do sends a request to the vault HTTP API and returns the response body
+func (v *vault) do(method, apiPath string, body interface{}) ([]byte, error) {
+	var reader *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal vault request")
+		}
+		reader = bytes.NewReader(b)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("%s/v1/%s", v.address, apiPath), reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build vault request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if v.token != "" {
+		req.Header.Set("X-Vault-Token", v.token)
+	}
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to send %s request to vault", method)
+	}
+	defer resp.Body.Close()
+
+	out, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read vault response")
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrKeyNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Errorf("vault returned status %d. %s", resp.StatusCode, string(out))
+	}
+
+	return out, nil
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kms
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeVault is an in-memory stand-in for the vault KV and kubernetes auth HTTP APIs
+type fakeVault struct {
+	mutex     sync.Mutex
+	token     string
+	namespace string
+	role      string
+	jwt       string
+	kvVersion string
+	secrets   map[string]map[string]string
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.namespace != "" && r.Header.Get("X-Vault-Namespace") != f.namespace {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	if r.URL.Path == "/v1/auth/kubernetes/login" {
+		var login map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&login)
+		if login["role"] != f.role || login["jwt"] != f.jwt {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"auth": {"client_token": "` + f.token + `"}}`))
+		return
+	}
+
+	if r.Header.Get("X-Vault-Token") != f.token {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	p := strings.TrimPrefix(r.URL.Path, "/v1/secret/")
+	if f.kvVersion == vaultKVv2 {
+		if r.Method == http.MethodDelete {
+			p = strings.TrimPrefix(p, "metadata/")
+		} else {
+			p = strings.TrimPrefix(p, "data/")
+		}
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		data, ok := f.secrets[p]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var resp interface{} = map[string]interface{}{"data": data}
+		if f.kvVersion == vaultKVv2 {
+			resp = map[string]interface{}{"data": resp}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	case http.MethodPost:
+		body, _ := ioutil.ReadAll(r.Body)
+		data := map[string]string{}
+		if f.kvVersion == vaultKVv2 {
+			var kv2 vaultKVv2Data
+			_ = json.Unmarshal(body, &kv2)
+			data = kv2.Data
+		} else {
+			_ = json.Unmarshal(body, &data)
+		}
+		f.secrets[p] = data
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(f.secrets, p)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newFakeVault(kvVersion string) *fakeVault {
+	return &fakeVault{token: "s.root", kvVersion: kvVersion, secrets: map[string]map[string]string{}}
+}
+
+func TestVaultSecrets(t *testing.T) {
+	for _, kvVersion := range []string{vaultKVv1, vaultKVv2} {
+		fake := newFakeVault(kvVersion)
+		server := httptest.NewServer(fake)
+
+		km, err := NewKeyManager(map[string]string{
+			ProviderKey:     VaultProvider,
+			vaultAddressKey: server.URL,
+			vaultBackendKey: kvVersion,
+			vaultTokenKey:   "s.root",
+		})
+		assert.NoError(t, err)
+
+		// the key does not exist yet
+		_, err = km.GetSecret("rook-ceph-osd-encryption-key-set1-data-0")
+		assert.Equal(t, ErrKeyNotFound, err)
+
+		err = km.PutSecret("rook-ceph-osd-encryption-key-set1-data-0", "passphrase")
+		assert.NoError(t, err)
+		assert.Equal(t, "passphrase", fake.secrets["rook-ceph-osd-encryption-key-set1-data-0"]["rook-ceph-osd-encryption-key-set1-data-0"])
+
+		value, err := km.GetSecret("rook-ceph-osd-encryption-key-set1-data-0")
+		assert.NoError(t, err)
+		assert.Equal(t, "passphrase", value)
+
+		err = km.DeleteSecret("rook-ceph-osd-encryption-key-set1-data-0")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(fake.secrets))
+
+		// deleting a missing key is not an error
+		err = km.DeleteSecret("rook-ceph-osd-encryption-key-set1-data-0")
+		assert.NoError(t, err)
+
+		server.Close()
+	}
+}
+
+func TestVaultBadToken(t *testing.T) {
+	server := httptest.NewServer(newFakeVault(vaultKVv2))
+	defer server.Close()
+
+	km, err := NewKeyManager(map[string]string{ProviderKey: VaultProvider, vaultAddressKey: server.URL, vaultTokenKey: "s.wrong"})
+	assert.NoError(t, err)
+	err = km.PutSecret("foo", "bar")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+
+	// no token at all
+	_, err = NewKeyManager(map[string]string{ProviderKey: VaultProvider, vaultAddressKey: server.URL})
+	assert.Error(t, err)
+}
+
+func TestVaultKubernetesAuth(t *testing.T) {
+	fake := newFakeVault(vaultKVv2)
+	fake.role = "rook-ceph-osd"
+	fake.jwt = "service-account-jwt"
+	fake.namespace = "ns1"
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "kms")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	serviceAccountTokenPath = path.Join(dir, "token")
+	err = ioutil.WriteFile(serviceAccountTokenPath, []byte("service-account-jwt\n"), 0600)
+	assert.NoError(t, err)
+
+	details := map[string]string{
+		ProviderKey:                VaultProvider,
+		vaultAddressKey:            server.URL,
+		vaultNamespaceKey:          "ns1",
+		vaultAuthMethodKey:         vaultAuthMethodKubernetes,
+		vaultAuthKubernetesRoleKey: "rook-ceph-osd",
+	}
+	km, err := NewKeyManager(details)
+	assert.NoError(t, err)
+	assert.NoError(t, km.PutSecret("foo", "bar"))
+	value, err := km.GetSecret("foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", value)
+
+	// wrong role
+	details[vaultAuthKubernetesRoleKey] = "other"
+	_, err = NewKeyManager(details)
+	assert.Error(t, err)
+}
+
+func TestValidateVaultConnectionDetails(t *testing.T) {
+	kmsSpec := &cephv1.KeyManagementServiceSpec{ConnectionDetails: map[string]string{ProviderKey: VaultProvider}}
+	assert.Error(t, validateVaultConnectionDetails(kmsSpec))
+
+	kmsSpec.ConnectionDetails[vaultAddressKey] = "https://vault.default.svc:8200"
+	assert.Error(t, validateVaultConnectionDetails(kmsSpec))
+
+	kmsSpec.TokenSecretName = "vault-token"
+	assert.NoError(t, validateVaultConnectionDetails(kmsSpec))
+
+	kmsSpec.ConnectionDetails[vaultBackendKey] = "v3"
+	assert.Error(t, validateVaultConnectionDetails(kmsSpec))
+	kmsSpec.ConnectionDetails[vaultBackendKey] = vaultKVv1
+
+	kmsSpec.TokenSecretName = ""
+	kmsSpec.ConnectionDetails[vaultAuthMethodKey] = vaultAuthMethodKubernetes
+	assert.Error(t, validateVaultConnectionDetails(kmsSpec))
+	kmsSpec.ConnectionDetails[vaultAuthKubernetesRoleKey] = "rook-ceph-osd"
+	assert.NoError(t, validateVaultConnectionDetails(kmsSpec))
+}
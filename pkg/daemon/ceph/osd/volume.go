@@ -40,6 +40,7 @@ import (
 const (
 	osdsPerDeviceFlag    = "--osds-per-device"
 	crushDeviceClassFlag = "--crush-device-class"
+	osdIDFlag            = "--osd-id"
 	encryptedFlag        = "--dmcrypt"
 	databaseSizeFlag     = "--block-db-size"
 	dbDeviceFlag         = "--db-devices"
@@ -305,6 +306,16 @@ func (a *OsdAgent) initializeBlockPVC(context *clusterd.Context, devices *Device
 				immediateExecuteArgs = append(immediateExecuteArgs, []string{crushDeviceClassFlag, crushDeviceClass}...)
 			}
 
+			// The OSD replacing a destroyed OSD reuses its ID
+			replaceOSDID := os.Getenv(oposd.ReplaceOSDIDVarName)
+			if replaceOSDID != "" {
+				if cephVolumeMode == "raw" && !a.clusterInfo.CephVersion.IsAtLeast(oposd.ReplaceOSDRawModeMinCephVersion) {
+					return "", "", "", errors.Errorf("failed to re-create destroyed osd.%s, ceph-volume raw mode reuses the osd ids since ceph %s", replaceOSDID, oposd.ReplaceOSDRawModeMinCephVersion.String())
+				}
+				logger.Infof("re-creating destroyed osd.%s on device %q", replaceOSDID, device.Config.Name)
+				immediateExecuteArgs = append(immediateExecuteArgs, []string{osdIDFlag, replaceOSDID}...)
+			}
+
 			if isEncrypted {
 				immediateExecuteArgs = append(immediateExecuteArgs, encryptedFlag)
 			}
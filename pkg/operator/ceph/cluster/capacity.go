@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	defaultNearFullPercent = 75
+	defaultFullPercent     = 85
+
+	capacityNearFullReason = "NearFull"
+	capacityFullReason     = "Full"
+	capacityNormalReason   = "CapacityNormal"
+)
+
+// capacityLevel is how full the cluster, a device class or a pool is
+type capacityLevel int
+
+const (
+	capacityNormal capacityLevel = iota
+	capacityNearFull
+	capacityFull
+)
+
+// getCapacity returns the capacity of the cluster from its status, with the capacity of the device classes
+// and the usage of the pools if they can be retrieved
+func (c *cephStatusChecker) getCapacity(status *cephclient.CephStatus) *cephv1.Capacity {
+	capacity := &cephv1.Capacity{
+		TotalBytes:     status.PgMap.TotalBytes,
+		UsedBytes:      status.PgMap.UsedBytes,
+		AvailableBytes: status.PgMap.AvailableBytes,
+		UsedPercent:    usedPercent(status.PgMap.UsedBytes, status.PgMap.TotalBytes),
+		LastUpdated:    formatTime(time.Now().UTC()),
+	}
+
+	stats, err := cephclient.GetPoolStats(c.context, c.clusterInfo)
+	if err != nil {
+		logger.Warningf("failed to get the capacity of the device classes and pools. %v", err)
+		return capacity
+	}
+	for name, class := range stats.StatsByClass {
+		capacity.DeviceClasses = append(capacity.DeviceClasses, cephv1.DeviceClassCapacity{
+			Name:           name,
+			TotalBytes:     class.TotalBytes,
+			UsedBytes:      class.TotalUsedRawBytes,
+			AvailableBytes: class.TotalAvailBytes,
+			UsedPercent:    usedPercent(class.TotalUsedRawBytes, class.TotalBytes),
+		})
+	}
+	sort.Slice(capacity.DeviceClasses, func(i, j int) bool {
+		return capacity.DeviceClasses[i].Name < capacity.DeviceClasses[j].Name
+	})
+	for _, pool := range stats.Pools {
+		capacity.Pools = append(capacity.Pools, cephv1.PoolCapacity{
+			Name:           pool.Name,
+			StoredBytes:    uint64(pool.Stats.Stored),
+			UsedBytes:      uint64(pool.Stats.BytesUsed),
+			AvailableBytes: uint64(pool.Stats.MaxAvail),
+			Objects:        uint64(pool.Stats.Objects),
+			UsedPercent:    int(math.Round(pool.Stats.PercentUsed * 100)),
+		})
+	}
+	return capacity
+}
+
+func usedPercent(used, total uint64) int {
+	if total == 0 {
+		return 0
+	}
+	return int(used * 100 / total)
+}
+
+// capacityThresholds returns the near full and full thresholds of the cluster spec
+func capacityThresholds(spec cephv1.CapacityThresholdSpec) (int, int) {
+	nearFull := defaultNearFullPercent
+	if spec.NearFullPercent > 0 {
+		nearFull = spec.NearFullPercent
+	}
+	full := defaultFullPercent
+	if spec.FullPercent > 0 {
+		full = spec.FullPercent
+	}
+	return nearFull, full
+}
+
+// capacityLevels returns the level of the cluster, of each device class and of each pool by their
+// description in the events
+func capacityLevels(capacity *cephv1.Capacity, nearFull, full int) map[string]capacityLevel {
+	levels := map[string]capacityLevel{}
+	if capacity == nil {
+		return levels
+	}
+	level := func(usedPercent int) capacityLevel {
+		switch {
+		case usedPercent >= full:
+			return capacityFull
+		case usedPercent >= nearFull:
+			return capacityNearFull
+		}
+		return capacityNormal
+	}
+	levels["cluster"] = level(capacity.UsedPercent)
+	for _, class := range capacity.DeviceClasses {
+		levels[fmt.Sprintf("device class %q", class.Name)] = level(class.UsedPercent)
+	}
+	for _, pool := range capacity.Pools {
+		levels[fmt.Sprintf("pool %q", pool.Name)] = level(pool.UsedPercent)
+	}
+	return levels
+}
+
+// checkCapacity emits an event for each part of the cluster crossing a capacity threshold since the
+// previous check and reports the parts above the near full threshold in the NearFull condition
+func (c *cephStatusChecker) checkCapacity(cephCluster *cephv1.CephCluster, previous *cephv1.Capacity) {
+	capacity := cephCluster.Status.CephStatus.Capacity
+	nearFull, full := capacityThresholds(cephCluster.Spec.HealthCheck.Capacity)
+	previousLevels := capacityLevels(previous, nearFull, full)
+	levels := capacityLevels(capacity, nearFull, full)
+
+	names := []string{}
+	for name := range levels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	nearFullParts := []string{}
+	fullParts := []string{}
+	for _, name := range names {
+		level := levels[name]
+		switch level {
+		case capacityFull:
+			fullParts = append(fullParts, name)
+		case capacityNearFull:
+			nearFullParts = append(nearFullParts, name)
+		}
+		if level == previousLevels[name] {
+			continue
+		}
+		switch level {
+		case capacityFull:
+			c.recorder.Eventf(cephCluster, v1.EventTypeWarning, capacityFullReason, "The %s is above the full threshold of %d%%", name, full)
+		case capacityNearFull:
+			c.recorder.Eventf(cephCluster, v1.EventTypeWarning, capacityNearFullReason, "The %s is above the near full threshold of %d%%", name, nearFull)
+		default:
+			c.recorder.Eventf(cephCluster, v1.EventTypeNormal, capacityNormalReason, "The %s is below the near full threshold of %d%%", name, nearFull)
+		}
+	}
+
+	if len(fullParts) > 0 || len(nearFullParts) > 0 {
+		reason := capacityNearFullReason
+		messages := []string{}
+		if len(fullParts) > 0 {
+			reason = capacityFullReason
+			messages = append(messages, fmt.Sprintf("Above the full threshold of %d%%: %s", full, strings.Join(fullParts, ", ")))
+		}
+		if len(nearFullParts) > 0 {
+			messages = append(messages, fmt.Sprintf("Above the near full threshold of %d%%: %s", nearFull, strings.Join(nearFullParts, ", ")))
+		}
+		config.ConditionExport(c.context, c.clusterInfo.NamespacedName(), cephv1.ConditionNearFull, v1.ConditionTrue, reason, strings.Join(messages, ". "))
+		return
+	}
+
+	// only reset the condition once it was reported, not to add it to the clusters which were never near full
+	for _, condition := range cephCluster.Status.Conditions {
+		if condition.Type == cephv1.ConditionNearFull && condition.Status == v1.ConditionTrue {
+			config.ConditionExport(c.context, c.clusterInfo.NamespacedName(), cephv1.ConditionNearFull, v1.ConditionFalse, capacityNormalReason, "The used capacity is below the near full threshold")
+		}
+	}
+}
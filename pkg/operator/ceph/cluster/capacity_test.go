@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// this JSON was generated from `ceph df detail -f json` and chopped to the fields used by the operator
+const dfDetailJSON = `{"stats":{"total_bytes":32212254720,"total_avail_bytes":22548578304,"total_used_raw_bytes":9663676416},
+"stats_by_class":{"ssd":{"total_bytes":10737418240,"total_avail_bytes":1073741824,"total_used_raw_bytes":9663676416},"hdd":{"total_bytes":21474836480,"total_avail_bytes":21474836480,"total_used_raw_bytes":0}},
+"pools":[{"name":"replicapool","id":1,"stats":{"stored":3221225472,"objects":1024,"bytes_used":9663676416,"max_avail":357913941,"percent_used":0.9}}]}`
+
+func TestGetCapacity(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "df" && args[1] == "detail" {
+				return dfDetailJSON, nil
+			}
+			return "", nil
+		},
+	}
+	c := &cephStatusChecker{context: &clusterd.Context{Executor: executor}, clusterInfo: cephclient.AdminClusterInfo("ns")}
+	status := &cephclient.CephStatus{PgMap: cephclient.PgMap{TotalBytes: 32212254720, UsedBytes: 9663676416, AvailableBytes: 22548578304}}
+
+	capacity := c.getCapacity(status)
+	assert.Equal(t, uint64(32212254720), capacity.TotalBytes)
+	assert.Equal(t, uint64(9663676416), capacity.UsedBytes)
+	assert.Equal(t, uint64(22548578304), capacity.AvailableBytes)
+	assert.Equal(t, 30, capacity.UsedPercent)
+	assert.NotEqual(t, "", capacity.LastUpdated)
+	assert.Equal(t, []cephv1.DeviceClassCapacity{
+		{Name: "hdd", TotalBytes: 21474836480, AvailableBytes: 21474836480, UsedPercent: 0},
+		{Name: "ssd", TotalBytes: 10737418240, UsedBytes: 9663676416, AvailableBytes: 1073741824, UsedPercent: 90},
+	}, capacity.DeviceClasses)
+	assert.Equal(t, []cephv1.PoolCapacity{
+		{Name: "replicapool", StoredBytes: 3221225472, UsedBytes: 9663676416, AvailableBytes: 357913941, Objects: 1024, UsedPercent: 90},
+	}, capacity.Pools)
+}
+
+func TestCapacityLevels(t *testing.T) {
+	nearFull, full := capacityThresholds(cephv1.CapacityThresholdSpec{})
+	assert.Equal(t, 75, nearFull)
+	assert.Equal(t, 85, full)
+	nearFull, full = capacityThresholds(cephv1.CapacityThresholdSpec{NearFullPercent: 60, FullPercent: 70})
+	assert.Equal(t, 60, nearFull)
+	assert.Equal(t, 70, full)
+
+	assert.Equal(t, 0, len(capacityLevels(nil, 60, 70)))
+	capacity := &cephv1.Capacity{
+		UsedPercent:   30,
+		DeviceClasses: []cephv1.DeviceClassCapacity{{Name: "ssd", UsedPercent: 65}},
+		Pools:         []cephv1.PoolCapacity{{Name: "replicapool", UsedPercent: 70}},
+	}
+	assert.Equal(t, map[string]capacityLevel{
+		"cluster":            capacityNormal,
+		`device class "ssd"`: capacityNearFull,
+		`pool "replicapool"`: capacityFull,
+	}, capacityLevels(capacity, nearFull, full))
+}
+
+func TestCheckCapacity(t *testing.T) {
+	ns := "rook-ceph"
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: ns, Namespace: ns},
+		Status: cephv1.ClusterStatus{
+			Phase:      cephv1.ConditionReady,
+			CephStatus: &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{})
+	c := &clusterd.Context{
+		RookClientset: rookclient.NewSimpleClientset(cephCluster),
+		Client:        fake.NewFakeClientWithScheme(s, cephCluster),
+	}
+	clusterInfo := cephclient.AdminClusterInfo(ns)
+	clusterInfo.SetName(ns)
+	recorder := record.NewFakeRecorder(10)
+	checker := &cephStatusChecker{context: c, clusterInfo: clusterInfo, recorder: recorder}
+
+	// a pool crossing the near full threshold
+	previous := &cephv1.Capacity{UsedPercent: 50, Pools: []cephv1.PoolCapacity{{Name: "replicapool", UsedPercent: 70}}}
+	cephCluster.Status.CephStatus.Capacity = &cephv1.Capacity{UsedPercent: 50, Pools: []cephv1.PoolCapacity{{Name: "replicapool", UsedPercent: 80}}}
+	checker.checkCapacity(cephCluster, previous)
+	assert.Equal(t, `Warning NearFull The pool "replicapool" is above the near full threshold of 75%`, <-recorder.Events)
+	assert.Equal(t, 0, len(recorder.Events))
+	updated := &cephv1.CephCluster{}
+	assert.NoError(t, c.Client.Get(context.TODO(), clusterInfo.NamespacedName(), updated))
+	condition := findCondition(updated.Status.Conditions, cephv1.ConditionNearFull)
+	assert.NotNil(t, condition)
+	assert.Equal(t, v1.ConditionTrue, condition.Status)
+	assert.Equal(t, "NearFull", condition.Reason)
+	assert.Equal(t, `Above the near full threshold of 75%: pool "replicapool"`, condition.Message)
+	// the phase is not changed by the condition
+	assert.Equal(t, cephv1.ConditionReady, updated.Status.Phase)
+
+	// no new event while the pool remains near full, and the cluster crossing the full threshold
+	previous = cephCluster.Status.CephStatus.Capacity
+	cephCluster.Status.CephStatus.Capacity = &cephv1.Capacity{UsedPercent: 90, Pools: []cephv1.PoolCapacity{{Name: "replicapool", UsedPercent: 80}}}
+	checker.checkCapacity(cephCluster, previous)
+	assert.Equal(t, `Warning Full The cluster is above the full threshold of 85%`, <-recorder.Events)
+	assert.Equal(t, 0, len(recorder.Events))
+	assert.NoError(t, c.Client.Get(context.TODO(), clusterInfo.NamespacedName(), updated))
+	condition = findCondition(updated.Status.Conditions, cephv1.ConditionNearFull)
+	assert.Equal(t, "Full", condition.Reason)
+	assert.Equal(t, `Above the full threshold of 85%: cluster. Above the near full threshold of 75%: pool "replicapool"`, condition.Message)
+
+	// the condition is reset once the capacity is freed
+	previous = cephCluster.Status.CephStatus.Capacity
+	cephCluster.Status.CephStatus.Capacity = &cephv1.Capacity{UsedPercent: 40, Pools: []cephv1.PoolCapacity{{Name: "replicapool", UsedPercent: 40}}}
+	cephCluster.Status.Conditions = updated.Status.Conditions
+	checker.checkCapacity(cephCluster, previous)
+	assert.Equal(t, 2, len(recorder.Events))
+	assert.Equal(t, `Normal CapacityNormal The cluster is below the near full threshold of 75%`, <-recorder.Events)
+	assert.Equal(t, `Normal CapacityNormal The pool "replicapool" is below the near full threshold of 75%`, <-recorder.Events)
+	assert.NoError(t, c.Client.Get(context.TODO(), clusterInfo.NamespacedName(), updated))
+	condition = findCondition(updated.Status.Conditions, cephv1.ConditionNearFull)
+	assert.Equal(t, v1.ConditionFalse, condition.Status)
+	assert.Equal(t, "CapacityNormal", condition.Reason)
+}
+
+func findCondition(conditions []cephv1.Condition, conditionType cephv1.ConditionType) *cephv1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
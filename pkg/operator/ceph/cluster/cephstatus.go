@@ -31,6 +31,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -46,16 +47,18 @@ type cephStatusChecker struct {
 	interval    time.Duration
 	client      client.Client
 	isExternal  bool
+	recorder    record.EventRecorder
 }
 
 // newCephStatusChecker creates a new HealthChecker object
-func newCephStatusChecker(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec) *cephStatusChecker {
+func newCephStatusChecker(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec, recorder record.EventRecorder) *cephStatusChecker {
 	c := &cephStatusChecker{
 		context:     context,
 		clusterInfo: clusterInfo,
 		interval:    defaultStatusCheckInterval,
 		client:      context.Client,
 		isExternal:  clusterSpec.External.Enable,
+		recorder:    recorder,
 	}
 
 	// allow overriding the check interval with an env var on the operator
@@ -111,21 +114,22 @@ func (c *cephStatusChecker) checkStatus() {
 	if err != nil {
 		logger.Errorf("failed to get ceph status. %v", err)
 		condition, reason, message := c.conditionMessageReason(cephv1.ConditionFailure)
-		if err := c.updateCephStatus(cephStatusOnError(err.Error()), condition, reason, message); err != nil {
+		if err := c.updateCephStatus(cephStatusOnError(err.Error()), nil, condition, reason, message); err != nil {
 			logger.Errorf("failed to query cluster status in namespace %q. %v", c.clusterInfo.Namespace, err)
 		}
 		return
 	}
 
 	logger.Debugf("cluster status: %+v", status)
+	capacity := c.getCapacity(&status)
 	condition, reason, message := c.conditionMessageReason(cephv1.ConditionReady)
-	if err := c.updateCephStatus(&status, condition, reason, message); err != nil {
+	if err := c.updateCephStatus(&status, capacity, condition, reason, message); err != nil {
 		logger.Errorf("failed to query cluster status in namespace %q. %v", c.clusterInfo.Namespace, err)
 	}
 }
 
-// updateStatus updates an object with a given status
-func (c *cephStatusChecker) updateCephStatus(status *cephclient.CephStatus, condition cephv1.ConditionType, reason, message string) error {
+// updateStatus updates an object with a given status, the previous capacity is kept if the new one is nil
+func (c *cephStatusChecker) updateCephStatus(status *cephclient.CephStatus, capacity *cephv1.Capacity, condition cephv1.ConditionType, reason, message string) error {
 	clusterName := c.clusterInfo.NamespacedName()
 	cephCluster, err := c.context.RookClientset.CephV1().CephClusters(clusterName.Namespace).Get(clusterName.Name, metav1.GetOptions{})
 	if err != nil {
@@ -136,8 +140,16 @@ func (c *cephStatusChecker) updateCephStatus(status *cephclient.CephStatus, cond
 		return errors.Wrapf(err, "failed to retrieve ceph cluster %q in namespace %q to update status to %+v", clusterName.Name, clusterName.Namespace, status)
 	}
 
+	var previousCapacity *cephv1.Capacity
+	if cephCluster.Status.CephStatus != nil {
+		previousCapacity = cephCluster.Status.CephStatus.Capacity
+	}
+
 	// Update with Ceph Status
 	cephCluster.Status.CephStatus = toCustomResourceStatus(cephCluster.Status, status)
+	if capacity != nil {
+		cephCluster.Status.CephStatus.Capacity = capacity
+	}
 	cephCluster.Status.Phase = condition
 	if err := opcontroller.UpdateStatus(c.client, cephCluster); err != nil {
 		return errors.Wrapf(err, "failed to update cluster %q status", clusterName.Namespace)
@@ -146,6 +158,11 @@ func (c *cephStatusChecker) updateCephStatus(status *cephclient.CephStatus, cond
 	// Update condition
 	config.ConditionExport(c.context, c.clusterInfo.NamespacedName(), condition, v1.ConditionTrue, reason, message)
 
+	// Report the crossed capacity thresholds
+	if capacity != nil {
+		c.checkCapacity(cephCluster, previousCapacity)
+	}
+
 	logger.Debugf("ceph cluster %q status and condition updated to %+v, %v, %s, %s", clusterName.Namespace, status, v1.ConditionTrue, reason, message)
 	return nil
 }
@@ -166,6 +183,7 @@ func toCustomResourceStatus(currentStatus cephv1.ClusterStatus, newStatus *cephc
 	if currentStatus.CephStatus != nil {
 		s.PreviousHealth = currentStatus.CephStatus.PreviousHealth
 		s.LastChanged = currentStatus.CephStatus.LastChanged
+		s.Capacity = currentStatus.CephStatus.Capacity
 		if currentStatus.CephStatus.Health != s.Health {
 			s.PreviousHealth = currentStatus.CephStatus.Health
 			s.LastChanged = s.LastChecked
@@ -62,6 +62,8 @@ type cluster struct {
 	isUpgrade            bool
 	watchersActivated    bool
 	monitoringChannels   map[string]*clusterHealth
+	// stretchModePending is whether the stretch mode is waiting for the OSDs of the data zones to be enabled
+	stretchModePending bool
 }
 
 type clusterHealth struct {
@@ -141,6 +143,12 @@ func (c *cluster) doOrchestration(rookImage string, cephVersion cephver.CephVers
 		return errors.Wrap(err, "failed to execute post actions after all the ceph monitors started")
 	}
 
+	// Apply the ceph config options of the cluster spec in the mon config database
+	err = c.configureCephConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to apply the ceph config options")
+	}
+
 	// If this is an upgrade, notify all the child controllers
 	if c.isUpgrade {
 		logger.Info("upgrade in progress, notifying child CRs")
@@ -164,6 +172,12 @@ func (c *cluster) doOrchestration(rookImage string, cephVersion cephver.CephVers
 		return errors.Wrap(err, "failed to start ceph osds")
 	}
 
+	// Enable the stretch mode once the OSDs of the data zones are up, the reconcile is requeued until then
+	c.stretchModePending, err = c.mons.ConfigureArbiter()
+	if err != nil {
+		return errors.Wrap(err, "failed to configure the arbiter of the stretch cluster")
+	}
+
 	logger.Infof("done reconciling ceph cluster in namespace %q", c.Namespace)
 
 	// We should be done updating by now
@@ -264,6 +278,10 @@ func (c *ClusterController) configureLocalCephCluster(cluster *cluster, clusterO
 	}
 
 	// Set the condition to the cluster object
+	if cluster.stretchModePending {
+		config.ConditionExport(c.context, c.namespacedName, cephv1.ConditionReady, v1.ConditionTrue, "StretchModePending", "Cluster created successfully, the stretch mode is enabled once the OSDs of the data zones are up")
+		return nil
+	}
 	config.ConditionExport(c.context, c.namespacedName, cephv1.ConditionReady, v1.ConditionTrue, "ClusterCreated", "Cluster created successfully")
 
 	return nil
@@ -389,3 +407,23 @@ func (c *cluster) postMonStartupActions() error {
 
 	return nil
 }
+
+// configureCephConfig applies the ceph config options of the cluster spec and reports the options
+// rejected by the mons in the CephConfigApplied condition
+func (c *cluster) configureCephConfig() error {
+	rejected, err := config.SetCephConfig(c.context, c.ClusterInfo, c.Spec.CephConfig)
+	if err != nil {
+		return err
+	}
+	if rejected == nil {
+		// no ceph config option was ever set in the cluster spec
+		return nil
+	}
+	if len(rejected) > 0 {
+		message := fmt.Sprintf("Failed to apply ceph config options %s", strings.Join(rejected, ", "))
+		config.ConditionExport(c.context, c.ClusterInfo.NamespacedName(), cephv1.ConditionCephConfigApplied, v1.ConditionFalse, "CephConfigRejected", message)
+		return nil
+	}
+	config.ConditionExport(c.context, c.ClusterInfo.NamespacedName(), cephv1.ConditionCephConfigApplied, v1.ConditionTrue, "CephConfigApplied", "All the ceph config options are applied")
+	return nil
+}
@@ -40,6 +40,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -64,6 +65,8 @@ var (
 	logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
 	// disallowedHostDirectories directories which are not allowed to be used
 	disallowedHostDirectories = []string{"/etc/ceph", "/rook", "/var/log/ceph"}
+	// waitForRequeueIfStretchModePending waits for the OSDs of the data zones of a stretch cluster
+	waitForRequeueIfStretchModePending = reconcile.Result{Requeue: true, RequeueAfter: time.Minute}
 )
 
 // List of object resources to watch by the controller
@@ -92,6 +95,7 @@ type ClusterController struct {
 	osdChecker              *osd.OSDHealthMonitor
 	client                  client.Client
 	namespacedName          types.NamespacedName
+	recorder                record.EventRecorder
 }
 
 // ReconcileCephCluster reconciles a CephFilesystem object
@@ -116,6 +120,9 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, clusterContro
 		panic(err)
 	}
 
+	// The events of the cluster are recorded by the health checkers
+	clusterController.recorder = mgr.GetEventRecorderFor(controllerName)
+
 	return &ReconcileCephCluster{
 		client:            mgr.GetClient(),
 		scheme:            mgrScheme,
@@ -184,6 +191,22 @@ func add(mgr manager.Manager, r reconcile.Reconciler, context *clusterd.Context)
 		return err
 	}
 
+	// Watch for the expansion of the volumes of the OSDs on PVC and the deletion of the PVCs of the replaced OSDs
+	err = c.Watch(
+		&source.Kind{
+			Type: &corev1.PersistentVolumeClaim{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "PersistentVolumeClaim",
+					APIVersion: corev1.SchemeGroupVersion.String(),
+				},
+			},
+		},
+		&handler.EnqueueRequestsFromMapFunc{ToRequests: handerFunc},
+		predicateForOSDPVCWatcher(mgr.GetClient()))
+	if err != nil {
+		return err
+	}
+
 	// Watch for changes on the hotplug config map
 	// TODO: to improve, can we run this against the operator namespace only?
 	disableVal := os.Getenv(disableHotplugEnv)
@@ -303,6 +326,12 @@ func (r *ReconcileCephCluster) reconcile(request reconcile.Request) (reconcile.R
 		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile cluster %q", cephCluster.Name)
 	}
 
+	// Requeue until the stretch mode is enabled, it waits for the OSDs of the data zones
+	if cluster, ok := r.clusterController.clusterMap[cephCluster.Namespace]; ok && cluster.stretchModePending {
+		logger.Infof("stretch mode of cluster %q not enabled yet, retrying in %q", cephCluster.Name, waitForRequeueIfStretchModePending.RequeueAfter.String())
+		return waitForRequeueIfStretchModePending, nil
+	}
+
 	// Return and do not requeue
 	return reconcile.Result{}, nil
 }
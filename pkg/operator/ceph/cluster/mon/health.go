@@ -336,8 +336,8 @@ func (c *Cluster) failoverMon(name string) error {
 		}
 	}()
 
-	// Start a new monitor
-	m := c.newMonConfig(c.maxMonID + 1)
+	// Start a new monitor, in the same zone as the failed mon in a stretch cluster or preferably in an empty zone
+	m := c.newMonConfig(c.maxMonID+1, c.failoverZone(name))
 	logger.Infof("starting new mon: %+v", m)
 
 	mConf := []*monConfig{m}
@@ -372,6 +372,13 @@ func (c *Cluster) failoverMon(name string) error {
 	c.maxMonID++
 	newMonSucceeded = true
 
+	// The new mon takes over the location of the failed mon, and its role of tiebreaker if the mon was in the arbiter zone
+	if c.spec.Mon.IsStretchCluster() {
+		if err := c.failoverStretchMon(name, m); err != nil {
+			return errors.Wrapf(err, "failed to configure new mon %s of the stretch cluster", m.DaemonName)
+		}
+	}
+
 	return c.removeMon(name)
 }
 
@@ -400,6 +407,7 @@ func (c *Cluster) removeMon(daemonName string) error {
 	delete(c.ClusterInfo.Monitors, daemonName)
 
 	delete(c.mapping.Node, daemonName)
+	delete(c.mapping.Zone, daemonName)
 
 	// Remove the service endpoint
 	if err := c.context.Clientset.CoreV1().Services(c.Namespace).Delete(resourceName, options); err != nil {
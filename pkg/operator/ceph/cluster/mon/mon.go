@@ -141,11 +141,15 @@ type monConfig struct {
 	// DataPathMap is the mapping relationship between mon data stored on the host and mon data
 	// stored in containers.
 	DataPathMap *config.DataPathMap
+	// Zone is the zone of the mon when the mons are spread across zones
+	Zone string
 }
 
 // Mapping is mon node and port mapping
 type Mapping struct {
 	Node map[string]*NodeInfo `json:"node"`
+	// Zone is the mon->zone mapping of zone aware mons
+	Zone map[string]string `json:"zone,omitempty"`
 }
 
 // NodeInfo contains name and address of a node
@@ -203,6 +207,15 @@ func (c *Cluster) Start(clusterInfo *cephclient.ClusterInfo, rookVersion string,
 		return nil, errors.Wrap(err, "error checking pod memory")
 	}
 
+	if err := validateMonZones(&c.spec.Mon); err != nil {
+		return nil, errors.Wrap(err, "invalid mon zones")
+	}
+	if c.spec.Mon.IsStretchCluster() {
+		if err := validateStretchCluster(&c.spec.Mon, cephVersion); err != nil {
+			return nil, errors.Wrap(err, "invalid stretch cluster settings")
+		}
+	}
+
 	logger.Infof("start running mons")
 
 	logger.Debugf("establishing ceph cluster info")
@@ -210,6 +223,15 @@ func (c *Cluster) Start(clusterInfo *cephclient.ClusterInfo, rookVersion string,
 		return nil, errors.Wrap(err, "failed to initialize ceph cluster info")
 	}
 
+	// the zones of the mons are decided when the mons are created
+	if c.spec.Mon.IsStretchCluster() {
+		for name := range c.ClusterInfo.Monitors {
+			if c.mapping.Zone[name] == "" {
+				return nil, errors.Errorf("mon %q has no zone, the stretch mode can only be configured when creating a cluster", name)
+			}
+		}
+	}
+
 	logger.Infof("targeting the mon count %d", c.spec.Mon.Count)
 
 	// create the mons for a new cluster or ensure mons are running in an existing cluster
@@ -281,6 +303,13 @@ func (c *Cluster) startMons(targetCount int) error {
 		}
 	}
 
+	// Set the location of the mons in the CRUSH hierarchy, the stretch mode is enabled after the OSDs are created
+	if c.spec.Mon.IsStretchCluster() {
+		if err := c.setMonsLocation(mons); err != nil {
+			return errors.Wrap(err, "failed to set the location of the mons of the stretch cluster")
+		}
+	}
+
 	logger.Debugf("mon endpoints used are: %s", FlattenMonEndpoints(c.ClusterInfo.Monitors))
 	return nil
 }
@@ -371,6 +400,7 @@ func (c *Cluster) initMonConfig(size int) (int, []*monConfig) {
 			Port:         cephutil.GetPortFromEndpoint(monitor.Endpoint),
 			DataPathMap: config.NewStatefulDaemonDataPathMap(
 				c.spec.DataDirHostPath, dataDirRelativeHostPath(monitor.Name), config.MonType, monitor.Name, c.Namespace),
+			Zone: c.mapping.Zone[monitor.Name],
 		})
 	}
 
@@ -378,13 +408,13 @@ func (c *Cluster) initMonConfig(size int) (int, []*monConfig) {
 	existingCount := len(c.ClusterInfo.Monitors)
 	for i := len(c.ClusterInfo.Monitors); i < size; i++ {
 		c.maxMonID++
-		mons = append(mons, c.newMonConfig(c.maxMonID))
+		mons = append(mons, c.newMonConfig(c.maxMonID, c.nextMonZone(mons, "")))
 	}
 
 	return existingCount, mons
 }
 
-func (c *Cluster) newMonConfig(monID int) *monConfig {
+func (c *Cluster) newMonConfig(monID int, zone string) *monConfig {
 	daemonName := k8sutil.IndexToName(monID)
 
 	return &monConfig{
@@ -393,6 +423,7 @@ func (c *Cluster) newMonConfig(monID int) *monConfig {
 		Port:         DefaultMsgr1Port,
 		DataPathMap: config.NewStatefulDaemonDataPathMap(
 			c.spec.DataDirHostPath, dataDirRelativeHostPath(daemonName), config.MonType, daemonName, c.Namespace),
+		Zone: zone,
 	}
 }
 
@@ -433,7 +464,7 @@ func realScheduleMonitor(c *Cluster, mon *monConfig) (SchedulingResult, error) {
 	d.Spec.Template.Spec.Containers[0].LivenessProbe = nil
 
 	// setup affinity settings for pod scheduling
-	p := cephv1.GetMonPlacement(c.spec.Placement)
+	p := c.getMonPlacement(mon.Zone)
 	k8sutil.SetNodeAntiAffinityForPod(&d.Spec.Template.Spec, p, requiredDuringScheduling(&c.spec), PreferredDuringScheduling,
 		map[string]string{k8sutil.AppAttr: AppName}, nil)
 
@@ -629,6 +660,16 @@ func (c *Cluster) assignMons(mons []*monConfig) error {
 		}
 
 		c.mapping.Node[mon.DaemonName] = nodeInfo
+
+		// the zone is recorded in the mapping to spread the mons across the zones on failover
+		if zone := c.scheduledZone(mon, nodeChoice); zone != "" {
+			if c.mapping.Zone == nil {
+				c.mapping.Zone = map[string]string{}
+			}
+			logger.Infof("assignmon: mon %s assigned to zone %s", mon.DaemonName, zone)
+			mon.Zone = zone
+			c.mapping.Zone[mon.DaemonName] = zone
+		}
 	}
 
 	logger.Debug("assignmons: mons have been scheduled")
@@ -883,7 +924,7 @@ func (c *Cluster) startMon(m *monConfig, node *NodeInfo) error {
 	}
 
 	// placement settings from the CRD
-	p := cephv1.GetMonPlacement(c.spec.Placement)
+	p := c.getMonPlacement(m.Zone)
 
 	if deploymentExists {
 		// the existing deployment may have a node selector. if the cluster
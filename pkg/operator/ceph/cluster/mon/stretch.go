@@ -0,0 +1,187 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+)
+
+const (
+	// the number of zones of a stretch cluster, two data zones and an arbiter zone
+	stretchClusterZoneCount = 3
+	// stretchCrushRoot is the CRUSH root of the stretch rule
+	stretchCrushRoot = "default"
+)
+
+func validateStretchCluster(spec *cephv1.MonSpec, cephVersion cephver.CephVersion) error {
+	if !cephVersion.IsAtLeastPacific() {
+		return errors.Errorf("stretch mode requires ceph pacific or newer, found version %q", cephVersion.String())
+	}
+	if spec.Count != cephv1.StretchClusterMonCount {
+		return errors.Errorf("a stretch cluster requires %d mons, found %d", cephv1.StretchClusterMonCount, spec.Count)
+	}
+	if len(spec.StretchCluster.Zones) != stretchClusterZoneCount {
+		return errors.Errorf("a stretch cluster requires %d zones, found %d", stretchClusterZoneCount, len(spec.StretchCluster.Zones))
+	}
+
+	arbiters := 0
+	names := map[string]struct{}{}
+	for _, zone := range spec.StretchCluster.Zones {
+		if zone.Name == "" {
+			return errors.New("missing name of a stretch cluster zone")
+		}
+		if _, ok := names[zone.Name]; ok {
+			return errors.Errorf("duplicate stretch cluster zone %q", zone.Name)
+		}
+		names[zone.Name] = struct{}{}
+		if zone.Arbiter {
+			arbiters++
+		}
+	}
+	if arbiters != 1 {
+		return errors.Errorf("a stretch cluster requires exactly one arbiter zone, found %d", arbiters)
+	}
+
+	return nil
+}
+
+// nextStretchZone returns the zone of a new mon. The arbiter zone has a single mon and the data zones
+// have the other mons.
+func (c *Cluster) nextStretchZone(mons []*monConfig) string {
+	monsPerZone := map[string]int{}
+	for _, m := range mons {
+		monsPerZone[m.Zone]++
+	}
+
+	for _, zone := range c.spec.Mon.StretchCluster.Zones {
+		desired := (cephv1.StretchClusterMonCount - 1) / 2
+		if zone.Arbiter {
+			desired = 1
+		}
+		if monsPerZone[zone.Name] < desired {
+			return zone.Name
+		}
+	}
+
+	return ""
+}
+
+// setMonsLocation sets the zone of the mons in the CRUSH hierarchy
+func (c *Cluster) setMonsLocation(mons []*monConfig) error {
+	crushType := c.spec.Mon.StretchCluster.FailureDomainKey()
+	for _, m := range mons {
+		if m.Zone == "" {
+			continue
+		}
+		if err := client.SetMonStretchZone(c.context, c.ClusterInfo, m.DaemonName, crushType, client.NormalizeCrushName(m.Zone)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// failoverStretchMon configures a new mon that replaces a failed mon of a stretch cluster
+func (c *Cluster) failoverStretchMon(failedMon string, m *monConfig) error {
+	if err := c.setMonsLocation([]*monConfig{m}); err != nil {
+		return err
+	}
+	if m.Zone != c.spec.Mon.StretchCluster.ArbiterZone() {
+		return nil
+	}
+
+	// the tiebreaker cannot be removed, the new mon of the arbiter zone replaces it
+	quorumStatus, err := client.GetMonQuorumStatus(c.context, c.ClusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get mon quorum status")
+	}
+	if !quorumStatus.MonMap.StretchMode {
+		return nil
+	}
+	logger.Infof("replacing tiebreaker mon %q by mon %q", failedMon, m.DaemonName)
+	return client.SetNewTiebreaker(c.context, c.ClusterInfo, m.DaemonName)
+}
+
+// arbiterMon returns the name of the mon in the arbiter zone
+func (c *Cluster) arbiterMon() string {
+	arbiterZone := c.spec.Mon.StretchCluster.ArbiterZone()
+	for name := range c.ClusterInfo.Monitors {
+		if c.mapping.Zone[name] == arbiterZone {
+			return name
+		}
+	}
+	return ""
+}
+
+// ConfigureArbiter enables the stretch mode of a stretch cluster. The stretch mode can only be enabled after
+// the OSDs of the data zones are in the CRUSH map, returns whether it is still waiting for them.
+func (c *Cluster) ConfigureArbiter() (bool, error) {
+	if !c.spec.Mon.IsStretchCluster() {
+		return false, nil
+	}
+
+	c.acquireOrchestrationLock()
+	defer c.releaseOrchestrationLock()
+
+	quorumStatus, err := client.GetMonQuorumStatus(c.context, c.ClusterInfo)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get mon quorum status")
+	}
+	if quorumStatus.MonMap.StretchMode {
+		logger.Debugf("stretch mode is already enabled with tiebreaker mon %q", quorumStatus.MonMap.TiebreakerMon)
+		return false, nil
+	}
+
+	stretch := c.spec.Mon.StretchCluster
+	crushType := stretch.FailureDomainKey()
+	crushMap, err := client.GetCrushMap(c.context, c.ClusterInfo)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get crush map")
+	}
+	for _, zone := range stretch.Zones {
+		if zone.Arbiter {
+			continue
+		}
+		if !crushBucketExists(crushMap, crushType, client.NormalizeCrushName(zone.Name)) {
+			logger.Infof("waiting for the osds of zone %q in the crush map to enable the stretch mode", zone.Name)
+			return true, nil
+		}
+	}
+
+	if err := client.CreateStretchCrushRule(c.context, c.ClusterInfo, client.StretchCrushRuleName, stretchCrushRoot, crushType, stretch.GetSubFailureDomain()); err != nil {
+		return false, errors.Wrap(err, "failed to create the crush rule of the stretch cluster")
+	}
+
+	tiebreaker := c.arbiterMon()
+	if tiebreaker == "" {
+		return false, errors.Errorf("failed to find the mon of arbiter zone %q", stretch.ArbiterZone())
+	}
+
+	return false, client.EnableStretchMode(c.context, c.ClusterInfo, tiebreaker, client.StretchCrushRuleName, crushType)
+}
+
+func crushBucketExists(crushMap client.CrushMap, bucketType, name string) bool {
+	for _, bucket := range crushMap.Buckets {
+		if bucket.TypeName == bucketType && bucket.Name == name {
+			return true
+		}
+	}
+	return false
+}
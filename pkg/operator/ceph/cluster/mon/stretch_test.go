@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func testStretchCluster() *cephv1.StretchClusterSpec {
+	return &cephv1.StretchClusterSpec{
+		Zones: []cephv1.StretchClusterZoneSpec{
+			{Name: "a"},
+			{Name: "b"},
+			{Name: "arbiter", Arbiter: true},
+		},
+	}
+}
+
+func TestValidateStretchCluster(t *testing.T) {
+	spec := &cephv1.MonSpec{Count: 5, StretchCluster: testStretchCluster()}
+	assert.NoError(t, validateStretchCluster(spec, cephver.Pacific))
+
+	// stretch mode requires pacific
+	assert.Error(t, validateStretchCluster(spec, cephver.Octopus))
+
+	// five mons are required
+	spec.Count = 3
+	assert.Error(t, validateStretchCluster(spec, cephver.Pacific))
+	spec.Count = 5
+
+	// a single arbiter is required
+	spec.StretchCluster.Zones[0].Arbiter = true
+	assert.Error(t, validateStretchCluster(spec, cephver.Pacific))
+	spec.StretchCluster.Zones[0].Arbiter = false
+	spec.StretchCluster.Zones[2].Arbiter = false
+	assert.Error(t, validateStretchCluster(spec, cephver.Pacific))
+	spec.StretchCluster.Zones[2].Arbiter = true
+
+	// three unique zones are required
+	spec.StretchCluster.Zones[1].Name = "a"
+	assert.Error(t, validateStretchCluster(spec, cephver.Pacific))
+	spec.StretchCluster.Zones = spec.StretchCluster.Zones[1:]
+	assert.Error(t, validateStretchCluster(spec, cephver.Pacific))
+}
+
+func TestNextStretchZone(t *testing.T) {
+	c := &Cluster{spec: cephv1.ClusterSpec{Mon: cephv1.MonSpec{Count: 5, StretchCluster: testStretchCluster()}}}
+
+	mons := []*monConfig{}
+	expected := []string{"a", "a", "b", "b", "arbiter", ""}
+	for _, zone := range expected {
+		assert.Equal(t, zone, c.nextStretchZone(mons))
+		mons = append(mons, &monConfig{Zone: zone})
+	}
+
+	// the zone of a failed mon is filled again
+	mons = []*monConfig{{Zone: "a"}, {Zone: "a"}, {Zone: "b"}, {Zone: "arbiter"}}
+	assert.Equal(t, "b", c.nextStretchZone(mons))
+}
+
+func TestConfigureArbiter(t *testing.T) {
+	crushMap := `{"buckets": [{"id": -1, "name": "default", "type_name": "root"}, {"id": -2, "name": "a", "type_name": "zone"}]}`
+	enabled := false
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command string, outFileArg string, args ...string) (string, error) {
+			switch {
+			case args[0] == "quorum_status":
+				return clienttest.MonInQuorumResponse(), nil
+			case args[0] == "osd" && args[1] == "crush" && args[2] == "dump":
+				return crushMap, nil
+			case args[0] == "mon" && args[1] == "enable_stretch_mode":
+				assert.Equal(t, "c", args[2])
+				enabled = true
+			}
+			return "", nil
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			return "", errors.Errorf("unexpected command %q", command)
+		},
+	}
+	c := &Cluster{
+		context:     &clusterd.Context{Executor: executor},
+		ClusterInfo: clienttest.CreateTestClusterInfo(3),
+		spec:        cephv1.ClusterSpec{Mon: cephv1.MonSpec{Count: 5, StretchCluster: testStretchCluster()}},
+		mapping:     &Mapping{Zone: map[string]string{"a": "a", "b": "b", "c": "arbiter"}},
+	}
+
+	// stretch mode is not enabled until the osds of both data zones are in the crush map
+	waiting, err := c.ConfigureArbiter()
+	assert.NoError(t, err)
+	assert.True(t, waiting)
+	assert.False(t, enabled)
+
+	// the rule already exists so the crush map is not modified
+	crushMap = `{"buckets": [{"id": -1, "name": "default", "type_name": "root"}, {"id": -2, "name": "a", "type_name": "zone"},
+		{"id": -3, "name": "b", "type_name": "zone"}], "rules": [{"rule_id": 1, "rule_name": "default_stretch_cluster_rule"}]}`
+	waiting, err = c.ConfigureArbiter()
+	assert.NoError(t, err)
+	assert.False(t, waiting)
+	assert.True(t, enabled)
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookv1 "github.com/rook/rook/pkg/apis/rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func validateMonZones(spec *cephv1.MonSpec) error {
+	if spec.StretchCluster != nil && len(spec.Zones) > 0 {
+		return errors.New("the mon zones cannot be specified with a stretch cluster, the zones of the stretch cluster are used")
+	}
+
+	names := map[string]struct{}{}
+	for _, zone := range spec.Zones {
+		if zone.Name == "" {
+			return errors.New("missing name of a mon zone")
+		}
+		if _, ok := names[zone.Name]; ok {
+			return errors.Errorf("duplicate mon zone %q", zone.Name)
+		}
+		names[zone.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// monZones returns the zones across which the mons are spread. If the zones are not specified, they are the values
+// of the failure domain label of the nodes where the mons can run.
+func (c *Cluster) monZones() ([]string, error) {
+	if len(c.spec.Mon.Zones) > 0 {
+		zones := []string{}
+		for _, zone := range c.spec.Mon.Zones {
+			zones = append(zones, zone.Name)
+		}
+		return zones, nil
+	}
+
+	nodes, err := c.context.Clientset.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+
+	label := c.spec.Mon.GetFailureDomainLabel()
+	placement := cephv1.GetMonPlacement(c.spec.Placement)
+	found := map[string]struct{}{}
+	zones := []string{}
+	for _, node := range nodes.Items {
+		zone := node.Labels[label]
+		if zone == "" {
+			continue
+		}
+		if _, ok := found[zone]; ok {
+			continue
+		}
+		valid, err := k8sutil.ValidNode(node, placement)
+		if err != nil {
+			logger.Warningf("failed to validate node %q for mon placement. %v", node.Name, err)
+			continue
+		}
+		if valid {
+			found[zone] = struct{}{}
+			zones = append(zones, zone)
+		}
+	}
+	sort.Strings(zones)
+
+	return zones, nil
+}
+
+// nextMonZone returns the zone of a new mon, which is the zone with the fewest mons. The zone to avoid, e.g. the zone
+// of a failed mon, is only chosen if no other zone has as few mons.
+func (c *Cluster) nextMonZone(mons []*monConfig, avoid string) string {
+	if c.spec.Mon.IsStretchCluster() {
+		return c.nextStretchZone(mons)
+	}
+	if !c.spec.Mon.IsZoneAware() {
+		return ""
+	}
+
+	zones, err := c.monZones()
+	if err != nil {
+		logger.Warningf("failed to get the mon zones, the mon placement is not zone aware. %v", err)
+		return ""
+	}
+	if len(zones) == 0 {
+		logger.Warningf("no node found with label %q, the mon placement is not zone aware", c.spec.Mon.GetFailureDomainLabel())
+		return ""
+	}
+
+	monsPerZone := map[string]int{}
+	for _, m := range mons {
+		monsPerZone[m.Zone]++
+	}
+
+	next := ""
+	for _, zone := range zones {
+		if next == "" || monsPerZone[zone] < monsPerZone[next] || (monsPerZone[zone] == monsPerZone[next] && next == avoid) {
+			next = zone
+		}
+	}
+	return next
+}
+
+// failoverZone returns the zone of the mon replacing a failed mon. The mon of a stretch cluster stays in its zone,
+// otherwise an empty zone is preferred to the zone of the failed mon.
+func (c *Cluster) failoverZone(failedMon string) string {
+	if c.spec.Mon.IsStretchCluster() {
+		return c.mapping.Zone[failedMon]
+	}
+	if !c.spec.Mon.IsZoneAware() {
+		return ""
+	}
+
+	mons := []*monConfig{}
+	for name := range c.ClusterInfo.Monitors {
+		if name != failedMon {
+			mons = append(mons, &monConfig{DaemonName: name, Zone: c.mapping.Zone[name]})
+		}
+	}
+	return c.nextMonZone(mons, c.mapping.Zone[failedMon])
+}
+
+// scheduledZone returns the zone of the node where a mon is scheduled
+func (c *Cluster) scheduledZone(mon *monConfig, node *v1.Node) string {
+	if mon.Zone != "" || !c.spec.Mon.IsZoneAware() {
+		return mon.Zone
+	}
+	return node.Labels[c.spec.Mon.GetFailureDomainLabel()]
+}
+
+// getMonPlacement returns the placement of a mon, the mon of a zone is required to run in its zone
+func (c *Cluster) getMonPlacement(zone string) rookv1.Placement {
+	p := cephv1.GetMonPlacement(c.spec.Placement)
+	if zone == "" {
+		return p
+	}
+
+	requirement := v1.NodeSelectorRequirement{
+		Key:      c.spec.Mon.GetFailureDomainLabel(),
+		Operator: v1.NodeSelectorOpIn,
+		Values:   []string{zone},
+	}
+	if p.NodeAffinity == nil {
+		p.NodeAffinity = &v1.NodeAffinity{}
+	} else {
+		p.NodeAffinity = p.NodeAffinity.DeepCopy()
+	}
+
+	selector := p.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if selector == nil || len(selector.NodeSelectorTerms) == 0 {
+		p.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{{MatchExpressions: []v1.NodeSelectorRequirement{requirement}}},
+		}
+		return p
+	}
+
+	// the terms are ORed, so the zone is required by each of them
+	for i := range selector.NodeSelectorTerms {
+		selector.NodeSelectorTerms[i].MatchExpressions = append(selector.NodeSelectorTerms[i].MatchExpressions, requirement)
+	}
+	return p
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"fmt"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookv1 "github.com/rook/rook/pkg/apis/rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateMonZones(t *testing.T) {
+	spec := &cephv1.MonSpec{Count: 3, Zones: []cephv1.MonZoneSpec{{Name: "a"}, {Name: "b"}}}
+	assert.NoError(t, validateMonZones(spec))
+
+	spec.Zones = append(spec.Zones, cephv1.MonZoneSpec{Name: "a"})
+	assert.Error(t, validateMonZones(spec))
+
+	spec.Zones[2].Name = ""
+	assert.Error(t, validateMonZones(spec))
+
+	spec.Zones = spec.Zones[:2]
+	spec.StretchCluster = testStretchCluster()
+	assert.Error(t, validateMonZones(spec))
+}
+
+func TestNextMonZone(t *testing.T) {
+	c := &Cluster{spec: cephv1.ClusterSpec{Mon: cephv1.MonSpec{Count: 5}}}
+
+	// the mons are not zone aware by default
+	assert.Equal(t, "", c.nextMonZone([]*monConfig{}, ""))
+
+	// the mons are spread evenly across the zones
+	c.spec.Mon.Zones = []cephv1.MonZoneSpec{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	mons := []*monConfig{}
+	for _, zone := range []string{"a", "b", "c", "a", "b"} {
+		assert.Equal(t, zone, c.nextMonZone(mons, ""))
+		mons = append(mons, &monConfig{Zone: zone})
+	}
+
+	// the zone to avoid is only chosen when the other zones have more mons
+	mons = []*monConfig{{Zone: "b"}}
+	assert.Equal(t, "c", c.nextMonZone(mons, "a"))
+	mons = []*monConfig{{Zone: "b"}, {Zone: "c"}}
+	assert.Equal(t, "a", c.nextMonZone(mons, "a"))
+
+	// the zones are discovered from the node labels
+	clientset := test.New(t, 4)
+	for i, zone := range []string{"z2", "z1", "z2", ""} {
+		node, err := clientset.CoreV1().Nodes().Get(fmt.Sprintf("node%d", i), metav1.GetOptions{})
+		assert.NoError(t, err)
+		if zone != "" {
+			node.Labels = map[string]string{"failure-domain": zone}
+		}
+		_, err = clientset.CoreV1().Nodes().Update(node)
+		assert.NoError(t, err)
+	}
+	c.context = &clusterd.Context{Clientset: clientset}
+	c.spec.Mon.Zones = nil
+	c.spec.Mon.FailureDomainLabel = "failure-domain"
+	zones, err := c.monZones()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"z1", "z2"}, zones)
+	assert.Equal(t, "z2", c.nextMonZone([]*monConfig{{Zone: "z1"}}, ""))
+}
+
+func TestFailoverZone(t *testing.T) {
+	c := &Cluster{
+		ClusterInfo: clienttest.CreateTestClusterInfo(3),
+		spec:        cephv1.ClusterSpec{Mon: cephv1.MonSpec{Count: 3}},
+		mapping:     &Mapping{Zone: map[string]string{"a": "z1", "b": "z2", "c": "z3"}},
+	}
+	assert.Equal(t, "", c.failoverZone("a"))
+
+	// an empty zone is preferred to the zone of the failed mon
+	c.spec.Mon.Zones = []cephv1.MonZoneSpec{{Name: "z1"}, {Name: "z2"}, {Name: "z3"}, {Name: "z4"}}
+	assert.Equal(t, "z4", c.failoverZone("a"))
+	c.ClusterInfo.Monitors["d"] = &cephclient.MonInfo{Name: "d"}
+	c.mapping.Zone["d"] = "z4"
+	assert.Equal(t, "z1", c.failoverZone("a"))
+
+	// the mon of a stretch cluster stays in its zone
+	c.spec.Mon.Zones = nil
+	c.spec.Mon.StretchCluster = testStretchCluster()
+	assert.Equal(t, "z2", c.failoverZone("b"))
+}
+
+func TestScheduledZone(t *testing.T) {
+	c := &Cluster{spec: cephv1.ClusterSpec{Mon: cephv1.MonSpec{Count: 3}}}
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{cephv1.DefaultZoneFailureDomainLabel: "z1"}}}
+	assert.Equal(t, "", c.scheduledZone(&monConfig{}, node))
+
+	c.spec.Mon.FailureDomainLabel = cephv1.DefaultZoneFailureDomainLabel
+	assert.Equal(t, "z1", c.scheduledZone(&monConfig{}, node))
+	assert.Equal(t, "z2", c.scheduledZone(&monConfig{Zone: "z2"}, node))
+}
+
+func TestGetMonZonePlacement(t *testing.T) {
+	c := &Cluster{spec: cephv1.ClusterSpec{Mon: cephv1.MonSpec{Count: 5}}}
+
+	// no zone affinity for a mon without zone
+	p := c.getMonPlacement("")
+	assert.Nil(t, p.NodeAffinity)
+
+	c.spec.Mon.StretchCluster = testStretchCluster()
+	p = c.getMonPlacement("a")
+	terms := p.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	assert.Equal(t, 1, len(terms))
+	assert.Equal(t, cephv1.DefaultZoneFailureDomainLabel, terms[0].MatchExpressions[0].Key)
+	assert.Equal(t, []string{"a"}, terms[0].MatchExpressions[0].Values)
+
+	// the zone is added to each of the terms of the mon placement
+	c.spec.Placement = rookv1.PlacementSpec{
+		"mon": rookv1.Placement{NodeAffinity: &v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+				NodeSelectorTerms: []v1.NodeSelectorTerm{
+					{MatchExpressions: []v1.NodeSelectorRequirement{{Key: "role", Operator: v1.NodeSelectorOpExists}}},
+					{MatchExpressions: []v1.NodeSelectorRequirement{{Key: "storage", Operator: v1.NodeSelectorOpExists}}},
+				},
+			},
+		}},
+	}
+	p = c.getMonPlacement("b")
+	terms = p.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	assert.Equal(t, 2, len(terms))
+	for _, term := range terms {
+		assert.Equal(t, 2, len(term.MatchExpressions))
+		assert.Equal(t, []string{"b"}, term.MatchExpressions[1].Values)
+	}
+
+	// the spec is not modified
+	terms = c.spec.Placement["mon"].NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	assert.Equal(t, 1, len(terms[0].MatchExpressions))
+
+	// the failure domain label of the mons is used outside of a stretch cluster
+	c.spec.Mon.StretchCluster = nil
+	c.spec.Mon.FailureDomainLabel = "rack"
+	p = c.getMonPlacement("r1")
+	assert.Equal(t, "rack", p.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions[1].Key)
+}
@@ -27,7 +27,7 @@ import (
 )
 
 var (
-	monitorDaemonList = []string{"mon", "osd", "status"}
+	monitorDaemonList = []string{"mon", "osd", "status", "keyrotation"}
 )
 
 func (c *ClusterController) configureCephMonitoring(cluster *cluster, clusterInfo *cephclient.ClusterInfo) {
@@ -93,6 +93,9 @@ func (c *ClusterController) configureCephMonitoring(cluster *cluster, clusterInf
 			logger.Errorf("failed to run bucket controller. %v", err)
 		}
 	}()
+	if err := bucketProvisioner.StartClaimWatcher(c.context.KubeConfig, cluster.stopCh); err != nil {
+		logger.Errorf("failed to watch object bucket claims. %v", err)
+	}
 
 	// enable the cluster watcher once
 	cluster.watchersActivated = true
@@ -108,6 +111,9 @@ func isMonitoringDisabled(daemon string, clusterSpec *cephv1.ClusterSpec) bool {
 
 	case "status":
 		return clusterSpec.HealthCheck.DaemonHealth.Status.Disabled
+
+	case "keyrotation":
+		return !clusterSpec.Security.KeyRotation.Enabled || clusterSpec.External.Enable
 	}
 
 	return false
@@ -128,8 +134,13 @@ func (c *ClusterController) startMonitoringCheck(cluster *cluster, clusterInfo *
 		}
 
 	case "status":
-		cephChecker := newCephStatusChecker(c.context, clusterInfo, cluster.Spec)
+		cephChecker := newCephStatusChecker(c.context, clusterInfo, cluster.Spec, c.recorder)
 		logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
 		go cephChecker.checkCephStatus(cluster.monitoringChannels[daemon].stopChan)
+
+	case "keyrotation":
+		keyRotation := osd.NewKeyRotationController(c.context, clusterInfo, c.rookImage)
+		logger.Infof("enabling osd encryption keys rotation goroutine for cluster %q", cluster.Namespace)
+		go keyRotation.Start(cluster.monitoringChannels[daemon].stopChan)
 	}
 }
@@ -28,6 +28,7 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/config/keyring"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -95,7 +96,8 @@ func encryptionDMPath(pvcName, blockType string) string {
 	return fmt.Sprintf("/dev/mapper/%s", encryptionDMName(pvcName, blockType))
 }
 
-func generateDmCryptKey() (string, error) {
+// GenerateDmCryptKey generates a random passphrase suitable for a LUKS keyslot
+func GenerateDmCryptKey() (string, error) {
 	key, err := mgr.GenerateRandomBytes(dmCryptKeySize)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to generate random bytes")
@@ -120,6 +122,26 @@ func generateOSDEncryptedKeySecret(pvcName, namespace, key string) *v1.Secret {
 	}
 }
 
+// createOSDEncryptionSecret generates the dm-crypt key of an OSD on PVC and stores it in a Kubernetes Secret
+func (c *Cluster) createOSDEncryptionSecret(pvcName string) error {
+	key, err := GenerateDmCryptKey()
+	if err != nil {
+		return errors.Wrapf(err, "failed to generate dmcrypt key for osd claim %q", pvcName)
+	}
+
+	s := generateOSDEncryptedKeySecret(pvcName, c.clusterInfo.Namespace, key)
+
+	// Set the ownerref to the Secret
+	k8sutil.SetOwnerRef(&s.ObjectMeta, &c.clusterInfo.OwnerRef)
+
+	_, err = c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Create(s)
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to save ceph osd encryption key as a secret for pvc %q", pvcName)
+	}
+
+	return nil
+}
+
 func generateOSDEncryptionSecretName(pvcName string) string {
 	return fmt.Sprintf("%s-%s", osdEncryptionSecretNamePrefix, pvcName)
 }
@@ -18,6 +18,7 @@ package osd
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -25,11 +26,23 @@ import (
 	rookv1 "github.com/rook/rook/pkg/apis/rook.io/v1"
 	"github.com/rook/rook/pkg/operator/ceph/controller"
 	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// osdReplacement is an OSD destroyed by a removal request, which is re-created with the same ID on a new PVC with the
+// name of its previous PVC
+type osdReplacement struct {
+	osdID   int
+	pvcName string
+}
+
 func (c *Cluster) prepareStorageClassDeviceSets(config *provisionConfig) []rookv1.VolumeSource {
 	volumeSources := []rookv1.VolumeSource{}
+	if len(c.spec.Storage.StorageClassDeviceSets) == 0 {
+		return volumeSources
+	}
 
 	existingPVCs, err := c.getExistingOSDPVCs()
 	if err != nil {
@@ -37,6 +50,12 @@ func (c *Cluster) prepareStorageClassDeviceSets(config *provisionConfig) []rookv
 		return volumeSources
 	}
 
+	replacements, err := c.getOSDReplacements()
+	if err != nil {
+		config.addError("failed to detect the replaced OSDs. %v", err)
+		return volumeSources
+	}
+
 	// Iterate over storageClassDeviceSet
 	for _, storageClassDeviceSet := range c.spec.Storage.StorageClassDeviceSets {
 		if err := controller.CheckPodMemory(cephv1.ResourcesKeyPrepareOSD, storageClassDeviceSet.Resources, cephOsdPodMinimumMemory); err != nil {
@@ -54,17 +73,22 @@ func (c *Cluster) prepareStorageClassDeviceSets(config *provisionConfig) []rookv
 			pvcSources := map[string]v1.PersistentVolumeClaimVolumeSource{}
 			var dataSize string
 			var crushDeviceClass string
+			waitingForReplacement := false
 			for _, pvcTemplate := range storageClassDeviceSet.VolumeClaimTemplates {
 				if pvcTemplate.Name == "" {
 					// For backward compatibility a blank name must be treated as a data volume
 					pvcTemplate.Name = bluestorePVCData
 				}
 
-				pvc, err := c.createStorageClassDeviceSetPVC(existingPVCs, storageClassDeviceSet.Name, pvcTemplate, i)
+				pvc, err := c.createStorageClassDeviceSetPVC(existingPVCs, replacements, storageClassDeviceSet.Name, pvcTemplate, i)
 				if err != nil {
 					config.addError("failed to create osd for storageClassDeviceSet %q for count %d. %v", storageClassDeviceSet.Name, i, err)
 					continue
 				}
+				if pvc == nil {
+					waitingForReplacement = true
+					break
+				}
 
 				// The PVC type must be from a predefined set such as "data" and "metadata". These names must be enforced if the wal/db are specified
 				// with a separate device, but if there is a single volume template we can assume it is always the data template.
@@ -74,7 +98,7 @@ func (c *Cluster) prepareStorageClassDeviceSets(config *provisionConfig) []rookv
 				}
 
 				if pvcType == bluestorePVCData {
-					pvcSize := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+					pvcSize := getPVCSize(pvc)
 					dataSize = pvcSize.String()
 					crushDeviceClass = pvcTemplate.Annotations["crushDeviceClass"]
 				}
@@ -84,6 +108,11 @@ func (c *Cluster) prepareStorageClassDeviceSets(config *provisionConfig) []rookv
 				}
 			}
 
+			// The OSD is re-created on the next reconcile, once the PVCs of the destroyed OSD are deleted
+			if waitingForReplacement {
+				continue
+			}
+
 			volumeSources = append(volumeSources, rookv1.VolumeSource{
 				Name:                storageClassDeviceSet.Name,
 				Resources:           storageClassDeviceSet.Resources,
@@ -105,19 +134,37 @@ func (c *Cluster) prepareStorageClassDeviceSets(config *provisionConfig) []rookv
 	return volumeSources
 }
 
-func (c *Cluster) createStorageClassDeviceSetPVC(existingPVCs map[string]*v1.PersistentVolumeClaim, storageClassDeviceSetName string, pvcTemplate v1.PersistentVolumeClaim, setIndex int) (*v1.PersistentVolumeClaim, error) {
+// createStorageClassDeviceSetPVC returns the PVC of a template of the set at the index, which is created if it does not
+// exist. No PVC is returned while the PVC of a destroyed OSD is not yet deleted for its replacement.
+func (c *Cluster) createStorageClassDeviceSetPVC(existingPVCs map[string]*v1.PersistentVolumeClaim, replacements map[string]osdReplacement, storageClassDeviceSetName string, pvcTemplate v1.PersistentVolumeClaim, setIndex int) (*v1.PersistentVolumeClaim, error) {
 	// old labels and PVC ID for backward compatibility
 	pvcStorageClassDeviceSetPVCId := legacyDeviceSetPVCID(storageClassDeviceSetName, setIndex)
 
 	// check for the existence of the pvc
 	existingPVC, ok := existingPVCs[pvcStorageClassDeviceSetPVCId]
-	if !ok {
+	if _, replacing := replacements[pvcStorageClassDeviceSetPVCId]; !ok && !replacing {
 		// The old name of the PVC didn't exist, now try the new PVC name and label
 		pvcStorageClassDeviceSetPVCId = deviceSetPVCID(storageClassDeviceSetName, pvcTemplate.GetName(), setIndex)
 		existingPVC = existingPVCs[pvcStorageClassDeviceSetPVCId]
 	}
 	pvc := makeStorageClassDeviceSetPVC(storageClassDeviceSetName, pvcStorageClassDeviceSetPVCId, setIndex, pvcTemplate)
 
+	replacement, replacing := replacements[pvcStorageClassDeviceSetPVCId]
+	if replacing {
+		if existingPVC != nil && existingPVC.Annotations[OSDReplaceIDAnnotationKey] != strconv.Itoa(replacement.osdID) {
+			logger.Infof("waiting for pvc %q of the destroyed osd.%d to be deleted", existingPVC.Name, replacement.osdID)
+			return nil, nil
+		}
+		// the new PVC keeps the name of the PVC of the destroyed OSD, which is the crush host of a portable OSD
+		pvc.GenerateName = ""
+		pvc.Name = replacement.pvcName
+		annotations := map[string]string{OSDReplaceIDAnnotationKey: strconv.Itoa(replacement.osdID)}
+		for k, v := range pvc.Annotations {
+			annotations[k] = v
+		}
+		pvc.Annotations = annotations
+	}
+
 	if existingPVC != nil {
 		logger.Infof("OSD PVC %q already exists", existingPVC.Name)
 
@@ -129,6 +176,10 @@ func (c *Cluster) createStorageClassDeviceSetPVC(existingPVCs map[string]*v1.Per
 	// No PVC found, creating a new one
 	deployedPVC, err := c.context.Clientset.CoreV1().PersistentVolumeClaims(c.clusterInfo.Namespace).Create(pvc)
 	if err != nil {
+		if replacing && kerrors.IsAlreadyExists(err) {
+			logger.Infof("waiting for pvc %q of the destroyed osd.%d to be deleted", pvc.Name, replacement.osdID)
+			return nil, nil
+		}
 		return nil, errors.Wrapf(err, "failed to create pvc %q for storageClassDeviceSet %q", pvc.GetGenerateName(), storageClassDeviceSetName)
 	}
 	logger.Infof("successfully provisioned PVC %q", deployedPVC.Name)
@@ -156,6 +207,20 @@ func (c *Cluster) updatePVCIfChanged(desiredPVC *v1.PersistentVolumeClaim, curre
 	}
 }
 
+// getPVCSize returns the capacity of a bound PVC, or its requested size until it is bound. When the size of the
+// template grows, the capacity only changes once the volume is expanded, the OSD is then restarted to expand BlueStore.
+func getPVCSize(pvc *v1.PersistentVolumeClaim) resource.Quantity {
+	requested := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+	capacity, ok := pvc.Status.Capacity[v1.ResourceStorage]
+	if !ok {
+		return requested
+	}
+	if requested.Cmp(capacity) > 0 {
+		logger.Infof("waiting for pvc %q to be expanded from %s to %s", pvc.Name, capacity.String(), requested.String())
+	}
+	return capacity
+}
+
 func makeStorageClassDeviceSetPVC(storageClassDeviceSetName, pvcStorageClassDeviceSetPVCId string, setIndex int, pvcTemplate v1.PersistentVolumeClaim) *v1.PersistentVolumeClaim {
 	pvcLabels := makeStorageClassDeviceSetPVCLabel(storageClassDeviceSetName, pvcStorageClassDeviceSetPVCId, setIndex)
 
@@ -185,6 +250,10 @@ func (c *Cluster) getExistingOSDPVCs() (map[string]*v1.PersistentVolumeClaim, er
 	}
 	result := map[string]*v1.PersistentVolumeClaim{}
 	for i, pvc := range pvcs.Items {
+		// the PVCs of a destroyed OSD are replaced by new PVCs
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
 		pvcID := pvc.Labels[CephDeviceSetPVCIDLabelKey]
 		result[pvcID] = &pvcs.Items[i]
 	}
@@ -192,6 +261,48 @@ func (c *Cluster) getExistingOSDPVCs() (map[string]*v1.PersistentVolumeClaim, er
 	return result, nil
 }
 
+// getOSDReplacements returns the OSDs destroyed by a removal request to be re-created on a new PVC, by the ID of their
+// data PVC in the storageClassDeviceSet
+func (c *Cluster) getOSDReplacements() (map[string]osdReplacement, error) {
+	removals, err := c.context.RookClientset.CephV1().CephOSDRemovals(c.clusterInfo.Namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list osd removals")
+	}
+	result := map[string]osdReplacement{}
+	for _, removal := range removals.Items {
+		if removal.Status == nil {
+			continue
+		}
+		for _, osd := range removal.Status.OSDs {
+			if osd.Phase == cephv1.OSDRemovalReplacing && osd.DeviceSetPVCID != "" {
+				result[osd.DeviceSetPVCID] = osdReplacement{osdID: osd.ID, pvcName: osd.PVCName}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// getReplaceOSDID returns the ID of the destroyed OSD to re-create on the new PVC of a replaced OSD
+func (c *Cluster) getReplaceOSDID(pvcName string) (*int, error) {
+	pvc, err := c.context.Clientset.CoreV1().PersistentVolumeClaims(c.clusterInfo.Namespace).Get(pvcName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get pvc %q", pvcName)
+	}
+	value, ok := pvc.Annotations[OSDReplaceIDAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	osdID, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid osd id %q in annotation %q of pvc %q", value, OSDReplaceIDAnnotationKey, pvcName)
+	}
+	return &osdID, nil
+}
+
 func legacyDeviceSetPVCID(storageClassDeviceSetName string, setIndex int) string {
 	return fmt.Sprintf("%s-%d", storageClassDeviceSetName, setIndex)
 }
@@ -21,6 +21,7 @@ import (
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	rookv1 "github.com/rook/rook/pkg/apis/rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	testexec "github.com/rook/rook/pkg/operator/test"
@@ -38,7 +39,8 @@ func TestPrepareDeviceSets(t *testing.T) {
 func testPrepareDeviceSets(t *testing.T, setTemplateName bool) {
 	clientset := testexec.New(t, 1)
 	context := &clusterd.Context{
-		Clientset: clientset,
+		Clientset:     clientset,
+		RookClientset: rookclient.NewSimpleClientset(),
 	}
 	storageClass := "mysource"
 	claim := v1.PersistentVolumeClaim{Spec: v1.PersistentVolumeClaimSpec{
@@ -85,6 +87,138 @@ func testPrepareDeviceSets(t *testing.T, setTemplateName bool) {
 	assert.Equal(t, cluster.clusterInfo.Namespace, pvcs.Items[0].Namespace)
 }
 
+func TestPrepareDeviceSetsReplacement(t *testing.T) {
+	ns := "testns"
+	removal := &cephv1.CephOSDRemoval{
+		ObjectMeta: metav1.ObjectMeta{Name: "replace-osd", Namespace: ns},
+		Spec:       cephv1.OSDRemovalSpec{OSDIDs: []int{3}, Replace: true},
+		Status: &cephv1.CephOSDRemovalStatus{
+			OSDs: []cephv1.OSDRemovalStatus{{ID: 3, Phase: cephv1.OSDRemovalReplacing, PVCName: "mydata-data-0-abcde", DeviceSetPVCID: "mydata-data-0"}},
+		},
+	}
+	clientset := testexec.New(t, 1)
+	context := &clusterd.Context{
+		Clientset:     clientset,
+		RookClientset: rookclient.NewSimpleClientset(removal),
+	}
+	storageClass := "mysource"
+	claim := v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Annotations: map[string]string{"crushDeviceClass": "ssd"}},
+		Spec:       v1.PersistentVolumeClaimSpec{StorageClassName: &storageClass},
+	}
+	deviceSet := rookv1.StorageClassDeviceSet{
+		Name:                 "mydata",
+		Count:                1,
+		Portable:             true,
+		VolumeClaimTemplates: []v1.PersistentVolumeClaim{claim},
+	}
+	cluster := &Cluster{
+		context:     context,
+		clusterInfo: client.AdminClusterInfo(ns),
+		spec: cephv1.ClusterSpec{
+			Storage: rookv1.StorageScopeSpec{StorageClassDeviceSets: []rookv1.StorageClassDeviceSet{deviceSet}},
+		},
+	}
+
+	// the pvc of the destroyed osd must be deleted first
+	oldPVC := makeStorageClassDeviceSetPVC("mydata", "mydata-data-0", 0, claim)
+	oldPVC.GenerateName = ""
+	oldPVC.Name = "mydata-data-0-abcde"
+	_, err := clientset.CoreV1().PersistentVolumeClaims(ns).Create(oldPVC)
+	assert.NoError(t, err)
+	config := &provisionConfig{}
+	volumeSources := cluster.prepareStorageClassDeviceSets(config)
+	assert.Equal(t, 0, len(config.errorMessages))
+	assert.Equal(t, 0, len(volumeSources))
+
+	// the new pvc has the same name and the id of the osd to re-create
+	err = clientset.CoreV1().PersistentVolumeClaims(ns).Delete(oldPVC.Name, &metav1.DeleteOptions{})
+	assert.NoError(t, err)
+	config = &provisionConfig{}
+	volumeSources = cluster.prepareStorageClassDeviceSets(config)
+	assert.Equal(t, 0, len(config.errorMessages))
+	assert.Equal(t, "mydata-data-0-abcde", volumeSources[0].PVCSources["data"].ClaimName)
+	pvc, err := clientset.CoreV1().PersistentVolumeClaims(ns).Get("mydata-data-0-abcde", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "3", pvc.Annotations[OSDReplaceIDAnnotationKey])
+	assert.Equal(t, "ssd", pvc.Annotations["crushDeviceClass"])
+	assert.Equal(t, "mydata-data-0", pvc.Labels[CephDeviceSetPVCIDLabelKey])
+	// the template is not modified
+	assert.Equal(t, 1, len(claim.Annotations))
+
+	replaceOSDID, err := cluster.getReplaceOSDID(pvc.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, *replaceOSDID)
+
+	// the new pvc is used while the osd is re-created
+	config = &provisionConfig{}
+	volumeSources = cluster.prepareStorageClassDeviceSets(config)
+	assert.Equal(t, 0, len(config.errorMessages))
+	assert.Equal(t, "mydata-data-0-abcde", volumeSources[0].PVCSources["data"].ClaimName)
+	pvcs, err := clientset.CoreV1().PersistentVolumeClaims(ns).List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(pvcs.Items))
+}
+
+func TestPrepareDeviceSetsExpansion(t *testing.T) {
+	ns := "testns"
+	clientset := testexec.New(t, 1)
+	context := &clusterd.Context{
+		Clientset:     clientset,
+		RookClientset: rookclient.NewSimpleClientset(),
+	}
+	claim := v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data"},
+		Spec: v1.PersistentVolumeClaimSpec{
+			Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")}},
+		},
+	}
+	deviceSet := rookv1.StorageClassDeviceSet{Name: "mydata", Count: 1, VolumeClaimTemplates: []v1.PersistentVolumeClaim{claim}}
+	cluster := &Cluster{
+		context:     context,
+		clusterInfo: client.AdminClusterInfo(ns),
+		spec: cephv1.ClusterSpec{
+			Storage: rookv1.StorageScopeSpec{StorageClassDeviceSets: []rookv1.StorageClassDeviceSet{deviceSet}},
+		},
+	}
+
+	// the pvc of the osd was created with a smaller template
+	pvc := makeStorageClassDeviceSetPVC("mydata", "mydata-data-0", 0, claim)
+	pvc.Name = "mydata-data-0-abcde"
+	pvc.Spec.Resources.Requests = v1.ResourceList{v1.ResourceStorage: resource.MustParse("5Gi")}
+	pvc.Status.Capacity = v1.ResourceList{v1.ResourceStorage: resource.MustParse("5Gi")}
+	_, err := clientset.CoreV1().PersistentVolumeClaims(ns).Create(pvc)
+	assert.NoError(t, err)
+
+	// the pvc is expanded, the osd keeps its size until the volume is expanded
+	config := &provisionConfig{}
+	volumeSources := cluster.prepareStorageClassDeviceSets(config)
+	assert.Equal(t, 0, len(config.errorMessages))
+	assert.Equal(t, "5Gi", volumeSources[0].Size)
+	pvc, err = clientset.CoreV1().PersistentVolumeClaims(ns).Get(pvc.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	requested := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+	assert.Equal(t, "10Gi", requested.String())
+
+	// the osd follows the capacity of the expanded volume
+	pvc.Status.Capacity = v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")}
+	_, err = clientset.CoreV1().PersistentVolumeClaims(ns).Update(pvc)
+	assert.NoError(t, err)
+	volumeSources = cluster.prepareStorageClassDeviceSets(config)
+	assert.Equal(t, "10Gi", volumeSources[0].Size)
+}
+
+func TestGetPVCSize(t *testing.T) {
+	pvc := &v1.PersistentVolumeClaim{}
+	pvc.Spec.Resources.Requests = v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")}
+	size := getPVCSize(pvc)
+	assert.Equal(t, "10Gi", size.String())
+
+	pvc.Status.Capacity = v1.ResourceList{v1.ResourceStorage: resource.MustParse("8Gi")}
+	size = getPVCSize(pvc)
+	assert.Equal(t, "8Gi", size.String())
+}
+
 func TestUpdatePVCSize(t *testing.T) {
 	clientset := testexec.New(t, 1)
 	context := &clusterd.Context{
@@ -21,8 +21,10 @@ import (
 
 	"github.com/pkg/errors"
 	opmon "github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"gopkg.in/ini.v1"
+	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 )
 
@@ -34,7 +36,9 @@ const (
 	EncryptedDeviceEnvVarName = "ROOK_ENCRYPTED_DEVICE"
 	// CephVolumeEncryptedKeyEnvVarName is the env variable used by ceph-volume to encrypt the OSD (raw mode)
 	// Hardcoded in ceph-volume do NOT touch
-	CephVolumeEncryptedKeyEnvVarName    = "CEPH_VOLUME_DMCRYPT_SECRET"
+	CephVolumeEncryptedKeyEnvVarName = "CEPH_VOLUME_DMCRYPT_SECRET"
+	// EncryptionKeyNameEnvVarName is the name of the OSD dm-crypt key in the KMS
+	EncryptionKeyNameEnvVarName         = "ROOK_ENCRYPTION_KEY_NAME"
 	osdMetadataDeviceEnvVarName         = "ROOK_METADATA_DEVICE"
 	osdWalDeviceEnvVarName              = "ROOK_WAL_DEVICE"
 	pvcBackedOSDVarName                 = "ROOK_PVC_BACKED_OSD"
@@ -43,10 +47,16 @@ const (
 	lvBackedPVVarName                   = "ROOK_LV_BACKED_PV"
 	CrushDeviceClassVarName             = "ROOK_OSD_CRUSH_DEVICE_CLASS"
 	tcmallocMaxTotalThreadCacheBytesEnv = "TCMALLOC_MAX_TOTAL_THREAD_CACHE_BYTES"
+	// ReplaceOSDIDVarName is the ID of a destroyed OSD to re-create on a new PVC
+	ReplaceOSDIDVarName = "ROOK_REPLACE_OSD_ID"
 )
 
 var (
 	cephEnvConfigFile = "/etc/sysconfig/ceph"
+
+	// ReplaceOSDRawModeMinCephVersion is the first Ceph version re-creating a destroyed OSD with its ID in ceph-volume
+	// raw mode, "ceph-volume raw prepare" has no --osd-id flag in Nautilus and Octopus
+	ReplaceOSDRawModeMinCephVersion = cephver.Pacific
 )
 
 func (c *Cluster) getConfigEnvVars(osdProps osdProperties, dataDir string) []v1.EnvVar {
@@ -165,6 +175,10 @@ func crushDeviceClassEnvVar(crushDeviceClass string) v1.EnvVar {
 	return v1.EnvVar{Name: CrushDeviceClassVarName, Value: crushDeviceClass}
 }
 
+func replaceOSDIDEnvVar(osdID int) v1.EnvVar {
+	return v1.EnvVar{Name: ReplaceOSDIDVarName, Value: strconv.Itoa(osdID)}
+}
+
 func encryptedDeviceEnvVar(encryptedDevice bool) v1.EnvVar {
 	return v1.EnvVar{Name: EncryptedDeviceEnvVarName, Value: strconv.FormatBool(encryptedDevice)}
 }
@@ -180,6 +194,10 @@ func cephVolumeRawEncryptedEnvVar(pvcName string) v1.EnvVar {
 	}
 }
 
+func encryptionKeyNameEnvVar(pvcName string) v1.EnvVar {
+	return v1.EnvVar{Name: EncryptionKeyNameEnvVarName, Value: generateOSDEncryptionSecretName(pvcName)}
+}
+
 func cephVolumeEnvVar() []v1.EnvVar {
 	return []v1.EnvVar{
 		{Name: "CEPH_VOLUME_DEBUG", Value: "1"},
@@ -223,3 +241,15 @@ func getTcmallocMaxTotalThreadCacheBytesFromFile() string {
 
 	return iniCephEnvConfigFile.Section("").Key(tcmallocMaxTotalThreadCacheBytesEnv).String()
 }
+
+// IsRawModeDeployment returns whether the OSD of the deployment was prepared with ceph-volume raw mode
+func IsRawModeDeployment(d *apps.Deployment) bool {
+	for _, container := range d.Spec.Template.Spec.Containers {
+		for _, envVar := range container.Env {
+			if envVar.Name == cvModeVarName {
+				return envVar.Value == "raw"
+			}
+		}
+	}
+	return false
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -28,6 +30,8 @@ import (
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/k8sutil"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -102,6 +106,10 @@ func (m *OSDHealthMonitor) checkOSDHealth() {
 	if err != nil {
 		logger.Debugf("failed to check device classes. %v", err)
 	}
+	err = m.checkOSDsStatus()
+	if err != nil {
+		logger.Debugf("failed to check the status of the osds. %v", err)
+	}
 }
 
 func (m *OSDHealthMonitor) checkDeviceClasses() error {
@@ -211,12 +219,162 @@ func (m *OSDHealthMonitor) restartOSDIfStuck(osdID int) error {
 
 // updateCephStorage updates the CR with deviceclass details
 func (m *OSDHealthMonitor) updateCephStatus(devices []string) {
-	cephCluster := &cephv1.CephCluster{}
-	cephClusterStorage := cephv1.CephStorage{}
-
+	deviceClasses := []cephv1.DeviceClasses{}
 	for _, device := range devices {
-		cephClusterStorage.DeviceClasses = append(cephClusterStorage.DeviceClasses, cephv1.DeviceClasses{Name: device})
+		deviceClasses = append(deviceClasses, cephv1.DeviceClasses{Name: device})
+	}
+	m.updateCephStorage(func(storage *cephv1.CephStorage) {
+		storage.DeviceClasses = deviceClasses
+	})
+}
+
+// checkOSDsStatus reports the inventory of the OSDs in the CR: how each OSD was provisioned from its deployment,
+// its state and size from Ceph, and the capacity of the data PVC of the OSDs on PVC so that the expansion of the
+// OSDs can be followed
+func (m *OSDHealthMonitor) checkOSDsStatus() error {
+	usage, err := client.GetOSDUsage(m.context, m.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get osd usage")
 	}
+	sizes := map[int]uint64{}
+	for _, node := range usage.OSDNodes {
+		if kb, err := node.KB.Int64(); err == nil {
+			sizes[node.ID] = uint64(kb) * 1024
+		}
+	}
+
+	osdDump, err := client.GetOSDDump(m.context, m.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get osd dump")
+	}
+
+	crushMap, err := client.GetCrushMap(m.context, m.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get crush map")
+	}
+	deviceClasses := map[int]string{}
+	for _, device := range crushMap.Devices {
+		deviceClasses[device.ID] = device.Class
+	}
+
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)}
+	deployments, err := m.context.Clientset.AppsV1().Deployments(m.clusterInfo.Namespace).List(listOpts)
+	if err != nil {
+		return errors.Wrap(err, "failed to list osd deployments")
+	}
+	pods, err := m.context.Clientset.CoreV1().Pods(m.clusterInfo.Namespace).List(listOpts)
+	if err != nil {
+		return errors.Wrap(err, "failed to list osd pods")
+	}
+	nodes := map[string]string{}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != "" {
+			nodes[pod.Labels[OsdIdLabelKey]] = pod.Spec.NodeName
+		}
+	}
+	pvcs, err := m.context.Clientset.CoreV1().PersistentVolumeClaims(m.clusterInfo.Namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list pvcs")
+	}
+	capacities := map[string]string{}
+	for _, pvc := range pvcs.Items {
+		if capacity, ok := pvc.Status.Capacity[v1.ResourceStorage]; ok {
+			capacities[pvc.Name] = capacity.String()
+		}
+	}
+	failures, err := m.getOrchestrationFailures()
+	if err != nil {
+		return errors.Wrap(err, "failed to get osd orchestration failures")
+	}
+
+	osds := []cephv1.OSDStatus{}
+	for i := range deployments.Items {
+		osd, err := osdStatusFromDeployment(&deployments.Items[i])
+		if err != nil {
+			logger.Debugf("skipping osd deployment %q. %v", deployments.Items[i].Name, err)
+			continue
+		}
+		if node, ok := nodes[strconv.Itoa(osd.ID)]; ok {
+			osd.Host = node
+		}
+		if up, in, err := osdDump.StatusByID(int64(osd.ID)); err == nil {
+			osd.Up = up == upStatus
+			osd.In = in == inStatus
+		}
+		osd.DeviceClass = deviceClasses[osd.ID]
+		osd.SizeBytes = sizes[osd.ID]
+		if osd.PVCName != "" {
+			osd.PVCSize = capacities[osd.PVCName]
+			osd.LastError = failures[osd.PVCName]
+		} else {
+			osd.LastError = failures[osd.Host]
+		}
+		osds = append(osds, osd)
+	}
+	sort.Slice(osds, func(i, j int) bool { return osds[i].ID < osds[j].ID })
+
+	m.updateCephStorage(func(storage *cephv1.CephStorage) {
+		storage.OSDs = osds
+	})
+	return nil
+}
+
+// getOrchestrationFailures returns the message of the failed provisionings by node or PVC name
+func (m *OSDHealthMonitor) getOrchestrationFailures() (map[string]string, error) {
+	selector := fmt.Sprintf("%s=%s,%s=%s", k8sutil.AppAttr, AppName, orchestrationStatusKey, provisioningLabelKey)
+	statuses, err := m.context.Clientset.CoreV1().ConfigMaps(m.clusterInfo.Namespace).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	failures := map[string]string{}
+	for _, configMap := range statuses.Items {
+		status := parseOrchestrationStatus(configMap.Data)
+		if status != nil && status.Status == OrchestrationStatusFailed {
+			failures[configMap.Labels[nodeLabelKey]] = status.Message
+		}
+	}
+	return failures, nil
+}
+
+// osdStatusFromDeployment returns the status of an OSD with the properties set in its deployment
+func osdStatusFromDeployment(d *apps.Deployment) (cephv1.OSDStatus, error) {
+	osdID, err := osdIDFromDeployment(d)
+	if err != nil {
+		return cephv1.OSDStatus{}, err
+	}
+	osd := cephv1.OSDStatus{
+		ID:      osdID,
+		PVCName: d.Labels[OSDOverPVCLabelKey],
+		Host:    d.Spec.Template.Spec.NodeSelector[v1.LabelHostname],
+	}
+	if len(d.Spec.Template.Spec.Containers) == 0 {
+		return osd, nil
+	}
+
+	container := d.Spec.Template.Spec.Containers[0]
+	osd.DevicePath = blockPathFromEnv(container.Env)
+	osd.Location, _ = crushLocationFromArgs(container.Args)
+	for _, envVar := range container.Env {
+		switch envVar.Name {
+		case "ROOK_OSD_STORE_TYPE":
+			osd.Store = envVar.Value
+		case EncryptedDeviceEnvVarName:
+			osd.Encrypted = envVar.Value == "true"
+		}
+	}
+	for _, initContainer := range d.Spec.Template.Spec.InitContainers {
+		if initContainer.Name == blockEncryptionOpenInitContainer {
+			osd.Encrypted = true
+		}
+	}
+
+	return osd, nil
+}
+
+// updateCephStorage updates the storage status of the CR if it changes
+func (m *OSDHealthMonitor) updateCephStorage(update func(storage *cephv1.CephStorage)) {
+	cephCluster := &cephv1.CephCluster{}
 	err := m.context.Client.Get(context.TODO(), m.clusterInfo.NamespacedName(), cephCluster)
 	if err != nil {
 		if kerrors.IsNotFound(err) {
@@ -226,6 +384,12 @@ func (m *OSDHealthMonitor) updateCephStatus(devices []string) {
 		logger.Errorf("failed to retrieve ceph cluster %q to update ceph Storage. %v", m.clusterInfo.NamespacedName().Name, err)
 		return
 	}
+
+	cephClusterStorage := cephv1.CephStorage{}
+	if cephCluster.Status.CephStorage != nil {
+		cephClusterStorage = *cephCluster.Status.CephStorage.DeepCopy()
+	}
+	update(&cephClusterStorage)
 	if !reflect.DeepEqual(cephCluster.Status.CephStorage, &cephClusterStorage) {
 		cephCluster.Status.CephStorage = &cephClusterStorage
 		if err := opcontroller.UpdateStatus(m.context.Client, cephCluster); err != nil {
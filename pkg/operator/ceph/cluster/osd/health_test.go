@@ -16,6 +16,7 @@ limitations under the License.
 package osd
 
 import (
+	gocontext "context"
 	"fmt"
 	"reflect"
 	"testing"
@@ -32,6 +33,7 @@ import (
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -243,3 +245,142 @@ func TestDeviceClasses(t *testing.T) {
 	// checkDeviceClasses has 1 mocked cmd for fetching the device classes
 	assert.Equal(t, 1, execCount)
 }
+
+func TestOSDsStatus(t *testing.T) {
+	clusterInfo := client.AdminClusterInfo("fake")
+	clusterInfo.SetName("rook-ceph")
+
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command string, outFileArg string, args ...string) (string, error) {
+			logger.Infof("ExecuteCommandWithOutputFile: %s %v", command, args)
+			if args[0] == "osd" && args[1] == "df" {
+				return `{"nodes":[{"id":0,"kb":20971520},{"id":1,"kb":10485760}]}`, nil
+			}
+			if args[0] == "osd" && args[1] == "dump" {
+				return `{"OSDs": [{"OSD": 0, "Up": 1, "In": 1}, {"OSD": 1, "Up": 0, "In": 1}]}`, nil
+			}
+			if args[0] == "osd" && args[1] == "crush" && args[2] == "dump" {
+				return `{"devices":[{"id":0,"name":"osd.0","class":"ssd"},{"id":1,"name":"osd.1","class":"hdd"}]}`, nil
+			}
+			return "", nil
+		},
+	}
+
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph", Namespace: "fake"},
+		Status: cephv1.ClusterStatus{
+			CephStorage: &cephv1.CephStorage{DeviceClasses: []cephv1.DeviceClasses{{Name: "ssd"}}},
+		},
+	}
+	cl := fake.NewFakeClientWithScheme(scheme.Scheme, cephCluster)
+	clientset := testexec.New(t, 1)
+	context := &clusterd.Context{
+		Executor:  executor,
+		Client:    cl,
+		Clientset: clientset,
+	}
+
+	// osd.0 is on an encrypted pvc that was expanded, osd.1 is on a raw device of a node where the provisioning failed
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "set1-data-0", Namespace: "fake"},
+		Status: v1.PersistentVolumeClaimStatus{
+			Capacity: v1.ResourceList{v1.ResourceStorage: resource.MustParse("20Gi")},
+		},
+	}
+	_, err := clientset.CoreV1().PersistentVolumeClaims("fake").Create(pvc)
+	assert.NoError(t, err)
+	osd0 := &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rook-ceph-osd-0",
+			Namespace: "fake",
+			Labels:    map[string]string{k8sutil.AppAttr: AppName, OsdIdLabelKey: "0", OSDOverPVCLabelKey: "set1-data-0"},
+		},
+		Spec: apps.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: blockEncryptionOpenInitContainer}},
+			Containers: []v1.Container{{
+				Args: []string{"--foreground", "--crush-location=root=default host=set1-data-0"},
+				Env: []v1.EnvVar{
+					{Name: "ROOK_BLOCK_PATH", Value: "/mnt/set1-data-0"},
+					{Name: "ROOK_OSD_STORE_TYPE", Value: "bluestore"},
+				},
+			}},
+		}}},
+	}
+	osd1 := &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rook-ceph-osd-1",
+			Namespace: "fake",
+			Labels:    map[string]string{k8sutil.AppAttr: AppName, OsdIdLabelKey: "1"},
+		},
+		Spec: apps.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+			NodeSelector: map[string]string{v1.LabelHostname: "node1"},
+			Containers: []v1.Container{{
+				Args: []string{"--foreground", "--crush-location=root=default host=node1"},
+				Env: []v1.EnvVar{
+					{Name: "ROOK_LV_PATH", Value: "/dev/ceph-vg/osd-block-1"},
+					{Name: "ROOK_OSD_STORE_TYPE", Value: "bluestore"},
+				},
+			}},
+		}}},
+	}
+	for _, d := range []*apps.Deployment{osd0, osd1} {
+		_, err := clientset.AppsV1().Deployments("fake").Create(d)
+		assert.NoError(t, err)
+	}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rook-ceph-osd-0-abc",
+			Namespace: "fake",
+			Labels:    map[string]string{k8sutil.AppAttr: AppName, OsdIdLabelKey: "0"},
+		},
+		Spec: v1.PodSpec{NodeName: "node0"},
+	}
+	_, err = clientset.CoreV1().Pods("fake").Create(pod)
+	assert.NoError(t, err)
+	status := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rook-ceph-osd-node1-status",
+			Namespace: "fake",
+			Labels:    map[string]string{k8sutil.AppAttr: AppName, orchestrationStatusKey: provisioningLabelKey, nodeLabelKey: "node1"},
+		},
+		Data: map[string]string{orchestrationStatusKey: `{"status":"failed","message":"failed to configure devices"}`},
+	}
+	_, err = clientset.CoreV1().ConfigMaps("fake").Create(status)
+	assert.NoError(t, err)
+
+	osdMon := NewOSDHealthMonitor(context, clusterInfo, true, cephv1.CephClusterHealthCheckSpec{})
+	err = osdMon.checkOSDsStatus()
+	assert.NoError(t, err)
+
+	updated := &cephv1.CephCluster{}
+	err = cl.Get(gocontext.TODO(), clusterInfo.NamespacedName(), updated)
+	assert.NoError(t, err)
+	assert.Equal(t, []cephv1.DeviceClasses{{Name: "ssd"}}, updated.Status.CephStorage.DeviceClasses)
+	assert.Equal(t, []cephv1.OSDStatus{
+		{
+			ID:          0,
+			Host:        "node0",
+			DevicePath:  "/mnt/set1-data-0",
+			DeviceClass: "ssd",
+			Store:       "bluestore",
+			Encrypted:   true,
+			Location:    "root=default host=set1-data-0",
+			Up:          true,
+			In:          true,
+			PVCName:     "set1-data-0",
+			PVCSize:     "20Gi",
+			SizeBytes:   20 * 1024 * 1024 * 1024,
+		},
+		{
+			ID:          1,
+			Host:        "node1",
+			DevicePath:  "/dev/ceph-vg/osd-block-1",
+			DeviceClass: "hdd",
+			Store:       "bluestore",
+			Location:    "root=default host=node1",
+			In:          true,
+			LastError:   "failed to configure devices",
+			SizeBytes:   10 * 1024 * 1024 * 1024,
+		},
+	}, updated.Status.CephStorage.OSDs)
+}
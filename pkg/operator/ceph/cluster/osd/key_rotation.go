@@ -0,0 +1,538 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	apps "k8s.io/api/apps/v1"
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	keyRotationAppName        = "rook-ceph-osd-key-rotation"
+	keyRotationAppNameFmt     = "rook-ceph-osd-key-rotation-%s"
+	keyRotationPhaseRotating  = "Rotating"
+	keyRotationPhaseSucceeded = "Succeeded"
+	keyRotationPhaseFailed    = "Failed"
+	keyRotationJobTimeout     = 15 * time.Minute
+	// the key of the staged Secret holding the old key of an OSD until its keyslots are removed
+	oldEncryptionKeyName = "old-" + OsdEncryptionSecretNameKeyName
+	// NewEncryptionKeyFileSuffix is appended to the path of the key of an OSD for the path of its new key during a rotation
+	NewEncryptionKeyFileSuffix = ".new"
+)
+
+var (
+	defaultKeyRotationPeriod = 90 * 24 * time.Hour
+	keyRotationCheckInterval = time.Hour
+)
+
+// KeyRotationController periodically rotates the dm-crypt keys of the encrypted OSDs on PVC, one OSD at a time
+type KeyRotationController struct {
+	context     *clusterd.Context
+	clusterInfo *client.ClusterInfo
+	rookImage   string
+	interval    time.Duration
+}
+
+// NewKeyRotationController instantiates the key rotation of the OSDs
+func NewKeyRotationController(context *clusterd.Context, clusterInfo *client.ClusterInfo, rookImage string) *KeyRotationController {
+	return &KeyRotationController{
+		context:     context,
+		clusterInfo: clusterInfo,
+		rookImage:   rookImage,
+		interval:    keyRotationCheckInterval,
+	}
+}
+
+// Start checks at set intervals whether the key of an OSD is due for rotation
+func (k *KeyRotationController) Start(stopCh chan struct{}) {
+	for {
+		select {
+		case <-time.After(k.interval):
+			logger.Debug("checking osd encryption keys rotation.")
+			k.rotateKeys()
+
+		case <-stopCh:
+			logger.Infof("stopping osd encryption keys rotation in namespace %s", k.clusterInfo.Namespace)
+			return
+		}
+	}
+}
+
+// keyRotationPeriod returns the time between two rotations of the key of an OSD
+func keyRotationPeriod(spec cephv1.KeyRotationSpec) (time.Duration, error) {
+	if spec.Period == "" {
+		return defaultKeyRotationPeriod, nil
+	}
+
+	period, err := time.ParseDuration(spec.Period)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse key rotation period %q", spec.Period)
+	}
+	if period <= 0 {
+		return 0, errors.Errorf("invalid key rotation period %q, must be positive", spec.Period)
+	}
+	return period, nil
+}
+
+// rotateKeys rotates the key of each encrypted OSD on PVC whose last rotation is older than the period
+func (k *KeyRotationController) rotateKeys() {
+	cephCluster := &cephv1.CephCluster{}
+	err := k.context.Client.Get(context.TODO(), k.clusterInfo.NamespacedName(), cephCluster)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Errorf("failed to retrieve ceph cluster %q to rotate the osd keys. %v", k.clusterInfo.NamespacedName().Name, err)
+		return
+	}
+
+	security := cephCluster.Spec.Security
+	if !security.KeyRotation.Enabled {
+		return
+	}
+	period, err := keyRotationPeriod(security.KeyRotation)
+	if err != nil {
+		logger.Errorf("failed to rotate osd keys. %v", err)
+		return
+	}
+
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)}
+	deployments, err := k.context.Clientset.AppsV1().Deployments(k.clusterInfo.Namespace).List(listOpts)
+	if err != nil {
+		logger.Errorf("failed to list osd deployments to rotate the osd keys. %v", err)
+		return
+	}
+	sort.Slice(deployments.Items, func(i, j int) bool {
+		return deployments.Items[i].Name < deployments.Items[j].Name
+	})
+
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if !isEncryptedOSDOnPVC(d) {
+			continue
+		}
+		osdID, err := strconv.Atoi(d.Labels[OsdIdLabelKey])
+		if err != nil {
+			logger.Errorf("failed to parse the id of osd deployment %q. %v", d.Name, err)
+			continue
+		}
+
+		status := OSDKeyRotationStatus(cephCluster.Status.KeyRotation, osdID)
+		status.PVCName = d.Labels[OSDOverPVCLabelKey]
+		lastRotation := d.CreationTimestamp.Time
+		if status.LastRotation != "" {
+			if t, err := time.Parse(time.RFC3339, status.LastRotation); err == nil {
+				lastRotation = t
+			}
+		}
+		if time.Since(lastRotation) < period {
+			continue
+		}
+
+		// The OSDs are rotated one after the other, each rotation job must complete before the next one starts
+		logger.Infof("rotating encryption key of osd.%d on pvc %q", osdID, status.PVCName)
+		status.Phase = keyRotationPhaseRotating
+		status.Message = ""
+		k.updateKeyRotationStatus(status)
+
+		if err := k.rotateKey(d, status.PVCName, security); err != nil {
+			logger.Errorf("failed to rotate encryption key of osd.%d. %v", osdID, err)
+			status.Phase = keyRotationPhaseFailed
+			status.Message = err.Error()
+		} else {
+			logger.Infof("successfully rotated encryption key of osd.%d", osdID)
+			status.Phase = keyRotationPhaseSucceeded
+			status.LastRotation = time.Now().UTC().Format(time.RFC3339)
+		}
+		k.updateKeyRotationStatus(status)
+	}
+}
+
+// isEncryptedOSDOnPVC returns whether the OSD runs on a PVC encrypted by Rook, these OSDs open their block with a key
+// from a Secret or a KMS in an init container
+func isEncryptedOSDOnPVC(d *apps.Deployment) bool {
+	if d.Labels[OSDOverPVCLabelKey] == "" {
+		return false
+	}
+	for _, c := range d.Spec.Template.Spec.InitContainers {
+		if c.Name == blockEncryptionOpenInitContainer {
+			return true
+		}
+	}
+	return false
+}
+
+// OSDKeyRotationStatus returns the key rotation status of the OSD, or an empty status if it was never rotated
+func OSDKeyRotationStatus(status *cephv1.KeyRotationStatus, osdID int) cephv1.OSDKeyRotationStatus {
+	if status != nil {
+		for _, s := range status.OSDs {
+			if s.ID == osdID {
+				return s
+			}
+		}
+	}
+	return cephv1.OSDKeyRotationStatus{ID: osdID}
+}
+
+func (k *KeyRotationController) updateKeyRotationStatus(osdStatus cephv1.OSDKeyRotationStatus) {
+	cephCluster := &cephv1.CephCluster{}
+	err := k.context.Client.Get(context.TODO(), k.clusterInfo.NamespacedName(), cephCluster)
+	if err != nil {
+		logger.Errorf("failed to retrieve ceph cluster %q to update the key rotation status. %v", k.clusterInfo.NamespacedName().Name, err)
+		return
+	}
+
+	if cephCluster.Status.KeyRotation == nil {
+		cephCluster.Status.KeyRotation = &cephv1.KeyRotationStatus{}
+	}
+	found := false
+	for i, s := range cephCluster.Status.KeyRotation.OSDs {
+		if s.ID == osdStatus.ID {
+			cephCluster.Status.KeyRotation.OSDs[i] = osdStatus
+			found = true
+			break
+		}
+	}
+	if !found {
+		cephCluster.Status.KeyRotation.OSDs = append(cephCluster.Status.KeyRotation.OSDs, osdStatus)
+		sort.Slice(cephCluster.Status.KeyRotation.OSDs, func(i, j int) bool {
+			return cephCluster.Status.KeyRotation.OSDs[i].ID < cephCluster.Status.KeyRotation.OSDs[j].ID
+		})
+	}
+
+	if err := opcontroller.UpdateStatus(k.context.Client, cephCluster); err != nil {
+		logger.Errorf("failed to update cluster %q key rotation status. %v", k.clusterInfo.NamespacedName().Name, err)
+	}
+}
+
+// rotateKey runs the jobs rotating the key of an OSD and waits for their completion. With a KMS a single job adds the
+// new keyslots, stores the new key and removes the old keyslots. Without a KMS the operator stages the new key in a Secret
+// mounted by a first job adding the new keyslots, stores it in the Secret of the OSD once the job succeeded, then runs a
+// second job removing the old keyslots. The jobs do not need access to the Secrets and each step can be retried.
+func (k *KeyRotationController) rotateKey(d *apps.Deployment, pvcName string, security cephv1.SecuritySpec) error {
+	if security.KeyManagementService.IsEnabled() {
+		return k.runKeyRotationJob(d, pvcName, security, false)
+	}
+
+	stored, err := k.stageEncryptionKey(pvcName)
+	if err != nil {
+		return err
+	}
+	if stored {
+		logger.Infof("new encryption key of pvc %q is already stored, removing the old keyslots", pvcName)
+	} else {
+		if err := k.runKeyRotationJob(d, pvcName, security, false); err != nil {
+			return err
+		}
+		if err := k.storeEncryptionKey(pvcName); err != nil {
+			return err
+		}
+	}
+
+	// the old key is kept in the staged Secret until its keyslots are removed
+	if err := k.runKeyRotationJob(d, pvcName, security, true); err != nil {
+		return err
+	}
+	return k.deleteStagedEncryptionKey(pvcName)
+}
+
+// runKeyRotationJob runs a key rotation job on the node of the OSD and waits for its completion
+func (k *KeyRotationController) runKeyRotationJob(d *apps.Deployment, pvcName string, security cephv1.SecuritySpec, removeOldKey bool) error {
+	hostname, err := k.getOSDHostName(d)
+	if err != nil {
+		return err
+	}
+
+	job, err := k.makeKeyRotationJob(d, pvcName, hostname, security, removeOldKey)
+	if err != nil {
+		return err
+	}
+
+	if err := k8sutil.RunReplaceableJob(k.context.Clientset, job, true); err != nil {
+		return errors.Wrapf(err, "failed to run key rotation job for pvc %q", pvcName)
+	}
+	if err := k8sutil.WaitForJobCompletion(k.context.Clientset, job, keyRotationJobTimeout); err != nil {
+		return errors.Wrapf(err, "failed to complete key rotation job for pvc %q", pvcName)
+	}
+
+	// keep the job around on failure for troubleshooting only
+	if err := k8sutil.DeleteBatchJob(k.context.Clientset, job.Namespace, job.Name, false); err != nil {
+		logger.Warningf("failed to delete key rotation job %q. %v", job.Name, err)
+	}
+	return nil
+}
+
+// stagedEncryptionKeySecretName returns the name of the Secret holding the new key of an OSD during its rotation
+func stagedEncryptionKeySecretName(pvcName string) string {
+	return k8sutil.TruncateNodeName(keyRotationAppNameFmt, pvcName)
+}
+
+// stageEncryptionKey generates the new key of the OSD in a Secret mounted by the rotation jobs. The key staged by a
+// failed rotation is kept since it may already open the devices. It returns true if the Secret of the OSD already holds
+// the staged key, when the rotation stopped after storing it.
+func (k *KeyRotationController) stageEncryptionKey(pvcName string) (bool, error) {
+	secrets := k.context.Clientset.CoreV1().Secrets(k.clusterInfo.Namespace)
+	stagedName := stagedEncryptionKeySecretName(pvcName)
+	staged, err := secrets.Get(stagedName, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return false, errors.Wrapf(err, "failed to get staged encryption key %q", stagedName)
+		}
+		newKey, err := GenerateDmCryptKey()
+		if err != nil {
+			return false, errors.Wrap(err, "failed to generate new encryption key")
+		}
+		staged = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      stagedName,
+				Namespace: k.clusterInfo.Namespace,
+				Labels: map[string]string{
+					k8sutil.AppAttr:    keyRotationAppName,
+					OSDOverPVCLabelKey: pvcName,
+				},
+			},
+			Data: map[string][]byte{OsdEncryptionSecretNameKeyName: []byte(newKey)},
+			Type: k8sutil.RookType,
+		}
+		k8sutil.SetOwnerRef(&staged.ObjectMeta, &k.clusterInfo.OwnerRef)
+		if _, err := secrets.Create(staged); err != nil {
+			return false, errors.Wrapf(err, "failed to stage new encryption key %q", stagedName)
+		}
+		return false, nil
+	}
+
+	secretName := generateOSDEncryptionSecretName(pvcName)
+	secret, err := secrets.Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get encryption key %q", secretName)
+	}
+	return bytes.Equal(secret.Data[OsdEncryptionSecretNameKeyName], staged.Data[OsdEncryptionSecretNameKeyName]), nil
+}
+
+// storeEncryptionKey stores the staged key in the Secret of the OSD once the devices are opened by it. The old key is
+// saved in the staged Secret first, the job removing the old keyslots reads it from there.
+func (k *KeyRotationController) storeEncryptionKey(pvcName string) error {
+	secrets := k.context.Clientset.CoreV1().Secrets(k.clusterInfo.Namespace)
+	stagedName := stagedEncryptionKeySecretName(pvcName)
+	staged, err := secrets.Get(stagedName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get staged encryption key %q", stagedName)
+	}
+
+	secretName := generateOSDEncryptionSecretName(pvcName)
+	secret, err := secrets.Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get encryption key %q", secretName)
+	}
+	newKey := staged.Data[OsdEncryptionSecretNameKeyName]
+	if bytes.Equal(secret.Data[OsdEncryptionSecretNameKeyName], newKey) {
+		return nil
+	}
+
+	staged.Data[oldEncryptionKeyName] = secret.Data[OsdEncryptionSecretNameKeyName]
+	if _, err := secrets.Update(staged); err != nil {
+		return errors.Wrapf(err, "failed to save old encryption key in %q", stagedName)
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[OsdEncryptionSecretNameKeyName] = newKey
+	if _, err := secrets.Update(secret); err != nil {
+		return errors.Wrapf(err, "failed to store new encryption key %q", secretName)
+	}
+	logger.Infof("stored new encryption key %q", secretName)
+	return nil
+}
+
+// deleteStagedEncryptionKey deletes the staged Secret once the old keyslots are removed, which ends the rotation
+func (k *KeyRotationController) deleteStagedEncryptionKey(pvcName string) error {
+	stagedName := stagedEncryptionKeySecretName(pvcName)
+	err := k.context.Clientset.CoreV1().Secrets(k.clusterInfo.Namespace).Delete(stagedName, &metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete staged encryption key %q", stagedName)
+	}
+	return nil
+}
+
+// getOSDHostName returns the host name of the node running the OSD, the PVC can only be attached on that node
+func (k *KeyRotationController) getOSDHostName(d *apps.Deployment) (string, error) {
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", OsdIdLabelKey, d.Labels[OsdIdLabelKey])}
+	pods, err := k.context.Clientset.CoreV1().Pods(k.clusterInfo.Namespace).List(listOpts)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get pod of osd deployment %q", d.Name)
+	}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		name, err := k8sutil.GetNodeHostName(k.context.Clientset, pod.Spec.NodeName)
+		if err != nil {
+			logger.Warningf("falling back to node name %s since hostname not found for node", pod.Spec.NodeName)
+			name = pod.Spec.NodeName
+		}
+		return name, nil
+	}
+
+	return "", errors.Errorf("osd deployment %q has no pod scheduled on a node", d.Name)
+}
+
+// makeKeyRotationJob builds the job adding a keyslot with a new key to the encrypted blocks of the OSD, or removing the
+// keyslot of the old key. The blocks are attached the same way as in the init containers opening them in the OSD pod.
+func (k *KeyRotationController) makeKeyRotationJob(d *apps.Deployment, pvcName, hostname string, security cephv1.SecuritySpec, removeOldKey bool) (*batch.Job, error) {
+	podSpec := d.Spec.Template.Spec
+
+	blockDevices := []v1.VolumeDevice{}
+	for _, c := range podSpec.InitContainers {
+		switch c.Name {
+		case blockEncryptionOpenInitContainer, blockEncryptionOpenMetadataInitContainer, blockEncryptionOpenWalInitContainer:
+			blockDevices = append(blockDevices, c.VolumeDevices...)
+		}
+	}
+	if len(blockDevices) == 0 {
+		return nil, errors.Errorf("no encrypted block found in osd deployment %q", d.Name)
+	}
+
+	blockPaths := []string{}
+	volumes := []v1.Volume{}
+	for _, device := range blockDevices {
+		blockPaths = append(blockPaths, device.DevicePath)
+		for _, volume := range podSpec.Volumes {
+			if volume.Name == device.Name {
+				volumes = append(volumes, volume)
+			}
+		}
+	}
+
+	envVars := []v1.EnvVar{k8sutil.NamespaceEnvVar()}
+	keyVolumeMount := v1.VolumeMount{Name: osdEncryptionVolName, MountPath: config.EtcCephDir}
+	if security.KeyManagementService.IsEnabled() {
+		// The keys are only written to a memory backed volume
+		volumes = append(volumes, v1.Volume{
+			Name: osdEncryptionVolName,
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory},
+			},
+		})
+		envVars = append(envVars, kms.EnvVars(security.KeyManagementService)...)
+	} else {
+		// The old key of the OSD and the new key staged by the operator are mounted from their Secrets. Once the new
+		// key is stored the old key is read from the staged Secret and the new key from the Secret of the OSD.
+		oldKey := v1.SecretProjection{
+			LocalObjectReference: v1.LocalObjectReference{Name: generateOSDEncryptionSecretName(pvcName)},
+			Items:                []v1.KeyToPath{{Key: OsdEncryptionSecretNameKeyName, Path: encryptionKeyFileName}},
+		}
+		newKey := v1.SecretProjection{
+			LocalObjectReference: v1.LocalObjectReference{Name: stagedEncryptionKeySecretName(pvcName)},
+			Items:                []v1.KeyToPath{{Key: OsdEncryptionSecretNameKeyName, Path: encryptionKeyFileName + NewEncryptionKeyFileSuffix}},
+		}
+		if removeOldKey {
+			oldKey.Name, newKey.Name = newKey.Name, oldKey.Name
+			oldKey.Items[0].Key = oldEncryptionKeyName
+		}
+		var mode int32 = 0400
+		volumes = append(volumes, v1.Volume{
+			Name: osdEncryptionVolName,
+			VolumeSource: v1.VolumeSource{
+				Projected: &v1.ProjectedVolumeSource{
+					Sources:     []v1.VolumeProjection{{Secret: &oldKey}, {Secret: &newKey}},
+					DefaultMode: &mode,
+				},
+			},
+		})
+		keyVolumeMount.ReadOnly = true
+	}
+
+	args := []string{
+		"ceph", "osd", "rotate-key",
+		"--key-name", generateOSDEncryptionSecretName(pvcName),
+		"--key-path", encryptionKeyPath(),
+		"--block-paths", strings.Join(blockPaths, ","),
+	}
+	if removeOldKey {
+		args = append(args, "--remove-old-key")
+	}
+	container := v1.Container{
+		Name:            "rotate-key",
+		Image:           k.rookImage,
+		Args:            args,
+		Env:             envVars,
+		VolumeDevices:   blockDevices,
+		VolumeMounts:    []v1.VolumeMount{keyVolumeMount},
+		SecurityContext: PrivilegedContext(),
+	}
+
+	backoffLimit := int32(0)
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      k8sutil.TruncateNodeName(keyRotationAppNameFmt, pvcName),
+			Namespace: k.clusterInfo.Namespace,
+			Labels: map[string]string{
+				k8sutil.AppAttr:     keyRotationAppName,
+				k8sutil.ClusterAttr: k.clusterInfo.Namespace,
+				OSDOverPVCLabelKey:  pvcName,
+			},
+		},
+		Spec: batch.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						k8sutil.AppAttr:     keyRotationAppName,
+						k8sutil.ClusterAttr: k.clusterInfo.Namespace,
+						OSDOverPVCLabelKey:  pvcName,
+					},
+				},
+				Spec: v1.PodSpec{
+					ServiceAccountName: serviceAccountName,
+					Containers:         []v1.Container{container},
+					RestartPolicy:      v1.RestartPolicyNever,
+					Volumes:            volumes,
+					NodeSelector:       map[string]string{v1.LabelHostname: hostname},
+					Tolerations:        podSpec.Tolerations,
+					PriorityClassName:  podSpec.PriorityClassName,
+					// cryptsetup synchronizes with udev on the host through semaphores
+					HostIPC: true,
+				},
+			},
+		},
+	}
+	k8sutil.AddRookVersionLabelToJob(job)
+	k8sutil.SetOwnerRef(&job.ObjectMeta, &k.clusterInfo.OwnerRef)
+
+	return job, nil
+}
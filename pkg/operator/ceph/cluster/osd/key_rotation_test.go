@@ -0,0 +1,262 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	gocontext "context"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testexec "github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func encryptedOSDDeployment(namespace string) *apps.Deployment {
+	return &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rook-ceph-osd-0",
+			Namespace: namespace,
+			Labels: map[string]string{
+				k8sutil.AppAttr:    AppName,
+				OsdIdLabelKey:      "0",
+				OSDOverPVCLabelKey: "set1-data-0",
+			},
+		},
+		Spec: apps.DeploymentSpec{
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					InitContainers: []v1.Container{
+						{Name: blockEncryptionOpenInitContainer, VolumeDevices: []v1.VolumeDevice{{Name: "set1-data-0", DevicePath: "/set1-data-0"}}},
+						{Name: blockEncryptionOpenMetadataInitContainer, VolumeDevices: []v1.VolumeDevice{{Name: "set1-metadata-0", DevicePath: "/set1-metadata-0"}}},
+						{Name: blockPVCMapperEncryptionInitContainer},
+					},
+					Volumes: []v1.Volume{
+						{Name: "set1-data-0", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "set1-data-0"}}},
+						{Name: "set1-metadata-0", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "set1-metadata-0"}}},
+						{Name: osdEncryptionVolName},
+					},
+					Tolerations: []v1.Toleration{{Key: "storage-node", Operator: v1.TolerationOpExists}},
+				},
+			},
+		},
+	}
+}
+
+func TestKeyRotationPeriod(t *testing.T) {
+	period, err := keyRotationPeriod(cephv1.KeyRotationSpec{})
+	assert.NoError(t, err)
+	assert.Equal(t, 90*24*time.Hour, period)
+
+	period, err = keyRotationPeriod(cephv1.KeyRotationSpec{Period: "720h"})
+	assert.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, period)
+
+	_, err = keyRotationPeriod(cephv1.KeyRotationSpec{Period: "90d"})
+	assert.Error(t, err)
+	_, err = keyRotationPeriod(cephv1.KeyRotationSpec{Period: "-1h"})
+	assert.Error(t, err)
+}
+
+func TestIsEncryptedOSDOnPVC(t *testing.T) {
+	d := encryptedOSDDeployment("ns")
+	assert.True(t, isEncryptedOSDOnPVC(d))
+
+	d.Spec.Template.Spec.InitContainers = d.Spec.Template.Spec.InitContainers[2:]
+	assert.False(t, isEncryptedOSDOnPVC(d))
+
+	d = encryptedOSDDeployment("ns")
+	delete(d.Labels, OSDOverPVCLabelKey)
+	assert.False(t, isEncryptedOSDOnPVC(d))
+}
+
+func TestMakeKeyRotationJob(t *testing.T) {
+	clusterInfo := client.AdminClusterInfo("ns")
+	k := NewKeyRotationController(&clusterd.Context{}, clusterInfo, "rook/ceph:myversion")
+	d := encryptedOSDDeployment("ns")
+
+	job, err := k.makeKeyRotationJob(d, "set1-data-0", "node1", cephv1.SecuritySpec{}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "rook-ceph-osd-key-rotation-set1-data-0", job.Name)
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	podSpec := job.Spec.Template.Spec
+	assert.Equal(t, "node1", podSpec.NodeSelector[v1.LabelHostname])
+	assert.Equal(t, serviceAccountName, podSpec.ServiceAccountName)
+	assert.True(t, podSpec.HostIPC)
+	assert.Equal(t, 1, len(podSpec.Tolerations))
+	// the two blocks and the keys mounted from the secret of the osd and the staged secret
+	assert.Equal(t, 3, len(podSpec.Volumes))
+	sources := podSpec.Volumes[2].Projected.Sources
+	assert.Equal(t, 2, len(sources))
+	assert.Equal(t, "rook-ceph-osd-encryption-key-set1-data-0", sources[0].Secret.Name)
+	assert.Equal(t, "luks_key", sources[0].Secret.Items[0].Path)
+	assert.Equal(t, "rook-ceph-osd-key-rotation-set1-data-0", sources[1].Secret.Name)
+	assert.Equal(t, "luks_key.new", sources[1].Secret.Items[0].Path)
+	assert.True(t, podSpec.Containers[0].VolumeMounts[0].ReadOnly)
+
+	container := podSpec.Containers[0]
+	assert.Equal(t, "rook/ceph:myversion", container.Image)
+	assert.Equal(t, []string{"ceph", "osd", "rotate-key",
+		"--key-name", "rook-ceph-osd-encryption-key-set1-data-0",
+		"--key-path", "/etc/ceph/luks_key",
+		"--block-paths", "/set1-data-0,/set1-metadata-0"}, container.Args)
+	assert.Equal(t, 2, len(container.VolumeDevices))
+	assert.Equal(t, 1, len(container.Env))
+
+	// once the new key is stored the old key is read from the staged secret
+	job, err = k.makeKeyRotationJob(d, "set1-data-0", "node1", cephv1.SecuritySpec{}, true)
+	assert.NoError(t, err)
+	sources = job.Spec.Template.Spec.Volumes[2].Projected.Sources
+	assert.Equal(t, "rook-ceph-osd-key-rotation-set1-data-0", sources[0].Secret.Name)
+	assert.Equal(t, v1.KeyToPath{Key: "old-dmcrypt-key", Path: "luks_key"}, sources[0].Secret.Items[0])
+	assert.Equal(t, "rook-ceph-osd-encryption-key-set1-data-0", sources[1].Secret.Name)
+	assert.Equal(t, v1.KeyToPath{Key: "dmcrypt-key", Path: "luks_key.new"}, sources[1].Secret.Items[0])
+	assert.Equal(t, "--remove-old-key", job.Spec.Template.Spec.Containers[0].Args[len(container.Args)])
+
+	// the KMS connection details are passed to the job
+	security := cephv1.SecuritySpec{KeyManagementService: cephv1.KeyManagementServiceSpec{
+		ConnectionDetails: map[string]string{kms.ProviderKey: kms.VaultProvider},
+		TokenSecretName:   "vault-token",
+	}}
+	job, err = k.makeKeyRotationJob(d, "set1-data-0", "node1", security, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(job.Spec.Template.Spec.Containers[0].Env))
+	// the keys are written by the job to a memory backed volume
+	assert.Equal(t, v1.StorageMediumMemory, job.Spec.Template.Spec.Volumes[2].EmptyDir.Medium)
+
+	// not an encrypted osd
+	d.Spec.Template.Spec.InitContainers = nil
+	_, err = k.makeKeyRotationJob(d, "set1-data-0", "node1", security, false)
+	assert.Error(t, err)
+}
+
+func TestRotateKeys(t *testing.T) {
+	clusterInfo := client.AdminClusterInfo("ns")
+	clusterInfo.SetName("rook-ceph")
+	clientset := testexec.New(t, 1)
+
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph", Namespace: "ns"},
+		Spec: cephv1.ClusterSpec{
+			Security: cephv1.SecuritySpec{KeyRotation: cephv1.KeyRotationSpec{Enabled: true, Period: "24h"}},
+		},
+		Status: cephv1.ClusterStatus{
+			KeyRotation: &cephv1.KeyRotationStatus{
+				OSDs: []cephv1.OSDKeyRotationStatus{
+					{ID: 0, Phase: keyRotationPhaseSucceeded, LastRotation: time.Now().UTC().Format(time.RFC3339)},
+				},
+			},
+		},
+	}
+	cl := fake.NewFakeClientWithScheme(scheme.Scheme, []runtime.Object{cephCluster}...)
+	context := &clusterd.Context{Clientset: clientset, Client: cl}
+
+	_, err := clientset.AppsV1().Deployments("ns").Create(encryptedOSDDeployment("ns"))
+	assert.NoError(t, err)
+
+	// the key was rotated recently, no job is started
+	k := NewKeyRotationController(context, clusterInfo, "rook/ceph:myversion")
+	k.rotateKeys()
+	jobs, err := clientset.BatchV1().Jobs("ns").List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(jobs.Items))
+
+	// the status of a new osd is added
+	k.updateKeyRotationStatus(cephv1.OSDKeyRotationStatus{ID: 3, PVCName: "set1-data-1", Phase: keyRotationPhaseFailed, Message: "failed"})
+	// the status of an existing osd is replaced
+	k.updateKeyRotationStatus(cephv1.OSDKeyRotationStatus{ID: 0, PVCName: "set1-data-0", Phase: keyRotationPhaseRotating})
+	err = cl.Get(gocontext.TODO(), clusterInfo.NamespacedName(), cephCluster)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(cephCluster.Status.KeyRotation.OSDs))
+	assert.Equal(t, keyRotationPhaseRotating, OSDKeyRotationStatus(cephCluster.Status.KeyRotation, 0).Phase)
+	assert.Equal(t, "set1-data-1", OSDKeyRotationStatus(cephCluster.Status.KeyRotation, 3).PVCName)
+	assert.Equal(t, 5, OSDKeyRotationStatus(cephCluster.Status.KeyRotation, 5).ID)
+	assert.Equal(t, "", OSDKeyRotationStatus(nil, 5).Phase)
+}
+
+func TestStageEncryptionKey(t *testing.T) {
+	clusterInfo := client.AdminClusterInfo("ns")
+	clientset := testexec.New(t, 1)
+	k := NewKeyRotationController(&clusterd.Context{Clientset: clientset}, clusterInfo, "rook/ceph:myversion")
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-encryption-key-set1-data-0", Namespace: "ns"},
+		Data:       map[string][]byte{OsdEncryptionSecretNameKeyName: []byte("old")},
+	}
+	_, err := clientset.CoreV1().Secrets("ns").Create(secret)
+	assert.NoError(t, err)
+	getKey := func(name string) string {
+		s, err := clientset.CoreV1().Secrets("ns").Get(name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		return string(s.Data[OsdEncryptionSecretNameKeyName])
+	}
+
+	// a new key is staged
+	rotated, err := k.stageEncryptionKey("set1-data-0")
+	assert.NoError(t, err)
+	assert.False(t, rotated)
+	staged, err := clientset.CoreV1().Secrets("ns").Get("rook-ceph-osd-key-rotation-set1-data-0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	newKey := string(staged.Data[OsdEncryptionSecretNameKeyName])
+	assert.NotEqual(t, "", newKey)
+	assert.NotEqual(t, "old", newKey)
+
+	// the key staged by a failed rotation is kept
+	rotated, err = k.stageEncryptionKey("set1-data-0")
+	assert.NoError(t, err)
+	assert.False(t, rotated)
+	assert.Equal(t, newKey, getKey("rook-ceph-osd-key-rotation-set1-data-0"))
+
+	// the staged key is stored in the secret of the osd once the job succeeded, the old key is kept in the staged secret
+	err = k.storeEncryptionKey("set1-data-0")
+	assert.NoError(t, err)
+	assert.Equal(t, newKey, getKey(secret.Name))
+	staged, err = clientset.CoreV1().Secrets("ns").Get("rook-ceph-osd-key-rotation-set1-data-0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "old", string(staged.Data[oldEncryptionKeyName]))
+
+	// storing the key again does not overwrite the saved old key
+	err = k.storeEncryptionKey("set1-data-0")
+	assert.NoError(t, err)
+	staged, err = clientset.CoreV1().Secrets("ns").Get("rook-ceph-osd-key-rotation-set1-data-0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "old", string(staged.Data[oldEncryptionKeyName]))
+
+	// the rotation stopped after storing the staged key, only the old keyslots remain to be removed
+	stored, err := k.stageEncryptionKey("set1-data-0")
+	assert.NoError(t, err)
+	assert.True(t, stored)
+	assert.Equal(t, newKey, getKey("rook-ceph-osd-key-rotation-set1-data-0"))
+
+	// the staged secret is deleted once the old keyslots are removed
+	err = k.deleteStagedEncryptionKey("set1-data-0")
+	assert.NoError(t, err)
+	_, err = clientset.CoreV1().Secrets("ns").Get("rook-ceph-osd-key-rotation-set1-data-0", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+	err = k.deleteStagedEncryptionKey("set1-data-0")
+	assert.NoError(t, err)
+}
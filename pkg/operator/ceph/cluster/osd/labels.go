@@ -32,6 +32,8 @@ const (
 	CephDeviceSetPVCIDLabelKey = "ceph.rook.io/DeviceSetPVCId"
 	// OSDOverPVCLabelKey is the Rook PVC label key
 	OSDOverPVCLabelKey = "ceph.rook.io/pvc"
+	// OSDReplaceIDAnnotationKey is the annotation of the new PVC of a replaced OSD with the ID to re-create the OSD with
+	OSDReplaceIDAnnotationKey = "ceph.rook.io/replace-osd-id"
 )
 
 func makeStorageClassDeviceSetPVCLabel(storageClassDeviceSetName, pvcStorageClassDeviceSetPVCId string, setIndex int) map[string]string {
@@ -33,6 +33,7 @@ import (
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	osdconfig "github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
 	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
@@ -140,6 +141,8 @@ type osdProperties struct {
 	crushDeviceClass    string
 	encrypted           bool
 	deviceSetName       string
+	// replaceOSDID is the ID of the destroyed OSD re-created on the PVC
+	replaceOSDID *int
 	// Drive Groups which apply to the node
 	driveGroups cephv1.DriveGroupsSpec
 }
@@ -276,25 +279,16 @@ func (c *Cluster) startProvisioningOverPVCs(config *provisionConfig) {
 				continue
 			}
 
-			// create encryption Kubernetes Secret if the PVC is encrypted
-			key, err := generateDmCryptKey()
-			if err != nil {
-				errMsg := fmt.Sprintf("failed to generate dmcrypt key for osd claim %q. %v", osdProps.pvc.ClaimName, err)
-				config.addError(errMsg)
-				continue
-			}
-
-			// Store the kube secret here!
-			s := generateOSDEncryptedKeySecret(osdProps.pvc.ClaimName, c.clusterInfo.Namespace, key)
-
-			// Set the ownerref to the Secret
-			k8sutil.SetOwnerRef(&s.ObjectMeta, &c.clusterInfo.OwnerRef)
-
-			// Create the Kubernetes Secret
-			_, err = c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Create(s)
-			if err != nil && !kerrors.IsAlreadyExists(err) {
-				errMsg := fmt.Sprintf("failed to save ceph osd encryption key as a secret for pvc %q. %v", osdProps.pvc.ClaimName, err)
-				config.addError(errMsg)
+			// With a KMS, the prepare job generates the key and stores it in the KMS so it never lands in etcd
+			if c.spec.Security.KeyManagementService.IsEnabled() {
+				err := kms.ValidateConnectionDetails(c.context.Clientset, c.clusterInfo.Namespace, &c.spec.Security.KeyManagementService)
+				if err != nil {
+					config.addError("failed to validate kms connection details for storageClassDeviceSet %q. %v", volume.Name, err)
+					continue
+				}
+				logger.Infof("encryption key of osd claim %q is stored in the kms", osdProps.pvc.ClaimName)
+			} else if err := c.createOSDEncryptionSecret(osdProps.pvc.ClaimName); err != nil {
+				config.addError(err.Error())
 				continue
 			}
 		}
@@ -313,6 +307,13 @@ func (c *Cluster) startProvisioningOverPVCs(config *provisionConfig) {
 			continue
 		}
 
+		// The new PVC of a replaced OSD is prepared with the ID of the destroyed OSD
+		osdProps.replaceOSDID, err = c.getReplaceOSDID(dataSource.ClaimName)
+		if err != nil {
+			config.addError("failed to get the osd to replace on pvc %q. %v", dataSource.ClaimName, err)
+			continue
+		}
+
 		// Update the orchestration status of this pvc to the starting state
 		status := OrchestrationStatus{Status: OrchestrationStatusStarting, PvcBackedOSD: true}
 		c.updateOSDStatus(osdProps.crushHostname, status)
@@ -773,19 +774,17 @@ func (c *Cluster) getOSDInfo(d *apps.Deployment) ([]OSDInfo, error) {
 	container := d.Spec.Template.Spec.Containers[0]
 	var osd OSDInfo
 
-	osdID, err := strconv.Atoi(d.Labels[OsdIdLabelKey])
+	osdID, err := osdIDFromDeployment(d)
 	if err != nil {
-		return []OSDInfo{}, errors.Wrap(err, "error parsing ceph-osd-id")
+		return []OSDInfo{}, err
 	}
 	osd.ID = osdID
+	osd.BlockPath = blockPathFromEnv(container.Env)
 
-	for _, envVar := range d.Spec.Template.Spec.Containers[0].Env {
+	for _, envVar := range container.Env {
 		if envVar.Name == "ROOK_OSD_UUID" {
 			osd.UUID = envVar.Value
 		}
-		if envVar.Name == "ROOK_BLOCK_PATH" || envVar.Name == "ROOK_LV_PATH" {
-			osd.BlockPath = envVar.Value
-		}
 		if envVar.Name == "ROOK_CV_MODE" {
 			osd.CVMode = envVar.Value
 		}
@@ -810,16 +809,8 @@ func (c *Cluster) getOSDInfo(d *apps.Deployment) ([]OSDInfo, error) {
 		osd.CVMode = "lvm"
 	}
 
-	locationFound := false
-	for _, a := range container.Args {
-		locationPrefix := "--crush-location="
-		if strings.HasPrefix(a, locationPrefix) {
-			locationFound = true
-			// Extract the same CRUSH location as originally determined by the OSD prepare pod
-			// by cutting off the prefix: --crush-location=
-			osd.Location = a[len(locationPrefix):]
-		}
-	}
+	location, locationFound := crushLocationFromArgs(container.Args)
+	osd.Location = location
 
 	if !locationFound {
 		location, err := getLocationFromPod(c.context.Clientset, d)
@@ -837,6 +828,40 @@ func (c *Cluster) getOSDInfo(d *apps.Deployment) ([]OSDInfo, error) {
 	return []OSDInfo{osd}, nil
 }
 
+// osdIDFromDeployment returns the id of the OSD run by the deployment
+func osdIDFromDeployment(d *apps.Deployment) (int, error) {
+	osdID, err := strconv.Atoi(d.Labels[OsdIdLabelKey])
+	if err != nil {
+		return 0, errors.Wrap(err, "error parsing ceph-osd-id")
+	}
+	return osdID, nil
+}
+
+// blockPathFromEnv returns the path of the OSD block device in the env of the OSD container
+func blockPathFromEnv(env []v1.EnvVar) string {
+	blockPath := ""
+	for _, envVar := range env {
+		if envVar.Name == "ROOK_BLOCK_PATH" || envVar.Name == "ROOK_LV_PATH" {
+			blockPath = envVar.Value
+		}
+	}
+	return blockPath
+}
+
+// crushLocationFromArgs returns the CRUSH location in the args of the OSD container, as originally determined by the
+// OSD prepare pod, and whether it was found
+func crushLocationFromArgs(args []string) (string, bool) {
+	locationPrefix := "--crush-location="
+	location, found := "", false
+	for _, a := range args {
+		if strings.HasPrefix(a, locationPrefix) {
+			// cut off the prefix: --crush-location=
+			location, found = a[len(locationPrefix):], true
+		}
+	}
+	return location, found
+}
+
 func getLocationFromPod(clientset kubernetes.Interface, d *apps.Deployment) (string, error) {
 	pods, err := clientset.CoreV1().Pods(d.Namespace).List(metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", OsdIdLabelKey, d.Labels[OsdIdLabelKey])})
 	if err != nil || len(pods.Items) == 0 {
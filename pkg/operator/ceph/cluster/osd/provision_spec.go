@@ -23,6 +23,7 @@ import (
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
 	"github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/k8sutil"
@@ -271,9 +272,18 @@ func (c *Cluster) provisionOSDContainer(osdProps osdProperties, copyBinariesMoun
 		envVars = append(envVars, pvcBackedOSDEnvVar("true"))
 		envVars = append(envVars, crushDeviceClassEnvVar(osdProps.crushDeviceClass))
 		envVars = append(envVars, encryptedDeviceEnvVar(osdProps.encrypted))
+		if osdProps.replaceOSDID != nil {
+			envVars = append(envVars, replaceOSDIDEnvVar(*osdProps.replaceOSDID))
+		}
 
 		if osdProps.encrypted {
-			envVars = append(envVars, cephVolumeRawEncryptedEnvVar(osdProps.pvc.ClaimName))
+			// With a KMS, the prepare job fetches the key or generates it and stores it in the KMS
+			if c.spec.Security.KeyManagementService.IsEnabled() {
+				envVars = append(envVars, kms.EnvVars(c.spec.Security.KeyManagementService)...)
+				envVars = append(envVars, encryptionKeyNameEnvVar(osdProps.pvc.ClaimName))
+			} else {
+				envVars = append(envVars, cephVolumeRawEncryptedEnvVar(osdProps.pvc.ClaimName))
+			}
 		}
 	}
 
@@ -0,0 +1,280 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package removal to remove OSDs from a cluster on request
+package removal
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/disruption/controllerconfig"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-osd-removal-controller"
+)
+
+// waitForRemoval is the result while the OSDs are draining or their disks are sanitized
+var waitForRemoval = reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephOSDRemovalKind = reflect.TypeOf(cephv1.CephOSDRemoval{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephOSDRemovalKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileCephOSDRemoval reconciles a CephOSDRemoval object
+type ReconcileCephOSDRemoval struct {
+	client      client.Client
+	scheme      *runtime.Scheme
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+	rookImage   string
+}
+
+// Add creates a new CephOSDRemoval Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *controllerconfig.Context) error {
+	return add(mgr, newReconciler(mgr, context))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *controllerconfig.Context) reconcile.Reconciler {
+	// Add the cephv1 scheme to the manager scheme so that the controller knows about it
+	mgrScheme := mgr.GetScheme()
+	if err := cephv1.AddToScheme(mgr.GetScheme()); err != nil {
+		panic(err)
+	}
+	return &ReconcileCephOSDRemoval{
+		client:    mgr.GetClient(),
+		scheme:    mgrScheme,
+		context:   context.ClusterdContext,
+		rookImage: context.RookImage,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephOSDRemoval CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephOSDRemoval{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephOSDRemoval object and makes changes based on the state read
+// and what is in the CephOSDRemoval.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephOSDRemoval) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime loggin interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile: %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileCephOSDRemoval) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephOSDRemoval instance
+	removal := &cephv1.CephOSDRemoval{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, removal)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephOSDRemoval resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to get CephOSDRemoval")
+	}
+
+	// The removal was completed or failed, nothing left to do
+	if removal.Status != nil && (removal.Status.Phase == cephv1.ConditionReady || removal.Status.Phase == cephv1.ConditionFailure) {
+		logger.Debugf("removal of osds %v is done with phase %q", removal.Spec.OSDIDs, removal.Status.Phase)
+		return reconcile.Result{}, nil
+	}
+
+	// Nothing to clean up when the request is deleted, the removal of the OSDs stops
+	if !removal.GetDeletionTimestamp().IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	// validate the removal settings
+	if err := validateOSDRemoval(removal); err != nil {
+		updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure, nil)
+		return reconcile.Result{}, errors.Wrapf(err, "invalid CephOSDRemoval CR %q spec", removal.Name)
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	cephCluster, isReadyToReconcile, _, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		logger.Debugf("CephCluster resource not ready in namespace %q, retrying in %q.", request.NamespacedName.Namespace, reconcileResponse.RequeueAfter.String())
+		return reconcileResponse, nil
+	}
+
+	// Populate clusterInfo during each reconcile
+	r.clusterInfo, _, _, err = mon.LoadClusterInfo(r.context, request.NamespacedName.Namespace)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to populate cluster info")
+	}
+
+	// Populate CephVersion, the OSDs prepared in raw mode can only be replaced by recent versions
+	currentCephVersion, err := cephclient.LeastUptodateDaemonVersion(r.context, r.clusterInfo, opconfig.MonType)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to retrieve current ceph %q version", opconfig.MonType)
+	}
+	r.clusterInfo.CephVersion = currentCephVersion
+
+	// Remove the OSDs, each OSD goes through its removal phases independently
+	osds := initOSDsStatus(removal)
+	osdDump, err := cephclient.GetOSDDump(r.context, r.clusterInfo)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to get osd dump")
+	}
+	for i := range osds {
+		r.removeOSD(removal, &cephCluster, osdDump, &osds[i])
+	}
+
+	phase := removalPhase(osds)
+	updateStatus(r.client, request.NamespacedName, phase, osds)
+	if phase == cephv1.ConditionProgressing {
+		return waitForRemoval, nil
+	}
+
+	// Return and do not requeue
+	logger.Infof("removal of osds %v done with phase %q", removal.Spec.OSDIDs, phase)
+	return reconcile.Result{}, nil
+}
+
+// validateOSDRemoval validates the removal arguments
+func validateOSDRemoval(removal *cephv1.CephOSDRemoval) error {
+	if len(removal.Spec.OSDIDs) == 0 {
+		return errors.New("missing osdIDs")
+	}
+
+	ids := map[int]struct{}{}
+	for _, id := range removal.Spec.OSDIDs {
+		if id < 0 {
+			return errors.Errorf("invalid osd id %d", id)
+		}
+		if _, ok := ids[id]; ok {
+			return errors.Errorf("duplicate osd id %d", id)
+		}
+		ids[id] = struct{}{}
+	}
+
+	// the PVCs of the replaced OSDs are re-created for the new OSDs
+	if removal.Spec.Replace && (removal.Spec.SanitizeDisks != nil || removal.Spec.PreservePVC) {
+		return errors.New("replace cannot be combined with sanitizeDisks or preservePVC")
+	}
+
+	return nil
+}
+
+// initOSDsStatus returns the removal status of each OSD of the request
+func initOSDsStatus(removal *cephv1.CephOSDRemoval) []cephv1.OSDRemovalStatus {
+	osds := []cephv1.OSDRemovalStatus{}
+	for _, id := range removal.Spec.OSDIDs {
+		status := cephv1.OSDRemovalStatus{ID: id, Phase: cephv1.OSDRemovalPending}
+		if removal.Status != nil {
+			for _, s := range removal.Status.OSDs {
+				if s.ID == id {
+					status = s
+				}
+			}
+		}
+		osds = append(osds, status)
+	}
+	return osds
+}
+
+// removalPhase returns the phase of the request, which is progressing until all the OSDs are either removed or failed
+func removalPhase(osds []cephv1.OSDRemovalStatus) cephv1.ConditionType {
+	phase := cephv1.ConditionReady
+	for _, osd := range osds {
+		switch osd.Phase {
+		case cephv1.OSDRemovalCompleted:
+		case cephv1.OSDRemovalFailed:
+			if phase == cephv1.ConditionReady {
+				phase = cephv1.ConditionFailure
+			}
+		default:
+			phase = cephv1.ConditionProgressing
+		}
+	}
+	return phase
+}
+
+// updateStatus updates an OSD removal request with a given status
+func updateStatus(client client.Client, name types.NamespacedName, status cephv1.ConditionType, osds []cephv1.OSDRemovalStatus) {
+	removal := &cephv1.CephOSDRemoval{}
+	if err := client.Get(context.TODO(), name, removal); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephOSDRemoval resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve osd removal %q to update status to %q. %v", name, status, err)
+		return
+	}
+
+	if removal.Status == nil {
+		removal.Status = &cephv1.CephOSDRemovalStatus{}
+	}
+
+	removal.Status.Phase = status
+	if osds != nil {
+		removal.Status.OSDs = osds
+	}
+	if err := opcontroller.UpdateStatus(client, removal); err != nil {
+		logger.Warningf("failed to set osd removal %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("osd removal %q status updated to %q", name, status)
+}
@@ -0,0 +1,455 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package removal
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestValidateOSDRemoval(t *testing.T) {
+	removal := &cephv1.CephOSDRemoval{}
+	assert.Error(t, validateOSDRemoval(removal))
+
+	removal.Spec.OSDIDs = []int{0, 1}
+	assert.NoError(t, validateOSDRemoval(removal))
+
+	removal.Spec.OSDIDs = []int{0, 0}
+	assert.Error(t, validateOSDRemoval(removal))
+
+	removal.Spec.OSDIDs = []int{-1}
+	assert.Error(t, validateOSDRemoval(removal))
+
+	removal.Spec.OSDIDs = []int{0}
+	removal.Spec.Replace = true
+	assert.NoError(t, validateOSDRemoval(removal))
+
+	removal.Spec.PreservePVC = true
+	assert.Error(t, validateOSDRemoval(removal))
+
+	removal.Spec.PreservePVC = false
+	removal.Spec.SanitizeDisks = &cephv1.SanitizeDisksSpec{}
+	assert.Error(t, validateOSDRemoval(removal))
+}
+
+func TestRemovalPhase(t *testing.T) {
+	osds := []cephv1.OSDRemovalStatus{{ID: 0, Phase: cephv1.OSDRemovalCompleted}, {ID: 1, Phase: cephv1.OSDRemovalDraining}}
+	assert.Equal(t, cephv1.ConditionProgressing, removalPhase(osds))
+
+	osds[1].Phase = cephv1.OSDRemovalFailed
+	assert.Equal(t, cephv1.ConditionFailure, removalPhase(osds))
+
+	osds[1].Phase = cephv1.OSDRemovalCompleted
+	assert.Equal(t, cephv1.ConditionReady, removalPhase(osds))
+}
+
+func TestCephOSDRemovalController(t *testing.T) {
+	namespace := "rook-ceph"
+	removal := &cephv1.CephOSDRemoval{
+		ObjectMeta: metav1.ObjectMeta{Name: "remove-osds", Namespace: namespace},
+		TypeMeta:   metav1.TypeMeta{Kind: "CephOSDRemoval"},
+		Spec: cephv1.OSDRemovalSpec{
+			OSDIDs:        []int{0, 1},
+			SanitizeDisks: &cephv1.SanitizeDisksSpec{Method: cephv1.SanitizeMethodQuick},
+		},
+	}
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+		Status: cephv1.ClusterStatus{
+			Phase:      k8sutil.ReadyStatus,
+			CephStatus: &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+
+	safeToDestroy := false
+	commands := [][]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "status" {
+				return `{"fsid":"c47cac40-9bee-4d52-823b-ccd803ba5bfe","health":{"checks":{},"status":"HEALTH_OK"},"pgmap":{"num_pgs":100,"pgs_by_state":[{"state_name":"active+clean","count":100}]}}`, nil
+			}
+			if args[0] == "osd" {
+				switch args[1] {
+				case "dump":
+					return `{"osds":[{"osd":0,"up":1,"in":1}]}`, nil
+				case "safe-to-destroy":
+					if safeToDestroy {
+						return `{"safe_to_destroy":[0]}`, nil
+					}
+					return `{"safe_to_destroy":[]}`, nil
+				case "ok-to-stop", "out", "purge":
+					commands = append(commands, args[:3])
+				}
+			}
+			return "", nil
+		},
+	}
+	clientset := test.New(t, 3)
+	c := &clusterd.Context{
+		Executor:      executor,
+		RookClientset: rookclient.NewSimpleClientset(),
+		Clientset:     clientset,
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+		Data: map[string][]byte{
+			"fsid":         []byte("c47cac40-9bee-4d52-823b-ccd803ba5bfe"),
+			"mon-secret":   []byte("monsecret"),
+			"admin-secret": []byte("adminsecret"),
+		},
+		Type: k8sutil.RookType,
+	}
+	_, err := clientset.CoreV1().Secrets(namespace).Create(secret)
+	assert.NoError(t, err)
+	deployment := &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-0", Namespace: namespace, Labels: map[string]string{osd.OsdIdLabelKey: "0"}},
+		Spec: apps.DeploymentSpec{
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{NodeSelector: map[string]string{v1.LabelHostname: "node0"}},
+			},
+		},
+	}
+	_, err = clientset.AppsV1().Deployments(namespace).Create(deployment)
+	assert.NoError(t, err)
+
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephOSDRemoval{}, &cephv1.CephOSDRemovalList{},
+		&cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	cl := fake.NewFakeClientWithScheme(s, []runtime.Object{removal, cephCluster}...)
+	r := &ReconcileCephOSDRemoval{client: cl, scheme: s, context: c, rookImage: "rook/ceph:myversion"}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "remove-osds", Namespace: namespace}}
+
+	osdStatus := func(id int) cephv1.OSDRemovalStatus {
+		err := cl.Get(context.TODO(), req.NamespacedName, removal)
+		assert.NoError(t, err)
+		return removal.Status.OSDs[id]
+	}
+
+	//
+	// TEST 1: osd.0 is marked out and osd.1 is not in the osd map
+	//
+	res, err := r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.Equal(t, [][]string{{"osd", "ok-to-stop", "0"}, {"osd", "out", "0"}}, commands)
+	assert.Equal(t, cephv1.OSDRemovalDraining, osdStatus(0).Phase)
+	assert.Equal(t, "node0", osdStatus(0).Host)
+	assert.Equal(t, cephv1.ConditionProgressing, removal.Status.Phase)
+	assert.Equal(t, cephv1.OSDRemovalDraining, osdStatus(1).Phase)
+
+	//
+	// TEST 2: osd.0 waits for backfill and osd.1 is removed
+	//
+	commands = [][]string{}
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.Equal(t, 0, len(commands))
+	assert.Equal(t, cephv1.OSDRemovalDraining, osdStatus(0).Phase)
+	assert.Contains(t, osdStatus(0).Message, "backfill")
+	assert.Equal(t, cephv1.OSDRemovalCompleted, osdStatus(1).Phase)
+
+	//
+	// TEST 3: osd.0 is purged and its disks are sanitized
+	//
+	safeToDestroy = true
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.Equal(t, [][]string{{"osd", "purge", "osd.0"}}, commands)
+	assert.Equal(t, cephv1.OSDRemovalSanitizing, osdStatus(0).Phase)
+	assert.Equal(t, "node0", osdStatus(0).Host)
+	_, err = clientset.AppsV1().Deployments(namespace).Get("rook-ceph-osd-0", metav1.GetOptions{})
+	assert.Error(t, err)
+	job, err := clientset.BatchV1().Jobs(namespace).Get(sanitizeJobName(0), metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "node0", job.Spec.Template.Spec.NodeSelector[v1.LabelHostname])
+	assert.Contains(t, job.Spec.Template.Spec.Containers[0].Env, v1.EnvVar{Name: "ROOK_SANITIZE_OSD_IDS", Value: "0"})
+
+	//
+	// TEST 4: the removal is completed once the disks are sanitized
+	//
+	job.Status.Succeeded = 1
+	_, err = clientset.BatchV1().Jobs(namespace).Update(job)
+	assert.NoError(t, err)
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, cephv1.OSDRemovalCompleted, osdStatus(0).Phase)
+	assert.Equal(t, cephv1.ConditionReady, removal.Status.Phase)
+}
+
+func TestCephOSDRemovalReplace(t *testing.T) {
+	namespace := "rook-ceph"
+	removal := &cephv1.CephOSDRemoval{
+		ObjectMeta: metav1.ObjectMeta{Name: "replace-osds", Namespace: namespace},
+		TypeMeta:   metav1.TypeMeta{Kind: "CephOSDRemoval"},
+		Spec: cephv1.OSDRemovalSpec{
+			OSDIDs:  []int{2},
+			Replace: true,
+		},
+	}
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+		Status: cephv1.ClusterStatus{
+			Phase:      k8sutil.ReadyStatus,
+			CephStatus: &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+
+	osdDump := `{"osds":[{"osd":2,"up":1,"in":1}]}`
+	commands := [][]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "status" {
+				return `{"fsid":"c47cac40-9bee-4d52-823b-ccd803ba5bfe","health":{"checks":{},"status":"HEALTH_OK"},"pgmap":{"num_pgs":100,"pgs_by_state":[{"state_name":"active+clean","count":100}]}}`, nil
+			}
+			if args[0] == "osd" {
+				switch args[1] {
+				case "dump":
+					return osdDump, nil
+				case "safe-to-destroy":
+					return `{"safe_to_destroy":[2]}`, nil
+				case "ok-to-stop", "out", "destroy", "in":
+					commands = append(commands, args[:3])
+				}
+			}
+			return "", nil
+		},
+	}
+	clientset := test.New(t, 3)
+	c := &clusterd.Context{
+		Executor:      executor,
+		RookClientset: rookclient.NewSimpleClientset(),
+		Clientset:     clientset,
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+		Data: map[string][]byte{
+			"fsid":         []byte("c47cac40-9bee-4d52-823b-ccd803ba5bfe"),
+			"mon-secret":   []byte("monsecret"),
+			"admin-secret": []byte("adminsecret"),
+		},
+		Type: k8sutil.RookType,
+	}
+	_, err := clientset.CoreV1().Secrets(namespace).Create(secret)
+	assert.NoError(t, err)
+	deployment := &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-2", Namespace: namespace, Labels: map[string]string{
+			osd.OsdIdLabelKey:      "2",
+			osd.OSDOverPVCLabelKey: "set1-data-0-abcde",
+		}},
+	}
+	_, err = clientset.AppsV1().Deployments(namespace).Create(deployment)
+	assert.NoError(t, err)
+	for _, pvcID := range []string{"set1-data-0", "set1-metadata-0"} {
+		pvc := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: pvcID + "-abcde", Namespace: namespace, Labels: map[string]string{
+				osd.CephDeviceSetLabelKey:      "set1",
+				osd.CephSetIndexLabelKey:       "0",
+				osd.CephDeviceSetPVCIDLabelKey: pvcID,
+			}},
+		}
+		_, err = clientset.CoreV1().PersistentVolumeClaims(namespace).Create(pvc)
+		assert.NoError(t, err)
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephOSDRemoval{}, &cephv1.CephOSDRemovalList{},
+		&cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	cl := fake.NewFakeClientWithScheme(s, []runtime.Object{removal, cephCluster}...)
+	r := &ReconcileCephOSDRemoval{client: cl, scheme: s, context: c, rookImage: "rook/ceph:myversion"}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "replace-osds", Namespace: namespace}}
+
+	osdStatus := func() cephv1.OSDRemovalStatus {
+		err := cl.Get(context.TODO(), req.NamespacedName, removal)
+		assert.NoError(t, err)
+		return removal.Status.OSDs[0]
+	}
+
+	//
+	// TEST 1: the osd is marked out and its pvc is recorded
+	//
+	res, err := r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.Equal(t, [][]string{{"osd", "ok-to-stop", "2"}, {"osd", "out", "2"}}, commands)
+	assert.Equal(t, cephv1.OSDRemovalDraining, osdStatus().Phase)
+	assert.Equal(t, "set1-data-0-abcde", osdStatus().PVCName)
+	assert.Equal(t, "set1-data-0", osdStatus().DeviceSetPVCID)
+
+	//
+	// TEST 2: the osd is destroyed, its resources are kept until the replacement is recorded
+	//
+	commands = [][]string{}
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.Equal(t, [][]string{{"osd", "destroy", "osd.2"}}, commands)
+	assert.Equal(t, cephv1.OSDRemovalReplacing, osdStatus().Phase)
+	_, err = clientset.AppsV1().Deployments(namespace).Get("rook-ceph-osd-2", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	//
+	// TEST 3: the pvcs and the deployment of the destroyed osd are removed
+	//
+	commands = [][]string{}
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.Equal(t, 0, len(commands))
+	_, err = clientset.AppsV1().Deployments(namespace).Get("rook-ceph-osd-2", metav1.GetOptions{})
+	assert.Error(t, err)
+	pvcs, err := clientset.CoreV1().PersistentVolumeClaims(namespace).List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(pvcs.Items))
+
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.Equal(t, cephv1.OSDRemovalReplacing, osdStatus().Phase)
+	assert.Contains(t, osdStatus().Message, "to be re-created")
+
+	//
+	// TEST 4: the replacement is completed once the osd is up on the new pvc
+	//
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "set1-data-0-abcde", Namespace: namespace, Annotations: map[string]string{osd.OSDReplaceIDAnnotationKey: "2"}},
+	}
+	_, err = clientset.CoreV1().PersistentVolumeClaims(namespace).Create(pvc)
+	assert.NoError(t, err)
+	osdDump = `{"osds":[{"osd":2,"up":0,"in":0}]}`
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.Equal(t, cephv1.OSDRemovalReplacing, osdStatus().Phase)
+	assert.Contains(t, osdStatus().Message, "waiting for the osd to be re-created")
+
+	osdDump = `{"osds":[{"osd":2,"up":1,"in":0}]}`
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, [][]string{{"osd", "in", "2"}}, commands)
+	assert.Equal(t, cephv1.OSDRemovalCompleted, osdStatus().Phase)
+	assert.Equal(t, cephv1.ConditionReady, removal.Status.Phase)
+	pvc, err = clientset.CoreV1().PersistentVolumeClaims(namespace).Get("set1-data-0-abcde", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(pvc.Annotations))
+}
+
+func TestSetReplacedPVCRawMode(t *testing.T) {
+	namespace := "rook-ceph"
+	clientset := test.New(t, 1)
+	deployment := &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-2", Namespace: namespace, Labels: map[string]string{
+			osd.OsdIdLabelKey:      "2",
+			osd.OSDOverPVCLabelKey: "set1-data-0-abcde",
+		}},
+	}
+	deployment.Spec.Template.Spec.Containers = []v1.Container{{Env: []v1.EnvVar{{Name: "ROOK_CV_MODE", Value: "raw"}}}}
+	_, err := clientset.AppsV1().Deployments(namespace).Create(deployment)
+	assert.NoError(t, err)
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "set1-data-0-abcde", Namespace: namespace, Labels: map[string]string{
+			osd.CephDeviceSetPVCIDLabelKey: "set1-data-0",
+		}},
+	}
+	_, err = clientset.CoreV1().PersistentVolumeClaims(namespace).Create(pvc)
+	assert.NoError(t, err)
+	r := &ReconcileCephOSDRemoval{
+		context:     &clusterd.Context{Clientset: clientset},
+		clusterInfo: &cephclient.ClusterInfo{Namespace: namespace, CephVersion: cephver.Octopus},
+	}
+	removal := &cephv1.CephOSDRemoval{
+		ObjectMeta: metav1.ObjectMeta{Name: "replace-osds", Namespace: namespace},
+		Spec:       cephv1.OSDRemovalSpec{OSDIDs: []int{2}, Replace: true},
+	}
+
+	// the osd prepared in raw mode cannot be re-created with its id before pacific
+	status := &cephv1.OSDRemovalStatus{ID: 2, Phase: cephv1.OSDRemovalPending}
+	replaceable, err := r.setReplacedPVC(removal, status)
+	assert.NoError(t, err)
+	assert.False(t, replaceable)
+	assert.Equal(t, cephv1.OSDRemovalFailed, status.Phase)
+	assert.Contains(t, status.Message, "raw mode")
+
+	r.clusterInfo.CephVersion = cephver.Pacific
+	status = &cephv1.OSDRemovalStatus{ID: 2, Phase: cephv1.OSDRemovalPending}
+	replaceable, err = r.setReplacedPVC(removal, status)
+	assert.NoError(t, err)
+	assert.True(t, replaceable)
+	assert.Equal(t, "set1-data-0-abcde", status.PVCName)
+}
+
+func TestPurgeOSDOnPVC(t *testing.T) {
+	namespace := "rook-ceph"
+	clientset := test.New(t, 1)
+	deployment := &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-2", Namespace: namespace, Labels: map[string]string{
+			osd.OsdIdLabelKey:      "2",
+			osd.OSDOverPVCLabelKey: "set1-data-0-abcde",
+		}},
+	}
+	_, err := clientset.AppsV1().Deployments(namespace).Create(deployment)
+	assert.NoError(t, err)
+	pvc := &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "set1-data-0-abcde", Namespace: namespace}}
+	_, err = clientset.CoreV1().PersistentVolumeClaims(namespace).Create(pvc)
+	assert.NoError(t, err)
+	r := &ReconcileCephOSDRemoval{
+		context:     &clusterd.Context{Clientset: clientset},
+		clusterInfo: &cephclient.ClusterInfo{Namespace: namespace},
+	}
+	removal := &cephv1.CephOSDRemoval{
+		ObjectMeta: metav1.ObjectMeta{Name: "remove-osds", Namespace: namespace},
+		Spec:       cephv1.OSDRemovalSpec{OSDIDs: []int{2}},
+	}
+
+	// the pvc is recorded while draining the osd
+	status := &cephv1.OSDRemovalStatus{ID: 2, Phase: cephv1.OSDRemovalPending}
+	err = r.drainOSD(removal, &cephclient.OSDDump{}, status)
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.OSDRemovalDraining, status.Phase)
+	assert.Equal(t, "set1-data-0-abcde", status.PVCName)
+
+	// the pvc is removed even if the deployment was removed by a previous attempt
+	err = clientset.AppsV1().Deployments(namespace).Delete(deployment.Name, &metav1.DeleteOptions{})
+	assert.NoError(t, err)
+	err = r.purgeOSD(removal, &cephv1.CephCluster{}, &cephclient.OSDDump{}, status)
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.OSDRemovalCompleted, status.Phase)
+	_, err = clientset.CoreV1().PersistentVolumeClaims(namespace).Get(pvc.Name, metav1.GetOptions{})
+	assert.Error(t, err)
+}
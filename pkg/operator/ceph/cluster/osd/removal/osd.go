@@ -0,0 +1,476 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package removal
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	sanitizeAppName = "rook-ceph-osd-sanitize"
+)
+
+// removeOSD moves the removal of an OSD to its next phase
+func (r *ReconcileCephOSDRemoval) removeOSD(removal *cephv1.CephOSDRemoval, cephCluster *cephv1.CephCluster, osdDump *cephclient.OSDDump, status *cephv1.OSDRemovalStatus) {
+	var err error
+	switch status.Phase {
+	case cephv1.OSDRemovalPending:
+		err = r.drainOSD(removal, osdDump, status)
+	case cephv1.OSDRemovalDraining:
+		if removal.Spec.Replace {
+			err = r.destroyOSD(removal, status)
+		} else {
+			err = r.purgeOSD(removal, cephCluster, osdDump, status)
+		}
+	case cephv1.OSDRemovalReplacing:
+		err = r.replaceOSD(removal, osdDump, status)
+	case cephv1.OSDRemovalSanitizing:
+		err = r.checkSanitizeJob(status)
+	default:
+		return
+	}
+	if err != nil {
+		logger.Errorf("failed to remove osd.%d. %v", status.ID, err)
+		status.Message = err.Error()
+	}
+	status.LastUpdate = time.Now().UTC().Format(time.RFC3339)
+}
+
+// drainOSD marks the OSD out so that its data is moved to the other OSDs
+func (r *ReconcileCephOSDRemoval) drainOSD(removal *cephv1.CephOSDRemoval, osdDump *cephclient.OSDDump, status *cephv1.OSDRemovalStatus) error {
+	if _, _, err := osdDump.StatusByID(int64(status.ID)); err != nil {
+		if removal.Spec.Replace {
+			status.Phase = cephv1.OSDRemovalFailed
+			status.Message = "osd not found in the osd map, it cannot be replaced"
+			return nil
+		}
+		// the deployment and PVC of the OSD may still exist
+		logger.Infof("osd.%d not found in the osd map, removing its resources", status.ID)
+		if err := r.recordOSDResources(removal, status); err != nil {
+			return err
+		}
+		status.Phase = cephv1.OSDRemovalDraining
+		status.Message = "osd not found in the osd map"
+		return nil
+	}
+
+	if removal.Spec.Replace {
+		replaceable, err := r.setReplacedPVC(removal, status)
+		if err != nil || !replaceable {
+			return err
+		}
+	} else if err := r.recordOSDResources(removal, status); err != nil {
+		return err
+	}
+
+	if !removal.Spec.ForceRemoval {
+		if err := cephclient.OSDOkToStop(r.context, r.clusterInfo, status.ID); err != nil {
+			return err
+		}
+	}
+
+	logger.Infof("marking osd.%d out", status.ID)
+	if output, err := cephclient.OSDOut(r.context, r.clusterInfo, status.ID); err != nil {
+		return errors.Wrapf(err, "failed to mark osd.%d out. %s", status.ID, output)
+	}
+	status.Phase = cephv1.OSDRemovalDraining
+	status.Message = "waiting for the data of the osd to be moved to the other osds"
+	return nil
+}
+
+// purgeOSD removes the OSD once its data is safe on the other OSDs, with its deployment and PVC
+func (r *ReconcileCephOSDRemoval) purgeOSD(removal *cephv1.CephOSDRemoval, cephCluster *cephv1.CephCluster, osdDump *cephclient.OSDDump, status *cephv1.OSDRemovalStatus) error {
+	_, _, err := osdDump.StatusByID(int64(status.ID))
+	inOSDMap := err == nil
+	if inOSDMap && !r.isSafeToDestroy(removal, status) {
+		return nil
+	}
+
+	// the PVC and the node of the OSD were recorded from its deployment while draining
+	if err := r.removeOSDDeployments(removal, status); err != nil {
+		return err
+	}
+
+	if status.PVCName != "" {
+		if err := r.removeOSDPVC(removal, status.PVCName); err != nil {
+			return err
+		}
+	}
+
+	if inOSDMap {
+		crushHost, err := cephclient.GetCrushHostName(r.context, r.clusterInfo, status.ID)
+		if err != nil {
+			logger.Warningf("failed to get the crush host of osd.%d. %v", status.ID, err)
+		}
+		if err := cephclient.PurgeOSD(r.context, r.clusterInfo, status.ID, removal.Spec.ForceRemoval); err != nil {
+			return err
+		}
+		// the host is only removed from the crush map if it has no other OSD
+		if crushHost != "" {
+			if _, err := cephclient.NewCephCommand(r.context, r.clusterInfo, []string{"osd", "crush", "rm", crushHost}).Run(); err != nil {
+				logger.Debugf("crush host %q not removed. %v", crushHost, err)
+			}
+		}
+	}
+
+	// the devices of the OSDs on PVC are released with their PVC
+	if removal.Spec.SanitizeDisks != nil && status.PVCName == "" && status.Host != "" {
+		job := r.sanitizeJob(removal, cephCluster, status)
+		// the job is garbage collected with the removal request
+		if err := controllerutil.SetControllerReference(removal, job, r.scheme); err != nil {
+			return errors.Wrapf(err, "failed to set owner reference of job %q", job.Name)
+		}
+		logger.Infof("starting job %q to sanitize the disks of osd.%d on node %q", job.Name, status.ID, status.Host)
+		if err := k8sutil.RunReplaceableJob(r.context.Clientset, job, true); err != nil {
+			return errors.Wrapf(err, "failed to run job to sanitize the disks of osd.%d", status.ID)
+		}
+		status.Phase = cephv1.OSDRemovalSanitizing
+		status.Message = "waiting for the disks of the osd to be sanitized"
+		return nil
+	}
+
+	logger.Infof("osd.%d removed", status.ID)
+	status.Phase = cephv1.OSDRemovalCompleted
+	status.Message = ""
+	return nil
+}
+
+// recordOSDResources records the PVC of the OSD, or its node if it is not on PVC, from its deployment. They are
+// recorded in the status before the deployment is removed so that the removal can resume after a failure.
+func (r *ReconcileCephOSDRemoval) recordOSDResources(removal *cephv1.CephOSDRemoval, status *cephv1.OSDRemovalStatus) error {
+	label := fmt.Sprintf("%s=%d", osd.OsdIdLabelKey, status.ID)
+	deployments, err := k8sutil.GetDeployments(r.context.Clientset, removal.Namespace, label)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get deployment of osd.%d", status.ID)
+	}
+	if deployments != nil {
+		for _, d := range deployments.Items {
+			status.PVCName = d.Labels[osd.OSDOverPVCLabelKey]
+			if status.PVCName == "" {
+				status.Host = d.Spec.Template.Spec.NodeSelector[v1.LabelHostname]
+			}
+		}
+	}
+	return nil
+}
+
+// removeOSDDeployments removes the deployment of an OSD
+func (r *ReconcileCephOSDRemoval) removeOSDDeployments(removal *cephv1.CephOSDRemoval, status *cephv1.OSDRemovalStatus) error {
+	label := fmt.Sprintf("%s=%d", osd.OsdIdLabelKey, status.ID)
+	deployments, err := k8sutil.GetDeployments(r.context.Clientset, removal.Namespace, label)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get deployment of osd.%d", status.ID)
+	}
+	if deployments != nil {
+		for _, d := range deployments.Items {
+			logger.Infof("removing deployment %q of osd.%d", d.Name, status.ID)
+			if err := k8sutil.DeleteDeployment(r.context.Clientset, removal.Namespace, d.Name); err != nil {
+				return errors.Wrapf(err, "failed to delete deployment %q", d.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// isSafeToDestroy checks that the data of the OSD is safe on the other OSDs, unless the removal is forced
+func (r *ReconcileCephOSDRemoval) isSafeToDestroy(removal *cephv1.CephOSDRemoval, status *cephv1.OSDRemovalStatus) bool {
+	if removal.Spec.ForceRemoval {
+		return true
+	}
+	safe, err := cephclient.OsdSafeToDestroy(r.context, r.clusterInfo, status.ID)
+	if err != nil || !safe {
+		logger.Infof("osd.%d is not yet safe to destroy, waiting for backfill to complete", status.ID)
+		status.Message = "waiting for backfill to complete before the osd is safe to destroy"
+		return false
+	}
+	return true
+}
+
+// removeOSDPVC removes the prepare job of an OSD on PVC, and its PVC unless it is preserved
+func (r *ReconcileCephOSDRemoval) removeOSDPVC(removal *cephv1.CephOSDRemoval, pvcName string) error {
+	if err := r.removePrepareJobs(removal, pvcName); err != nil {
+		return err
+	}
+
+	if removal.Spec.PreservePVC {
+		return nil
+	}
+	return r.removePVC(removal, pvcName)
+}
+
+// removePrepareJobs removes the prepare jobs of an OSD on PVC
+func (r *ReconcileCephOSDRemoval) removePrepareJobs(removal *cephv1.CephOSDRemoval, pvcName string) error {
+	label := fmt.Sprintf("%s=%s", osd.OSDOverPVCLabelKey, pvcName)
+	jobs, err := r.context.Clientset.BatchV1().Jobs(removal.Namespace).List(metav1.ListOptions{LabelSelector: label})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list prepare jobs of pvc %q", pvcName)
+	}
+	for _, job := range jobs.Items {
+		logger.Infof("removing osd prepare job %q", job.Name)
+		if err := k8sutil.DeleteBatchJob(r.context.Clientset, removal.Namespace, job.Name, false); err != nil {
+			return errors.Wrapf(err, "failed to delete prepare job %q", job.Name)
+		}
+	}
+	return nil
+}
+
+func (r *ReconcileCephOSDRemoval) removePVC(removal *cephv1.CephOSDRemoval, pvcName string) error {
+	logger.Infof("removing osd pvc %q", pvcName)
+	err := r.context.Clientset.CoreV1().PersistentVolumeClaims(removal.Namespace).Delete(pvcName, &metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete pvc %q", pvcName)
+	}
+	return nil
+}
+
+// setReplacedPVC records the data PVC of an OSD to replace. Only the OSDs on the PVCs of a storageClassDeviceSet can be
+// replaced since the operator creates the new PVC from the template of the set.
+func (r *ReconcileCephOSDRemoval) setReplacedPVC(removal *cephv1.CephOSDRemoval, status *cephv1.OSDRemovalStatus) (bool, error) {
+	label := fmt.Sprintf("%s=%d", osd.OsdIdLabelKey, status.ID)
+	deployments, err := k8sutil.GetDeployments(r.context.Clientset, removal.Namespace, label)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return false, errors.Wrapf(err, "failed to get deployment of osd.%d", status.ID)
+	}
+	pvcName := ""
+	rawMode := false
+	if deployments != nil {
+		for i, d := range deployments.Items {
+			pvcName = d.Labels[osd.OSDOverPVCLabelKey]
+			rawMode = osd.IsRawModeDeployment(&deployments.Items[i])
+		}
+	}
+
+	// the OSD is re-created with the ceph-volume mode it was prepared with
+	if rawMode && !r.clusterInfo.CephVersion.IsAtLeast(osd.ReplaceOSDRawModeMinCephVersion) {
+		status.Phase = cephv1.OSDRemovalFailed
+		status.Message = fmt.Sprintf("the osd was prepared with ceph-volume raw mode, which can only re-create it with the same id since ceph %s. remove it without replace instead", osd.ReplaceOSDRawModeMinCephVersion.String())
+		return false, nil
+	}
+
+	pvcID := ""
+	if pvcName != "" {
+		pvc, err := r.context.Clientset.CoreV1().PersistentVolumeClaims(removal.Namespace).Get(pvcName, metav1.GetOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return false, errors.Wrapf(err, "failed to get pvc %q", pvcName)
+		}
+		if err == nil {
+			pvcID = pvc.Labels[osd.CephDeviceSetPVCIDLabelKey]
+		}
+	}
+	if pvcID == "" {
+		status.Phase = cephv1.OSDRemovalFailed
+		status.Message = "only the osds on the pvcs of a storageClassDeviceSet can be replaced"
+		return false, nil
+	}
+
+	status.PVCName = pvcName
+	status.DeviceSetPVCID = pvcID
+	return true, nil
+}
+
+// destroyOSD destroys the OSD once its data is safe on the other OSDs, keeping its ID and CRUSH weight. Its PVC is only
+// removed on the next reconcile, once the replacement is recorded in the status for the operator to create the new PVC.
+func (r *ReconcileCephOSDRemoval) destroyOSD(removal *cephv1.CephOSDRemoval, status *cephv1.OSDRemovalStatus) error {
+	if !r.isSafeToDestroy(removal, status) {
+		return nil
+	}
+
+	if err := cephclient.DestroyOSD(r.context, r.clusterInfo, status.ID, removal.Spec.ForceRemoval); err != nil {
+		return err
+	}
+	status.Phase = cephv1.OSDRemovalReplacing
+	status.Message = fmt.Sprintf("removing pvc %q of the destroyed osd", status.PVCName)
+	return nil
+}
+
+// replaceOSD removes the PVC and the deployment of a destroyed OSD, and completes the replacement once the operator
+// re-created the OSD on the new PVC with the same name
+func (r *ReconcileCephOSDRemoval) replaceOSD(removal *cephv1.CephOSDRemoval, osdDump *cephclient.OSDDump, status *cephv1.OSDRemovalStatus) error {
+	pvcs := r.context.Clientset.CoreV1().PersistentVolumeClaims(removal.Namespace)
+	pvc, err := pvcs.Get(status.PVCName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			status.Message = fmt.Sprintf("waiting for pvc %q to be re-created", status.PVCName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get pvc %q", status.PVCName)
+	}
+
+	// the new PVC is annotated with the ID of the OSD to re-create
+	if pvc.Annotations[osd.OSDReplaceIDAnnotationKey] != strconv.Itoa(status.ID) {
+		if pvc.DeletionTimestamp == nil {
+			if err := r.removeDestroyedOSD(removal, status, pvc); err != nil {
+				return err
+			}
+		}
+		status.Message = fmt.Sprintf("waiting for pvc %q of the destroyed osd to be deleted", status.PVCName)
+		return nil
+	}
+
+	up, in, err := osdDump.StatusByID(int64(status.ID))
+	if err != nil || up == 0 {
+		status.Message = fmt.Sprintf("waiting for the osd to be re-created on pvc %q", status.PVCName)
+		return nil
+	}
+	if in == 0 {
+		logger.Infof("marking osd.%d in", status.ID)
+		if output, err := cephclient.NewCephCommand(r.context, r.clusterInfo, []string{"osd", "in", strconv.Itoa(status.ID)}).Run(); err != nil {
+			return errors.Wrapf(err, "failed to mark osd.%d in. %s", status.ID, string(output))
+		}
+	}
+
+	// the annotation is only needed to prepare the OSD, the PVC must not be taken for a replacement again
+	delete(pvc.Annotations, osd.OSDReplaceIDAnnotationKey)
+	if _, err := pvcs.Update(pvc); err != nil {
+		return errors.Wrapf(err, "failed to update pvc %q", pvc.Name)
+	}
+	logger.Infof("osd.%d replaced on pvc %q", status.ID, status.PVCName)
+	status.Phase = cephv1.OSDRemovalCompleted
+	status.Message = ""
+	return nil
+}
+
+// removeDestroyedOSD removes the PVCs, the prepare jobs and the deployment of a destroyed OSD. The PVCs are deleted
+// first so that the operator does not prepare the destroyed OSD again on them when the deployment is deleted.
+func (r *ReconcileCephOSDRemoval) removeDestroyedOSD(removal *cephv1.CephOSDRemoval, status *cephv1.OSDRemovalStatus, dataPVC *v1.PersistentVolumeClaim) error {
+	// the metadata and wal PVCs of the OSD have the same index in the set
+	selector := fmt.Sprintf("%s=%s,%s=%s", osd.CephDeviceSetLabelKey, dataPVC.Labels[osd.CephDeviceSetLabelKey], osd.CephSetIndexLabelKey, dataPVC.Labels[osd.CephSetIndexLabelKey])
+	pvcs, err := r.context.Clientset.CoreV1().PersistentVolumeClaims(removal.Namespace).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list pvcs of osd.%d", status.ID)
+	}
+	for _, pvc := range pvcs.Items {
+		if pvc.DeletionTimestamp == nil {
+			if err := r.removePVC(removal, pvc.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := r.removePrepareJobs(removal, dataPVC.Name); err != nil {
+		return err
+	}
+
+	return r.removeOSDDeployments(removal, status)
+}
+
+// checkSanitizeJob completes the removal of the OSD when its disks are sanitized
+func (r *ReconcileCephOSDRemoval) checkSanitizeJob(status *cephv1.OSDRemovalStatus) error {
+	name := sanitizeJobName(status.ID)
+	job, err := r.context.Clientset.BatchV1().Jobs(r.clusterInfo.Namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get job %q", name)
+	}
+
+	if job.Status.Succeeded > 0 {
+		logger.Infof("osd.%d removed and its disks sanitized", status.ID)
+		status.Phase = cephv1.OSDRemovalCompleted
+		status.Message = ""
+		return nil
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batch.JobFailed && condition.Status == v1.ConditionTrue {
+			status.Phase = cephv1.OSDRemovalFailed
+			status.Message = fmt.Sprintf("failed to sanitize the disks of the osd. %s", condition.Message)
+			return nil
+		}
+	}
+	return nil
+}
+
+func sanitizeJobName(osdID int) string {
+	return fmt.Sprintf("%s-%d", sanitizeAppName, osdID)
+}
+
+// sanitizeJob returns the job wiping the disks of a removed OSD on its node
+func (r *ReconcileCephOSDRemoval) sanitizeJob(removal *cephv1.CephOSDRemoval, cephCluster *cephv1.CephCluster, status *cephv1.OSDRemovalStatus) *batch.Job {
+	sanitizeDisks := removal.Spec.SanitizeDisks
+	iteration := sanitizeDisks.Iteration
+	if iteration == 0 {
+		iteration = 1
+	}
+	envVars := []v1.EnvVar{
+		{Name: "ROOK_CLUSTER_FSID", Value: r.clusterInfo.FSID},
+		{Name: "ROOK_SANITIZE_OSD_IDS", Value: strconv.Itoa(status.ID)},
+		{Name: "ROOK_SANITIZE_ITERATION", Value: strconv.Itoa(int(iteration))},
+		mon.PodNamespaceEnvVar(removal.Namespace),
+	}
+	if sanitizeDisks.Method != "" {
+		envVars = append(envVars, v1.EnvVar{Name: "ROOK_SANITIZE_METHOD", Value: sanitizeDisks.Method.String()})
+	}
+	if sanitizeDisks.DataSource != "" {
+		envVars = append(envVars, v1.EnvVar{Name: "ROOK_SANITIZE_DATA_SOURCE", Value: sanitizeDisks.DataSource.String()})
+	}
+
+	podSpec := v1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: sanitizeAppName,
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:            "sanitize-disks",
+					Image:           r.rookImage,
+					SecurityContext: osd.PrivilegedContext(),
+					VolumeMounts:    []v1.VolumeMount{{Name: "devices", MountPath: "/dev"}},
+					Env:             envVars,
+					Args:            []string{"ceph", "clean"},
+					Resources:       cephv1.GetCleanupResources(cephCluster.Spec.Resources),
+				},
+			},
+			Volumes: []v1.Volume{
+				{Name: "devices", VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/dev"}}},
+			},
+			NodeSelector:      map[string]string{v1.LabelHostname: status.Host},
+			RestartPolicy:     v1.RestartPolicyOnFailure,
+			PriorityClassName: cephv1.GetCleanupPriorityClassName(cephCluster.Spec.PriorityClassNames),
+		},
+	}
+	cephv1.GetCleanupPlacement(cephCluster.Spec.Placement).ApplyToPodSpec(&podSpec.Spec)
+
+	labels := opcontroller.AppLabels(sanitizeAppName, removal.Namespace)
+	labels[osd.OsdIdLabelKey] = strconv.Itoa(status.ID)
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sanitizeJobName(status.ID),
+			Namespace: removal.Namespace,
+			Labels:    labels,
+		},
+		Spec: batch.JobSpec{
+			Template: podSpec,
+		},
+	}
+	cephv1.GetCleanupAnnotations(cephCluster.Spec.Annotations).ApplyToObjectMeta(&job.ObjectMeta)
+	cephv1.GetCleanupLabels(cephCluster.Spec.Labels).ApplyToObjectMeta(&job.ObjectMeta)
+
+	return job
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
 	opmon "github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
 	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
@@ -40,6 +41,7 @@ const (
 	activateOSDVolumeName                         = "activate-osd"
 	activateOSDMountPath                          = "/var/lib/ceph/osd/ceph-"
 	blockPVCMapperInitContainer                   = "blkdevmapper"
+	blockEncryptionKMSGetKeyInitContainer         = "encryption-kms-get-key"
 	blockEncryptionOpenInitContainer              = "encryption-open"
 	blockEncryptionOpenMetadataInitContainer      = "encryption-open-metadata"
 	blockEncryptionOpenWalInitContainer           = "encryption-open-wal"
@@ -210,7 +212,7 @@ func (c *Cluster) makeDeployment(osdProps osdProperties, osd OSDInfo, provisionC
 		volumes = append(volumes, getPVCOSDVolumes(&osdProps)...)
 		// If encrypted let's add the secret key mount path
 		if osdProps.encrypted && osd.CVMode == "raw" {
-			encryptedVol, _ := getEncryptionVolume(osdProps.pvc.ClaimName)
+			encryptedVol, _ := c.getEncryptionVolume(osdProps.pvc.ClaimName)
 			volumes = append(volumes, encryptedVol)
 		}
 	}
@@ -397,6 +399,10 @@ func (c *Cluster) makeDeployment(osdProps osdProperties, osd OSDInfo, provisionC
 
 	if osdProps.onPVC() && osd.CVMode == "raw" {
 		if osdProps.encrypted {
+			// Fetch the encryption key from the KMS
+			if c.spec.Security.KeyManagementService.IsEnabled() {
+				initContainers = append(initContainers, c.getPVCEncryptionKMSGetKeyInitContainer(osdProps))
+			}
 			// Open the encrypted disk
 			initContainers = append(initContainers, c.getPVCEncryptionOpenInitContainerActivate(osdProps)...)
 			// Copy the encrypted block to the osd data location, e,g: /var/lib/ceph/osd/ceph-0/block
@@ -700,20 +706,41 @@ func (c *Cluster) generateEncryptionOpenBlockContainer(resources v1.ResourceRequ
 	}
 }
 
+// The key is written in the memory backed encryption volume so the open containers find it at the same location
+// as the key stored in a Kubernetes Secret
+func (c *Cluster) getPVCEncryptionKMSGetKeyInitContainer(osdProps osdProperties) v1.Container {
+	_, volMount := c.getEncryptionVolume(osdProps.pvc.ClaimName)
+	volMount.ReadOnly = false
+
+	return v1.Container{
+		Name:  blockEncryptionKMSGetKeyInitContainer,
+		Image: k8sutil.MakeRookImage(c.rookVersion),
+		Args: []string{
+			"ceph", "osd", "get-key",
+			"--key-name", generateOSDEncryptionSecretName(osdProps.pvc.ClaimName),
+			"--key-path", encryptionKeyPath(),
+		},
+		Env:             kms.EnvVars(c.spec.Security.KeyManagementService),
+		VolumeMounts:    []v1.VolumeMount{volMount},
+		SecurityContext: opmon.PodSecurityContext(),
+		Resources:       osdProps.resources,
+	}
+}
+
 func (c *Cluster) getPVCEncryptionOpenInitContainerActivate(osdProps osdProperties) []v1.Container {
 	containers := []v1.Container{}
 
 	// Main block container
 	blockContainer := c.generateEncryptionOpenBlockContainer(osdProps.resources, blockEncryptionOpenInitContainer, osdProps.pvc.ClaimName, DmcryptBlockType)
-	_, volMount := getEncryptionVolume(osdProps.pvc.ClaimName)
+	_, volMount := c.getEncryptionVolume(osdProps.pvc.ClaimName)
 	blockContainer.VolumeMounts = append(blockContainer.VolumeMounts, volMount)
 	containers = append(containers, blockContainer)
 
 	// If there is a metadata PVC
 	if osdProps.metadataPVC.ClaimName != "" {
 		metadataContainer := c.generateEncryptionOpenBlockContainer(osdProps.resources, blockEncryptionOpenMetadataInitContainer, osdProps.metadataPVC.ClaimName, DmcryptMetadataType)
-		// We use the same key for both block and block.db so we must use osdProps.pvc.ClaimName for the getEncryptionVolume()
-		_, volMount := getEncryptionVolume(osdProps.pvc.ClaimName)
+		// We use the same key for both block and block.db so we must use osdProps.pvc.ClaimName for the c.getEncryptionVolume()
+		_, volMount := c.getEncryptionVolume(osdProps.pvc.ClaimName)
 		metadataContainer.VolumeMounts = append(metadataContainer.VolumeMounts, volMount)
 		containers = append(containers, metadataContainer)
 	}
@@ -721,8 +748,8 @@ func (c *Cluster) getPVCEncryptionOpenInitContainerActivate(osdProps osdProperti
 	// If there is a wal PVC
 	if osdProps.walPVC.ClaimName != "" {
 		metadataContainer := c.generateEncryptionOpenBlockContainer(osdProps.resources, blockEncryptionOpenWalInitContainer, osdProps.walPVC.ClaimName, DmcryptWalType)
-		// We use the same key for both block and block.db so we must use osdProps.pvc.ClaimName for the getEncryptionVolume()
-		_, volMount := getEncryptionVolume(osdProps.pvc.ClaimName)
+		// We use the same key for both block and block.db so we must use osdProps.pvc.ClaimName for the c.getEncryptionVolume()
+		_, volMount := c.getEncryptionVolume(osdProps.pvc.ClaimName)
 		metadataContainer.VolumeMounts = append(metadataContainer.VolumeMounts, volMount)
 		containers = append(containers, metadataContainer)
 	}
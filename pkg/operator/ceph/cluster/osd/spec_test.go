@@ -556,3 +556,32 @@ func TestClusterGetPVCEncryptionInitContainerActivate(t *testing.T) {
 	containers = c.getPVCEncryptionInitContainerActivate(mountPath, osdProperties)
 	assert.Equal(t, 3, len(containers))
 }
+
+func TestClusterGetPVCEncryptionKMSGetKeyInitContainer(t *testing.T) {
+	spec := cephv1.ClusterSpec{
+		Security: cephv1.SecuritySpec{
+			KeyManagementService: cephv1.KeyManagementServiceSpec{
+				ConnectionDetails: map[string]string{"KMS_PROVIDER": "vault", "VAULT_ADDR": "https://vault.default.svc:8200"},
+				TokenSecretName:   "vault-token",
+			},
+		},
+	}
+	c := New(&clusterd.Context{}, &cephclient.ClusterInfo{}, spec, "rook/rook:myversion")
+	osdProperties := osdProperties{
+		pvc: v1.PersistentVolumeClaimVolumeSource{
+			ClaimName: "pvc1",
+		},
+	}
+
+	container := c.getPVCEncryptionKMSGetKeyInitContainer(osdProperties)
+	assert.Equal(t, "encryption-kms-get-key", container.Name)
+	assert.Equal(t, "rook/rook:myversion", container.Image)
+	assert.Equal(t, []string{"ceph", "osd", "get-key", "--key-name", "rook-ceph-osd-encryption-key-pvc1", "--key-path", "/etc/ceph/luks_key"}, container.Args)
+	assert.Equal(t, 3, len(container.Env))
+	assert.False(t, container.VolumeMounts[0].ReadOnly)
+
+	// The key is never read from a Kubernetes Secret
+	volume, _ := c.getEncryptionVolume("pvc1")
+	assert.Nil(t, volume.Secret)
+	assert.Equal(t, v1.StorageMediumMemory, volume.EmptyDir.Medium)
+}
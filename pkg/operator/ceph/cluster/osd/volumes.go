@@ -176,8 +176,26 @@ func getUdevVolume() (v1.Volume, v1.VolumeMount) {
 	return volume, volumeMounts
 }
 
-func getEncryptionVolume(pvcName string) (v1.Volume, v1.VolumeMount) {
+func (c *Cluster) getEncryptionVolume(pvcName string) (v1.Volume, v1.VolumeMount) {
 	var m int32 = 0400
+	// Mounts /etc/ceph/luks_key
+	volumeMounts := v1.VolumeMount{
+		Name:      osdEncryptionVolName,
+		ReadOnly:  true,
+		MountPath: config.EtcCephDir,
+	}
+
+	// With a KMS the key is written to a memory backed volume by an init container
+	if c.spec.Security.KeyManagementService.IsEnabled() {
+		volume := v1.Volume{
+			Name: osdEncryptionVolName,
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory},
+			},
+		}
+		return volume, volumeMounts
+	}
+
 	volume := v1.Volume{
 		Name: osdEncryptionVolName,
 		VolumeSource: v1.VolumeSource{
@@ -194,12 +212,5 @@ func getEncryptionVolume(pvcName string) (v1.Volume, v1.VolumeMount) {
 		},
 	}
 
-	// Mounts /etc/ceph/luks_key
-	volumeMounts := v1.VolumeMount{
-		Name:      osdEncryptionVolName,
-		ReadOnly:  true,
-		MountPath: config.EtcCephDir,
-	}
-
 	return volume, volumeMounts
 }
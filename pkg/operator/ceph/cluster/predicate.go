@@ -18,8 +18,12 @@ limitations under the License.
 package cluster
 
 import (
+	"context"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	discoverDaemon "github.com/rook/rook/pkg/daemon/discover"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -100,3 +104,78 @@ func isHotPlugCM(obj runtime.Object) bool {
 
 	return false
 }
+
+// predicateForOSDPVCWatcher is the predicate function to trigger reconcile when the volume of an OSD PVC is expanded, or
+// when the PVC of an OSD destroyed for its replacement is deleted so that the OSD is re-created on a new PVC
+func predicateForOSDPVCWatcher(client client.Client) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isOSDPVCExpanded(e.ObjectOld, e.ObjectNew)
+		},
+
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return isReplacedOSDPVC(client, e.Object)
+		},
+
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// isOSDPVCExpanded informs whether the capacity of the PVC of an OSD changed, the OSD must be restarted to use it
+func isOSDPVCExpanded(oldObj, newObj runtime.Object) bool {
+	oldPVC, ok := oldObj.(*corev1.PersistentVolumeClaim)
+	if !ok {
+		return false
+	}
+	newPVC, ok := newObj.(*corev1.PersistentVolumeClaim)
+	if !ok {
+		return false
+	}
+	if _, ok := newPVC.Labels[osd.CephDeviceSetPVCIDLabelKey]; !ok {
+		return false
+	}
+
+	oldCapacity := oldPVC.Status.Capacity[corev1.ResourceStorage]
+	newCapacity := newPVC.Status.Capacity[corev1.ResourceStorage]
+	if oldCapacity.IsZero() || oldCapacity.Cmp(newCapacity) == 0 {
+		return false
+	}
+	logger.Infof("osd pvc %q expanded from %s to %s", newPVC.Name, oldCapacity.String(), newCapacity.String())
+	return true
+}
+
+// isReplacedOSDPVC informs whether the PVC is the PVC of an OSD destroyed by a removal request to be replaced
+func isReplacedOSDPVC(c client.Client, obj runtime.Object) bool {
+	pvc, ok := obj.(*corev1.PersistentVolumeClaim)
+	if !ok {
+		return false
+	}
+	if _, ok := pvc.Labels[osd.CephDeviceSetPVCIDLabelKey]; !ok {
+		return false
+	}
+
+	removals := &cephv1.CephOSDRemovalList{}
+	if err := c.List(context.TODO(), removals, client.InNamespace(pvc.Namespace)); err != nil {
+		// the orchestration only re-creates the PVCs of the replaced OSDs
+		logger.Warningf("failed to list osd removals, reconciling after the deletion of osd pvc %q. %v", pvc.Name, err)
+		return true
+	}
+	for _, removal := range removals.Items {
+		if removal.Status == nil {
+			continue
+		}
+		for _, status := range removal.Status.OSDs {
+			if status.Phase == cephv1.OSDRemovalReplacing && status.PVCName == pvc.Name {
+				logger.Infof("pvc %q of the destroyed osd.%d deleted, re-creating the osd", pvc.Name, status.ID)
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -20,8 +20,14 @@ import (
 	"testing"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestIsHotPlugCM(t *testing.T) {
@@ -44,3 +50,56 @@ func TestIsHotPlugCM(t *testing.T) {
 	b = isHotPlugCM(cm)
 	assert.True(t, b)
 }
+
+func TestIsOSDPVCExpanded(t *testing.T) {
+	oldPVC := &corev1.PersistentVolumeClaim{}
+	oldPVC.Labels = map[string]string{osd.CephDeviceSetPVCIDLabelKey: "set1-data-0"}
+	newPVC := oldPVC.DeepCopy()
+
+	// not bound yet
+	newPVC.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}
+	assert.False(t, isOSDPVCExpanded(oldPVC, newPVC))
+
+	// same capacity
+	oldPVC.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}
+	assert.False(t, isOSDPVCExpanded(oldPVC, newPVC))
+
+	// expanded
+	newPVC.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")}
+	assert.True(t, isOSDPVCExpanded(oldPVC, newPVC))
+
+	// not the pvc of an osd
+	newPVC.Labels = nil
+	assert.False(t, isOSDPVCExpanded(oldPVC, newPVC))
+	assert.False(t, isOSDPVCExpanded(&corev1.ConfigMap{}, newPVC))
+}
+
+func TestIsReplacedOSDPVC(t *testing.T) {
+	removal := &cephv1.CephOSDRemoval{
+		ObjectMeta: metav1.ObjectMeta{Name: "replace-osd", Namespace: "rook-ceph"},
+		Status: &cephv1.CephOSDRemovalStatus{
+			OSDs: []cephv1.OSDRemovalStatus{{ID: 3, Phase: cephv1.OSDRemovalReplacing, PVCName: "set1-data-0-abcde"}},
+		},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephOSDRemoval{}, &cephv1.CephOSDRemovalList{})
+	cl := fake.NewFakeClientWithScheme(s, []runtime.Object{removal}...)
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+		Name:      "set1-data-0-abcde",
+		Namespace: "rook-ceph",
+		Labels:    map[string]string{osd.CephDeviceSetPVCIDLabelKey: "set1-data-0"},
+	}}
+
+	// the pvc of the destroyed osd
+	assert.True(t, isReplacedOSDPVC(cl, pvc))
+
+	// the pvc of another osd
+	pvc.Name = "set1-data-1-fghij"
+	assert.False(t, isReplacedOSDPVC(cl, pvc))
+
+	// not the pvc of an osd
+	pvc.Name = "set1-data-0-abcde"
+	pvc.Labels = nil
+	assert.False(t, isReplacedOSDPVC(cl, pvc))
+	assert.False(t, isReplacedOSDPVC(cl, &corev1.ConfigMap{}))
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AppliedCephConfigName is the name of the configmap recording the ceph config options of the
+	// cluster spec applied in the centralized mon config database
+	AppliedCephConfigName = "rook-ceph-config-applied"
+	appliedCephConfigKey  = "config"
+)
+
+// SetCephConfig applies the ceph config options of the cluster spec in the centralized mon config
+// database. The options applied by a previous call which are no longer in the spec are removed from
+// the database. The options rejected by the mons are returned as "<section>/<option>".
+func SetCephConfig(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, cephConfig map[string]map[string]string) ([]string, error) {
+	previous, err := getAppliedCephConfig(context, clusterInfo.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(cephConfig) == 0 && len(previous) == 0 {
+		return nil, nil
+	}
+
+	monStore := GetMonStore(context, clusterInfo)
+	applied := map[string]map[string]string{}
+	rejected := []string{}
+	for who, options := range cephConfig {
+		for option, value := range options {
+			if err := monStore.Set(who, option, value); err != nil {
+				logger.Warningf("failed to set ceph config option %q of section %q. %v", option, who, err)
+				rejected = append(rejected, fmt.Sprintf("%s/%s", who, normalizeKey(option)))
+				// an option still in the store with its previous value must be removed when it leaves the spec
+				if previousValue, ok := previous[who][normalizeKey(option)]; ok {
+					setAppliedOption(applied, who, normalizeKey(option), previousValue)
+				}
+				continue
+			}
+			setAppliedOption(applied, who, normalizeKey(option), value)
+		}
+	}
+
+	for who, options := range previous {
+		for option, value := range options {
+			if _, ok := applied[who][option]; ok {
+				continue
+			}
+			if err := monStore.Delete(who, option); err != nil {
+				// keep the option to retry the removal on the next reconcile
+				logger.Warningf("failed to remove ceph config option %q of section %q. %v", option, who, err)
+				setAppliedOption(applied, who, option, value)
+				continue
+			}
+			logger.Infof("removed ceph config option %q of section %q", option, who)
+		}
+	}
+
+	if err := saveAppliedCephConfig(context, clusterInfo, applied); err != nil {
+		return nil, err
+	}
+	sort.Strings(rejected)
+	return rejected, nil
+}
+
+func setAppliedOption(applied map[string]map[string]string, who, option, value string) {
+	if _, ok := applied[who]; !ok {
+		applied[who] = map[string]string{}
+	}
+	applied[who][option] = value
+}
+
+func getAppliedCephConfig(context *clusterd.Context, namespace string) (map[string]map[string]string, error) {
+	applied := map[string]map[string]string{}
+	cm, err := context.Clientset.CoreV1().ConfigMaps(namespace).Get(AppliedCephConfigName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return applied, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get configmap %q", AppliedCephConfigName)
+	}
+	if data, ok := cm.Data[appliedCephConfigKey]; ok && data != "" {
+		if err := json.Unmarshal([]byte(data), &applied); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the applied ceph config of configmap %q", AppliedCephConfigName)
+		}
+	}
+	return applied, nil
+}
+
+func saveAppliedCephConfig(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, applied map[string]map[string]string) error {
+	data, err := json.Marshal(applied)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize the applied ceph config")
+	}
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      AppliedCephConfigName,
+			Namespace: clusterInfo.Namespace,
+		},
+		Data: map[string]string{appliedCephConfigKey: string(data)},
+	}
+	k8sutil.SetOwnerRef(&cm.ObjectMeta, &clusterInfo.OwnerRef)
+
+	_, err = context.Clientset.CoreV1().ConfigMaps(clusterInfo.Namespace).Create(cm)
+	if err == nil {
+		return nil
+	}
+	if !kerrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create configmap %q", AppliedCephConfigName)
+	}
+	if _, err := context.Clientset.CoreV1().ConfigMaps(clusterInfo.Namespace).Update(cm); err != nil {
+		return errors.Wrapf(err, "failed to update configmap %q", AppliedCephConfigName)
+	}
+	return nil
+}
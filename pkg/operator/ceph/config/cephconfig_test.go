@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetCephConfig(t *testing.T) {
+	execedCmds := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command string, outfile string, args ...string) (string, error) {
+			execedCmds = append(execedCmds, strings.Join(args[:4], " "))
+			if args[3] == "unknown_setting" {
+				return "unrecognized config option", errors.New("mocked error")
+			}
+			return "", nil
+		},
+	}
+	ctx := &clusterd.Context{
+		Clientset: testop.New(t, 1),
+		Executor:  executor,
+	}
+	clusterInfo := &client.ClusterInfo{Namespace: "ns"}
+
+	// nothing to do without options
+	rejected, err := SetCephConfig(ctx, clusterInfo, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, rejected)
+	assert.Equal(t, 0, len(execedCmds))
+
+	// the options are set and the rejected ones are reported
+	cephConfig := map[string]map[string]string{
+		"global": {"osd pool default size": "2"},
+		"osd.3":  {"osd_max_backfills": "2", "unknown-setting": "1"},
+	}
+	rejected, err = SetCephConfig(ctx, clusterInfo, cephConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"osd.3/unknown_setting"}, rejected)
+	assert.ElementsMatch(t, []string{
+		"config set global osd_pool_default_size",
+		"config set osd.3 osd_max_backfills",
+		"config set osd.3 unknown_setting",
+	}, execedCmds)
+	applied, err := getAppliedCephConfig(ctx, "ns")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"global": {"osd_pool_default_size": "2"},
+		"osd.3":  {"osd_max_backfills": "2"},
+	}, applied)
+
+	// the options removed from the spec are removed from the mon store
+	execedCmds = []string{}
+	cephConfig = map[string]map[string]string{
+		"global": {"osd-pool-default-size": "3"},
+	}
+	rejected, err = SetCephConfig(ctx, clusterInfo, cephConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(rejected))
+	assert.Equal(t, []string{
+		"config set global osd_pool_default_size",
+		"config rm osd.3 osd_max_backfills",
+	}, execedCmds)
+	applied, err = getAppliedCephConfig(ctx, "ns")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{"global": {"osd_pool_default_size": "3"}}, applied)
+
+	// all the options are removed with the spec
+	execedCmds = []string{}
+	rejected, err = SetCephConfig(ctx, clusterInfo, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(rejected))
+	assert.Equal(t, []string{"config rm global osd_pool_default_size"}, execedCmds)
+	applied, err = getAppliedCephConfig(ctx, "ns")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(applied))
+
+	// nothing more to do once all the options are removed
+	execedCmds = []string{}
+	rejected, err = SetCephConfig(ctx, clusterInfo, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, rejected)
+	assert.Equal(t, 0, len(execedCmds))
+}
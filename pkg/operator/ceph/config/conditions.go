@@ -79,7 +79,7 @@ func setCondition(c *clusterd.Context, namespaceName types.NamespacedName, newCo
 	}
 	cluster.Status.Conditions = *conditions
 
-	if newCondition.Status == v1.ConditionTrue {
+	if newCondition.Status == v1.ConditionTrue && isPhaseCondition(newCondition.Type) {
 		cluster.Status.Phase = newCondition.Type
 		if state := translatePhasetoState(newCondition.Type); state != "" {
 			cluster.Status.State = state
@@ -97,6 +97,16 @@ func setCondition(c *clusterd.Context, namespaceName types.NamespacedName, newCo
 	}
 }
 
+// isPhaseCondition returns whether the condition is a phase of the cluster, the other conditions report
+// the state of a part of the cluster and do not change its phase
+func isPhaseCondition(conditionType cephv1.ConditionType) bool {
+	switch conditionType {
+	case cephv1.ConditionCephConfigApplied, cephv1.ConditionNearFull:
+		return false
+	}
+	return true
+}
+
 // translatePhasetoState convert the Phases to corresponding State
 // 1. We still need to set the State in case someone is still using it
 // instead of Phase. If we stopped setting the State it would be a
@@ -18,11 +18,14 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 
 	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -56,3 +59,24 @@ func UpdateStatus(client client.Client, obj runtime.Object) error {
 
 	return nil
 }
+
+// SetCondition adds or updates a condition of a custom resource status, the transition time only changes
+// with the status of the condition
+func SetCondition(conditions *[]cephv1.Condition, newCondition cephv1.Condition) {
+	now := metav1.NewTime(time.Now())
+	newCondition.LastHeartbeatTime = now
+	for i := range *conditions {
+		existing := &(*conditions)[i]
+		if existing.Type != newCondition.Type {
+			continue
+		}
+		newCondition.LastTransitionTime = existing.LastTransitionTime
+		if existing.Status != newCondition.Status {
+			newCondition.LastTransitionTime = now
+		}
+		*existing = newCondition
+		return
+	}
+	newCondition.LastTransitionTime = now
+	*conditions = append(*conditions, newCondition)
+}
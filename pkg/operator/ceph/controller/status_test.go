@@ -18,10 +18,12 @@ package controller
 
 import (
 	"testing"
+	"time"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -52,3 +54,25 @@ func TestUpdateStatus(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, fakeObject.Status.Phase, cephv1.ConditionReady)
 }
+
+func TestSetCondition(t *testing.T) {
+	conditions := []cephv1.Condition{}
+	SetCondition(&conditions, cephv1.Condition{Type: cephv1.ConditionDataSynced, Status: v1.ConditionFalse, Reason: "Behind"})
+	assert.Equal(t, 1, len(conditions))
+	transition := metav1.NewTime(conditions[0].LastTransitionTime.Add(-time.Hour))
+	conditions[0].LastTransitionTime = transition
+
+	// same status, the transition time is kept
+	SetCondition(&conditions, cephv1.Condition{Type: cephv1.ConditionDataSynced, Status: v1.ConditionFalse, Reason: "Behind", Message: "2 shards behind"})
+	assert.Equal(t, 1, len(conditions))
+	assert.Equal(t, transition, conditions[0].LastTransitionTime)
+	assert.Equal(t, "2 shards behind", conditions[0].Message)
+
+	// the status changed
+	SetCondition(&conditions, cephv1.Condition{Type: cephv1.ConditionDataSynced, Status: v1.ConditionTrue, Reason: "CaughtUp"})
+	assert.NotEqual(t, transition, conditions[0].LastTransitionTime)
+	assert.Equal(t, v1.ConditionTrue, conditions[0].Status)
+
+	SetCondition(&conditions, cephv1.Condition{Type: cephv1.ConditionMetadataSynced, Status: v1.ConditionTrue})
+	assert.Equal(t, 2, len(conditions))
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/file/mirror"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -73,6 +74,12 @@ type ReconcileCephFilesystem struct {
 	context         *clusterd.Context
 	cephClusterSpec *cephv1.ClusterSpec
 	clusterInfo     *cephclient.ClusterInfo
+	fsChannels      map[string]*fsHealth
+}
+
+type fsHealth struct {
+	stopChan          chan struct{}
+	monitoringRunning bool
 }
 
 // Add creates a new CephFilesystem Controller and adds it to the Manager. The Manager will set fields on the Controller
@@ -89,9 +96,10 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Rec
 		panic(err)
 	}
 	return &ReconcileCephFilesystem{
-		client:  mgr.GetClient(),
-		scheme:  mgrScheme,
-		context: context,
+		client:     mgr.GetClient(),
+		scheme:     mgrScheme,
+		context:    context,
+		fsChannels: make(map[string]*fsHealth),
 	}
 }
 
@@ -152,7 +160,7 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 
 	// The CR was just created, initializing status fields
 	if cephFilesystem.Status == nil {
-		updateStatus(r.client, request.NamespacedName, k8sutil.Created)
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, nil)
 	}
 
 	// Make sure a CephCluster is present otherwise do nothing
@@ -193,6 +201,16 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 	}
 	r.clusterInfo.CephVersion = currentCephVersion
 
+	// Initialize the channel for this filesystem
+	// This allows us to track multiple CephFilesystem in the same namespace
+	_, fsChannelExists := r.fsChannels[cephFilesystem.Name]
+	if !fsChannelExists {
+		r.fsChannels[cephFilesystem.Name] = &fsHealth{
+			stopChan:          make(chan struct{}),
+			monitoringRunning: false,
+		}
+	}
+
 	// Set a finalizer so we can do cleanup before the object goes away
 	err = opcontroller.AddFinalizerIfNotPresent(r.client, cephFilesystem)
 	if err != nil {
@@ -207,6 +225,15 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 			return reconcile.Result{}, errors.Wrapf(err, "failed to delete filesystem %q. ", cephFilesystem.Name)
 		}
 
+		// If the filesystem is still in the map, we must remove it during CR deletion
+		if fsChannelExists {
+			// Close the channel to stop the mirroring status
+			close(r.fsChannels[cephFilesystem.Name].stopChan)
+
+			// Remove filesystem from the map
+			delete(r.fsChannels, cephFilesystem.Name)
+		}
+
 		// Remove finalizer
 		err = opcontroller.RemoveFinalizer(r.client, cephFilesystem)
 		if err != nil {
@@ -222,16 +249,56 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 		return reconcile.Result{}, errors.Wrapf(err, "invalid object filesystem %q arguments", cephFilesystem.Name)
 	}
 
+	// the mirroring is refused before the filesystem is updated
+	mirroringEnabled := cephFilesystem.Spec.Mirroring != nil && cephFilesystem.Spec.Mirroring.Enabled
+	mirroringSupported := r.clusterInfo.CephVersion.IsAtLeast(mirror.MinCephVersion)
+	if mirroringEnabled && !mirroringSupported {
+		message := fmt.Sprintf("filesystem mirroring requires ceph pacific or newer, the cluster is running ceph %s", r.clusterInfo.CephVersion.String())
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, map[string]string{mirroringErrorKey: message})
+		return reconcile.Result{}, errors.Errorf("failed to configure mirroring of filesystem %q. %s", cephFilesystem.Name, message)
+	}
+
 	// RECONCILE
 	logger.Debug("reconciling ceph filesystem store deployments")
 	reconcileResponse, err = r.reconcileCreateFilesystem(cephFilesystem)
 	if err != nil {
-		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, nil)
 		return reconcileResponse, err
 	}
 
+	// MIRRORING
+	var statusInfo map[string]string
+	if mirroringEnabled {
+		logger.Debug("reconciling filesystem snapshot mirroring")
+		reconcileResponse, err = r.reconcileMirroring(cephFilesystem)
+		if err != nil {
+			updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, nil)
+			return reconcileResponse, errors.Wrapf(err, "failed to configure mirroring of filesystem %q", cephFilesystem.Name)
+		}
+
+		// Run the goroutine to update the mirroring status
+		if !cephFilesystem.Spec.StatusCheck.Mirror.Disabled {
+			if r.fsChannels[cephFilesystem.Name].monitoringRunning {
+				logger.Debug("filesystem mirroring monitoring go routine already running!")
+			} else {
+				checker := newMirrorChecker(r.context, r.client, r.clusterInfo, request.NamespacedName, &cephFilesystem.Spec.StatusCheck, cephFilesystem.Name)
+				r.fsChannels[cephFilesystem.Name].monitoringRunning = true
+				go checker.checkMirroring(r.fsChannels[cephFilesystem.Name].stopChan)
+			}
+		}
+
+		statusInfo = generateStatusInfo(cephFilesystem)
+	} else if mirroringSupported {
+		// older versions cannot have mirrored the filesystem, there is nothing to disable
+		reconcileResponse, err = r.disableMirroring(cephFilesystem)
+		if err != nil {
+			updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, nil)
+			return reconcileResponse, errors.Wrapf(err, "failed to disable mirroring of filesystem %q", cephFilesystem.Name)
+		}
+	}
+
 	// Set Ready status, we are done reconciling
-	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, statusInfo)
 
 	// Return and do not requeue
 	logger.Debug("done reconciling")
@@ -279,7 +346,7 @@ func (r *ReconcileCephFilesystem) reconcileDeleteFilesystem(cephFilesystem *ceph
 }
 
 // updateStatus updates an object with a given status
-func updateStatus(client client.Client, name types.NamespacedName, status string) {
+func updateStatus(client client.Client, name types.NamespacedName, status string, info map[string]string) {
 	fs := &cephv1.CephFilesystem{}
 	err := client.Get(context.TODO(), name, fs)
 	if err != nil {
@@ -292,10 +359,11 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 	}
 
 	if fs.Status == nil {
-		fs.Status = &cephv1.Status{}
+		fs.Status = &cephv1.CephFilesystemStatus{}
 	}
 
 	fs.Status.Phase = status
+	fs.Status.Info = info
 	if err := opcontroller.UpdateStatus(client, fs); err != nil {
 		logger.Errorf("failed to set filesystem %q status to %q. %v", fs.Name, status, err)
 		return
@@ -202,7 +202,7 @@ func TestCephFilesystemController(t *testing.T) {
 	// Create a fake client to mock API calls.
 	cl := fake.NewFakeClientWithScheme(s, object...)
 	// Create a ReconcileCephFilesystem object with the scheme and fake client.
-	r := &ReconcileCephFilesystem{client: cl, scheme: s, context: c}
+	r := &ReconcileCephFilesystem{client: cl, scheme: s, context: c, fsChannels: make(map[string]*fsHealth)}
 
 	// Mock request to simulate Reconcile() being called on an event for a
 	// watched resource .
@@ -239,7 +239,7 @@ func TestCephFilesystemController(t *testing.T) {
 	// Create a fake client to mock API calls.
 	cl = fake.NewFakeClientWithScheme(s, object...)
 	// Create a ReconcileCephFilesystem object with the scheme and fake client.
-	r = &ReconcileCephFilesystem{client: cl, scheme: s, context: c}
+	r = &ReconcileCephFilesystem{client: cl, scheme: s, context: c, fsChannels: make(map[string]*fsHealth)}
 	logger.Info("STARTING PHASE 2")
 	res, err = r.Reconcile(req)
 	assert.NoError(t, err)
@@ -296,7 +296,7 @@ func TestCephFilesystemController(t *testing.T) {
 	c.Executor = executor
 
 	// Create a ReconcileCephFilesystem object with the scheme and fake client.
-	r = &ReconcileCephFilesystem{client: cl, scheme: s, context: c}
+	r = &ReconcileCephFilesystem{client: cl, scheme: s, context: c, fsChannels: make(map[string]*fsHealth)}
 
 	logger.Info("STARTING PHASE 3")
 	res, err = r.Reconcile(req)
@@ -306,4 +306,20 @@ func TestCephFilesystemController(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "Ready", fs.Status.Phase, fs)
 	logger.Info("PHASE 3 DONE")
+
+	//
+	// TEST 4:
+	//
+	// FAILURE! The mirroring requires ceph pacific
+	//
+	fs.Spec.Mirroring = &cephv1.FSMirroringSpec{Enabled: true}
+	err = r.client.Update(context.TODO(), fs)
+	assert.NoError(t, err)
+	_, err = r.Reconcile(req)
+	assert.Error(t, err)
+	fs = &cephv1.CephFilesystem{}
+	err = r.client.Get(context.TODO(), req.NamespacedName, fs)
+	assert.NoError(t, err)
+	assert.Equal(t, k8sutil.ReconcileFailedStatus, fs.Status.Phase)
+	assert.Contains(t, fs.Status.Info[mirroringErrorKey], "requires ceph pacific")
 }
@@ -148,6 +148,9 @@ func validateFilesystem(context *clusterd.Context, clusterInfo *client.ClusterIn
 	if f.Spec.MetadataServer.ActiveCount < 1 {
 		return errors.New("MetadataServer.ActiveCount must be at least 1")
 	}
+	if err := validateMirroring(f); err != nil {
+		return errors.Wrap(err, "invalid mirroring")
+	}
 	// No data pool means that we expect the fs to exist already
 	if len(f.Spec.DataPools) == 0 {
 		return nil
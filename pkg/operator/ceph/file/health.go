@@ -0,0 +1,232 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/file/mirror"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultHealthCheckInterval = 1 * time.Minute
+)
+
+type mirrorChecker struct {
+	context        *clusterd.Context
+	interval       time.Duration
+	client         client.Client
+	clusterInfo    *cephclient.ClusterInfo
+	namespacedName types.NamespacedName
+	fsName         string
+	// runs an admin socket command of the cephfs-mirror daemon, only the daemon knows the sync status of the directories
+	execMirrorDaemon func(args ...string) ([]byte, error)
+}
+
+// directorySyncStatus is the sync status of a directory returned by the 'fs mirror peer status' admin socket command
+type directorySyncStatus struct {
+	State          string `json:"state"`
+	FailureReason  string `json:"failure_reason"`
+	LastSyncedSnap *struct {
+		Name         string  `json:"name"`
+		SyncDuration float64 `json:"sync_duration"`
+	} `json:"last_synced_snap"`
+	SnapsSynced  int `json:"snaps_synced"`
+	SnapsDeleted int `json:"snaps_deleted"`
+	SnapsRenamed int `json:"snaps_renamed"`
+}
+
+// newMirrorChecker creates a new HealthChecker object
+func newMirrorChecker(context *clusterd.Context, client client.Client, clusterInfo *cephclient.ClusterInfo, namespacedName types.NamespacedName, healthCheckSpec *cephv1.MirrorHealthCheckSpec, fsName string) *mirrorChecker {
+	c := &mirrorChecker{
+		context:        context,
+		interval:       defaultHealthCheckInterval,
+		clusterInfo:    clusterInfo,
+		namespacedName: namespacedName,
+		client:         client,
+		fsName:         fsName,
+	}
+	c.execMirrorDaemon = func(args ...string) ([]byte, error) {
+		// Run with env -i to clean the CEPH_ARGS env of the daemon container
+		command := append([]string{"env", "-i", "ceph", "--admin-daemon", mirror.AdminSocketPath()}, args...)
+		return k8sutil.ExecInPod(context, namespacedName.Namespace, fmt.Sprintf("%s=%s", k8sutil.AppAttr, mirror.AppName), mirror.ContainerName, command)
+	}
+
+	// allow overriding the check interval
+	checkInterval := healthCheckSpec.Mirror.Interval
+	if checkInterval != "" {
+		if duration, err := time.ParseDuration(checkInterval); err == nil {
+			logger.Infof("filesystem mirroring status check interval for filesystem %q is %q", namespacedName.Name, checkInterval)
+			c.interval = duration
+		}
+	}
+
+	return c
+}
+
+// checkMirroring periodically checks the mirroring status of the filesystem directories
+func (c *mirrorChecker) checkMirroring(stopCh chan struct{}) {
+	// check the mirroring status immediately before starting the loop
+	c.checkMirroringHealth()
+
+	for {
+		select {
+		case <-stopCh:
+			logger.Infof("stopping monitoring filesystem mirroring status %q", c.namespacedName.Name)
+			return
+
+		case <-time.After(c.interval):
+			logger.Debugf("checking filesystem mirroring status %q", c.namespacedName.Name)
+			c.checkMirroringHealth()
+		}
+	}
+}
+
+func (c *mirrorChecker) checkMirroringHealth() {
+	fs := &cephv1.CephFilesystem{}
+	if err := c.client.Get(context.TODO(), c.namespacedName, fs); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve filesystem %q to check mirroring status. %v", c.namespacedName.Name, err)
+		return
+	}
+	if fs.Spec.Mirroring == nil || !fs.Spec.Mirroring.Enabled {
+		return
+	}
+
+	peers, directories, details := c.mirroringStatus()
+	c.updateStatusMirroring(fs, peers, directories, strings.Join(details, "; "))
+}
+
+// mirroringStatus returns the status of the peers of the filesystem and the sync status of its directories to each peer
+func (c *mirrorChecker) mirroringStatus() ([]cephv1.FilesystemMirrorPeerStatus, []cephv1.FilesystemMirrorDirectoryStatus, []string) {
+	daemons, err := cephclient.GetFilesystemMirrorDaemonStatus(c.context, c.clusterInfo)
+	if err != nil {
+		logger.Debugf("failed to check mirroring status of filesystem %q. %v", c.fsName, err)
+		return nil, nil, []string{err.Error()}
+	}
+
+	var peers []cephv1.FilesystemMirrorPeerStatus
+	var directories []cephv1.FilesystemMirrorDirectoryStatus
+	var details []string
+	for _, daemon := range daemons {
+		for _, fs := range daemon.Filesystems {
+			if fs.Name != c.fsName {
+				continue
+			}
+			for _, peer := range fs.Peers {
+				peers = append(peers, toPeerStatus(peer))
+				peerDirectories, err := c.peerDirectoryStatus(fs.FilesystemID, peer.UUID)
+				if err != nil {
+					logger.Debugf("failed to check sync status of filesystem %q to peer %q. %v", c.fsName, peer.UUID, err)
+					details = append(details, err.Error())
+					continue
+				}
+				directories = append(directories, peerDirectories...)
+			}
+		}
+	}
+	if len(peers) == 0 {
+		details = append(details, fmt.Sprintf("no cephfs-mirror daemon syncs filesystem %q to a peer", c.fsName))
+	}
+
+	return peers, directories, details
+}
+
+// peerDirectoryStatus returns the sync status of the mirrored directories to a peer
+func (c *mirrorChecker) peerDirectoryStatus(fsID int, peerUUID string) ([]cephv1.FilesystemMirrorDirectoryStatus, error) {
+	buf, err := c.execMirrorDaemon("fs", "mirror", "peer", "status", fmt.Sprintf("%s@%d", c.fsName, fsID), peerUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get sync status to peer %q", peerUUID)
+	}
+
+	var syncStatus map[string]directorySyncStatus
+	if err := json.Unmarshal(buf, &syncStatus); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal sync status to peer %q. %s", peerUUID, string(buf))
+	}
+
+	var directories []cephv1.FilesystemMirrorDirectoryStatus
+	for path, status := range syncStatus {
+		directories = append(directories, toDirectoryStatus(path, peerUUID, status))
+	}
+	sort.Slice(directories, func(i, j int) bool { return directories[i].Path < directories[j].Path })
+
+	return directories, nil
+}
+
+func (c *mirrorChecker) updateStatusMirroring(fs *cephv1.CephFilesystem, peers []cephv1.FilesystemMirrorPeerStatus, directories []cephv1.FilesystemMirrorDirectoryStatus, details string) {
+	if fs.Status == nil {
+		fs.Status = &cephv1.CephFilesystemStatus{}
+	}
+	fs.Status.MirroringStatus = &cephv1.FilesystemMirroringStatus{
+		Peers:       peers,
+		Directories: directories,
+		LastChecked: time.Now().UTC().Format(time.RFC3339),
+		// Always display the details, typically an error
+		Details: details,
+	}
+	if err := opcontroller.UpdateStatus(c.client, fs); err != nil {
+		logger.Errorf("failed to set filesystem %q mirroring status. %v", c.namespacedName.Name, err)
+		return
+	}
+
+	logger.Debugf("filesystem %q mirroring status updated", c.namespacedName.Name)
+}
+
+func toPeerStatus(peer cephclient.FilesystemMirrorPeer) cephv1.FilesystemMirrorPeerStatus {
+	return cephv1.FilesystemMirrorPeerStatus{
+		UUID:          peer.UUID,
+		Remote:        fmt.Sprintf("%s@%s:%s", peer.Remote.ClientName, peer.Remote.ClusterName, peer.Remote.FSName),
+		FailureCount:  peer.Stats.FailureCount,
+		RecoveryCount: peer.Stats.RecoveryCount,
+	}
+}
+
+func toDirectoryStatus(path, peerUUID string, syncStatus directorySyncStatus) cephv1.FilesystemMirrorDirectoryStatus {
+	status := cephv1.FilesystemMirrorDirectoryStatus{
+		Path:             path,
+		PeerUUID:         peerUUID,
+		State:            syncStatus.State,
+		FailureReason:    syncStatus.FailureReason,
+		SnapshotsSynced:  syncStatus.SnapsSynced,
+		SnapshotsDeleted: syncStatus.SnapsDeleted,
+		SnapshotsRenamed: syncStatus.SnapsRenamed,
+	}
+	if syncStatus.LastSyncedSnap != nil {
+		status.LastSyncedSnapshot = syncStatus.LastSyncedSnap.Name
+		duration := time.Duration(syncStatus.LastSyncedSnap.SyncDuration * float64(time.Second))
+		status.LastSyncDuration = duration.Round(time.Millisecond).String()
+	}
+
+	return status
+}
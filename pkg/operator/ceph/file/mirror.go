@@ -0,0 +1,295 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// #nosec G101 since this is not leaking any hardcoded credentials, it's just the prefix of the secret name
+	boostrapPeerSecretName = "fs-peer-token"
+	// FSMirrorBootstrapPeerSecretName #nosec G101 since this is not leaking any hardcoded credentials, it's just the prefix of the secret name
+	FSMirrorBootstrapPeerSecretName = "fsMirrorBootstrapPeerSecretName"
+	// mirroringErrorKey is the status info key explaining why the mirroring could not be configured
+	mirroringErrorKey = "mirroringError"
+	// snapScheduleModuleName is the mgr module scheduling the snapshots
+	snapScheduleModuleName = "snap_schedule"
+	// defaultMirrorPath is the path used by the snapshot schedules without path
+	defaultMirrorPath = "/"
+)
+
+// reconcileMirroring enables the snapshot mirroring of the filesystem, adds its peers and directories and
+// schedules the snapshots that are mirrored
+func (r *ReconcileCephFilesystem) reconcileMirroring(cephFilesystem *cephv1.CephFilesystem) (reconcile.Result, error) {
+	err := cephclient.EnableFilesystemSnapshotMirror(r.context, r.clusterInfo, cephFilesystem.Name)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to enable mirroring on filesystem %q", cephFilesystem.Name)
+	}
+
+	// Always create a bootstrap peer token in case another cluster wants to add us as a peer
+	reconcileResponse, err := r.createBootstrapPeerSecret(cephFilesystem)
+	if err != nil {
+		return reconcileResponse, errors.Wrapf(err, "failed to create cephfs-mirror bootstrap peer for filesystem %q", cephFilesystem.Name)
+	}
+
+	// Add the peers, if any
+	reconcileResponse, err = r.reconcileAddBoostrapPeer(cephFilesystem)
+	if err != nil {
+		return reconcileResponse, errors.Wrapf(err, "failed to add cephfs-mirror bootstrap peers to filesystem %q", cephFilesystem.Name)
+	}
+
+	for _, path := range cephFilesystem.Spec.Mirroring.Directories {
+		err = cephclient.AddFilesystemMirrorDirectory(r.context, r.clusterInfo, cephFilesystem.Name, path)
+		if err != nil {
+			return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to mirror directory %q", path)
+		}
+	}
+
+	// Schedule the snapshots, only the snapshots are mirrored
+	err = cephclient.MgrEnableModule(r.context, r.clusterInfo, snapScheduleModuleName, false)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to enable mgr %q module", snapScheduleModuleName)
+	}
+	for _, schedule := range cephFilesystem.Spec.Mirroring.SnapshotSchedules {
+		err = cephclient.AddFilesystemSnapshotSchedule(r.context, r.clusterInfo, cephFilesystem.Name, mirrorPath(schedule.Path), schedule.Interval, schedule.StartTime)
+		if err != nil {
+			return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to add snapshot schedule")
+		}
+	}
+	for _, retention := range cephFilesystem.Spec.Mirroring.SnapshotRetention {
+		err = cephclient.AddFilesystemSnapshotRetention(r.context, r.clusterInfo, cephFilesystem.Name, mirrorPath(retention.Path), retention.Duration)
+		if err != nil {
+			return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to add snapshot retention")
+		}
+	}
+
+	// Remove the schedules and retention removed from the spec
+	err = r.removeStaleSnapshotSchedules(cephFilesystem)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to remove stale snapshot schedules")
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// removeStaleSnapshotSchedules removes the snapshot schedules and retention of the filesystem that are not in the spec
+func (r *ReconcileCephFilesystem) removeStaleSnapshotSchedules(cephFilesystem *cephv1.CephFilesystem) error {
+	schedules, err := cephclient.ListFilesystemSnapshotSchedules(r.context, r.clusterInfo, cephFilesystem.Name)
+	if err != nil {
+		return err
+	}
+
+	wantedSchedules := map[string]bool{}
+	for _, schedule := range cephFilesystem.Spec.Mirroring.SnapshotSchedules {
+		wantedSchedules[mirrorPath(schedule.Path)+" "+schedule.Interval] = true
+	}
+	wantedRetention := map[string]bool{}
+	for _, retention := range cephFilesystem.Spec.Mirroring.SnapshotRetention {
+		for _, duration := range cephclient.SplitFilesystemSnapshotRetention(retention.Duration) {
+			wantedRetention[mirrorPath(retention.Path)+" "+duration] = true
+		}
+	}
+
+	removedRetention := map[string]bool{}
+	for _, schedule := range schedules {
+		if !wantedSchedules[schedule.Path+" "+schedule.Interval] {
+			err = cephclient.RemoveFilesystemSnapshotSchedule(r.context, r.clusterInfo, cephFilesystem.Name, schedule.Path, schedule.Interval)
+			if err != nil {
+				return err
+			}
+		}
+		// The retention of a path is listed with each of its schedules
+		for _, duration := range schedule.Retention {
+			key := schedule.Path + " " + duration
+			if wantedRetention[key] || removedRetention[key] {
+				continue
+			}
+			err = cephclient.RemoveFilesystemSnapshotRetention(r.context, r.clusterInfo, cephFilesystem.Name, schedule.Path, duration)
+			if err != nil {
+				return err
+			}
+			removedRetention[key] = true
+		}
+	}
+
+	return nil
+}
+
+// disableMirroring stops the mirroring status check and disables the snapshot mirroring of a filesystem that was mirrored
+func (r *ReconcileCephFilesystem) disableMirroring(cephFilesystem *cephv1.CephFilesystem) (reconcile.Result, error) {
+	if health := r.fsChannels[cephFilesystem.Name]; health != nil && health.monitoringRunning {
+		close(health.stopChan)
+		r.fsChannels[cephFilesystem.Name] = &fsHealth{stopChan: make(chan struct{})}
+	}
+	// The mirroring status is not refreshed anymore
+	if cephFilesystem.Status != nil && cephFilesystem.Status.MirroringStatus != nil {
+		cephFilesystem.Status.MirroringStatus = nil
+		if err := opcontroller.UpdateStatus(r.client, cephFilesystem); err != nil {
+			return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to clear mirroring status of filesystem %q", cephFilesystem.Name)
+		}
+	}
+
+	fs, err := cephclient.GetFilesystem(r.context, r.clusterInfo, cephFilesystem.Name)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to get filesystem %q", cephFilesystem.Name)
+	}
+	if fs.MirrorInfo == nil {
+		return reconcile.Result{}, nil
+	}
+
+	err = cephclient.DisableFilesystemSnapshotMirror(r.context, r.clusterInfo, cephFilesystem.Name)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to disable mirroring on filesystem %q", cephFilesystem.Name)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func (r *ReconcileCephFilesystem) createBootstrapPeerSecret(cephFilesystem *cephv1.CephFilesystem) (reconcile.Result, error) {
+	// Create cephfs-mirror boostrap peer token
+	boostrapToken, err := cephclient.CreateFilesystemMirrorBootstrapPeer(r.context, r.clusterInfo, cephFilesystem.Name)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to create cephfs-mirror bootstrap peer")
+	}
+
+	// Generate and create a Kubernetes Secret with this token
+	s := generateBootstrapPeerSecret(cephFilesystem.Name, cephFilesystem.Namespace, boostrapToken)
+
+	// set ownerref to the Secret
+	err = controllerutil.SetControllerReference(cephFilesystem, s, r.scheme)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to set owner reference for cephfs-mirror bootstrap peer %q secret", s.Name)
+	}
+
+	// Create Secret
+	logger.Debugf("store cephfs-mirror bootstrap token in a Kubernetes Secret %q", s.Name)
+	_, err = r.context.Clientset.CoreV1().Secrets(cephFilesystem.Namespace).Create(s)
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to create cephfs-mirror bootstrap peer %q secret", s.Name)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func (r *ReconcileCephFilesystem) reconcileAddBoostrapPeer(cephFilesystem *cephv1.CephFilesystem) (reconcile.Result, error) {
+	if !cephFilesystem.Spec.Mirroring.HasPeers() {
+		return reconcile.Result{}, nil
+	}
+
+	// List all the peers secret, we can have more than one peer we might want to configure
+	// For each, get the Kubernetes Secret and import the "peer token" so that we can configure the mirroring
+	for _, peerSecret := range cephFilesystem.Spec.Mirroring.Peers.SecretNames {
+		logger.Debugf("fetching bootstrap peer kubernetes secret %q", peerSecret)
+		s, err := r.context.Clientset.CoreV1().Secrets(r.clusterInfo.Namespace).Get(peerSecret, metav1.GetOptions{})
+		// We don't care about IsNotFound here, we still need to fail
+		if err != nil {
+			return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to fetch kubernetes secret %q bootstrap peer", peerSecret)
+		}
+
+		// Validate peer secret content
+		err = validatePeerToken(s.Data)
+		if err != nil {
+			return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to validate cephfs-mirror bootstrap peer secret %q data", peerSecret)
+		}
+
+		// Import bootstrap peer
+		err = cephclient.ImportFilesystemMirrorBootstrapPeer(r.context, r.clusterInfo, cephFilesystem.Name, s.Data["token"])
+		if err != nil {
+			return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to import bootstrap peer token")
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func validatePeerToken(data map[string][]byte) error {
+	if len(data) == 0 {
+		return errors.Errorf("failed to lookup 'data' secret field (empty)")
+	}
+
+	// Lookup Secret keys and content
+	k, ok := data["token"]
+	if !ok || len(k) == 0 {
+		return errors.Errorf("failed to lookup %q key in secret bootstrap peer (missing or empty)", "token")
+	}
+
+	return nil
+}
+
+func validateMirroring(f *cephv1.CephFilesystem) error {
+	if f.Spec.Mirroring == nil {
+		return nil
+	}
+	for _, schedule := range f.Spec.Mirroring.SnapshotSchedules {
+		if schedule.Interval == "" {
+			return errors.Errorf("missing interval of snapshot schedule on path %q", mirrorPath(schedule.Path))
+		}
+	}
+	for _, retention := range f.Spec.Mirroring.SnapshotRetention {
+		if retention.Duration == "" {
+			return errors.Errorf("missing duration of snapshot retention on path %q", mirrorPath(retention.Path))
+		}
+	}
+
+	return nil
+}
+
+// generateBootstrapPeerSecret generates a Kubernetes Secret for the mirror bootstrap peer token
+func generateBootstrapPeerSecret(name, namespace string, token []byte) *corev1.Secret {
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      buildBoostrapPeerSecretName(name),
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			"token":      token,
+			"filesystem": []byte(name),
+		},
+		Type: k8sutil.RookType,
+	}
+
+	return s
+}
+
+func buildBoostrapPeerSecretName(name string) string {
+	return fmt.Sprintf("%s-%s", boostrapPeerSecretName, name)
+}
+
+func generateStatusInfo(fs *cephv1.CephFilesystem) map[string]string {
+	m := make(map[string]string)
+	m[FSMirrorBootstrapPeerSecretName] = buildBoostrapPeerSecretName(fs.Name)
+	return m
+}
+
+func mirrorPath(path string) string {
+	if path == "" {
+		return defaultMirrorPath
+	}
+	return path
+}
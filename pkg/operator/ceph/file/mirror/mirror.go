@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mirror
+
+import (
+	"fmt"
+
+	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// AppName is the ceph filesystem mirror application name
+	AppName = "rook-ceph-fs-mirror"
+	// ContainerName is the name of the cephfs-mirror daemon container
+	ContainerName = "fs-mirror"
+	// minimum amount of memory in MB to run the pod
+	cephFilesystemMirrorPodMinimumMemory uint64 = 512
+)
+
+var updateDeploymentAndWait = mon.UpdateCephDeploymentAndWait
+
+// Start begins the process of running the filesystem mirroring daemon.
+func (r *ReconcileFilesystemMirror) start(fsMirror *cephv1.CephFilesystemMirror) error {
+	// Validate pod's memory if specified
+	err := controller.CheckPodMemory(cephv1.ResourcesKeyFilesystemMirror, fsMirror.Spec.Resources, cephFilesystemMirrorPodMinimumMemory)
+	if err != nil {
+		return errors.Wrap(err, "error checking pod memory")
+	}
+
+	// Create the controller owner ref
+	// It will be associated to all resources of the CephFilesystemMirror
+	ref, err := controller.GetControllerObjectOwnerReference(fsMirror, r.scheme)
+	if err != nil || ref == nil {
+		return errors.Wrapf(err, "failed to get controller %q owner reference", fsMirror.Name)
+	}
+
+	resourceName := fmt.Sprintf("%s-%s", AppName, daemonID)
+	daemonConf := &daemonConfig{
+		DaemonID:     daemonID,
+		ResourceName: resourceName,
+		DataPathMap:  config.NewDatalessDaemonDataPathMap(fsMirror.Namespace, r.cephClusterSpec.DataDirHostPath),
+		ownerRef:     *ref,
+	}
+
+	_, err = r.generateKeyring(r.clusterInfo, daemonConf)
+	if err != nil {
+		return errors.Wrapf(err, "failed to generate keyring for %q", resourceName)
+	}
+
+	// Start the deployment
+	d, err := r.makeDeployment(daemonConf, fsMirror)
+	if err != nil {
+		return errors.Wrap(err, "failed to create filesystem-mirror deployment")
+	}
+
+	// Set owner ref to fsMirror object
+	err = controllerutil.SetControllerReference(fsMirror, d, r.scheme)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set owner reference for ceph filesystem-mirror deployment %q", d.Name)
+	}
+
+	// Set the deployment hash as an annotation
+	err = patch.DefaultAnnotator.SetLastAppliedAnnotation(d)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set annotation for deployment %q", d.Name)
+	}
+
+	if _, err := r.context.Clientset.AppsV1().Deployments(fsMirror.Namespace).Create(d); err != nil {
+		if !kerrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create %q deployment", resourceName)
+		}
+		logger.Infof("deployment for filesystem-mirror %q already exists. updating if needed", resourceName)
+
+		if err := updateDeploymentAndWait(r.context, r.clusterInfo, d, config.FilesystemMirrorType, daemonConf.DaemonID, r.cephClusterSpec.SkipUpgradeChecks, false); err != nil {
+			// fail could be an issue updating label selector (immutable), so try del and recreate
+			logger.Debugf("updateDeploymentAndWait failed for filesystem-mirror %q. Attempting del-and-recreate. %v", resourceName, err)
+			err = r.context.Clientset.AppsV1().Deployments(fsMirror.Namespace).Delete(resourceName, &metav1.DeleteOptions{})
+			if err != nil {
+				return errors.Wrapf(err, "failed to delete filesystem-mirror %q during del-and-recreate update attempt", resourceName)
+			}
+			if _, err := r.context.Clientset.AppsV1().Deployments(fsMirror.Namespace).Create(d); err != nil {
+				return errors.Wrapf(err, "failed to recreate filesystem-mirror deployment %q during del-and-recreate update attempt", resourceName)
+			}
+		}
+	}
+
+	logger.Infof("%q deployment started", resourceName)
+	return nil
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileMirroring(t *testing.T) {
+	var mirrorCommands [][]string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "fs" && (args[1] == "snapshot" || args[1] == "snap-schedule") {
+				mirrorCommands = append(mirrorCommands, args)
+				if args[3] == "peer_bootstrap" && args[4] == "create" {
+					return `{"token": "eyJmc2lkIjogIjgxNDFlMjc"}`, nil
+				}
+				if args[2] == "list" {
+					return "/ 1h 24h7d\n/volumes 1d \n", nil
+				}
+			}
+			return "", nil
+		},
+	}
+	c := &clusterd.Context{Executor: executor, Clientset: test.New(t, 3)}
+
+	fs := &cephv1.CephFilesystem{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: cephv1.FilesystemSpec{
+			Mirroring: &cephv1.FSMirroringSpec{
+				Enabled:           true,
+				Peers:             &cephv1.MirroringPeerSpec{SecretNames: []string{"peer-secret"}},
+				Directories:       []string{"/volumes/csi"},
+				SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Interval: "1h"}},
+				SnapshotRetention: []cephv1.SnapshotScheduleRetentionSpec{{Duration: "24h"}},
+			},
+		},
+		TypeMeta: controllerTypeMeta,
+	}
+	s := scheme.Scheme
+	cl := fake.NewFakeClientWithScheme(s, []runtime.Object{fs}...)
+	r := &ReconcileCephFilesystem{client: cl, scheme: s, context: c, clusterInfo: cephclient.AdminClusterInfo(namespace)}
+
+	// the peer secret is missing
+	_, err := r.reconcileMirroring(fs)
+	assert.Error(t, err)
+
+	peerSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "peer-secret", Namespace: namespace},
+		Data:       map[string][]byte{"token": []byte("eyJmc2lkIjogImM5Mzk")},
+		Type:       k8sutil.RookType,
+	}
+	_, err = c.Clientset.CoreV1().Secrets(namespace).Create(peerSecret)
+	assert.NoError(t, err)
+
+	mirrorCommands = nil
+	res, err := r.reconcileMirroring(fs)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+
+	// the local bootstrap token is stored for the peers
+	bootstrapSecret, err := c.Clientset.CoreV1().Secrets(namespace).Get("fs-peer-token-my-fs", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "eyJmc2lkIjogIjgxNDFlMjc", string(bootstrapSecret.Data["token"]))
+	assert.Equal(t, "fs-peer-token-my-fs", generateStatusInfo(fs)[FSMirrorBootstrapPeerSecretName])
+
+	assert.Equal(t, 9, len(mirrorCommands))
+	assert.Equal(t, []string{"fs", "snapshot", "mirror", "enable", name}, mirrorCommands[0][:5])
+	assert.Equal(t, []string{"peer_bootstrap", "import", name, "eyJmc2lkIjogImM5Mzk"}, mirrorCommands[2][3:7])
+	assert.Equal(t, []string{"add", name, "/volumes/csi"}, mirrorCommands[3][3:6])
+	assert.Equal(t, []string{"snap-schedule", "add", "/", "1h", "--fs", name}, mirrorCommands[4][1:7])
+	assert.Equal(t, []string{"retention", "add", "/", "24h", "--fs", name}, mirrorCommands[5][2:8])
+	// the schedules and retention removed from the spec are removed
+	assert.Equal(t, []string{"snap-schedule", "list", "/", "--recursive", "--fs", name}, mirrorCommands[6][1:7])
+	assert.Equal(t, []string{"retention", "remove", "/", "7d", "--fs", name}, mirrorCommands[7][2:8])
+	assert.Equal(t, []string{"snap-schedule", "remove", "/volumes", "1d", "--fs", name}, mirrorCommands[8][1:7])
+}
+
+func TestDisableMirroring(t *testing.T) {
+	mirrored := true
+	var disabled bool
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "fs" && args[1] == "get" {
+				if mirrored {
+					return `{"id": 1, "mdsmap": {"fs_name": "my-fs"}, "mirror_info": {"peers": []}}`, nil
+				}
+				return `{"id": 1, "mdsmap": {"fs_name": "my-fs"}}`, nil
+			}
+			if args[0] == "fs" && args[1] == "snapshot" && args[3] == "disable" {
+				assert.Equal(t, name, args[4])
+				disabled = true
+			}
+			return "", nil
+		},
+	}
+	c := &clusterd.Context{Executor: executor, Clientset: test.New(t, 3)}
+
+	fs := &cephv1.CephFilesystem{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       cephv1.FilesystemSpec{Mirroring: &cephv1.FSMirroringSpec{Enabled: false}},
+		Status:     &cephv1.CephFilesystemStatus{MirroringStatus: &cephv1.FilesystemMirroringStatus{Details: "stale"}},
+		TypeMeta:   controllerTypeMeta,
+	}
+	s := scheme.Scheme
+	cl := fake.NewFakeClientWithScheme(s, []runtime.Object{fs}...)
+	stopChan := make(chan struct{})
+	r := &ReconcileCephFilesystem{
+		client:      cl,
+		scheme:      s,
+		context:     c,
+		clusterInfo: cephclient.AdminClusterInfo(namespace),
+		fsChannels:  map[string]*fsHealth{name: {stopChan: stopChan, monitoringRunning: true}},
+	}
+
+	_, err := r.disableMirroring(fs)
+	assert.NoError(t, err)
+	assert.True(t, disabled)
+
+	// the status check is stopped and its status cleared
+	_, open := <-stopChan
+	assert.False(t, open)
+	assert.False(t, r.fsChannels[name].monitoringRunning)
+	updated := &cephv1.CephFilesystem{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, updated)
+	assert.NoError(t, err)
+	assert.Nil(t, updated.Status.MirroringStatus)
+
+	// nothing to disable on a filesystem that is not mirrored
+	mirrored = false
+	disabled = false
+	_, err = r.disableMirroring(fs)
+	assert.NoError(t, err)
+	assert.False(t, disabled)
+}
+
+func TestValidatePeerToken(t *testing.T) {
+	// Empty map
+	err := validatePeerToken(map[string][]byte{})
+	assert.Error(t, err)
+
+	// Missing token
+	err = validatePeerToken(map[string][]byte{"foo": []byte("bar")})
+	assert.Error(t, err)
+
+	// Success
+	err = validatePeerToken(map[string][]byte{"token": []byte("eyJmc2lkIjogImM5Mzk")})
+	assert.NoError(t, err)
+}
+
+func TestValidateMirroring(t *testing.T) {
+	fs := &cephv1.CephFilesystem{ObjectMeta: metav1.ObjectMeta{Name: "myfs", Namespace: "rook-ceph"}}
+	assert.NoError(t, validateMirroring(fs))
+
+	fs.Spec.Mirroring = &cephv1.FSMirroringSpec{
+		Enabled:           true,
+		SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Path: "/volumes", Interval: "1h"}},
+		SnapshotRetention: []cephv1.SnapshotScheduleRetentionSpec{{Duration: "24h"}},
+	}
+	assert.NoError(t, validateMirroring(fs))
+
+	// a schedule needs an interval
+	fs.Spec.Mirroring.SnapshotSchedules = append(fs.Spec.Mirroring.SnapshotSchedules, cephv1.SnapshotScheduleSpec{StartTime: "2020-11-01T00:00:00"})
+	assert.Error(t, validateMirroring(fs))
+	fs.Spec.Mirroring.SnapshotSchedules = fs.Spec.Mirroring.SnapshotSchedules[:1]
+
+	// a retention needs a duration
+	fs.Spec.Mirroring.SnapshotRetention = append(fs.Spec.Mirroring.SnapshotRetention, cephv1.SnapshotScheduleRetentionSpec{Path: "/volumes"})
+	assert.Error(t, validateMirroring(fs))
+}
+
+func TestMirroringStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "fs" && args[1] == "snapshot" && args[3] == "daemon" {
+				return `[{"daemon_id": 4115, "filesystems": [{"filesystem_id": 2, "name": "my-fs", "directory_count": 2,
+					"peers": [{"uuid": "a2dc7784", "remote": {"client_name": "client.mirror_remote", "cluster_name": "site-b", "fs_name": "backup"},
+					"stats": {"failure_count": 1, "recovery_count": 0}}]}]}]`, nil
+			}
+			return "", nil
+		},
+	}
+	c := &mirrorChecker{
+		context:     &clusterd.Context{Executor: executor},
+		clusterInfo: cephclient.AdminClusterInfo(namespace),
+		fsName:      name,
+		execMirrorDaemon: func(args ...string) ([]byte, error) {
+			assert.Equal(t, []string{"fs", "mirror", "peer", "status", "my-fs@2", "a2dc7784"}, args)
+			return []byte(`{
+				"/volumes/csi": {"state": "idle", "last_synced_snap": {"id": 120, "name": "scheduled-2020-11-10-12_00_00", "sync_duration": 1.52, "sync_time_stamp": "274900.558797s"},
+					"snaps_synced": 2, "snaps_deleted": 1, "snaps_renamed": 0},
+				"/data": {"state": "failed", "failure_reason": "snapshot comparison failed", "snaps_synced": 0, "snaps_deleted": 0, "snaps_renamed": 0}
+			}`), nil
+		},
+	}
+
+	peers, directories, details := c.mirroringStatus()
+	assert.Empty(t, details)
+	assert.Equal(t, []cephv1.FilesystemMirrorPeerStatus{
+		{UUID: "a2dc7784", Remote: "client.mirror_remote@site-b:backup", FailureCount: 1},
+	}, peers)
+	assert.Equal(t, []cephv1.FilesystemMirrorDirectoryStatus{
+		{Path: "/data", PeerUUID: "a2dc7784", State: "failed", FailureReason: "snapshot comparison failed"},
+		{Path: "/volumes/csi", PeerUUID: "a2dc7784", State: "idle", LastSyncedSnapshot: "scheduled-2020-11-10-12_00_00", LastSyncDuration: "1.52s", SnapshotsSynced: 2, SnapshotsDeleted: 1},
+	}, directories)
+
+	// the daemon does not answer
+	c.execMirrorDaemon = func(args ...string) ([]byte, error) {
+		return nil, errors.New("did not find any running pod")
+	}
+	peers, directories, details = c.mirroringStatus()
+	assert.Equal(t, 1, len(peers))
+	assert.Empty(t, directories)
+	assert.Equal(t, 1, len(details))
+
+	// the filesystem is not synced by the daemon
+	c.fsName = "other-fs"
+	peers, _, details = c.mirroringStatus()
+	assert.Empty(t, peers)
+	assert.Equal(t, []string{`no cephfs-mirror daemon syncs filesystem "other-fs" to a peer`}, details)
+}
@@ -0,0 +1,478 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package subvolumegroup to manage CephFS subvolume groups
+package subvolumegroup
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-fs-subvolumegroup-controller"
+
+	// the keys of the status info
+	pathKey             = "path"
+	pendingSubVolumeKey = "pendingSubVolumes"
+	messageKey          = "message"
+	// the quota and the pinning last applied to the group, they are only updated in ceph when the spec changes
+	appliedQuotaKey   = "quota"
+	appliedPinningKey = "pinning"
+)
+
+// quotaAndPinningMinCephVersion is the first Ceph version with the "fs subvolumegroup resize" and "fs subvolumegroup pin" commands
+var quotaAndPinningMinCephVersion = cephver.Pacific
+
+// the settings resetting each pinning policy on the group
+var unpinnedSettings = map[string]string{
+	"export":      "-1",
+	"distributed": "0",
+	"random":      "0",
+}
+
+var waitForRequeueIfFilesystemNotReady = reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephFilesystemSubVolumeGroupKind = reflect.TypeOf(cephv1.CephFilesystemSubVolumeGroup{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephFilesystemSubVolumeGroupKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileCephFilesystemSubVolumeGroup reconciles a CephFilesystemSubVolumeGroup object
+type ReconcileCephFilesystemSubVolumeGroup struct {
+	client      client.Client
+	scheme      *runtime.Scheme
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+}
+
+// Add creates a new CephFilesystemSubVolumeGroup Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context) error {
+	return add(mgr, newReconciler(mgr, context))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Reconciler {
+	// Add the cephv1 scheme to the manager scheme so that the controller knows about it
+	mgrScheme := mgr.GetScheme()
+	if err := cephv1.AddToScheme(mgr.GetScheme()); err != nil {
+		panic(err)
+	}
+	return &ReconcileCephFilesystemSubVolumeGroup{
+		client:  mgr.GetClient(),
+		scheme:  mgrScheme,
+		context: context,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephFilesystemSubVolumeGroup CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephFilesystemSubVolumeGroup{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephFilesystemSubVolumeGroup object and makes changes based on the state read
+// and what is in the CephFilesystemSubVolumeGroup.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephFilesystemSubVolumeGroup) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime loggin interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile: %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileCephFilesystemSubVolumeGroup) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephFilesystemSubVolumeGroup instance
+	cephFilesystemSubVolumeGroup := &cephv1.CephFilesystemSubVolumeGroup{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, cephFilesystemSubVolumeGroup)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystemSubVolumeGroup resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to get CephFilesystemSubVolumeGroup")
+	}
+
+	// The CR was just created, initializing status fields
+	if cephFilesystemSubVolumeGroup.Status == nil {
+		updateStatus(r.client, request.NamespacedName, cephv1.ConditionProgressing, nil)
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	_, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		// We skip the deletion of the group since everything is gone already
+		if !cephFilesystemSubVolumeGroup.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// Remove finalizer
+			err = opcontroller.RemoveFinalizer(r.client, cephFilesystemSubVolumeGroup)
+			if err != nil {
+				return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		return reconcileResponse, nil
+	}
+
+	// Populate clusterInfo during each reconcile
+	r.clusterInfo, _, _, err = mon.LoadClusterInfo(r.context, request.NamespacedName.Namespace)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to populate cluster info")
+	}
+
+	// Populate CephVersion, the quota and the pinning of the group are not supported by older versions
+	currentCephVersion, err := cephclient.LeastUptodateDaemonVersion(r.context, r.clusterInfo, opconfig.MonType)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to retrieve current ceph %q version", opconfig.MonType)
+	}
+	r.clusterInfo.CephVersion = currentCephVersion
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, cephFilesystemSubVolumeGroup)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// DELETE: the CR was deleted
+	if !cephFilesystemSubVolumeGroup.GetDeletionTimestamp().IsZero() {
+		logger.Debugf("deleting subvolume group %q", cephFilesystemSubVolumeGroup.Name)
+		reconcileResponse, err = r.deleteSubVolumeGroup(cephFilesystemSubVolumeGroup, request.NamespacedName)
+		if err != nil || reconcileResponse.Requeue {
+			return reconcileResponse, err
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.client, cephFilesystemSubVolumeGroup)
+		if err != nil {
+			return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, nil
+	}
+
+	// validate the subvolume group settings
+	if err := validateSubVolumeGroup(cephFilesystemSubVolumeGroup); err != nil {
+		updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure, nil)
+		return reconcile.Result{}, errors.Wrapf(err, "invalid CephFilesystemSubVolumeGroup CR %q spec", cephFilesystemSubVolumeGroup.Name)
+	}
+
+	// the quota and the pinning need commands that older ceph versions do not have
+	if err := validateCephVersion(cephFilesystemSubVolumeGroup, r.clusterInfo.CephVersion); err != nil {
+		info := appliedSettings(cephFilesystemSubVolumeGroup)
+		info[messageKey] = err.Error()
+		updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure, info)
+		return reconcile.Result{}, errors.Wrapf(err, "invalid CephFilesystemSubVolumeGroup CR %q spec", cephFilesystemSubVolumeGroup.Name)
+	}
+
+	// Make sure the CephFilesystem is ready
+	reconcileResponse, err = r.checkFilesystem(cephFilesystemSubVolumeGroup)
+	if err != nil || reconcileResponse.Requeue {
+		return reconcileResponse, err
+	}
+
+	// CREATE/UPDATE
+	info, err := r.createOrUpdateSubVolumeGroup(cephFilesystemSubVolumeGroup)
+	if err != nil {
+		// keep what was applied so far so that the next reconcile only applies the remaining changes
+		info[messageKey] = err.Error()
+		updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure, info)
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to create subvolume group %q", cephFilesystemSubVolumeGroup.Name)
+	}
+
+	// Set Ready status, we are done reconciling
+	updateStatus(r.client, request.NamespacedName, cephv1.ConditionReady, info)
+
+	// Return and do not requeue
+	logger.Debug("done reconciling")
+	return reconcile.Result{}, nil
+}
+
+func (r *ReconcileCephFilesystemSubVolumeGroup) checkFilesystem(group *cephv1.CephFilesystemSubVolumeGroup) (reconcile.Result, error) {
+	cephFilesystem := &cephv1.CephFilesystem{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: group.Spec.FilesystemName, Namespace: group.Namespace}, cephFilesystem)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return waitForRequeueIfFilesystemNotReady, errors.Wrapf(err, "CephFilesystem %q not found", group.Spec.FilesystemName)
+		}
+		return waitForRequeueIfFilesystemNotReady, errors.Wrapf(err, "failed to get CephFilesystem %q", group.Spec.FilesystemName)
+	}
+
+	if cephFilesystem.Status == nil || cephFilesystem.Status.Phase != k8sutil.ReadyStatus {
+		logger.Infof("waiting for CephFilesystem %q to be ready before creating subvolume group %q", cephFilesystem.Name, group.Name)
+		return waitForRequeueIfFilesystemNotReady, nil
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// createOrUpdateSubVolumeGroup creates the group and applies its quota and pinning when they changed since the last
+// reconcile, it returns the status info with the path of the group and the settings applied to it
+func (r *ReconcileCephFilesystemSubVolumeGroup) createOrUpdateSubVolumeGroup(group *cephv1.CephFilesystemSubVolumeGroup) (map[string]string, error) {
+	fsName := group.Spec.FilesystemName
+	info := appliedSettings(group)
+	err := cephclient.CreateSubVolumeGroup(r.context, r.clusterInfo, fsName, group.Name, group.Spec.DataPoolName)
+	if err != nil {
+		return info, err
+	}
+
+	// a quota removed from the spec is removed from the group by resizing it to "inf"
+	quota := desiredQuota(group)
+	if quota != info[appliedQuotaKey] {
+		var size int64
+		if group.Spec.Quota != nil {
+			size = group.Spec.Quota.Value()
+		}
+		err = cephclient.ResizeSubVolumeGroup(r.context, r.clusterInfo, fsName, group.Name, size)
+		if err != nil {
+			return info, err
+		}
+		setInfo(info, appliedQuotaKey, quota)
+	}
+
+	// the policy previously applied is reset when the pinning is removed from the spec or changed to another policy
+	pinType, setting := desiredPinning(group)
+	pinning := pinningString(pinType, setting)
+	if pinning != info[appliedPinningKey] {
+		appliedType, _ := parsePinning(info[appliedPinningKey])
+		if appliedType != "" && appliedType != pinType {
+			err = cephclient.PinSubVolumeGroup(r.context, r.clusterInfo, fsName, group.Name, appliedType, unpinnedSettings[appliedType])
+			if err != nil {
+				return info, err
+			}
+			delete(info, appliedPinningKey)
+		}
+		if pinType != "" {
+			err = cephclient.PinSubVolumeGroup(r.context, r.clusterInfo, fsName, group.Name, pinType, setting)
+			if err != nil {
+				return info, err
+			}
+		}
+		setInfo(info, appliedPinningKey, pinning)
+	}
+
+	path, err := cephclient.GetSubVolumeGroupPath(r.context, r.clusterInfo, fsName, group.Name)
+	if err != nil {
+		return info, err
+	}
+	info[pathKey] = path
+
+	return info, nil
+}
+
+// appliedSettings returns a new status info with the quota and the pinning last applied to the group
+func appliedSettings(group *cephv1.CephFilesystemSubVolumeGroup) map[string]string {
+	info := map[string]string{}
+	if group.Status == nil {
+		return info
+	}
+	setInfo(info, appliedQuotaKey, group.Status.Info[appliedQuotaKey])
+	setInfo(info, appliedPinningKey, group.Status.Info[appliedPinningKey])
+	return info
+}
+
+func setInfo(info map[string]string, key, value string) {
+	if value == "" {
+		delete(info, key)
+		return
+	}
+	info[key] = value
+}
+
+// desiredQuota returns the quota of the spec in bytes, or an empty string if the group has no quota
+func desiredQuota(group *cephv1.CephFilesystemSubVolumeGroup) string {
+	if group.Spec.Quota == nil || group.Spec.Quota.IsZero() {
+		return ""
+	}
+	return strconv.FormatInt(group.Spec.Quota.Value(), 10)
+}
+
+// desiredPinning returns the pinning policy of the spec and its setting, or empty strings if the group is not pinned
+func desiredPinning(group *cephv1.CephFilesystemSubVolumeGroup) (string, string) {
+	pinning := group.Spec.Pinning
+	switch {
+	case pinning.Export != nil:
+		return "export", strconv.Itoa(*pinning.Export)
+	case pinning.Distributed != nil:
+		return "distributed", strconv.Itoa(*pinning.Distributed)
+	case pinning.Random != nil:
+		return "random", strconv.FormatFloat(*pinning.Random, 'f', -1, 64)
+	}
+	return "", ""
+}
+
+func pinningString(pinType, setting string) string {
+	if pinType == "" {
+		return ""
+	}
+	return pinType + "=" + setting
+}
+
+func parsePinning(pinning string) (string, string) {
+	parts := strings.SplitN(pinning, "=", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
+
+// deleteSubVolumeGroup removes the group from the filesystem, the deletion is blocked while the group has subvolumes
+func (r *ReconcileCephFilesystemSubVolumeGroup) deleteSubVolumeGroup(group *cephv1.CephFilesystemSubVolumeGroup, namespacedName types.NamespacedName) (reconcile.Result, error) {
+	fsName := group.Spec.FilesystemName
+	cephFilesystem := &cephv1.CephFilesystem{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: fsName, Namespace: group.Namespace}, cephFilesystem)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// the group was removed with the filesystem
+			logger.Infof("CephFilesystem %q not found, subvolume group %q is already gone", fsName, group.Name)
+			return reconcile.Result{}, nil
+		}
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to get CephFilesystem %q", fsName)
+	}
+
+	subVolumes, err := cephclient.ListSubVolumes(r.context, r.clusterInfo, fsName, group.Name)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, err
+	}
+	if len(subVolumes) > 0 {
+		names := make([]string, 0, len(subVolumes))
+		for _, subVolume := range subVolumes {
+			names = append(names, subVolume.Name)
+		}
+		logger.Errorf("failed to delete subvolume group %q. subvolumes are not cleaned up. remaining subvolumes: %+v", group.Name, names)
+		updateStatus(r.client, namespacedName, cephv1.ConditionDeleting, map[string]string{pendingSubVolumeKey: strings.Join(names, ",")})
+		return opcontroller.WaitForRequeueIfFinalizerBlocked, nil
+	}
+
+	err = cephclient.DeleteSubVolumeGroup(r.context, r.clusterInfo, fsName, group.Name)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// validateSubVolumeGroup validates the subvolume group arguments
+func validateSubVolumeGroup(group *cephv1.CephFilesystemSubVolumeGroup) error {
+	if group.Spec.FilesystemName == "" {
+		return errors.New("missing filesystemName")
+	}
+	if group.Spec.Quota != nil && group.Spec.Quota.Sign() < 0 {
+		return errors.New("quota cannot be negative")
+	}
+
+	pinning := group.Spec.Pinning
+	policies := 0
+	for _, set := range []bool{pinning.Export != nil, pinning.Distributed != nil, pinning.Random != nil} {
+		if set {
+			policies++
+		}
+	}
+	if policies > 1 {
+		return errors.New("only one of export, distributed or random pinning can be set")
+	}
+	if pinning.Random != nil && (*pinning.Random < 0 || *pinning.Random > 1) {
+		return errors.New("random pinning must be between 0 and 1")
+	}
+
+	return nil
+}
+
+// validateCephVersion checks that the ceph version supports the quota and the pinning requested for the group
+func validateCephVersion(group *cephv1.CephFilesystemSubVolumeGroup, cephVersion cephver.CephVersion) error {
+	if cephVersion.IsAtLeast(quotaAndPinningMinCephVersion) {
+		return nil
+	}
+	pinType, _ := desiredPinning(group)
+	if desiredQuota(group) != "" || pinType != "" {
+		return errors.Errorf("the quota and the pinning of a subvolume group require ceph pacific or newer, the cluster is running ceph %s", cephVersion.String())
+	}
+	return nil
+}
+
+// updateStatus updates a subvolume group with a given status
+func updateStatus(client client.Client, name types.NamespacedName, status cephv1.ConditionType, info map[string]string) {
+	group := &cephv1.CephFilesystemSubVolumeGroup{}
+	if err := client.Get(context.TODO(), name, group); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystemSubVolumeGroup resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve subvolume group %q to update status to %q. %v", name, status, err)
+		return
+	}
+
+	if group.Status == nil {
+		group.Status = &cephv1.CephFilesystemSubVolumeGroupStatus{}
+	}
+
+	group.Status.Phase = status
+	group.Status.Info = info
+	if err := opcontroller.UpdateStatus(client, group); err != nil {
+		logger.Warningf("failed to set subvolume group %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("subvolume group %q status updated to %q", name, status)
+}
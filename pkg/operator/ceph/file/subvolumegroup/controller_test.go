@@ -0,0 +1,268 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subvolumegroup
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestValidateSubVolumeGroup(t *testing.T) {
+	group := &cephv1.CephFilesystemSubVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "csi", Namespace: "rook-ceph"},
+	}
+
+	// missing filesystem
+	assert.Error(t, validateSubVolumeGroup(group))
+
+	group.Spec.FilesystemName = "myfs"
+	assert.NoError(t, validateSubVolumeGroup(group))
+
+	// negative quota
+	quota := resource.MustParse("-1Gi")
+	group.Spec.Quota = &quota
+	assert.Error(t, validateSubVolumeGroup(group))
+	group.Spec.Quota = nil
+
+	// two pinning policies
+	rank := 1
+	random := 0.5
+	group.Spec.Pinning = cephv1.SubVolumeGroupPinningSpec{Export: &rank, Random: &random}
+	assert.Error(t, validateSubVolumeGroup(group))
+	group.Spec.Pinning.Export = nil
+	assert.NoError(t, validateSubVolumeGroup(group))
+	random = 2
+	assert.Error(t, validateSubVolumeGroup(group))
+}
+
+func TestCephFilesystemSubVolumeGroupController(t *testing.T) {
+	namespace := "rook-ceph"
+	quota := resource.MustParse("1Gi")
+	rank := 1
+	group := &cephv1.CephFilesystemSubVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "csi", Namespace: namespace},
+		TypeMeta:   metav1.TypeMeta{Kind: "CephFilesystemSubVolumeGroup"},
+		Spec: cephv1.FilesystemSubVolumeGroupSpec{
+			FilesystemName: "myfs",
+			Quota:          &quota,
+			Pinning:        cephv1.SubVolumeGroupPinningSpec{Export: &rank},
+		},
+	}
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+		Status: cephv1.ClusterStatus{
+			Phase:      k8sutil.ReadyStatus,
+			CephStatus: &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+	cephFilesystem := &cephv1.CephFilesystem{
+		ObjectMeta: metav1.ObjectMeta{Name: "myfs", Namespace: namespace},
+		Status:     &cephv1.CephFilesystemStatus{Phase: k8sutil.ProcessingStatus},
+	}
+
+	subVolumes := "[]"
+	versions := `{"mon":{"ceph version 16.2.1 (afb9061ab4117f798c858c741efa6390e48ccf10) pacific (stable)":3}}`
+	commands := [][]string{}
+	settings := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "status" {
+				return `{"fsid":"c47cac40-9bee-4d52-823b-ccd803ba5bfe","health":{"checks":{},"status":"HEALTH_OK"},"pgmap":{"num_pgs":100,"pgs_by_state":[{"state_name":"active+clean","count":100}]}}`, nil
+			}
+			if args[0] == "versions" {
+				return versions, nil
+			}
+			if args[0] == "fs" {
+				commands = append(commands, args[:3])
+				if args[2] == "resize" {
+					settings = append(settings, args[5])
+				} else if args[2] == "pin" {
+					settings = append(settings, args[5]+"="+args[6])
+				}
+				if args[2] == "getpath" {
+					return "/volumes/csi", nil
+				}
+				if args[1] == "subvolume" && args[2] == "ls" {
+					return subVolumes, nil
+				}
+			}
+			return "", nil
+		},
+	}
+	clientset := test.New(t, 3)
+	c := &clusterd.Context{
+		Executor:      executor,
+		RookClientset: rookclient.NewSimpleClientset(),
+		Clientset:     clientset,
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+		Data: map[string][]byte{
+			"fsid":         []byte("c47cac40-9bee-4d52-823b-ccd803ba5bfe"),
+			"mon-secret":   []byte("monsecret"),
+			"admin-secret": []byte("adminsecret"),
+		},
+		Type: k8sutil.RookType,
+	}
+	_, err := clientset.CoreV1().Secrets(namespace).Create(secret)
+	assert.NoError(t, err)
+
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephFilesystemSubVolumeGroup{}, &cephv1.CephFilesystemSubVolumeGroupList{},
+		&cephv1.CephFilesystem{}, &cephv1.CephFilesystemList{}, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	cl := fake.NewFakeClientWithScheme(s, []runtime.Object{group, cephCluster, cephFilesystem}...)
+	r := &ReconcileCephFilesystemSubVolumeGroup{client: cl, scheme: s, context: c}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "csi", Namespace: namespace}}
+
+	//
+	// TEST 1: the filesystem is not ready
+	//
+	res, err := r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.Equal(t, 0, len(commands))
+
+	//
+	// TEST 2: the group is created
+	//
+	cephFilesystem.Status.Phase = k8sutil.ReadyStatus
+	err = cl.Update(context.TODO(), cephFilesystem)
+	assert.NoError(t, err)
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, [][]string{
+		{"fs", "subvolumegroup", "create"},
+		{"fs", "subvolumegroup", "resize"},
+		{"fs", "subvolumegroup", "pin"},
+		{"fs", "subvolumegroup", "getpath"},
+	}, commands)
+	assert.Equal(t, []string{"1073741824", "export=1"}, settings)
+	err = cl.Get(context.TODO(), req.NamespacedName, group)
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.ConditionReady, group.Status.Phase)
+	assert.Equal(t, "/volumes/csi", group.Status.Info[pathKey])
+	assert.Equal(t, "1073741824", group.Status.Info[appliedQuotaKey])
+	assert.Equal(t, "export=1", group.Status.Info[appliedPinningKey])
+	assert.Equal(t, 1, len(group.Finalizers))
+
+	//
+	// TEST 3: the quota and the pinning are not applied again when the spec did not change
+	//
+	commands = [][]string{}
+	settings = []string{}
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, [][]string{{"fs", "subvolumegroup", "create"}, {"fs", "subvolumegroup", "getpath"}}, commands)
+
+	//
+	// TEST 4: changing the pinning policy resets the previous policy
+	//
+	commands = [][]string{}
+	settings = []string{}
+	random := 0.5
+	group.Spec.Pinning = cephv1.SubVolumeGroupPinningSpec{Random: &random}
+	err = cl.Update(context.TODO(), group)
+	assert.NoError(t, err)
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, []string{"export=-1", "random=0.5"}, settings)
+
+	//
+	// TEST 5: the quota and the pinning removed from the spec are removed from the group
+	//
+	commands = [][]string{}
+	settings = []string{}
+	group = &cephv1.CephFilesystemSubVolumeGroup{}
+	err = cl.Get(context.TODO(), req.NamespacedName, group)
+	assert.NoError(t, err)
+	group.Spec.Quota = nil
+	group.Spec.Pinning = cephv1.SubVolumeGroupPinningSpec{}
+	err = cl.Update(context.TODO(), group)
+	assert.NoError(t, err)
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, []string{"inf", "random=0"}, settings)
+	group = &cephv1.CephFilesystemSubVolumeGroup{}
+	err = cl.Get(context.TODO(), req.NamespacedName, group)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{pathKey: "/volumes/csi"}, group.Status.Info)
+
+	//
+	// TEST 6: the quota is refused by ceph versions without the resize command
+	//
+	commands = [][]string{}
+	versions = `{"mon":{"ceph version 15.2.8 (bdf3eebcd22d7d0b3dd4d5501bee5bac354d5b55) octopus (stable)":3}}`
+	group.Spec.Quota = &quota
+	err = cl.Update(context.TODO(), group)
+	assert.NoError(t, err)
+	_, err = r.Reconcile(req)
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(commands))
+	group = &cephv1.CephFilesystemSubVolumeGroup{}
+	err = cl.Get(context.TODO(), req.NamespacedName, group)
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.ConditionFailure, group.Status.Phase)
+	assert.Contains(t, group.Status.Info[messageKey], "require ceph pacific")
+
+	//
+	// TEST 7: the deletion is blocked while the group has subvolumes
+	//
+	commands = [][]string{}
+	subVolumes = `[{"name":"csi-vol-1"}]`
+	now := metav1.Now()
+	group.DeletionTimestamp = &now
+	err = cl.Update(context.TODO(), group)
+	assert.NoError(t, err)
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	err = cl.Get(context.TODO(), req.NamespacedName, group)
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.ConditionDeleting, group.Status.Phase)
+	assert.Equal(t, "csi-vol-1", group.Status.Info[pendingSubVolumeKey])
+	assert.Equal(t, 1, len(group.Finalizers))
+
+	//
+	// TEST 8: the group is deleted once the subvolumes are gone
+	//
+	commands = [][]string{}
+	subVolumes = "[]"
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, [][]string{{"fs", "subvolume", "ls"}, {"fs", "subvolumegroup", "rm"}}, commands)
+}
@@ -49,6 +49,7 @@ type ObjectBucket struct {
 
 type rgwBucketStats struct {
 	Bucket string `json:"bucket"`
+	Owner  string `json:"owner"`
 	Usage  map[string]struct {
 		Size            uint64 `json:"size"`
 		NumberOfObjects uint64 `json:"num_objects"`
@@ -231,3 +232,45 @@ func DeleteObjectBucket(c *Context, bucketName string, purge bool) (int, error)
 
 	return RGWErrorUnknown, errors.Wrap(err, "failed to delete bucket")
 }
+
+// SetQuotaBucketObjectMax allows to set maximum limit on objects for a bucket
+func SetQuotaBucketObjectMax(c *Context, bucket string, maxobjects string) (string, error) {
+	logger.Debugf("Setting bucket %q max objects to %s", bucket, maxobjects)
+	args := []string{"--quota-scope", "bucket", "--max-objects", maxobjects}
+	result, err := setBucketQuota(c, bucket, args)
+	if err != nil {
+		err = errors.Wrap(err, "failed setting bucket object max")
+	}
+	return result, err
+}
+
+// SetQuotaBucketMaxSize allows to set maximum size for a bucket
+func SetQuotaBucketMaxSize(c *Context, bucket string, maxsize string) (string, error) {
+	logger.Debugf("Setting bucket %q max size to %s", bucket, maxsize)
+	args := []string{"--quota-scope", "bucket", "--max-size", maxsize}
+	result, err := setBucketQuota(c, bucket, args)
+	if err != nil {
+		err = errors.Wrap(err, "failed setting bucket max size")
+	}
+	return result, err
+}
+
+func setBucketQuota(c *Context, bucket string, args []string) (string, error) {
+	args = append([]string{"quota", "set", "--bucket", bucket}, args...)
+	result, err := runAdminCommand(c, args...)
+	if err != nil {
+		err = errors.Wrap(err, "failed to set quota for bucket")
+	}
+	return result, err
+}
+
+// EnableBucketQuota will allows to enable quota defined for a bucket
+func EnableBucketQuota(c *Context, bucket string) (string, error) {
+	logger.Debugf("Enabling bucket quota for %q", bucket)
+	args := []string{"quota", "enable", "--quota-scope", "bucket", "--bucket", bucket}
+	result, err := runAdminCommand(c, args...)
+	if err != nil {
+		err = errors.Wrap(err, "failed to enable quota for the bucket")
+	}
+	return result, err
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	"github.com/kube-object-storage/lib-bucket-provisioner/pkg/client/clientset/versioned"
+	"github.com/kube-object-storage/lib-bucket-provisioner/pkg/client/informers/externalversions"
+	"github.com/pkg/errors"
+	cephObject "github.com/rook/rook/pkg/operator/ceph/object"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// appliedConfigAnnotation records on the object bucket the additional config of the claim applied to the bucket
+	appliedConfigAnnotation = "ceph.rook.io/applied-additional-config"
+	// claimResyncPeriod is the period of the reconcile of all the claims, it retries the failed updates
+	claimResyncPeriod = 10 * time.Minute
+)
+
+// StartClaimWatcher watches the object bucket claims to apply the changes of their additional config
+// to the bound buckets, the bucket library only provisions the claims and ignores their later updates
+func (p *Provisioner) StartClaimWatcher(cfg *rest.Config, stopCh <-chan struct{}) error {
+	clientset, err := versioned.NewForConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to create object bucket clientset")
+	}
+
+	reconcile := func(obj interface{}) {
+		obc, ok := obj.(*bktv1alpha1.ObjectBucketClaim)
+		if !ok || !claimBound(obc) {
+			return
+		}
+		// work on a copy since the provisioner keeps the state of the bucket being reconciled
+		provisioner := *p
+		if err := provisioner.reconcileClaim(clientset, obc); err != nil {
+			logger.Errorf("failed to update the bucket of OBC %q in namespace %q. %v", obc.Name, obc.Namespace, err)
+		}
+	}
+
+	// the claims are reconciled when added, e.g. after an operator restart, when updated and on each resync
+	factory := externalversions.NewSharedInformerFactory(clientset, claimResyncPeriod)
+	factory.Objectbucket().V1alpha1().ObjectBucketClaims().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: reconcile,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			reconcile(newObj)
+		},
+	})
+
+	logger.Info("start watching object bucket claims")
+	factory.Start(stopCh)
+	return nil
+}
+
+// claimBound returns whether the claim is bound to a bucket and not being deleted
+func claimBound(obc *bktv1alpha1.ObjectBucketClaim) bool {
+	return obc.DeletionTimestamp == nil && obc.Status.Phase == bktv1alpha1.ObjectBucketClaimStatusPhaseBound
+}
+
+// appliedConfig returns the additional config last applied to the bucket, which is the config
+// the bucket was provisioned with until the claim is updated
+func appliedConfig(ob *bktv1alpha1.ObjectBucket) (map[string]string, error) {
+	if value, ok := ob.Annotations[appliedConfigAnnotation]; ok {
+		var config map[string]string
+		if err := json.Unmarshal([]byte(value), &config); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal annotation %q of object bucket %q", appliedConfigAnnotation, ob.Name)
+		}
+		return config, nil
+	}
+	if ob.Spec.Connection != nil && ob.Spec.Endpoint != nil {
+		return ob.Spec.Endpoint.AdditionalConfigData, nil
+	}
+	return nil, nil
+}
+
+// configEqual returns whether two additional configs are the same, a missing config being empty
+func configEqual(config, other map[string]string) bool {
+	if len(config) == 0 && len(other) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(config, other)
+}
+
+// reconcileClaim applies the additional config of a bound claim to its user and bucket if it differs
+// from the config applied last
+func (p *Provisioner) reconcileClaim(clientset versioned.Interface, obc *bktv1alpha1.ObjectBucketClaim) error {
+	sc, err := p.getStorageClassWithBackoff(obc.Spec.StorageClassName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get storage class %q", obc.Spec.StorageClassName)
+	}
+	// the claims of the other clusters are handled by their own provisioner
+	if sc.Provisioner != cephObject.GetObjectBucketProvisioner(p.context, p.clusterInfo.Namespace) {
+		return nil
+	}
+
+	ob, err := clientset.ObjectbucketV1alpha1().ObjectBuckets().Get(obc.Spec.ObjectBucketName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get object bucket %q", obc.Spec.ObjectBucketName)
+	}
+	applied, err := appliedConfig(ob)
+	if err != nil {
+		logger.Warningf("applying the whole additional config of OBC %q. %v", obc.Name, err)
+	}
+	if err == nil && configEqual(applied, obc.Spec.AdditionalConfig) {
+		return nil
+	}
+	logger.Infof("updating the bucket settings of OBC %q in namespace %q", obc.Name, obc.Namespace)
+
+	settings, err := newBucketSettings(obc.Spec.AdditionalConfig)
+	if err != nil {
+		return errors.Wrap(err, "invalid additional config")
+	}
+	previousSettings, err := newBucketSettings(applied)
+	if err != nil {
+		// the invalid config was never applied
+		previousSettings = &bucketSettings{}
+	}
+
+	err = p.initializeDeleteOrRevoke(ob)
+	if err != nil {
+		return err
+	}
+
+	err = p.setAdditionalSettings(settings, previousSettings)
+	if err != nil {
+		return err
+	}
+
+	if _, isStatic := isStaticBucket(sc); isStatic {
+		if settings.hasBucketSettings() {
+			logger.Warningf("ignoring the bucket settings of OBC %q, they only apply to the buckets provisioned for a claim", obc.Name)
+		}
+	} else {
+		objectUser, _, err := cephObject.GetUser(p.objectContext, p.cephUserName)
+		if err != nil {
+			return errors.Wrapf(err, "could not get user %q", p.cephUserName)
+		}
+		s3svc, err := cephObject.NewS3Agent(*objectUser.AccessKey, *objectUser.SecretKey, p.getObjectStoreEndpoint(), true)
+		if err != nil {
+			return err
+		}
+		err = p.setBucketSettings(s3svc, settings, previousSettings)
+		if err != nil {
+			return err
+		}
+	}
+
+	return recordAppliedConfig(clientset, ob, obc.Spec.AdditionalConfig)
+}
+
+// recordAppliedConfig stores the applied additional config in an annotation of the object bucket
+func recordAppliedConfig(clientset versioned.Interface, ob *bktv1alpha1.ObjectBucket, config map[string]string) error {
+	value, err := json.Marshal(config)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal additional config")
+	}
+	if ob.Annotations == nil {
+		ob.Annotations = map[string]string{}
+	}
+	ob.Annotations[appliedConfigAnnotation] = string(value)
+	if _, err := clientset.ObjectbucketV1alpha1().ObjectBuckets().Update(ob); err != nil {
+		return errors.Wrapf(err, "failed to record the applied additional config on object bucket %q", ob.Name)
+	}
+	return nil
+}
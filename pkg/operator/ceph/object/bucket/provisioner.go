@@ -39,6 +39,8 @@ type Provisioner struct {
 	storeDomainName string
 	storePort       int32
 	region          string
+	// placement target of the provisioned buckets, the default placement if empty
+	placement string
 	// access keys for acct for the bucket *owner*
 	cephUserName         string
 	accessKeyID          string
@@ -66,6 +68,11 @@ func (p Provisioner) Provision(options *apibkt.BucketOptions) (*bktv1alpha1.Obje
 	}
 	logger.Infof("Provision: creating bucket %q for OBC %q", p.bucketName, options.ObjectBucketClaim.Name)
 
+	settings, err := newBucketSettings(p.additionalConfigData)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid additional config of OBC %q", options.ObjectBucketClaim.Name)
+	}
+
 	// dynamically create a new ceph user
 	p.accessKeyID, p.secretAccessKey, err = p.createCephUser("")
 	if err != nil {
@@ -77,8 +84,8 @@ func (p Provisioner) Provision(options *apibkt.BucketOptions) (*bktv1alpha1.Obje
 		return nil, err
 	}
 
-	// create the bucket
-	err = s3svc.CreateBucket(p.bucketName)
+	// create the bucket, object lock can only be enabled at creation
+	err = s3svc.CreateBucketInPlacement(p.bucketName, p.placement, settings.objectLock)
 	if err != nil {
 		err = errors.Wrapf(err, "error creating bucket %q", p.bucketName)
 		logger.Errorf(err.Error())
@@ -93,8 +100,14 @@ func (p Provisioner) Provision(options *apibkt.BucketOptions) (*bktv1alpha1.Obje
 	}
 	logger.Infof("set user %q bucket max to %d", p.cephUserName, maxBuckets)
 
-	// setting quota limit if it is enabled
-	err = p.setAdditionalSettings(options)
+	// setting quota limit and bucket settings if they are enabled
+	err = p.setAdditionalSettings(settings, &bucketSettings{})
+	if err != nil {
+		p.deleteOBCResourceLogError(p.bucketName)
+		return nil, err
+	}
+	// the bucket was just created with or without object lock
+	err = p.setBucketSettings(s3svc, settings, &bucketSettings{objectLock: settings.objectLock})
 	if err != nil {
 		p.deleteOBCResourceLogError(p.bucketName)
 		return nil, err
@@ -114,6 +127,14 @@ func (p Provisioner) Grant(options *apibkt.BucketOptions) (*bktv1alpha1.ObjectBu
 	}
 	logger.Infof("Grant: allowing access to bucket %q for OBC %q", p.bucketName, options.ObjectBucketClaim.Name)
 
+	settings, err := newBucketSettings(p.additionalConfigData)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid additional config of OBC %q", options.ObjectBucketClaim.Name)
+	}
+	if settings.hasBucketSettings() {
+		logger.Warningf("ignoring the bucket settings of OBC %q, they only apply to the buckets provisioned for a claim", options.ObjectBucketClaim.Name)
+	}
+
 	// check and make sure the bucket exists
 	logger.Infof("Checking for existing bucket %q", p.bucketName)
 	if exists, err := p.bucketExists(p.bucketName); !exists {
@@ -182,7 +203,7 @@ func (p Provisioner) Grant(options *apibkt.BucketOptions) (*bktv1alpha1.ObjectBu
 	}
 
 	// setting quota limit if it is enabled
-	err = p.setAdditionalSettings(options)
+	err = p.setAdditionalSettings(settings, &bucketSettings{})
 	if err != nil {
 		p.deleteOBCResourceLogError("")
 		return nil, err
@@ -320,6 +341,7 @@ func (p *Provisioner) initializeCreateOrGrant(options *apibkt.BucketOptions) err
 
 	p.setObjectStoreName(sc)
 	p.setRegion(sc)
+	p.setPlacement(sc)
 	p.setAdditionalConfigData(obc.Spec.AdditionalConfig)
 	p.setEndpoint(sc)
 	err = p.setObjectContext()
@@ -471,6 +493,11 @@ func (p *Provisioner) setRegion(sc *storagev1.StorageClass) {
 	p.region = sc.Parameters[key]
 }
 
+func (p *Provisioner) setPlacement(sc *storagev1.StorageClass) {
+	const key = "placement"
+	p.placement = sc.Parameters[key]
+}
+
 func (p Provisioner) getObjectStoreEndpoint() string {
 	return fmt.Sprintf("%s:%d", p.storeDomainName, p.storePort)
 }
@@ -506,10 +533,11 @@ func (p *Provisioner) deleteOBCResourceLogError(bucketname string) {
 	}
 }
 
-// Check for additional options mentioned in OBC and set them accordingly
-func (p Provisioner) setAdditionalSettings(options *apibkt.BucketOptions) error {
-	maxObjects := MaxObjectQuota(options)
-	maxSize := MaxSizeQuota(options)
+// setAdditionalSettings sets the quotas of the user generated for the claim, the settings previously
+// applied are given so that the quotas removed from the claim are reset
+func (p Provisioner) setAdditionalSettings(settings, previous *bucketSettings) error {
+	maxObjects := quotaValue(settings.maxObjects, previous.maxObjects)
+	maxSize := quotaValue(settings.maxSize, previous.maxSize)
 	if maxObjects == "" && maxSize == "" {
 		return nil
 	}
@@ -534,3 +562,54 @@ func (p Provisioner) setAdditionalSettings(options *apibkt.BucketOptions) error
 
 	return nil
 }
+
+// setBucketSettings sets the quotas, lifecycle rules, versioning and object lock retention of the bucket
+// provisioned for the claim, the settings previously applied are given so that the removed settings are reset
+func (p Provisioner) setBucketSettings(s3svc *cephObject.S3Agent, settings, previous *bucketSettings) error {
+	if settings.objectLock != previous.objectLock {
+		return errors.Errorf("object lock of bucket %q can only be set when the bucket is provisioned", p.bucketName)
+	}
+
+	bucketMaxObjects := quotaValue(settings.bucketMaxObjects, previous.bucketMaxObjects)
+	bucketMaxSize := quotaValue(settings.bucketMaxSize, previous.bucketMaxSize)
+	if bucketMaxObjects != "" {
+		if _, err := cephObject.SetQuotaBucketObjectMax(p.objectContext, p.bucketName, bucketMaxObjects); err != nil {
+			return err
+		}
+	}
+	if bucketMaxSize != "" {
+		if _, err := cephObject.SetQuotaBucketMaxSize(p.objectContext, p.bucketName, bucketMaxSize); err != nil {
+			return err
+		}
+	}
+	if bucketMaxObjects != "" || bucketMaxSize != "" {
+		if _, err := cephObject.EnableBucketQuota(p.objectContext, p.bucketName); err != nil {
+			return err
+		}
+	}
+
+	if rules := settings.lifecycleRules(); rules != nil {
+		if err := s3svc.PutBucketLifecycle(p.bucketName, rules); err != nil {
+			return err
+		}
+	} else if previous.lifecycleRules() != nil {
+		if err := s3svc.DeleteBucketLifecycle(p.bucketName); err != nil {
+			return err
+		}
+	}
+
+	// versioning cannot be disabled once enabled, only suspended, so it is left untouched if removed from the claim
+	if settings.versioning != "" && settings.versioning != previous.versioning {
+		if err := s3svc.PutBucketVersioning(p.bucketName, settings.versioning); err != nil {
+			return err
+		}
+	}
+
+	if settings.objectLock && (settings.objectLockMode != previous.objectLockMode || settings.objectLockRetentionDays != previous.objectLockRetentionDays) {
+		if err := s3svc.PutObjectLockRetention(p.bucketName, settings.objectLockMode, settings.objectLockRetentionDays); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -18,14 +18,20 @@ package bucket
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	bktclient "github.com/kube-object-storage/lib-bucket-provisioner/pkg/client/clientset/versioned/fake"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/operator/ceph/object"
 	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -102,3 +108,183 @@ func TestPopulateDomainAndPort(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "rook-ceph-rgw-test-store.ns.svc", p.storeDomainName)
 }
+
+func TestSetBucketSettings(t *testing.T) {
+	// fake rgw recording the bucket configuration requests
+	requests := []string{}
+	rgw := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/my-bucket", req.URL.Path)
+		requests = append(requests, req.Method+" "+req.URL.RawQuery)
+	}))
+	defer rgw.Close()
+	s3svc, err := object.NewS3Agent("access", "secret", strings.TrimPrefix(rgw.URL, "http://"), false)
+	assert.NoError(t, err)
+
+	// radosgw-admin commands
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			commands = append(commands, strings.Join(args[:6], " "))
+			return "", nil
+		},
+	}
+	clusterInfo := client.AdminClusterInfo("ns")
+	p := NewProvisioner(&clusterd.Context{Executor: executor}, clusterInfo)
+	p.objectContext = object.NewContext(p.context, clusterInfo, "")
+	p.bucketName = "my-bucket"
+
+	// the bucket is provisioned with quotas, a lifecycle rule, versioning and object lock
+	settings, err := newBucketSettings(map[string]string{
+		"bucketMaxObjects":        "500",
+		"bucketMaxSize":           "1G",
+		"expirationDays":          "30",
+		"versioning":              "Enabled",
+		"objectLock":              "true",
+		"objectLockMode":          "GOVERNANCE",
+		"objectLockRetentionDays": "1",
+	})
+	assert.NoError(t, err)
+	err = p.setBucketSettings(s3svc, settings, &bucketSettings{objectLock: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"quota set --bucket my-bucket --quota-scope bucket",
+		"quota set --bucket my-bucket --quota-scope bucket",
+		"quota enable --quota-scope bucket --bucket my-bucket",
+	}, commands)
+	assert.Equal(t, []string{"PUT lifecycle=", "PUT versioning=", "PUT object-lock="}, requests)
+
+	// the claim drops the quotas and the lifecycle rule, the versioning is unchanged
+	commands = []string{}
+	requests = []string{}
+	previous := settings
+	settings, err = newBucketSettings(map[string]string{
+		"versioning":              "Enabled",
+		"objectLock":              "true",
+		"objectLockMode":          "GOVERNANCE",
+		"objectLockRetentionDays": "1",
+	})
+	assert.NoError(t, err)
+	err = p.setBucketSettings(s3svc, settings, previous)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(commands))
+	assert.Equal(t, []string{"DELETE lifecycle="}, requests)
+
+	// object lock cannot be changed once the bucket is provisioned
+	err = p.setBucketSettings(s3svc, &bucketSettings{}, settings)
+	assert.Error(t, err)
+}
+
+func TestSetAdditionalSettings(t *testing.T) {
+	commands := [][]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			commands = append(commands, args)
+			return "", nil
+		},
+	}
+	clusterInfo := client.AdminClusterInfo("ns")
+	p := NewProvisioner(&clusterd.Context{Executor: executor}, clusterInfo)
+	p.objectContext = object.NewContext(p.context, clusterInfo, "")
+	p.cephUserName = "ceph-user-12345678"
+
+	// no quota
+	err := p.setAdditionalSettings(&bucketSettings{}, &bucketSettings{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(commands))
+
+	// the max objects quota is set
+	err = p.setAdditionalSettings(&bucketSettings{maxObjects: "1000"}, &bucketSettings{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(commands))
+	assert.Equal(t, []string{"quota", "set", "--uid", "ceph-user-12345678", "--quota-scope", "user", "--max-objects", "1000"}, commands[0][:8])
+	assert.Equal(t, []string{"quota", "enable"}, commands[1][:2])
+
+	// the max objects quota is removed
+	commands = [][]string{}
+	err = p.setAdditionalSettings(&bucketSettings{}, &bucketSettings{maxObjects: "1000"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(commands))
+	assert.Equal(t, []string{"--max-objects", "-1"}, commands[0][6:8])
+}
+
+func TestClaimBound(t *testing.T) {
+	obc := &bktv1alpha1.ObjectBucketClaim{}
+	assert.False(t, claimBound(obc))
+
+	obc.Status.Phase = bktv1alpha1.ObjectBucketClaimStatusPhaseBound
+	assert.True(t, claimBound(obc))
+
+	// being deleted
+	obc.DeletionTimestamp = &metav1.Time{}
+	assert.False(t, claimBound(obc))
+}
+
+func TestAppliedConfig(t *testing.T) {
+	// never updated, the config the bucket was provisioned with
+	ob := &bktv1alpha1.ObjectBucket{
+		Spec: bktv1alpha1.ObjectBucketSpec{
+			Connection: &bktv1alpha1.Connection{
+				Endpoint: &bktv1alpha1.Endpoint{AdditionalConfigData: map[string]string{"maxObjects": "1000"}},
+			},
+		},
+	}
+	config, err := appliedConfig(ob)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"maxObjects": "1000"}, config)
+	assert.False(t, configEqual(config, map[string]string{"maxObjects": "2000"}))
+
+	// the annotation takes precedence
+	ob.Annotations = map[string]string{appliedConfigAnnotation: `{"expirationDays":"7"}`}
+	config, err = appliedConfig(ob)
+	assert.NoError(t, err)
+	assert.True(t, configEqual(config, map[string]string{"expirationDays": "7"}))
+
+	ob.Annotations[appliedConfigAnnotation] = "null"
+	config, err = appliedConfig(ob)
+	assert.NoError(t, err)
+	assert.True(t, configEqual(config, map[string]string{}))
+
+	ob.Annotations[appliedConfigAnnotation] = "{"
+	_, err = appliedConfig(ob)
+	assert.Error(t, err)
+}
+
+func TestReconcileClaim(t *testing.T) {
+	namespace := "rook-ceph"
+	c := &clusterd.Context{Clientset: test.New(t, 1)}
+	sc := &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "bucket-class"},
+		Provisioner: object.GetObjectBucketProvisioner(c, namespace),
+	}
+	_, err := c.Clientset.StorageV1().StorageClasses().Create(sc)
+	assert.NoError(t, err)
+
+	ob := &bktv1alpha1.ObjectBucket{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "obc-default-bucket",
+			Annotations: map[string]string{appliedConfigAnnotation: `{"maxObjects":"1000"}`},
+		},
+	}
+	clientset := bktclient.NewSimpleClientset(ob)
+	obc := &bktv1alpha1.ObjectBucketClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "bucket", Namespace: "default"},
+		Spec: bktv1alpha1.ObjectBucketClaimSpec{
+			StorageClassName: sc.Name,
+			ObjectBucketName: ob.Name,
+			AdditionalConfig: map[string]string{"maxObjects": "1000"},
+		},
+	}
+	p := &Provisioner{context: c, clusterInfo: client.AdminClusterInfo(namespace)}
+
+	// the config was already applied, the object bucket is only read
+	err = p.reconcileClaim(clientset, obc)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(clientset.Actions()))
+
+	// the applied config is recorded
+	err = recordAppliedConfig(clientset, ob.DeepCopy(), map[string]string{"maxObjects": "2000"})
+	assert.NoError(t, err)
+	updated, err := clientset.ObjectbucketV1alpha1().ObjectBuckets().Get(ob.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"maxObjects":"2000"}`, updated.Annotations[appliedConfigAnnotation])
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+)
+
+// The keys of the OBC additional config
+const (
+	// quotas of the user generated for the claim
+	maxObjectsKey = "maxObjects"
+	maxSizeKey    = "maxSize"
+	// quotas of the bucket
+	bucketMaxObjectsKey = "bucketMaxObjects"
+	bucketMaxSizeKey    = "bucketMaxSize"
+	// lifecycle rule of the bucket
+	expirationDaysKey         = "expirationDays"
+	transitionDaysKey         = "transitionDays"
+	transitionStorageClassKey = "transitionStorageClass"
+	lifecyclePrefixKey        = "lifecyclePrefix"
+	// versioning and object lock of the bucket
+	versioningKey              = "versioning"
+	objectLockKey              = "objectLock"
+	objectLockModeKey          = "objectLockMode"
+	objectLockRetentionDaysKey = "objectLockRetentionDays"
+
+	// the ID of the lifecycle rule managed from the claim
+	lifecycleRuleID = "rook-obc-lifecycle"
+	// the quota value removing a limit
+	noQuota = "-1"
+)
+
+// bucketSettings are the settings requested in the additional config of an OBC
+type bucketSettings struct {
+	maxObjects              string
+	maxSize                 string
+	bucketMaxObjects        string
+	bucketMaxSize           string
+	expirationDays          int64
+	transitionDays          int64
+	transitionStorageClass  string
+	lifecyclePrefix         string
+	versioning              string
+	objectLock              bool
+	objectLockMode          string
+	objectLockRetentionDays int64
+}
+
+// newBucketSettings parses and validates the additional config of an OBC
+func newBucketSettings(config map[string]string) (*bucketSettings, error) {
+	var err error
+	settings := &bucketSettings{
+		maxObjects:             config[maxObjectsKey],
+		maxSize:                config[maxSizeKey],
+		bucketMaxObjects:       config[bucketMaxObjectsKey],
+		bucketMaxSize:          config[bucketMaxSizeKey],
+		transitionStorageClass: config[transitionStorageClassKey],
+		lifecyclePrefix:        config[lifecyclePrefixKey],
+	}
+
+	if settings.expirationDays, err = parseDays(config, expirationDaysKey); err != nil {
+		return nil, err
+	}
+	if settings.transitionDays, err = parseDays(config, transitionDaysKey); err != nil {
+		return nil, err
+	}
+	if (settings.transitionDays == 0) != (settings.transitionStorageClass == "") {
+		return nil, errors.Errorf("%q and %q must be set together", transitionDaysKey, transitionStorageClassKey)
+	}
+	if settings.expirationDays != 0 && settings.transitionDays != 0 && settings.expirationDays <= settings.transitionDays {
+		return nil, errors.Errorf("%q must be greater than %q", expirationDaysKey, transitionDaysKey)
+	}
+
+	if value, ok := config[versioningKey]; ok {
+		switch strings.ToLower(value) {
+		case strings.ToLower(s3.BucketVersioningStatusEnabled):
+			settings.versioning = s3.BucketVersioningStatusEnabled
+		case strings.ToLower(s3.BucketVersioningStatusSuspended):
+			settings.versioning = s3.BucketVersioningStatusSuspended
+		default:
+			return nil, errors.Errorf("invalid %q value %q, must be %q or %q", versioningKey, value, s3.BucketVersioningStatusEnabled, s3.BucketVersioningStatusSuspended)
+		}
+	}
+
+	if value, ok := config[objectLockKey]; ok {
+		if settings.objectLock, err = strconv.ParseBool(value); err != nil {
+			return nil, errors.Wrapf(err, "invalid %q value %q", objectLockKey, value)
+		}
+	}
+	if settings.objectLockRetentionDays, err = parseDays(config, objectLockRetentionDaysKey); err != nil {
+		return nil, err
+	}
+	if value, ok := config[objectLockModeKey]; ok {
+		settings.objectLockMode = strings.ToUpper(value)
+		if settings.objectLockMode != s3.ObjectLockRetentionModeGovernance && settings.objectLockMode != s3.ObjectLockRetentionModeCompliance {
+			return nil, errors.Errorf("invalid %q value %q, must be %q or %q", objectLockModeKey, value, s3.ObjectLockRetentionModeGovernance, s3.ObjectLockRetentionModeCompliance)
+		}
+	}
+	if (settings.objectLockMode == "") != (settings.objectLockRetentionDays == 0) {
+		return nil, errors.Errorf("%q and %q must be set together", objectLockModeKey, objectLockRetentionDaysKey)
+	}
+	if settings.objectLockMode != "" && !settings.objectLock {
+		return nil, errors.Errorf("%q requires %q", objectLockModeKey, objectLockKey)
+	}
+	// object lock relies on the versioning of the bucket
+	if settings.objectLock && settings.versioning == s3.BucketVersioningStatusSuspended {
+		return nil, errors.Errorf("%q cannot be suspended when %q is enabled", versioningKey, objectLockKey)
+	}
+
+	return settings, nil
+}
+
+func parseDays(config map[string]string, key string) (int64, error) {
+	value, ok := config[key]
+	if !ok {
+		return 0, nil
+	}
+	days, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || days <= 0 {
+		return 0, errors.Errorf("invalid %q value %q, must be a positive number of days", key, value)
+	}
+	return days, nil
+}
+
+// hasBucketSettings returns whether settings of the bucket itself are requested, besides the user quotas
+func (s *bucketSettings) hasBucketSettings() bool {
+	return s.bucketMaxObjects != "" || s.bucketMaxSize != "" || s.lifecycleRules() != nil || s.versioning != "" || s.objectLock
+}
+
+// lifecycleRules returns the lifecycle rules of the bucket, nil if no expiration nor transition is requested
+func (s *bucketSettings) lifecycleRules() []*s3.LifecycleRule {
+	if s.expirationDays == 0 && s.transitionDays == 0 {
+		return nil
+	}
+
+	rule := &s3.LifecycleRule{
+		ID:     aws.String(lifecycleRuleID),
+		Status: aws.String(s3.ExpirationStatusEnabled),
+		Filter: &s3.LifecycleRuleFilter{Prefix: aws.String(s.lifecyclePrefix)},
+	}
+	if s.expirationDays != 0 {
+		rule.Expiration = &s3.LifecycleExpiration{Days: aws.Int64(s.expirationDays)}
+	}
+	if s.transitionDays != 0 {
+		rule.Transitions = []*s3.Transition{{
+			Days:         aws.Int64(s.transitionDays),
+			StorageClass: aws.String(s.transitionStorageClass),
+		}}
+	}
+	return []*s3.LifecycleRule{rule}
+}
+
+// quotaValue returns the quota to set, removing the limit when it was dropped from the claim
+func quotaValue(value, previous string) string {
+	if value == "" && previous != "" {
+		return noQuota
+	}
+	return value
+}
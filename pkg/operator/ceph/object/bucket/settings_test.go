@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBucketSettings(t *testing.T) {
+	// no additional config
+	settings, err := newBucketSettings(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &bucketSettings{}, settings)
+	assert.False(t, settings.hasBucketSettings())
+
+	// user quotas only
+	settings, err = newBucketSettings(map[string]string{"maxObjects": "1000", "maxSize": "2G"})
+	assert.NoError(t, err)
+	assert.Equal(t, "1000", settings.maxObjects)
+	assert.Equal(t, "2G", settings.maxSize)
+	assert.False(t, settings.hasBucketSettings())
+
+	// all the bucket settings
+	settings, err = newBucketSettings(map[string]string{
+		"bucketMaxObjects":        "500",
+		"bucketMaxSize":           "1G",
+		"expirationDays":          "30",
+		"transitionDays":          "7",
+		"transitionStorageClass":  "COLD",
+		"lifecyclePrefix":         "logs/",
+		"versioning":              "enabled",
+		"objectLock":              "true",
+		"objectLockMode":          "governance",
+		"objectLockRetentionDays": "1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &bucketSettings{
+		bucketMaxObjects:        "500",
+		bucketMaxSize:           "1G",
+		expirationDays:          30,
+		transitionDays:          7,
+		transitionStorageClass:  "COLD",
+		lifecyclePrefix:         "logs/",
+		versioning:              "Enabled",
+		objectLock:              true,
+		objectLockMode:          "GOVERNANCE",
+		objectLockRetentionDays: 1,
+	}, settings)
+	assert.True(t, settings.hasBucketSettings())
+
+	invalid := []map[string]string{
+		{"expirationDays": "0"},
+		{"expirationDays": "thirty"},
+		{"transitionDays": "7"},
+		{"transitionStorageClass": "COLD"},
+		{"expirationDays": "7", "transitionDays": "7", "transitionStorageClass": "COLD"},
+		{"versioning": "disabled"},
+		{"objectLock": "yes please"},
+		{"objectLock": "true", "objectLockMode": "LEGAL"},
+		{"objectLock": "true", "objectLockMode": "COMPLIANCE"},
+		{"objectLock": "true", "objectLockRetentionDays": "1"},
+		{"objectLockMode": "COMPLIANCE", "objectLockRetentionDays": "1"},
+		{"objectLock": "true", "versioning": "Suspended"},
+	}
+	for _, config := range invalid {
+		_, err = newBucketSettings(config)
+		assert.Error(t, err, config)
+	}
+}
+
+func TestLifecycleRules(t *testing.T) {
+	settings := &bucketSettings{}
+	assert.Nil(t, settings.lifecycleRules())
+
+	settings.expirationDays = 30
+	rules := settings.lifecycleRules()
+	assert.Equal(t, 1, len(rules))
+	assert.Equal(t, lifecycleRuleID, aws.StringValue(rules[0].ID))
+	assert.Equal(t, "Enabled", aws.StringValue(rules[0].Status))
+	assert.Equal(t, "", aws.StringValue(rules[0].Filter.Prefix))
+	assert.Equal(t, int64(30), aws.Int64Value(rules[0].Expiration.Days))
+	assert.Nil(t, rules[0].Transitions)
+
+	settings.transitionDays = 7
+	settings.transitionStorageClass = "COLD"
+	settings.lifecyclePrefix = "logs/"
+	rules = settings.lifecycleRules()
+	assert.Equal(t, "logs/", aws.StringValue(rules[0].Filter.Prefix))
+	assert.Equal(t, 1, len(rules[0].Transitions))
+	assert.Equal(t, int64(7), aws.Int64Value(rules[0].Transitions[0].Days))
+	assert.Equal(t, "COLD", aws.StringValue(rules[0].Transitions[0].StorageClass))
+}
+
+func TestQuotaValue(t *testing.T) {
+	assert.Equal(t, "", quotaValue("", ""))
+	assert.Equal(t, "10", quotaValue("10", ""))
+	assert.Equal(t, "10", quotaValue("10", "5"))
+	assert.Equal(t, "-1", quotaValue("", "5"))
+}
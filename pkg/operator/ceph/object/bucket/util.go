@@ -117,9 +117,9 @@ func randomString(n int) string {
 }
 
 func MaxObjectQuota(options *apibkt.BucketOptions) string {
-	return options.ObjectBucketClaim.Spec.AdditionalConfig["maxObjects"]
+	return options.ObjectBucketClaim.Spec.AdditionalConfig[maxObjectsKey]
 }
 
 func MaxSizeQuota(options *apibkt.BucketOptions) string {
-	return options.ObjectBucketClaim.Spec.AdditionalConfig["maxSize"]
+	return options.ObjectBucketClaim.Spec.AdditionalConfig[maxSizeKey]
 }
@@ -25,6 +25,8 @@ import (
 	"github.com/pkg/errors"
 	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
 	"github.com/rook/rook/pkg/operator/ceph/config/keyring"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -40,6 +42,22 @@ caps osd = "allow rwx"
 	certKeyName               = "cert"
 	certFilename              = "rgw-cert.pem"
 	rgwPortInternalPort int32 = 8080
+
+	// the keys of the secret of the Keystone admin user
+	keystoneUsernameKey  = "OS_USERNAME"
+	keystonePasswordKey  = "OS_PASSWORD"
+	keystoneProjectKey   = "OS_PROJECT_NAME"
+	keystoneDomainKey    = "OS_USER_DOMAIN_NAME"
+	keystoneVolumeName   = "rook-ceph-rgw-keystone"
+	keystoneDir          = "/etc/ceph/keystone"
+	keystonePasswordFile = "password"
+	// the keys of the secret of the LDAP bind user
+	ldapBindDNKey    = "binddn"
+	ldapPasswordKey  = "password"
+	ldapVolumeName   = "rook-ceph-rgw-ldap"
+	ldapDir          = "/etc/ceph/ldap"
+	ldapPasswordFile = "password"
+	ldapDNAttribute  = "uid"
 )
 
 var (
@@ -71,6 +89,141 @@ func (c *clusterConfig) portString() string {
 	return portString
 }
 
+// authFlags returns the rgw flags authenticating the users against the external identity services, the passwords are
+// read from files and the user names and the STS key are set in the mon configuration database so they are not on
+// the command line
+func (c *clusterConfig) authFlags() []string {
+	flags := []string{}
+	if keystone := c.store.Spec.Auth.Keystone; keystone != nil {
+		flags = append(flags,
+			cephconfig.NewFlag("rgw keystone url", keystone.URL),
+			cephconfig.NewFlag("rgw keystone api version", "3"),
+			cephconfig.NewFlag("rgw keystone admin password path", path.Join(keystoneDir, keystonePasswordFile)),
+			cephconfig.NewFlag("rgw keystone accepted roles", strings.Join(keystone.AcceptedRoles, ",")),
+			cephconfig.NewFlag("rgw s3 auth use keystone", "true"),
+		)
+		if keystone.ImplicitTenants != "" {
+			flags = append(flags, cephconfig.NewFlag("rgw keystone implicit tenants", keystone.ImplicitTenants))
+		}
+		if keystone.TokenCacheSize != nil {
+			flags = append(flags, cephconfig.NewFlag("rgw keystone token cache size", strconv.Itoa(*keystone.TokenCacheSize)))
+		}
+	}
+
+	if ldap := c.store.Spec.Auth.LDAP; ldap != nil {
+		dnAttribute := ldap.DNAttribute
+		if dnAttribute == "" {
+			dnAttribute = ldapDNAttribute
+		}
+		flags = append(flags,
+			cephconfig.NewFlag("rgw ldap uri", ldap.URI),
+			cephconfig.NewFlag("rgw ldap secret", path.Join(ldapDir, ldapPasswordFile)),
+			cephconfig.NewFlag("rgw ldap searchdn", ldap.SearchDN),
+			cephconfig.NewFlag("rgw ldap dnattr", dnAttribute),
+			cephconfig.NewFlag("rgw s3 auth use ldap", "true"),
+		)
+		if ldap.SearchFilter != "" {
+			flags = append(flags, cephconfig.NewFlag("rgw ldap searchfilter", ldap.SearchFilter))
+		}
+	}
+
+	if c.store.Spec.Auth.STS != nil {
+		flags = append(flags, cephconfig.NewFlag("rgw s3 auth use sts", "true"))
+	}
+	return flags
+}
+
+// authVolumes returns the volumes of the passwords of the Keystone admin user and of the LDAP bind user, rgw reads
+// them from files
+func (c *clusterConfig) authVolumes() ([]v1.Volume, []v1.VolumeMount) {
+	volumes := []v1.Volume{}
+	mounts := []v1.VolumeMount{}
+	if keystone := c.store.Spec.Auth.Keystone; keystone != nil {
+		volumes = append(volumes, secretFileVolume(keystoneVolumeName, keystone.ServiceUserSecretName, keystonePasswordKey, keystonePasswordFile))
+		mounts = append(mounts, v1.VolumeMount{Name: keystoneVolumeName, MountPath: keystoneDir, ReadOnly: true})
+	}
+	if ldap := c.store.Spec.Auth.LDAP; ldap != nil {
+		volumes = append(volumes, secretFileVolume(ldapVolumeName, ldap.BindSecretName, ldapPasswordKey, ldapPasswordFile))
+		mounts = append(mounts, v1.VolumeMount{Name: ldapVolumeName, MountPath: ldapDir, ReadOnly: true})
+	}
+	return volumes, mounts
+}
+
+// secretFileVolume returns a volume with the file of a key of a secret
+func secretFileVolume(volumeName, secretName, key, fileName string) v1.Volume {
+	// rgw reads the file after switching to the ceph user while the Secret mount is owned by "root", like the
+	// certificate the file is readable by everyone in the container since fsGroup cannot be predicted on OCP
+	worldReadable := int32(0444)
+	return v1.Volume{
+		Name: volumeName,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName: secretName,
+				Items: []v1.KeyToPath{
+					{Key: key, Path: fileName, Mode: &worldReadable},
+				}}}}
+}
+
+// authOption is a rgw option set in the mon configuration database from the key of a secret
+type authOption struct {
+	name       string
+	secretName string
+	key        string
+}
+
+// authOptions returns the names of the Keystone admin user and of the LDAP bind user and the key of the security
+// token service, the options of an identity service that is not configured have no secret so that they are removed
+func (c *clusterConfig) authOptions() []authOption {
+	keystoneSecretName := ""
+	if keystone := c.store.Spec.Auth.Keystone; keystone != nil {
+		keystoneSecretName = keystone.ServiceUserSecretName
+	}
+	ldapSecretName := ""
+	if ldap := c.store.Spec.Auth.LDAP; ldap != nil {
+		ldapSecretName = ldap.BindSecretName
+	}
+	stsKeySecretName := ""
+	if c.store.Spec.Auth.STS != nil {
+		stsKeySecretName = c.stsKeySecretName()
+	}
+	return []authOption{
+		{name: "rgw_keystone_admin_user", secretName: keystoneSecretName, key: keystoneUsernameKey},
+		{name: "rgw_keystone_admin_project", secretName: keystoneSecretName, key: keystoneProjectKey},
+		{name: "rgw_keystone_admin_domain", secretName: keystoneSecretName, key: keystoneDomainKey},
+		{name: "rgw_ldap_binddn", secretName: ldapSecretName, key: ldapBindDNKey},
+		{name: "rgw_sts_key", secretName: stsKeySecretName, key: stsKeyName},
+	}
+}
+
+// setAuthFlagsMonConfigStore sets the auth options read from their secrets in the mon configuration database so they
+// are not on the command line of rgw
+func (c *clusterConfig) setAuthFlagsMonConfigStore(rgwName string) error {
+	monStore := cephconfig.GetMonStore(c.context, c.clusterInfo)
+	who := generateCephXUser(rgwName)
+	secrets := map[string]*v1.Secret{}
+	for _, option := range c.authOptions() {
+		if option.secretName == "" {
+			if err := monStore.Delete(who, option.name); err != nil {
+				return errors.Wrapf(err, "failed to remove %q on %q", option.name, who)
+			}
+			continue
+		}
+		secret, ok := secrets[option.secretName]
+		if !ok {
+			var err error
+			secret, err = c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Get(option.secretName, metav1.GetOptions{})
+			if err != nil {
+				return errors.Wrapf(err, "failed to get secret %q", option.secretName)
+			}
+			secrets[option.secretName] = secret
+		}
+		if err := monStore.Set(who, option.name, string(secret.Data[option.key])); err != nil {
+			return errors.Wrapf(err, "failed to set %q on %q", option.name, who)
+		}
+	}
+	return nil
+}
+
 func generateCephXUser(name string) string {
 	user := strings.TrimPrefix(name, AppName)
 	return "client.rgw" + strings.Replace(user, "-", ".", -1)
@@ -17,12 +17,18 @@ limitations under the License.
 package object
 
 import (
+	"strings"
 	"testing"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func newConfig() *clusterConfig {
@@ -92,3 +98,144 @@ func TestGenerateCephXUser(t *testing.T) {
 	fakeUser := generateCephXUser("rook-ceph-rgw-fake-store-fake-user")
 	assert.Equal(t, "client.rgw.fake.store.fake.user", fakeUser)
 }
+
+func TestAuthFlags(t *testing.T) {
+	// No external identity service
+	cfg := newConfig()
+	assert.Equal(t, 0, len(cfg.authFlags()))
+	volumes, mounts := cfg.authVolumes()
+	assert.Equal(t, 0, len(volumes))
+	assert.Equal(t, 0, len(mounts))
+
+	// Keystone
+	cacheSize := 5000
+	cfg.store.Spec.Auth.Keystone = &cephv1.KeystoneSpec{
+		URL:                   "https://keystone:5000",
+		ServiceUserSecretName: "keystone-admin",
+		AcceptedRoles:         []string{"admin", "member"},
+		ImplicitTenants:       "swift",
+		TokenCacheSize:        &cacheSize,
+	}
+	flags := cfg.authFlags()
+	assert.Contains(t, flags, "--rgw-keystone-url=https://keystone:5000")
+	assert.Contains(t, flags, "--rgw-keystone-api-version=3")
+	assert.Contains(t, flags, "--rgw-keystone-admin-password-path=/etc/ceph/keystone/password")
+	assert.Contains(t, flags, "--rgw-keystone-accepted-roles=admin,member")
+	assert.Contains(t, flags, "--rgw-keystone-implicit-tenants=swift")
+	assert.Contains(t, flags, "--rgw-keystone-token-cache-size=5000")
+	assert.Contains(t, flags, "--rgw-s3-auth-use-keystone=true")
+	for _, flag := range flags {
+		assert.NotContains(t, flag, "admin-user")
+	}
+	volumes, mounts = cfg.authVolumes()
+	assert.Equal(t, 1, len(volumes))
+	assert.Equal(t, "keystone-admin", volumes[0].Secret.SecretName)
+	assert.Equal(t, "OS_PASSWORD", volumes[0].Secret.Items[0].Key)
+	assert.Equal(t, "/etc/ceph/keystone", mounts[0].MountPath)
+	assert.True(t, mounts[0].ReadOnly)
+
+	// LDAP
+	cfg = newConfig()
+	cfg.store.Spec.Auth.LDAP = &cephv1.LDAPSpec{
+		URI:            "ldaps://ldap:636",
+		BindSecretName: "ldap-bind",
+		SearchDN:       "ou=users,dc=example,dc=com",
+	}
+	flags = cfg.authFlags()
+	assert.Contains(t, flags, "--rgw-ldap-uri=ldaps://ldap:636")
+	assert.Contains(t, flags, "--rgw-ldap-secret=/etc/ceph/ldap/password")
+	assert.Contains(t, flags, "--rgw-ldap-searchdn=ou=users,dc=example,dc=com")
+	assert.Contains(t, flags, "--rgw-ldap-dnattr=uid")
+	assert.Contains(t, flags, "--rgw-s3-auth-use-ldap=true")
+	assert.NotContains(t, flags, "--rgw-s3-auth-use-keystone=true")
+	volumes, mounts = cfg.authVolumes()
+	assert.Equal(t, 1, len(volumes))
+	assert.Equal(t, "ldap-bind", volumes[0].Secret.SecretName)
+	assert.Equal(t, "password", volumes[0].Secret.Items[0].Key)
+	assert.Equal(t, "/etc/ceph/ldap", mounts[0].MountPath)
+
+	// Custom DN attribute and search filter
+	cfg.store.Spec.Auth.LDAP.DNAttribute = "cn"
+	cfg.store.Spec.Auth.LDAP.SearchFilter = "(objectclass=person)"
+	flags = cfg.authFlags()
+	assert.Contains(t, flags, "--rgw-ldap-dnattr=cn")
+	assert.Contains(t, flags, "--rgw-ldap-searchfilter=(objectclass=person)")
+
+	// STS
+	cfg = newConfig()
+	cfg.store.Name = "my-store"
+	cfg.store.Spec.Auth.STS = &cephv1.STSSpec{}
+	flags = cfg.authFlags()
+	assert.Equal(t, []string{"--rgw-s3-auth-use-sts=true"}, flags)
+	options := cfg.authOptions()
+	sts := options[len(options)-1]
+	assert.Equal(t, authOption{name: "rgw_sts_key", secretName: "rook-ceph-rgw-my-store-sts", key: "key"}, sts)
+}
+
+func TestSetAuthFlagsMonConfigStore(t *testing.T) {
+	execedCmds := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			execedCmds = append(execedCmds, strings.Join(args[:4], " "))
+			return "", nil
+		},
+	}
+	clientset := testop.New(t, 1)
+	cfg := newConfig()
+	cfg.context = &clusterd.Context{Clientset: clientset, Executor: executor}
+	cfg.clusterInfo.Namespace = "rook-ceph"
+	cfg.store.Name = "my-store"
+	cfg.store.Namespace = "rook-ceph"
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "keystone-admin", Namespace: "rook-ceph"},
+		Data: map[string][]byte{
+			"OS_USERNAME":         []byte("admin"),
+			"OS_PASSWORD":         []byte("secret"),
+			"OS_PROJECT_NAME":     []byte("admin"),
+			"OS_USER_DOMAIN_NAME": []byte("Default"),
+		},
+	}
+	_, err := clientset.CoreV1().Secrets("rook-ceph").Create(secret)
+	assert.NoError(t, err)
+
+	// the names of the keystone admin user are set and the LDAP bind DN is removed
+	cfg.store.Spec.Auth.Keystone = &cephv1.KeystoneSpec{URL: "https://keystone:5000", ServiceUserSecretName: "keystone-admin"}
+	err = cfg.setAuthFlagsMonConfigStore("rook-ceph-rgw-my-store-a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"config set client.rgw.my.store.a rgw_keystone_admin_user",
+		"config set client.rgw.my.store.a rgw_keystone_admin_project",
+		"config set client.rgw.my.store.a rgw_keystone_admin_domain",
+		"config rm client.rgw.my.store.a rgw_ldap_binddn",
+		"config rm client.rgw.my.store.a rgw_sts_key",
+	}, execedCmds)
+
+	// the sts key is set from the secret generated by the operator
+	stsSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-rgw-my-store-sts", Namespace: "rook-ceph"},
+		Data:       map[string][]byte{"key": []byte("0123456789abcdef")},
+	}
+	_, err = clientset.CoreV1().Secrets("rook-ceph").Create(stsSecret)
+	assert.NoError(t, err)
+	execedCmds = []string{}
+	cfg.store.Spec.Auth.STS = &cephv1.STSSpec{}
+	err = cfg.setAuthFlagsMonConfigStore("rook-ceph-rgw-my-store-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "config set client.rgw.my.store.a rgw_sts_key", execedCmds[4])
+
+	// the options are removed with the identity service
+	execedCmds = []string{}
+	cfg.store.Spec.Auth.Keystone = nil
+	cfg.store.Spec.Auth.STS = nil
+	err = cfg.setAuthFlagsMonConfigStore("rook-ceph-rgw-my-store-a")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(execedCmds))
+	for _, cmd := range execedCmds {
+		assert.True(t, strings.HasPrefix(cmd, "config rm client.rgw.my.store.a"))
+	}
+
+	// a missing secret fails
+	cfg.store.Spec.Auth.LDAP = &cephv1.LDAPSpec{URI: "ldaps://ldap:636", BindSecretName: "ldap-bind"}
+	err = cfg.setAuthFlagsMonConfigStore("rook-ceph-rgw-my-store-a")
+	assert.Error(t, err)
+}
@@ -85,8 +85,9 @@ type ReconcileCephObjectStore struct {
 }
 
 type objectStoreHealth struct {
-	stopChan          chan struct{}
-	monitoringRunning bool
+	stopChan               chan struct{}
+	monitoringRunning      bool
+	usageCollectionRunning bool
 }
 
 // Add creates a new cephObjectStore Controller and adds it to the Manager. The Manager will set fields on the Controller
@@ -355,6 +356,10 @@ func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *c
 			if err != nil {
 				return r.setFailedStatus(namespacedName, "failed to create object pools", err)
 			}
+			err = createPlacementPools(objContext, cephObjectStore.Spec)
+			if err != nil {
+				return r.setFailedStatus(namespacedName, "failed to create placement pools", err)
+			}
 		}
 
 		// Reconcile Multisite Creation
@@ -364,6 +369,12 @@ func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *c
 			return r.setFailedStatus(namespacedName, "failed to configure multisite for object store", err)
 		}
 
+		// Reconcile the placement targets and storage classes of the zone
+		err = setPlacementTargets(objContext, cephObjectStore.Spec)
+		if err != nil {
+			return r.setFailedStatus(namespacedName, "failed to configure placement targets for object store", err)
+		}
+
 		// Create or Update Store
 		err = cfg.createOrUpdateStore(realmName, zoneGroupName, zoneName)
 		if err != nil {
@@ -376,6 +387,11 @@ func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *c
 		r.startMonitoring(cephObjectStore, objContext, serviceIP, namespacedName)
 	}
 
+	// Start collecting the usage
+	if cephObjectStore.Spec.Usage != nil {
+		r.startUsageCollection(cephObjectStore, objContext, namespacedName)
+	}
+
 	return reconcile.Result{}, nil
 }
 
@@ -484,6 +500,19 @@ func (r *ReconcileCephObjectStore) startMonitoring(objectstore *cephv1.CephObjec
 	go rgwChecker.checkObjectStore(r.objectStoreChannels[objectstore.Name].stopChan)
 }
 
+func (r *ReconcileCephObjectStore) startUsageCollection(objectstore *cephv1.CephObjectStore, objContext *Context, namespacedName types.NamespacedName) {
+	if r.objectStoreChannels[objectstore.Name].usageCollectionRunning {
+		logger.Debug("usage collection go routine already running!")
+		return
+	}
+	// the go routine stops with the object store, it only skips the collections if the usage settings are removed
+	r.objectStoreChannels[objectstore.Name].usageCollectionRunning = true
+
+	collector := newUsageCollector(r.context, objContext, r.client, r.bktclient, namespacedName)
+	logger.Infof("starting the usage collection of object store %q", objectstore.Name)
+	go collector.collectUsage(r.objectStoreChannels[objectstore.Name].stopChan)
+}
+
 func (r *ReconcileCephObjectStore) verifyObjectUserCleanup(objectstore *cephv1.CephObjectStore) (reconcile.Result, bool) {
 	cephObjectUsers, err := r.context.RookClientset.CephV1().CephObjectStoreUsers(objectstore.Namespace).List(metav1.ListOptions{})
 	if err != nil {
@@ -0,0 +1,280 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// endpointProbeTimeout is how long the endpoint of the current master has to answer
+	endpointProbeTimeout = 5 * time.Second
+	// GatewayRestartPendingAnnotation is set on a promoted zone or zone group until its gateways were restarted to load
+	// the new period. The promotion is only done once, the restart is retried on each reconcile until it succeeds.
+	GatewayRestartPendingAnnotation = "ceph.rook.io/gateway-restart-pending"
+)
+
+// EndpointAnswers returns whether a gateway answers on the endpoint, overridden by the tests. Any answer counts, even
+// a TLS handshake with a certificate that does not verify or an HTTP error, only an endpoint that cannot be reached is down.
+var EndpointAnswers = func(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		logger.Warningf("invalid endpoint %q, it cannot be probed", endpoint)
+		return false
+	}
+
+	client := http.Client{
+		Timeout: endpointProbeTimeout,
+		Transport: &http.Transport{
+			// #nosec G402 the probe does not send anything, the master being alive is all that matters
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		return !isEndpointUnreachable(err)
+	}
+	resp.Body.Close()
+	return true
+}
+
+// isEndpointUnreachable returns whether a request failed without reaching a server: the connection was refused or
+// timed out, or the host could not be resolved
+func isEndpointUnreachable(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+type periodMapType struct {
+	MasterZoneGroup string `json:"master_zonegroup"`
+	PeriodMap       struct {
+		ZoneGroups []struct {
+			ID        string   `json:"id"`
+			Name      string   `json:"name"`
+			Endpoints []string `json:"endpoints"`
+		} `json:"zonegroups"`
+	} `json:"period_map"`
+}
+
+// PromoteZone makes the zone the master zone of its zone group and commits the period. The promotion is
+// refused while the current master zone answers on one of its endpoints. Returns whether the zone was promoted,
+// false if it already is the master zone.
+func PromoteZone(objContext *Context, realm, zoneGroup, zone string) (bool, error) {
+	realmArg := fmt.Sprintf("--rgw-realm=%s", realm)
+	zoneGroupArg := fmt.Sprintf("--rgw-zonegroup=%s", zoneGroup)
+	zoneArg := fmt.Sprintf("--rgw-zone=%s", zone)
+
+	output, err := RunAdminCommandNoMultisite(objContext, "zonegroup", "get", realmArg, zoneGroupArg)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get zone group %q", zoneGroup)
+	}
+	zoneGroupConfig, err := DecodeZoneGroupConfig(output)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to parse `radosgw-admin zonegroup get` output")
+	}
+	output, err = RunAdminCommandNoMultisite(objContext, "zone", "get", realmArg, zoneGroupArg, zoneArg)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get zone %q", zone)
+	}
+	zoneID, err := decodeID(output)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to parse `radosgw-admin zone get` output")
+	}
+	if zoneID == zoneGroupConfig.MasterZoneID {
+		return false, nil
+	}
+
+	for _, master := range zoneGroupConfig.Zones {
+		if master.ID != zoneGroupConfig.MasterZoneID {
+			continue
+		}
+		if endpoint, ok := answeringEndpoint(master.Endpoints); ok {
+			return false, errors.Errorf("refusing to promote zone %q, the master zone %q still answers on %q", zone, master.Name, endpoint)
+		}
+	}
+
+	logger.Infof("promoting zone %q to master zone of zone group %q", zone, zoneGroup)
+	output, err = RunAdminCommandNoMultisite(objContext, "zone", "modify", realmArg, zoneGroupArg, zoneArg, "--master", "--default")
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to promote zone %q. %s", zone, output)
+	}
+	if err := commitPeriod(objContext, realmArg); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PromoteZoneGroup makes the zone group the master zone group of its realm and commits the period. The promotion
+// is refused while the current master zone group answers on one of its endpoints. Returns whether the zone group
+// was promoted, false if it already is the master zone group.
+func PromoteZoneGroup(objContext *Context, realm, zoneGroup string) (bool, error) {
+	realmArg := fmt.Sprintf("--rgw-realm=%s", realm)
+	zoneGroupArg := fmt.Sprintf("--rgw-zonegroup=%s", zoneGroup)
+
+	output, err := RunAdminCommandNoMultisite(objContext, "period", "get", realmArg)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get the period of realm %q", realm)
+	}
+	var period periodMapType
+	if err := json.Unmarshal([]byte(output), &period); err != nil {
+		return false, errors.Wrap(err, "failed to parse `radosgw-admin period get` output")
+	}
+	output, err = RunAdminCommandNoMultisite(objContext, "zonegroup", "get", realmArg, zoneGroupArg)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get zone group %q", zoneGroup)
+	}
+	zoneGroupID, err := decodeID(output)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to parse `radosgw-admin zonegroup get` output")
+	}
+	if zoneGroupID == period.MasterZoneGroup {
+		return false, nil
+	}
+
+	for _, master := range period.PeriodMap.ZoneGroups {
+		if master.ID != period.MasterZoneGroup {
+			continue
+		}
+		if endpoint, ok := answeringEndpoint(master.Endpoints); ok {
+			return false, errors.Errorf("refusing to promote zone group %q, the master zone group %q still answers on %q", zoneGroup, master.Name, endpoint)
+		}
+	}
+
+	logger.Infof("promoting zone group %q to master zone group of realm %q", zoneGroup, realm)
+	output, err = RunAdminCommandNoMultisite(objContext, "zonegroup", "modify", realmArg, zoneGroupArg, "--master", "--default")
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to promote zone group %q. %s", zoneGroup, output)
+	}
+	if err := commitPeriod(objContext, realmArg); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func commitPeriod(objContext *Context, realmArg string) error {
+	output, err := RunAdminCommandNoMultisite(objContext, "period", "update", "--commit", realmArg)
+	if err != nil {
+		return errors.Wrapf(err, "failed to commit the period. %s", output)
+	}
+	return nil
+}
+
+// answeringEndpoint returns the first endpoint a gateway answers on. The endpoints without a host cannot be
+// checked and are skipped.
+func answeringEndpoint(endpoints []string) (string, bool) {
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Hostname() == "" {
+			logger.Warningf("cannot check endpoint %q of the current master", endpoint)
+			continue
+		}
+		if EndpointAnswers(endpoint) {
+			return endpoint, true
+		}
+	}
+	return "", false
+}
+
+// RestartZoneGateways restarts the gateways of the object stores of the zones so they load the new period
+func RestartZoneGateways(c client.Client, clusterdContext *clusterd.Context, namespace string, zones []string) error {
+	stores := &cephv1.CephObjectStoreList{}
+	if err := c.List(context.TODO(), stores, client.InNamespace(namespace)); err != nil {
+		return errors.Wrap(err, "failed to list object stores")
+	}
+	for _, store := range stores.Items {
+		for _, zone := range zones {
+			if store.Spec.Zone.Name != zone {
+				continue
+			}
+			if err := restartGateways(clusterdContext, namespace, store.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// IsGatewayRestartPending returns whether the gateways of the promoted zone or zone group still have to be restarted
+func IsGatewayRestartPending(obj metav1.Object) bool {
+	return obj.GetAnnotations()[GatewayRestartPendingAnnotation] == "true"
+}
+
+// SetGatewayRestartPending records in the annotations of the zone or zone group whether its gateways still have to be
+// restarted. The object is read again before being updated since its status may have changed during the reconcile.
+func SetGatewayRestartPending(c client.Client, name types.NamespacedName, obj runtime.Object, pending bool) error {
+	if err := c.Get(context.TODO(), name, obj); err != nil {
+		return errors.Wrapf(err, "failed to get %q", name.String())
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return errors.Wrapf(err, "failed to access the metadata of %q", name.String())
+	}
+	if IsGatewayRestartPending(accessor) == pending {
+		return nil
+	}
+
+	annotations := accessor.GetAnnotations()
+	if pending {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[GatewayRestartPendingAnnotation] = "true"
+	} else {
+		delete(annotations, GatewayRestartPendingAnnotation)
+	}
+	accessor.SetAnnotations(annotations)
+	if err := c.Update(context.TODO(), obj); err != nil {
+		return errors.Wrapf(err, "failed to update annotation %q of %q", GatewayRestartPendingAnnotation, name.String())
+	}
+	return nil
+}
+
+// restartGateways deletes the rgw pods of the object store, their deployment recreates them
+func restartGateways(clusterdContext *clusterd.Context, namespace, storeName string) error {
+	selector := fmt.Sprintf("%s=%s,rook_object_store=%s", k8sutil.AppAttr, AppName, storeName)
+	err := clusterdContext.Clientset.CoreV1().Pods(namespace).DeleteCollection(&metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return errors.Wrapf(err, "failed to restart the gateways of object store %q", storeName)
+	}
+	logger.Infof("restarted the gateways of object store %q", storeName)
+	return nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	failoverZoneGroupJSON = `{"id":"zg-1","name":"zonegroup-a","master_zone":"zone-1","zones":[
+		{"id":"zone-1","name":"zone-a","endpoints":["http://zone-a.example.com:80",":80"]},
+		{"id":"zone-2","name":"zone-b","endpoints":["http://zone-b.example.com:80"]}]}`
+	failoverPeriodJSON = `{"id":"period-1","master_zonegroup":"zg-1","period_map":{"zonegroups":[
+		{"id":"zg-1","name":"zonegroup-a","endpoints":["http://zonegroup-a.example.com:80"]},
+		{"id":"zg-2","name":"zonegroup-b","endpoints":["http://zonegroup-b.example.com:80"]}]}}`
+)
+
+func failoverContext(zoneID, zoneGroupID string, commands *[]string) *Context {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "zonegroup" && args[1] == "get":
+				return strings.Replace(failoverZoneGroupJSON, `"id":"zg-1"`, `"id":"`+zoneGroupID+`"`, 1), nil
+			case args[0] == "zone" && args[1] == "get":
+				return `{"id":"` + zoneID + `"}`, nil
+			case args[0] == "period" && args[1] == "get":
+				return failoverPeriodJSON, nil
+			}
+			*commands = append(*commands, strings.Join(args[:2], " "))
+			return "", nil
+		},
+	}
+	return NewContext(&clusterd.Context{Executor: executor}, client.AdminClusterInfo("mycluster"), "zone-b")
+}
+
+func TestPromoteZone(t *testing.T) {
+	defer func(probe func(string) bool) { EndpointAnswers = probe }(EndpointAnswers)
+	answering := map[string]bool{}
+	EndpointAnswers = func(endpoint string) bool { return answering[endpoint] }
+
+	// the zone already is the master
+	commands := []string{}
+	promoted, err := PromoteZone(failoverContext("zone-1", "zg-1", &commands), "realm-a", "zonegroup-a", "zone-a")
+	assert.NoError(t, err)
+	assert.False(t, promoted)
+	assert.Equal(t, 0, len(commands))
+
+	// the master zone still answers
+	answering["http://zone-a.example.com:80"] = true
+	promoted, err = PromoteZone(failoverContext("zone-2", "zg-1", &commands), "realm-a", "zonegroup-a", "zone-b")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "still answers")
+	assert.False(t, promoted)
+	assert.Equal(t, 0, len(commands))
+
+	// the master zone is lost
+	answering["http://zone-a.example.com:80"] = false
+	promoted, err = PromoteZone(failoverContext("zone-2", "zg-1", &commands), "realm-a", "zonegroup-a", "zone-b")
+	assert.NoError(t, err)
+	assert.True(t, promoted)
+	assert.Equal(t, []string{"zone modify", "period update"}, commands)
+}
+
+func TestPromoteZoneGroup(t *testing.T) {
+	defer func(probe func(string) bool) { EndpointAnswers = probe }(EndpointAnswers)
+	answering := map[string]bool{}
+	EndpointAnswers = func(endpoint string) bool { return answering[endpoint] }
+
+	// the zone group already is the master
+	commands := []string{}
+	promoted, err := PromoteZoneGroup(failoverContext("zone-2", "zg-1", &commands), "realm-a", "zonegroup-a")
+	assert.NoError(t, err)
+	assert.False(t, promoted)
+
+	// the master zone group still answers
+	answering["http://zonegroup-a.example.com:80"] = true
+	promoted, err = PromoteZoneGroup(failoverContext("zone-2", "zg-2", &commands), "realm-a", "zonegroup-b")
+	assert.Error(t, err)
+	assert.False(t, promoted)
+	assert.Equal(t, 0, len(commands))
+
+	// the master zone group is lost
+	answering["http://zonegroup-a.example.com:80"] = false
+	promoted, err = PromoteZoneGroup(failoverContext("zone-2", "zg-2", &commands), "realm-a", "zonegroup-b")
+	assert.NoError(t, err)
+	assert.True(t, promoted)
+	assert.Equal(t, []string{"zonegroup modify", "period update"}, commands)
+}
+
+func TestAnsweringEndpoint(t *testing.T) {
+	defer func(probe func(string) bool) { EndpointAnswers = probe }(EndpointAnswers)
+	probed := []string{}
+	EndpointAnswers = func(endpoint string) bool {
+		probed = append(probed, endpoint)
+		return endpoint == "http://b.example.com"
+	}
+
+	endpoint, ok := answeringEndpoint([]string{":80", "http://a.example.com", "http://b.example.com"})
+	assert.True(t, ok)
+	assert.Equal(t, "http://b.example.com", endpoint)
+	// the endpoint without a host is not probed
+	assert.Equal(t, []string{"http://a.example.com", "http://b.example.com"}, probed)
+
+	_, ok = answeringEndpoint([]string{})
+	assert.False(t, ok)
+}
+
+func TestEndpointAnswers(t *testing.T) {
+	// a gateway with a self-signed certificate answering with an error is alive
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	assert.True(t, EndpointAnswers(server.URL))
+
+	// a plain http gateway probed over https still answers
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer plain.Close()
+	assert.True(t, EndpointAnswers(plain.URL))
+	assert.True(t, EndpointAnswers(strings.Replace(plain.URL, "http://", "https://", 1)))
+
+	// nothing listens on the endpoint
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+	assert.False(t, EndpointAnswers("http://"+address))
+
+	assert.False(t, EndpointAnswers("zone-a.example.com:80"))
+}
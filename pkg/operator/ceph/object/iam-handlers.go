@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/pkg/errors"
+)
+
+// IAMAgent wraps the iam.IAM structure to manage the OIDC providers of the rgw
+type IAMAgent struct {
+	Client *iam.IAM
+}
+
+func NewIAMAgent(accessKey, secretKey, endpoint string, debug bool) (*IAMAgent, error) {
+	sess, err := newSession(accessKey, secretKey, endpoint, nil, debug)
+	if err != nil {
+		return nil, err
+	}
+	return &IAMAgent{
+		Client: iam.New(sess),
+	}, nil
+}
+
+// EnsureOpenIDConnectProvider creates the OIDC provider of the issuer, or recreates it if its audiences or
+// thumbprints changed, and returns its ARN
+func (a *IAMAgent) EnsureOpenIDConnectProvider(issuer string, audiences, thumbprints []string) (string, error) {
+	arn := OIDCProviderARN(issuer)
+	current, err := a.Client.GetOpenIDConnectProvider(&iam.GetOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(arn),
+	})
+	if err == nil {
+		if sameValues(aws.StringValueSlice(current.ClientIDList), audiences) && sameValues(aws.StringValueSlice(current.ThumbprintList), thumbprints) {
+			return arn, nil
+		}
+		logger.Infof("updating oidc provider %q", arn)
+		if _, err := a.Client.DeleteOpenIDConnectProvider(&iam.DeleteOpenIDConnectProviderInput{
+			OpenIDConnectProviderArn: aws.String(arn),
+		}); err != nil {
+			return "", errors.Wrapf(err, "failed to delete oidc provider %q", arn)
+		}
+	} else if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeNoSuchEntityException {
+		return "", errors.Wrapf(err, "failed to get oidc provider %q", arn)
+	}
+
+	output, err := a.Client.CreateOpenIDConnectProvider(&iam.CreateOpenIDConnectProviderInput{
+		Url:            aws.String(issuer),
+		ClientIDList:   aws.StringSlice(audiences),
+		ThumbprintList: aws.StringSlice(thumbprints),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create oidc provider of issuer %q", issuer)
+	}
+	return aws.StringValue(output.OpenIDConnectProviderArn), nil
+}
+
+func sameValues(a, b []string) bool {
+	a = append([]string{}, a...)
+	b = append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricsPrefix = "rook_ceph_object_"
+
+var (
+	bucketLabels = []string{"namespace", "object_store", "bucket", "owner"}
+	userLabels   = []string{"namespace", "object_store", "user"}
+
+	bucketMetrics = newUsageMetrics("bucket", "bucket", bucketLabels)
+	userMetrics   = newUsageMetrics("user", "buckets owned by the user", userLabels)
+
+	// the last usage collected of each object store, exported at each scrape so that the metrics of the buckets
+	// and users gone are removed with them
+	collectedUsage     = map[string]*storeUsage{}
+	collectedUsageLock sync.Mutex
+)
+
+type storeUsage struct {
+	namespace string
+	store     string
+	usage     *ObjectStoreUsage
+}
+
+type usageMetrics struct {
+	size          *prometheus.Desc
+	objects       *prometheus.Desc
+	ops           *prometheus.Desc
+	successfulOps *prometheus.Desc
+	bytesSent     *prometheus.Desc
+	bytesReceived *prometheus.Desc
+}
+
+func newUsageMetrics(kind, description string, labels []string) *usageMetrics {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(metricsPrefix+kind+"_"+name, help, labels, nil)
+	}
+	return &usageMetrics{
+		size:          desc("size_bytes", "Size of the objects of the "+description),
+		objects:       desc("objects", "Number of objects of the "+description),
+		ops:           desc("ops_total", "Number of operations on the "+description+" in the usage log"),
+		successfulOps: desc("successful_ops_total", "Number of successful operations on the "+description+" in the usage log"),
+		bytesSent:     desc("sent_bytes_total", "Bytes sent by the gateways for the "+description+" in the usage log"),
+		bytesReceived: desc("received_bytes_total", "Bytes received by the gateways for the "+description+" in the usage log"),
+	}
+}
+
+func (m *usageMetrics) describe(ch chan<- *prometheus.Desc) {
+	ch <- m.size
+	ch <- m.objects
+	ch <- m.ops
+	ch <- m.successfulOps
+	ch <- m.bytesSent
+	ch <- m.bytesReceived
+}
+
+// collect sends the metrics of a usage. The size and the objects are current values, the operations and the bytes
+// are the totals of the usage log, only reset when the log is trimmed.
+func (m *usageMetrics) collect(ch chan<- prometheus.Metric, usage *Usage, labels ...string) {
+	ch <- prometheus.MustNewConstMetric(m.size, prometheus.GaugeValue, float64(usage.Size), labels...)
+	ch <- prometheus.MustNewConstMetric(m.objects, prometheus.GaugeValue, float64(usage.Objects), labels...)
+	ch <- prometheus.MustNewConstMetric(m.ops, prometheus.CounterValue, float64(usage.Ops), labels...)
+	ch <- prometheus.MustNewConstMetric(m.successfulOps, prometheus.CounterValue, float64(usage.SuccessfulOps), labels...)
+	ch <- prometheus.MustNewConstMetric(m.bytesSent, prometheus.CounterValue, float64(usage.BytesSent), labels...)
+	ch <- prometheus.MustNewConstMetric(m.bytesReceived, prometheus.CounterValue, float64(usage.BytesReceived), labels...)
+}
+
+// usageMetricsCollector exports the last usage collected of the object stores
+type usageMetricsCollector struct{}
+
+func (usageMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	bucketMetrics.describe(ch)
+	userMetrics.describe(ch)
+}
+
+func (usageMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	collectedUsageLock.Lock()
+	defer collectedUsageLock.Unlock()
+	for _, s := range collectedUsage {
+		for bucket, bucketUsage := range s.usage.Buckets {
+			bucketMetrics.collect(ch, &bucketUsage.Usage, s.namespace, s.store, bucket, bucketUsage.Owner)
+		}
+		for user, userUsage := range s.usage.Users {
+			userMetrics.collect(ch, userUsage, s.namespace, s.store, user)
+		}
+	}
+}
+
+func init() {
+	metrics.Registry.MustRegister(usageMetricsCollector{})
+}
+
+// setUsageMetrics exports the usage of the buckets and users of an object store
+func setUsageMetrics(namespace, store string, usage *ObjectStoreUsage) {
+	collectedUsageLock.Lock()
+	defer collectedUsageLock.Unlock()
+	collectedUsage[namespace+"/"+store] = &storeUsage{namespace: namespace, store: store, usage: usage}
+}
+
+// deleteUsageMetrics removes the metrics of an object store
+func deleteUsageMetrics(namespace, store string) {
+	collectedUsageLock.Lock()
+	defer collectedUsageLock.Unlock()
+	delete(collectedUsage, namespace+"/"+store)
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notification to manage the rgw bucket notifications of the object bucket claims.
+package notification
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/coreos/pkg/capnslog"
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-bucket-notification-controller"
+	// NotificationLabelPrefix is the prefix of the label attaching a notification to an object bucket claim,
+	// the label "bucket-notification-<name>: <name>" attaches the notification <name>
+	NotificationLabelPrefix = "bucket-notification-"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephBucketNotificationKind = reflect.TypeOf(cephv1.CephBucketNotification{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephBucketNotificationKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileBucketNotification reconciles a CephBucketNotification object
+type ReconcileBucketNotification struct {
+	client  client.Client
+	scheme  *runtime.Scheme
+	context *clusterd.Context
+}
+
+// Add creates a new CephBucketNotification Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context) error {
+	return add(mgr, newReconciler(mgr, context))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Reconciler {
+	// Add the cephv1 and object bucket schemes to the manager scheme so that the controller knows about them
+	mgrScheme := mgr.GetScheme()
+	if err := cephv1.AddToScheme(mgr.GetScheme()); err != nil {
+		panic(err)
+	}
+	if err := bktv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		panic(err)
+	}
+
+	return &ReconcileBucketNotification{
+		client:  mgr.GetClient(),
+		scheme:  mgrScheme,
+		context: context,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephBucketNotification CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephBucketNotification{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	// Watch the object bucket claims labeled with notifications, the old and new labels are both mapped on update
+	// so that the notifications are also detached from the claims
+	err = c.Watch(&source.Kind{Type: &bktv1alpha1.ObjectBucketClaim{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+			return notificationRequests(mgr.GetClient(), obj.Meta.GetLabels())
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// notificationRequests returns the requests of the notifications attached by the labels of an object bucket claim
+func notificationRequests(c client.Client, labels map[string]string) []reconcile.Request {
+	requests := []reconcile.Request{}
+	names := notificationNames(labels)
+	if len(names) == 0 {
+		return requests
+	}
+
+	notifications := &cephv1.CephBucketNotificationList{}
+	err := c.List(context.TODO(), notifications)
+	if err != nil {
+		logger.Errorf("failed to list bucket notifications. %v", err)
+		return requests
+	}
+	for _, notification := range notifications.Items {
+		if names[notification.Name] {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: notification.Name, Namespace: notification.Namespace}})
+		}
+	}
+	return requests
+}
+
+func notificationNames(labels map[string]string) map[string]bool {
+	names := map[string]bool{}
+	for key, value := range labels {
+		if strings.HasPrefix(key, NotificationLabelPrefix) && strings.TrimPrefix(key, NotificationLabelPrefix) == value {
+			names[value] = true
+		}
+	}
+	return names
+}
+
+// Reconcile reads that state of the cluster for a CephBucketNotification object and makes changes based on the state read
+// and what is in the CephBucketNotification.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileBucketNotification) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime loggin interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileBucketNotification) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephBucketNotification instance
+	notification := &cephv1.CephBucketNotification{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, notification)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketNotification resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get CephBucketNotification")
+	}
+
+	// The CR was just created, initializing status fields
+	if notification.Status == nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, nil)
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	_, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		if !notification.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// Remove finalizer
+			err = opcontroller.RemoveFinalizer(r.client, notification)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		return reconcileResponse, nil
+	}
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, notification)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// DELETE: the CR was deleted
+	if !notification.GetDeletionTimestamp().IsZero() {
+		logger.Infof("deleting bucket notification %q", notification.Name)
+		err = r.detachNotification(notification, attachedClaims(notification), map[string]bool{})
+		if err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to delete bucket notification %q", notification.Name)
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.client, notification)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, nil
+	}
+
+	// validate the notification settings
+	err = validateNotification(notification)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, nil)
+		return reconcile.Result{}, errors.Wrapf(err, "invalid bucket notification CR %q spec", notification.Name)
+	}
+
+	// The topic must be created first
+	topic := &cephv1.CephBucketTopic{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: notification.Spec.Topic, Namespace: notification.Namespace}, topic)
+	if err != nil || topic.Status == nil || topic.Status.ARN == "" {
+		logger.Debugf("bucket topic %q not ready, retrying in %q. %v",
+			notification.Spec.Topic, opcontroller.WaitForRequeueIfCephClusterNotReady.RequeueAfter.String(), err)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, nil)
+		return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
+	}
+
+	// ATTACH the notification to the labeled claims and DETACH it from the others
+	attached, err := r.attachNotification(notification, topic)
+	if err != nil {
+		// keep track of the claims the notification may still be attached to
+		for _, claim := range attachedClaims(notification) {
+			attached[claim] = true
+		}
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, attached)
+		return reconcile.Result{}, errors.Wrapf(err, "failed to attach bucket notification %q", notification.Name)
+	}
+	err = r.detachNotification(notification, attachedClaims(notification), attached)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, attached)
+		return reconcile.Result{}, errors.Wrapf(err, "failed to detach bucket notification %q", notification.Name)
+	}
+
+	// Set Ready status, we are done reconciling
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, attached)
+
+	// Return and do not requeue
+	logger.Debug("done reconciling")
+	return reconcile.Result{}, nil
+}
+
+func attachedClaims(notification *cephv1.CephBucketNotification) []string {
+	if notification.Status == nil {
+		return nil
+	}
+	return notification.Status.ObjectBucketClaims
+}
+
+// updateStatus updates a notification with a given status and the claims it is attached to
+func updateStatus(client client.Client, name types.NamespacedName, status string, attached map[string]bool) {
+	notification := &cephv1.CephBucketNotification{}
+	if err := client.Get(context.TODO(), name, notification); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketNotification resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve bucket notification %q to update status to %q. %v", name, status, err)
+		return
+	}
+	if notification.Status == nil {
+		notification.Status = &cephv1.BucketNotificationStatus{}
+	}
+
+	notification.Status.Phase = status
+	if attached != nil {
+		notification.Status.ObjectBucketClaims = sortedClaims(attached)
+	}
+	if err := opcontroller.UpdateStatus(client, notification); err != nil {
+		logger.Errorf("failed to set bucket notification %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("bucket notification %q status updated to %q", name, status)
+}
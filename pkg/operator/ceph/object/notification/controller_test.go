@@ -0,0 +1,225 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const namespace = "rook-ceph"
+
+func TestValidateNotification(t *testing.T) {
+	notification := &cephv1.CephBucketNotification{}
+	assert.Error(t, validateNotification(notification))
+
+	notification.Spec.Topic = "my-topic"
+	assert.NoError(t, validateNotification(notification))
+
+	notification.Spec.Events = []cephv1.BucketNotificationEvent{"s3:ObjectCreated:*", "s3:ObjectRemoved:Delete"}
+	assert.NoError(t, validateNotification(notification))
+
+	notification.Spec.Events = []cephv1.BucketNotificationEvent{"s3:ObjectRestore:*"}
+	assert.Error(t, validateNotification(notification))
+
+	notification.Spec.Events = nil
+	notification.Spec.Filter = &cephv1.NotificationFilterSpec{KeyFilters: []cephv1.NotificationKeyFilterRule{{Name: "prefix", Value: "hello"}}}
+	assert.NoError(t, validateNotification(notification))
+
+	notification.Spec.Filter.KeyFilters[0].Name = "contains"
+	assert.Error(t, validateNotification(notification))
+}
+
+func TestTopicConfiguration(t *testing.T) {
+	notification := &cephv1.CephBucketNotification{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-notification", Namespace: namespace},
+		Spec:       cephv1.BucketNotificationSpec{Topic: "my-topic"},
+	}
+
+	config := topicConfiguration(notification, "arn:aws:sns:my-store::my-topic")
+	assert.Equal(t, "my-notification", *config.Id)
+	assert.Equal(t, "arn:aws:sns:my-store::my-topic", *config.TopicArn)
+	assert.Equal(t, []string{"s3:ObjectCreated:*", "s3:ObjectRemoved:*"}, aws.StringValueSlice(config.Events))
+	assert.Nil(t, config.Filter)
+
+	notification.Spec.Events = []cephv1.BucketNotificationEvent{"s3:ObjectCreated:Put"}
+	notification.Spec.Filter = &cephv1.NotificationFilterSpec{KeyFilters: []cephv1.NotificationKeyFilterRule{{Name: "suffix", Value: ".png"}}}
+	config = topicConfiguration(notification, "arn:aws:sns:my-store::my-topic")
+	assert.Equal(t, []string{"s3:ObjectCreated:Put"}, aws.StringValueSlice(config.Events))
+	assert.Equal(t, "suffix", *config.Filter.Key.FilterRules[0].Name)
+	assert.Equal(t, ".png", *config.Filter.Key.FilterRules[0].Value)
+}
+
+func TestNotificationNames(t *testing.T) {
+	labels := map[string]string{
+		"app":                                 "my-app",
+		"bucket-notification-my-notification": "my-notification",
+		"bucket-notification-other":           "mismatch",
+	}
+	assert.Equal(t, map[string]bool{"my-notification": true}, notificationNames(labels))
+}
+
+func TestCephBucketNotificationController(t *testing.T) {
+	// fake rgw serving the notification configuration of the bucket
+	bucketNotifications := `<NotificationConfiguration></NotificationConfiguration>`
+	puts := 0
+	rgw := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/my-bucket", req.URL.Path)
+		switch req.Method {
+		case http.MethodGet:
+			_, err := w.Write([]byte(bucketNotifications))
+			assert.NoError(t, err)
+		case http.MethodPut:
+			body, err := ioutil.ReadAll(req.Body)
+			assert.NoError(t, err)
+			bucketNotifications = string(body)
+			puts++
+		}
+	}))
+	defer rgw.Close()
+	rgwURL, err := url.Parse(rgw.URL)
+	assert.NoError(t, err)
+	rgwPort, err := strconv.Atoi(rgwURL.Port())
+	assert.NoError(t, err)
+
+	notification := &cephv1.CephBucketNotification{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-notification", Namespace: namespace},
+		TypeMeta:   metav1.TypeMeta{Kind: "CephBucketNotification"},
+		Spec:       cephv1.BucketNotificationSpec{Topic: "my-topic"},
+	}
+	topic := &cephv1.CephBucketTopic{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-topic", Namespace: namespace},
+		Spec:       cephv1.BucketTopicSpec{ObjectStoreName: "my-store"},
+		Status:     &cephv1.BucketTopicStatus{Phase: k8sutil.ReadyStatus, ARN: "arn:aws:sns:my-store::my-topic"},
+	}
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+		Status: cephv1.ClusterStatus{
+			Phase:      k8sutil.ReadyStatus,
+			CephStatus: &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+	claim := &bktv1alpha1.ObjectBucketClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-obc",
+			Namespace: "default",
+			Labels:    map[string]string{"bucket-notification-my-notification": "my-notification"},
+		},
+		Spec: bktv1alpha1.ObjectBucketClaimSpec{
+			StorageClassName: "rook-ceph-bucket",
+			ObjectBucketName: "obc-default-my-obc",
+		},
+		Status: bktv1alpha1.ObjectBucketClaimStatus{Phase: bktv1alpha1.ObjectBucketClaimStatusPhaseBound},
+	}
+	ob := &bktv1alpha1.ObjectBucket{
+		ObjectMeta: metav1.ObjectMeta{Name: "obc-default-my-obc"},
+		Spec: bktv1alpha1.ObjectBucketSpec{
+			Connection: &bktv1alpha1.Connection{
+				Endpoint: &bktv1alpha1.Endpoint{BucketHost: rgwURL.Hostname(), BucketPort: rgwPort, BucketName: "my-bucket"},
+			},
+		},
+	}
+
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "status" {
+				return `{"fsid":"c47cac40-9bee-4d52-823b-ccd803ba5bfe","health":{"checks":{},"status":"HEALTH_OK"},"pgmap":{"num_pgs":100,"pgs_by_state":[{"state_name":"active+clean","count":100}]}}`, nil
+			}
+			return "", nil
+		},
+	}
+	clientset := test.New(t, 3)
+	c := &clusterd.Context{
+		Executor:      executor,
+		RookClientset: rookclient.NewSimpleClientset(),
+		Clientset:     clientset,
+	}
+	_, err = clientset.StorageV1().StorageClasses().Create(&storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-bucket"},
+		Parameters: map[string]string{"objectStoreName": "my-store", "objectStoreNamespace": namespace},
+	})
+	assert.NoError(t, err)
+	_, err = clientset.CoreV1().Secrets("default").Create(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-obc", Namespace: "default"},
+		Data:       map[string][]byte{"AWS_ACCESS_KEY_ID": []byte("EOE7FYCNOBZJ5VFV909G"), "AWS_SECRET_ACCESS_KEY": []byte("qmIqpWm8HxCzmynCrD6U6vKWi4hnDBndOnmxXNsV")},
+	})
+	assert.NoError(t, err)
+
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephBucketNotification{}, &cephv1.CephBucketNotificationList{},
+		&cephv1.CephBucketTopic{}, &cephv1.CephBucketTopicList{}, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	assert.NoError(t, bktv1alpha1.AddToScheme(s))
+	cl := fake.NewFakeClientWithScheme(s, []runtime.Object{notification, topic, cephCluster, claim, ob}...)
+	r := &ReconcileBucketNotification{client: cl, scheme: s, context: c}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "my-notification", Namespace: namespace}}
+
+	//
+	// TEST 1: the notification is attached to the bucket of the labeled claim
+	//
+	res, err := r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, 1, puts)
+	assert.Contains(t, bucketNotifications, "<Id>my-notification</Id>")
+	assert.Contains(t, bucketNotifications, "<Topic>arn:aws:sns:my-store::my-topic</Topic>")
+	notification = &cephv1.CephBucketNotification{}
+	err = cl.Get(context.TODO(), req.NamespacedName, notification)
+	assert.NoError(t, err)
+	assert.Equal(t, k8sutil.ReadyStatus, notification.Status.Phase)
+	assert.Equal(t, []string{"default/my-obc"}, notification.Status.ObjectBucketClaims)
+
+	//
+	// TEST 2: the notification is detached when the label is removed from the claim
+	//
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "my-obc", Namespace: "default"}, claim)
+	assert.NoError(t, err)
+	claim.Labels = map[string]string{}
+	err = cl.Update(context.TODO(), claim)
+	assert.NoError(t, err)
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, 2, puts)
+	assert.NotContains(t, bucketNotifications, "my-notification")
+	notification = &cephv1.CephBucketNotification{}
+	err = cl.Get(context.TODO(), req.NamespacedName, notification)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(notification.Status.ObjectBucketClaims))
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// the keys of the secret created for the object bucket claim by the bucket provisioner
+	accessKeyIDKey     = "AWS_ACCESS_KEY_ID"
+	secretAccessKeyKey = "AWS_SECRET_ACCESS_KEY"
+)
+
+var (
+	supportedEvents = []cephv1.BucketNotificationEvent{
+		"s3:ObjectCreated:*",
+		"s3:ObjectCreated:Put",
+		"s3:ObjectCreated:Post",
+		"s3:ObjectCreated:Copy",
+		"s3:ObjectCreated:CompleteMultipartUpload",
+		"s3:ObjectRemoved:*",
+		"s3:ObjectRemoved:Delete",
+		"s3:ObjectRemoved:DeleteMarkerCreated",
+	}
+	defaultEvents      = []string{"s3:ObjectCreated:*", "s3:ObjectRemoved:*"}
+	supportedKeyFilter = []string{"prefix", "suffix", "regex"}
+)
+
+// validateNotification validates the notification settings
+func validateNotification(n *cephv1.CephBucketNotification) error {
+	if n.Spec.Topic == "" {
+		return errors.New("missing topic")
+	}
+
+	for _, event := range n.Spec.Events {
+		if !containsEvent(supportedEvents, event) {
+			return errors.Errorf("unsupported event %q, expected one of %v", event, supportedEvents)
+		}
+	}
+
+	if n.Spec.Filter != nil {
+		for _, rule := range n.Spec.Filter.KeyFilters {
+			if !containsString(supportedKeyFilter, rule.Name) {
+				return errors.Errorf("unsupported key filter %q, expected one of %v", rule.Name, supportedKeyFilter)
+			}
+		}
+	}
+
+	return nil
+}
+
+func containsEvent(events []cephv1.BucketNotificationEvent, event cephv1.BucketNotificationEvent) bool {
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// topicConfiguration builds the s3 notification of the bucket from the notification spec
+func topicConfiguration(n *cephv1.CephBucketNotification, topicARN string) *s3.TopicConfiguration {
+	events := defaultEvents
+	if len(n.Spec.Events) > 0 {
+		events = []string{}
+		for _, event := range n.Spec.Events {
+			events = append(events, string(event))
+		}
+	}
+
+	config := &s3.TopicConfiguration{
+		Id:       aws.String(n.Name),
+		TopicArn: aws.String(topicARN),
+		Events:   aws.StringSlice(events),
+	}
+	if n.Spec.Filter != nil && len(n.Spec.Filter.KeyFilters) > 0 {
+		rules := []*s3.FilterRule{}
+		for _, rule := range n.Spec.Filter.KeyFilters {
+			rules = append(rules, &s3.FilterRule{Name: aws.String(rule.Name), Value: aws.String(rule.Value)})
+		}
+		config.Filter = &s3.NotificationConfigurationFilter{Key: &s3.KeyFilter{FilterRules: rules}}
+	}
+	return config
+}
+
+// attachNotification puts the notification on the buckets of the bound claims labeled with it, and returns the claims it is attached to
+func (r *ReconcileBucketNotification) attachNotification(n *cephv1.CephBucketNotification, topic *cephv1.CephBucketTopic) (map[string]bool, error) {
+	attached := map[string]bool{}
+	claims := &bktv1alpha1.ObjectBucketClaimList{}
+	err := r.client.List(context.TODO(), claims, client.MatchingLabels{NotificationLabelPrefix + n.Name: n.Name})
+	if err != nil {
+		return attached, errors.Wrap(err, "failed to list object bucket claims")
+	}
+
+	config := topicConfiguration(n, topic.Status.ARN)
+	for i := range claims.Items {
+		claim := &claims.Items[i]
+		if claim.Status.Phase != bktv1alpha1.ObjectBucketClaimStatusPhaseBound {
+			logger.Debugf("object bucket claim %q is not bound yet", claimKey(claim))
+			continue
+		}
+		inStore, err := r.claimInObjectStore(claim, topic.Spec.ObjectStoreName, topic.Namespace)
+		if err != nil {
+			return attached, err
+		}
+		if !inStore {
+			logger.Warningf("cannot attach bucket notification %q to object bucket claim %q, its bucket is not in object store %q", n.Name, claimKey(claim), topic.Spec.ObjectStoreName)
+			continue
+		}
+
+		s3Agent, bucket, err := r.claimBucket(claim)
+		if err != nil {
+			return attached, err
+		}
+		err = s3Agent.PutBucketTopicNotification(bucket, config)
+		if err != nil {
+			return attached, err
+		}
+		logger.Infof("bucket notification %q attached to object bucket claim %q", n.Name, claimKey(claim))
+		attached[claimKey(claim)] = true
+	}
+
+	return attached, nil
+}
+
+// detachNotification removes the notification from the buckets of the claims it is not attached to anymore
+func (r *ReconcileBucketNotification) detachNotification(n *cephv1.CephBucketNotification, previous []string, attached map[string]bool) error {
+	for _, key := range previous {
+		if attached[key] {
+			continue
+		}
+		namespaceName := strings.SplitN(key, "/", 2)
+		if len(namespaceName) != 2 {
+			continue
+		}
+		claim := &bktv1alpha1.ObjectBucketClaim{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: namespaceName[0], Name: namespaceName[1]}, claim)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				// the bucket is gone with its notifications
+				continue
+			}
+			return errors.Wrapf(err, "failed to get object bucket claim %q", key)
+		}
+		if claim.Status.Phase != bktv1alpha1.ObjectBucketClaimStatusPhaseBound {
+			continue
+		}
+
+		s3Agent, bucket, err := r.claimBucket(claim)
+		if err != nil {
+			if kerrors.IsNotFound(errors.Cause(err)) {
+				continue
+			}
+			return err
+		}
+		err = s3Agent.DeleteBucketTopicNotification(bucket, n.Name)
+		if err != nil {
+			return err
+		}
+		logger.Infof("bucket notification %q detached from object bucket claim %q", n.Name, key)
+	}
+
+	return nil
+}
+
+// claimInObjectStore returns whether the bucket of the claim is provisioned in the object store
+func (r *ReconcileBucketNotification) claimInObjectStore(claim *bktv1alpha1.ObjectBucketClaim, storeName, storeNamespace string) (bool, error) {
+	sc, err := r.context.Clientset.StorageV1().StorageClasses().Get(claim.Spec.StorageClassName, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get storage class %q of object bucket claim %q", claim.Spec.StorageClassName, claimKey(claim))
+	}
+	return sc.Parameters["objectStoreName"] == storeName && sc.Parameters["objectStoreNamespace"] == storeNamespace, nil
+}
+
+// claimBucket returns the name of the bucket of the claim and an s3 agent authenticated as its owner
+func (r *ReconcileBucketNotification) claimBucket(claim *bktv1alpha1.ObjectBucketClaim) (*object.S3Agent, string, error) {
+	ob := &bktv1alpha1.ObjectBucket{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: claim.Spec.ObjectBucketName}, ob)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to get object bucket %q of object bucket claim %q", claim.Spec.ObjectBucketName, claimKey(claim))
+	}
+	if ob.Spec.Connection == nil || ob.Spec.Endpoint == nil {
+		return nil, "", errors.Errorf("object bucket %q has no endpoint", ob.Name)
+	}
+
+	secret, err := r.context.Clientset.CoreV1().Secrets(claim.Namespace).Get(claim.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to get secret of object bucket claim %q", claimKey(claim))
+	}
+
+	endpoint := fmt.Sprintf("%s:%d", ob.Spec.Endpoint.BucketHost, ob.Spec.Endpoint.BucketPort)
+	s3Agent, err := object.NewS3Agent(string(secret.Data[accessKeyIDKey]), string(secret.Data[secretAccessKeyKey]), endpoint, false)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to initialize s3 connection to bucket of object bucket claim %q", claimKey(claim))
+	}
+	return s3Agent, ob.Spec.Endpoint.BucketName, nil
+}
+
+func claimKey(claim *bktv1alpha1.ObjectBucketClaim) string {
+	return fmt.Sprintf("%s/%s", claim.Namespace, claim.Name)
+}
+
+func sortedClaims(claims map[string]bool) []string {
+	sorted := []string{}
+	for claim := range claims {
+		sorted = append(sorted, claim)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
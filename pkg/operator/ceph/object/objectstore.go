@@ -73,6 +73,7 @@ type zoneGroupType struct {
 }
 
 type zoneType struct {
+	ID        string   `json:"id"`
 	Name      string   `json:"name"`
 	Endpoints []string `json:"endpoints"`
 }
@@ -585,6 +586,7 @@ func deletePools(context *Context, spec cephv1.ObjectStoreSpec, lastStore bool)
 			logger.Warningf("failed to delete pool %q. %v", name, err)
 		}
 	}
+	deletePlacementPools(context, spec)
 
 	// Delete erasure code profile if any
 	erasureCodes, err := ceph.ListErasureCodeProfiles(context.Context, context.clusterInfo)
@@ -0,0 +1,264 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	ceph "github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+const (
+	// DefaultPlacement is the placement target the buckets are created in when none is requested
+	DefaultPlacement = "default-placement"
+	// StandardStorageClass is the storage class of the objects when none is requested
+	StandardStorageClass = "STANDARD"
+
+	indexPoolName     = "rgw.buckets.index"
+	dataExtraPoolName = "rgw.buckets.non-ec"
+)
+
+// placementPool is a data pool of a storage class of a placement target
+type placementPool struct {
+	placement    string
+	storageClass string
+	// name of the pool without the object store prefix
+	name string
+	spec cephv1.PoolSpec
+}
+
+type zonePlacementType struct {
+	Key string `json:"key"`
+	Val struct {
+		IndexPool      string `json:"index_pool"`
+		DataExtraPool  string `json:"data_extra_pool"`
+		StorageClasses map[string]struct {
+			DataPool string `json:"data_pool"`
+		} `json:"storage_classes"`
+	} `json:"val"`
+}
+
+type zonePlacementConfigType struct {
+	PlacementPools []zonePlacementType `json:"placement_pools"`
+}
+
+type zoneGroupPlacementType struct {
+	Name           string   `json:"name"`
+	StorageClasses []string `json:"storage_classes"`
+}
+
+type zoneGroupPlacementConfigType struct {
+	PlacementTargets []zoneGroupPlacementType `json:"placement_targets"`
+}
+
+// placementPoolName returns the name of the data pool of a storage class, without the object store prefix
+func placementPoolName(placement, storageClass string) string {
+	name := dataPoolName
+	if placement != DefaultPlacement {
+		name = fmt.Sprintf("rgw.%s.data", placement)
+	}
+	if storageClass != StandardStorageClass {
+		name = fmt.Sprintf("%s.%s", name, strings.ToLower(storageClass))
+	}
+	return name
+}
+
+// placementPools returns the data pools of the placement targets and storage classes, besides the data pool
+// of the STANDARD storage class of the default placement
+func placementPools(spec cephv1.ObjectStoreSpec) []placementPool {
+	pools := []placementPool{}
+	for _, storageClass := range spec.StorageClasses {
+		pools = append(pools, placementPool{DefaultPlacement, storageClass.Name, placementPoolName(DefaultPlacement, storageClass.Name), storageClass.DataPool})
+	}
+	for _, placement := range spec.PlacementTargets {
+		pools = append(pools, placementPool{placement.Name, StandardStorageClass, placementPoolName(placement.Name, StandardStorageClass), placement.DataPool})
+		for _, storageClass := range placement.StorageClasses {
+			pools = append(pools, placementPool{placement.Name, storageClass.Name, placementPoolName(placement.Name, storageClass.Name), storageClass.DataPool})
+		}
+	}
+	return pools
+}
+
+// validatePlacements validates the names of the placement targets and storage classes
+func validatePlacements(spec cephv1.ObjectStoreSpec) error {
+	if spec.IsMultisite() && (len(spec.StorageClasses) > 0 || len(spec.PlacementTargets) > 0) {
+		return errors.New("placement targets and storage classes are not supported on an object store in a multisite zone")
+	}
+	if err := validateStorageClasses(spec.StorageClasses); err != nil {
+		return errors.Wrapf(err, "invalid storage classes of placement %q", DefaultPlacement)
+	}
+
+	names := map[string]bool{DefaultPlacement: true}
+	for _, placement := range spec.PlacementTargets {
+		if placement.Name == "" {
+			return errors.New("missing placement target name")
+		}
+		if names[placement.Name] {
+			return errors.Errorf("duplicate placement target %q", placement.Name)
+		}
+		names[placement.Name] = true
+		if emptyPool(placement.DataPool) {
+			return errors.Errorf("missing data pool of placement target %q", placement.Name)
+		}
+		if err := validateStorageClasses(placement.StorageClasses); err != nil {
+			return errors.Wrapf(err, "invalid storage classes of placement %q", placement.Name)
+		}
+	}
+	return nil
+}
+
+func validateStorageClasses(storageClasses []cephv1.ObjectStorageClassSpec) error {
+	names := map[string]bool{StandardStorageClass: true}
+	for _, storageClass := range storageClasses {
+		if storageClass.Name == "" {
+			return errors.New("missing storage class name")
+		}
+		if names[storageClass.Name] {
+			return errors.Errorf("duplicate storage class %q", storageClass.Name)
+		}
+		names[storageClass.Name] = true
+		if emptyPool(storageClass.DataPool) {
+			return errors.Errorf("missing data pool of storage class %q", storageClass.Name)
+		}
+	}
+	return nil
+}
+
+// createPlacementPools creates the data pools of the placement targets and storage classes
+func createPlacementPools(context *Context, spec cephv1.ObjectStoreSpec) error {
+	for _, pool := range placementPools(spec) {
+		ecProfileName := ""
+		if pool.spec.IsErasureCoded() {
+			// each erasure coded pool gets its own profile named after the pool
+			ecProfileName = ceph.GetErasureCodeProfileForPool(poolName(context.Name, pool.name))
+			if err := ceph.CreateErasureCodeProfile(context.Context, context.clusterInfo, ecProfileName, pool.spec); err != nil {
+				return errors.Wrapf(err, "failed to create erasure code profile for storage class %q of placement %q", pool.storageClass, pool.placement)
+			}
+		}
+		if err := createSimilarPools(context, []string{pool.name}, pool.spec, ceph.DefaultPGCount, ecProfileName); err != nil {
+			return errors.Wrapf(err, "failed to create data pool of storage class %q of placement %q", pool.storageClass, pool.placement)
+		}
+	}
+	return nil
+}
+
+// deletePlacementPools deletes the data pools of the placement targets and storage classes
+func deletePlacementPools(context *Context, spec cephv1.ObjectStoreSpec) {
+	for _, pool := range placementPools(spec) {
+		name := poolName(context.Name, pool.name)
+		if err := ceph.DeletePool(context.Context, context.clusterInfo, name); err != nil {
+			logger.Warningf("failed to delete pool %q. %v", name, err)
+		}
+		if pool.spec.IsErasureCoded() {
+			ecProfileName := ceph.GetErasureCodeProfileForPool(name)
+			if err := ceph.DeleteErasureCodeProfile(context.Context, context.clusterInfo, ecProfileName); err != nil {
+				logger.Warningf("failed to delete erasure code profile %q. %v", ecProfileName, err)
+			}
+		}
+	}
+}
+
+// setPlacementTargets registers the placement targets and storage classes in the zonegroup and zone of the object store,
+// the period is only committed if the configuration changed
+func setPlacementTargets(context *Context, spec cephv1.ObjectStoreSpec) error {
+	pools := placementPools(spec)
+	if len(pools) == 0 {
+		return nil
+	}
+
+	output, err := runAdminCommand(context, "zonegroup", "get")
+	if err != nil {
+		return errors.Wrapf(err, "failed to get zonegroup %q", context.ZoneGroup)
+	}
+	var zoneGroup zoneGroupPlacementConfigType
+	if err := json.Unmarshal([]byte(output), &zoneGroup); err != nil {
+		return errors.Wrapf(err, "failed to parse zonegroup %q", context.ZoneGroup)
+	}
+	output, err = runAdminCommand(context, "zone", "get")
+	if err != nil {
+		return errors.Wrapf(err, "failed to get zone %q", context.Zone)
+	}
+	var zone zonePlacementConfigType
+	if err := json.Unmarshal([]byte(output), &zone); err != nil {
+		return errors.Wrapf(err, "failed to parse zone %q", context.Zone)
+	}
+
+	updated := false
+	for _, pool := range pools {
+		if !zoneGroupHasStorageClass(zoneGroup, pool.placement, pool.storageClass) {
+			args := []string{"zonegroup", "placement", "add", "--placement-id", pool.placement}
+			if pool.storageClass != StandardStorageClass {
+				args = append(args, "--storage-class", pool.storageClass)
+			}
+			if _, err := runAdminCommand(context, args...); err != nil {
+				return errors.Wrapf(err, "failed to add storage class %q of placement %q to zonegroup %q", pool.storageClass, pool.placement, context.ZoneGroup)
+			}
+			updated = true
+		}
+
+		dataPool := poolName(context.Name, pool.name)
+		if zoneStorageClassPool(zone, pool.placement, pool.storageClass) != dataPool {
+			args := []string{"zone", "placement", "add", "--placement-id", pool.placement, "--data-pool", dataPool}
+			if pool.storageClass != StandardStorageClass {
+				args = append(args, "--storage-class", pool.storageClass)
+			} else {
+				// the buckets of all the placement targets share the index and non-ec pools of the object store
+				args = append(args, "--index-pool", poolName(context.Name, indexPoolName), "--data-extra-pool", poolName(context.Name, dataExtraPoolName))
+			}
+			if _, err := runAdminCommand(context, args...); err != nil {
+				return errors.Wrapf(err, "failed to add storage class %q of placement %q to zone %q", pool.storageClass, pool.placement, context.Zone)
+			}
+			updated = true
+		}
+	}
+
+	if updated {
+		if _, err := runAdminCommand(context, "period", "update", "--commit"); err != nil {
+			return errors.Wrap(err, "failed to update period")
+		}
+		logger.Infof("placement targets of object store %q updated", context.Name)
+	}
+	return nil
+}
+
+func zoneGroupHasStorageClass(zoneGroup zoneGroupPlacementConfigType, placement, storageClass string) bool {
+	for _, target := range zoneGroup.PlacementTargets {
+		if target.Name != placement {
+			continue
+		}
+		for _, name := range target.StorageClasses {
+			if name == storageClass {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// zoneStorageClassPool returns the data pool of the storage class in the zone, empty if not registered
+func zoneStorageClassPool(zone zonePlacementConfigType, placement, storageClass string) string {
+	for _, target := range zone.PlacementPools {
+		if target.Key == placement {
+			return target.Val.StorageClasses[storageClass].DataPool
+		}
+	}
+	return ""
+}
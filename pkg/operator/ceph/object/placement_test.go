@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func placementSpec() cephv1.ObjectStoreSpec {
+	replicated := cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}}
+	erasureCoded := cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}}
+	return cephv1.ObjectStoreSpec{
+		DataPool:       replicated,
+		StorageClasses: []cephv1.ObjectStorageClassSpec{{Name: "COLD", DataPool: erasureCoded}},
+		PlacementTargets: []cephv1.PlacementTargetSpec{
+			{Name: "fast", DataPool: replicated, StorageClasses: []cephv1.ObjectStorageClassSpec{{Name: "ARCHIVE", DataPool: erasureCoded}}},
+		},
+	}
+}
+
+func TestPlacementPools(t *testing.T) {
+	assert.Equal(t, "rgw.buckets.data", placementPoolName(DefaultPlacement, StandardStorageClass))
+	assert.Equal(t, "rgw.buckets.data.cold", placementPoolName(DefaultPlacement, "COLD"))
+	assert.Equal(t, "rgw.fast.data", placementPoolName("fast", StandardStorageClass))
+	assert.Equal(t, "rgw.fast.data.archive", placementPoolName("fast", "ARCHIVE"))
+
+	assert.Equal(t, 0, len(placementPools(cephv1.ObjectStoreSpec{})))
+	pools := placementPools(placementSpec())
+	assert.Equal(t, 3, len(pools))
+	assert.Equal(t, placementPool{DefaultPlacement, "COLD", "rgw.buckets.data.cold", placementSpec().StorageClasses[0].DataPool}, pools[0])
+	assert.Equal(t, "fast", pools[1].placement)
+	assert.Equal(t, StandardStorageClass, pools[1].storageClass)
+	assert.Equal(t, "rgw.fast.data.archive", pools[2].name)
+}
+
+func TestValidatePlacements(t *testing.T) {
+	assert.NoError(t, validatePlacements(cephv1.ObjectStoreSpec{}))
+	assert.NoError(t, validatePlacements(placementSpec()))
+
+	spec := placementSpec()
+	spec.Zone.Name = "zone-a"
+	assert.Error(t, validatePlacements(spec))
+
+	spec = placementSpec()
+	spec.PlacementTargets[0].Name = DefaultPlacement
+	assert.Error(t, validatePlacements(spec))
+
+	spec = placementSpec()
+	spec.PlacementTargets = append(spec.PlacementTargets, spec.PlacementTargets[0])
+	assert.Error(t, validatePlacements(spec))
+
+	spec = placementSpec()
+	spec.PlacementTargets[0].DataPool = cephv1.PoolSpec{}
+	assert.Error(t, validatePlacements(spec))
+
+	spec = placementSpec()
+	spec.StorageClasses[0].Name = StandardStorageClass
+	assert.Error(t, validatePlacements(spec))
+
+	spec = placementSpec()
+	spec.PlacementTargets[0].StorageClasses[0].Name = ""
+	assert.Error(t, validatePlacements(spec))
+}
+
+func TestSetPlacementTargets(t *testing.T) {
+	// the zone only has the default placement
+	zoneGroupJSON := `{"name":"my-store","placement_targets":[{"name":"default-placement","tags":[],"storage_classes":["STANDARD"]}],"default_placement":"default-placement"}`
+	zoneJSON := `{"name":"my-store","placement_pools":[{"key":"default-placement","val":{"index_pool":"my-store.rgw.buckets.index","storage_classes":{"STANDARD":{"data_pool":"my-store.rgw.buckets.data"}},"data_extra_pool":"my-store.rgw.buckets.non-ec","index_type":0}}]}`
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "zonegroup" && args[1] == "get":
+				return zoneGroupJSON, nil
+			case args[0] == "zone" && args[1] == "get":
+				return zoneJSON, nil
+			}
+			commands = append(commands, strings.Join(args, " "))
+			return "", nil
+		},
+	}
+	objContext := NewContext(&clusterd.Context{Executor: executor}, client.AdminClusterInfo("mycluster"), "my-store")
+
+	// nothing to do without placement
+	assert.NoError(t, setPlacementTargets(objContext, cephv1.ObjectStoreSpec{}))
+	assert.Equal(t, 0, len(commands))
+
+	assert.NoError(t, setPlacementTargets(objContext, placementSpec()))
+	assert.Equal(t, 7, len(commands))
+	assert.True(t, strings.HasPrefix(commands[0], "zonegroup placement add --placement-id default-placement --storage-class COLD"))
+	assert.True(t, strings.HasPrefix(commands[1], "zone placement add --placement-id default-placement --data-pool my-store.rgw.buckets.data.cold --storage-class COLD"))
+	assert.True(t, strings.HasPrefix(commands[2], "zonegroup placement add --placement-id fast"))
+	assert.True(t, strings.HasPrefix(commands[3], "zone placement add --placement-id fast --data-pool my-store.rgw.fast.data --index-pool my-store.rgw.buckets.index --data-extra-pool my-store.rgw.buckets.non-ec"))
+	assert.True(t, strings.HasPrefix(commands[4], "zonegroup placement add --placement-id fast --storage-class ARCHIVE"))
+	assert.True(t, strings.HasPrefix(commands[5], "zone placement add --placement-id fast --data-pool my-store.rgw.fast.data.archive --storage-class ARCHIVE"))
+	assert.True(t, strings.HasPrefix(commands[6], "period update --commit"))
+
+	// the placement targets are already registered, the period is not committed
+	zoneGroupJSON = `{"placement_targets":[{"name":"default-placement","storage_classes":["COLD","STANDARD"]},{"name":"fast","storage_classes":["ARCHIVE","STANDARD"]}]}`
+	zoneJSON = `{"placement_pools":[
+		{"key":"default-placement","val":{"storage_classes":{"STANDARD":{"data_pool":"my-store.rgw.buckets.data"},"COLD":{"data_pool":"my-store.rgw.buckets.data.cold"}}}},
+		{"key":"fast","val":{"storage_classes":{"STANDARD":{"data_pool":"my-store.rgw.fast.data"},"ARCHIVE":{"data_pool":"my-store.rgw.fast.data.archive"}}}}]}`
+	commands = []string{}
+	assert.NoError(t, setPlacementTargets(objContext, placementSpec()))
+	assert.Equal(t, 0, len(commands))
+}
@@ -75,6 +75,9 @@ const (
 	PutObjectVersionAcl            action = "s3:PutObjectVersionAcl"
 	PutReplicationConfiguration    action = "s3:PutReplicationConfiguration"
 	RestoreObject                  action = "s3:RestoreObject"
+
+	// AssumeRoleWithWebIdentity is the action of the role trust policies allowing the OIDC users to assume the role
+	AssumeRoleWithWebIdentity action = "sts:AssumeRoleWithWebIdentity"
 )
 
 // AllowedActions is a lenient default list of actions
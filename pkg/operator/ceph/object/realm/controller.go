@@ -203,9 +203,14 @@ func (r *ReconcileObjectRealm) reconcile(request reconcile.Request) (reconcile.R
 	// Set Ready status, we are done reconciling
 	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
 
-	// Return and do not requeue
+	// Summarize the sync status of the zones of the realm
+	if err := r.refreshZonesStatus(cephObjectRealm); err != nil {
+		logger.Warningf("failed to refresh the zones status of realm %q. %v", cephObjectRealm.Name, err)
+	}
+
+	// Requeue to refresh the zones status
 	logger.Debug("realm done reconciling")
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: zonesStatusInterval}, nil
 }
 
 func (r *ReconcileObjectRealm) pullCephRealm(realm *cephv1.CephObjectRealm) (reconcile.Result, error) {
@@ -294,6 +299,11 @@ func (r *ReconcileObjectRealm) createRealmKeys(realm *cephv1.CephObjectRealm) (r
 
 	k8sutil.SetOwnerRef(&secret.ObjectMeta, ownerRef)
 	if _, err = r.context.Clientset.CoreV1().Secrets(realm.Namespace).Create(secret); err != nil {
+		// the keys of the realm must never change once created
+		if kerrors.IsAlreadyExists(err) {
+			logger.Debugf("secrets for keys already exist for realm %q", realm.Name)
+			return reconcile.Result{}, nil
+		}
 		return reconcile.Result{}, errors.Wrap(err, "failed to save rgw secrets")
 	}
 	logger.Infof("secrets for keys have been created for realm %q", realm.Name)
@@ -329,7 +339,7 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 		return
 	}
 	if objectRealm.Status == nil {
-		objectRealm.Status = &cephv1.Status{}
+		objectRealm.Status = &cephv1.ObjectRealmStatus{}
 	}
 
 	objectRealm.Status.Phase = status
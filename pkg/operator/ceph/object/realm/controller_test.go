@@ -247,3 +247,48 @@ func getObjectRealmAndReconcileObjectRealm(t *testing.T) (*ReconcileObjectRealm,
 
 	return r, objectRealm
 }
+
+func TestRealmZonesStatus(t *testing.T) {
+	zoneGroups := []cephv1.CephObjectZoneGroup{
+		{ObjectMeta: metav1.ObjectMeta{Name: "zonegroup-a"}, Spec: cephv1.ObjectZoneGroupSpec{Realm: name}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "zonegroup-other"}, Spec: cephv1.ObjectZoneGroupSpec{Realm: "other"}},
+	}
+	zone := func(zoneName, zoneGroup string, syncStatus *cephv1.ObjectZoneSyncStatus) cephv1.CephObjectZone {
+		return cephv1.CephObjectZone{
+			ObjectMeta: metav1.ObjectMeta{Name: zoneName},
+			Spec:       cephv1.ObjectZoneSpec{ZoneGroup: zoneGroup},
+			Status:     &cephv1.ObjectZoneStatus{SyncStatus: syncStatus},
+		}
+	}
+	caughtUp := cephv1.ObjectSyncProgress{CaughtUp: true}
+	zones := []cephv1.CephObjectZone{
+		zone("zone-b", "zonegroup-a", &cephv1.ObjectZoneSyncStatus{
+			Metadata: cephv1.ObjectSyncProgress{ShardsBehind: 1, LagSeconds: 30},
+			DataSources: []cephv1.ObjectDataSyncSource{
+				{Zone: "zone-a", ObjectSyncProgress: cephv1.ObjectSyncProgress{ShardsBehind: 2, LagSeconds: 120}},
+			},
+		}),
+		zone("zone-a", "zonegroup-a", &cephv1.ObjectZoneSyncStatus{
+			Metadata:    caughtUp,
+			DataSources: []cephv1.ObjectDataSyncSource{{Zone: "zone-b", ObjectSyncProgress: caughtUp}},
+		}),
+		// no sync status reported yet
+		zone("zone-c", "zonegroup-a", nil),
+		// another realm
+		zone("zone-d", "zonegroup-other", &cephv1.ObjectZoneSyncStatus{Metadata: caughtUp}),
+	}
+
+	zonesStatus := realmZonesStatus(name, zoneGroups, zones)
+	assert.Equal(t, []cephv1.ObjectRealmZoneStatus{
+		{Name: "zone-a", ZoneGroup: "zonegroup-a", CaughtUp: true},
+		{Name: "zone-b", ZoneGroup: "zonegroup-a", CaughtUp: false, LagSeconds: 120},
+	}, zonesStatus)
+
+	condition := zonesCondition(zonesStatus)
+	assert.Equal(t, cephv1.ConditionZonesSynced, condition.Type)
+	assert.Equal(t, v1.ConditionFalse, condition.Status)
+	assert.Equal(t, "zones behind: zone-b (120s)", condition.Message)
+
+	assert.Equal(t, v1.ConditionTrue, zonesCondition(zonesStatus[:1]).Status)
+	assert.Equal(t, v1.ConditionUnknown, zonesCondition([]cephv1.ObjectRealmZoneStatus{}).Status)
+}
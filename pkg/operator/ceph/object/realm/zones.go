@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// zonesStatusInterval is how often the sync status of the zones is summarized on the realm
+var zonesStatusInterval = time.Minute
+
+// refreshZonesStatus summarizes on the realm the sync status reported by its zones managed in this cluster
+func (r *ReconcileObjectRealm) refreshZonesStatus(realm *cephv1.CephObjectRealm) error {
+	zoneGroups := &cephv1.CephObjectZoneGroupList{}
+	if err := r.client.List(context.TODO(), zoneGroups, client.InNamespace(realm.Namespace)); err != nil {
+		return errors.Wrap(err, "failed to list object zone groups")
+	}
+	zones := &cephv1.CephObjectZoneList{}
+	if err := r.client.List(context.TODO(), zones, client.InNamespace(realm.Namespace)); err != nil {
+		return errors.Wrap(err, "failed to list object zones")
+	}
+
+	zonesStatus := realmZonesStatus(realm.Name, zoneGroups.Items, zones.Items)
+	updateZonesStatus(r.client, types.NamespacedName{Name: realm.Name, Namespace: realm.Namespace}, zonesStatus)
+	return nil
+}
+
+// realmZonesStatus returns the sync summary of the zones of the realm that reported their sync status
+func realmZonesStatus(realmName string, zoneGroups []cephv1.CephObjectZoneGroup, zones []cephv1.CephObjectZone) []cephv1.ObjectRealmZoneStatus {
+	realmZoneGroups := map[string]bool{}
+	for _, zoneGroup := range zoneGroups {
+		if zoneGroup.Spec.Realm == realmName {
+			realmZoneGroups[zoneGroup.Name] = true
+		}
+	}
+
+	zonesStatus := []cephv1.ObjectRealmZoneStatus{}
+	for _, zone := range zones {
+		if !realmZoneGroups[zone.Spec.ZoneGroup] || zone.Status == nil || zone.Status.SyncStatus == nil {
+			continue
+		}
+		syncStatus := zone.Status.SyncStatus
+		zoneStatus := cephv1.ObjectRealmZoneStatus{
+			Name:       zone.Name,
+			ZoneGroup:  zone.Spec.ZoneGroup,
+			CaughtUp:   syncStatus.Metadata.CaughtUp,
+			LagSeconds: syncStatus.Metadata.LagSeconds,
+		}
+		for _, source := range syncStatus.DataSources {
+			zoneStatus.CaughtUp = zoneStatus.CaughtUp && source.CaughtUp && source.BucketsBehind == 0
+			if source.LagSeconds > zoneStatus.LagSeconds {
+				zoneStatus.LagSeconds = source.LagSeconds
+			}
+		}
+		zonesStatus = append(zonesStatus, zoneStatus)
+	}
+	sort.Slice(zonesStatus, func(i, j int) bool { return zonesStatus[i].Name < zonesStatus[j].Name })
+	return zonesStatus
+}
+
+// zonesCondition returns the condition of the realm reporting whether all its zones are in sync
+func zonesCondition(zonesStatus []cephv1.ObjectRealmZoneStatus) cephv1.Condition {
+	if len(zonesStatus) == 0 {
+		return cephv1.Condition{Type: cephv1.ConditionZonesSynced, Status: v1.ConditionUnknown, Reason: "NoZoneStatus", Message: "no zone of the realm reported its sync status"}
+	}
+	behind := []string{}
+	for _, zone := range zonesStatus {
+		if !zone.CaughtUp {
+			behind = append(behind, fmt.Sprintf("%s (%ds)", zone.Name, zone.LagSeconds))
+		}
+	}
+	if len(behind) > 0 {
+		return cephv1.Condition{Type: cephv1.ConditionZonesSynced, Status: v1.ConditionFalse, Reason: "Behind", Message: fmt.Sprintf("zones behind: %s", strings.Join(behind, ", "))}
+	}
+	return cephv1.Condition{Type: cephv1.ConditionZonesSynced, Status: v1.ConditionTrue, Reason: "CaughtUp"}
+}
+
+// updateZonesStatus updates the zones summary and the sync condition of a realm
+func updateZonesStatus(client client.Client, name types.NamespacedName, zonesStatus []cephv1.ObjectRealmZoneStatus) {
+	objectRealm := &cephv1.CephObjectRealm{}
+	if err := client.Get(context.TODO(), name, objectRealm); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephObjectRealm resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve object realm %q to update its zones status. %v", name, err)
+		return
+	}
+	if objectRealm.Status == nil {
+		objectRealm.Status = &cephv1.ObjectRealmStatus{}
+	}
+
+	objectRealm.Status.Zones = zonesStatus
+	opcontroller.SetCondition(&objectRealm.Status.Conditions, zonesCondition(zonesStatus))
+	if err := opcontroller.UpdateStatus(client, objectRealm); err != nil {
+		logger.Errorf("failed to update the zones status of object realm %q. %v", name, err)
+		return
+	}
+	logger.Debugf("object realm %q zones status updated", name)
+}
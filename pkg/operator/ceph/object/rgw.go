@@ -18,8 +18,12 @@ limitations under the License.
 package object
 
 import (
+	"crypto/sha1" // #nosec G505 the thumbprints of the OIDC providers are SHA-1
+	"encoding/hex"
 	"fmt"
+	"net/url"
 	"reflect"
+	"strings"
 
 	"github.com/banzaicloud/k8s-objectmatcher/patch"
 	"github.com/pkg/errors"
@@ -31,9 +35,11 @@ import (
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/pool"
 	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -48,6 +54,8 @@ type clusterConfig struct {
 	DataPathMap *config.DataPathMap
 	client      client.Client
 	scheme      *runtime.Scheme
+	// gatewayGroup is the name of the gateway group the config describes, empty for the gateway of the store
+	gatewayGroup string
 }
 
 type rgwConfig struct {
@@ -60,6 +68,8 @@ type rgwConfig struct {
 
 const (
 	oldRgwKeyName = "client.radosgw.gateway"
+	// the label of the gateways of a gateway group
+	gatewayGroupLabel = "rook_object_store_gateway_group"
 )
 
 var updateDeploymentAndWait = mon.UpdateCephDeploymentAndWait
@@ -71,6 +81,19 @@ func (c *clusterConfig) createOrUpdateStore(realmName, zoneGroupName, zoneName s
 		return errors.Wrap(err, "failed to start rgw pods")
 	}
 
+	for _, group := range c.store.Spec.GatewayGroups {
+		groupConfig := c.forGatewayGroup(group)
+		if _, err := groupConfig.reconcileService(groupConfig.store); err != nil {
+			return errors.Wrapf(err, "failed to reconcile the service of gateway group %q", group.Name)
+		}
+		if err := groupConfig.startRGWPods(realmName, zoneGroupName, zoneName); err != nil {
+			return errors.Wrapf(err, "failed to start the rgw pods of gateway group %q", group.Name)
+		}
+	}
+	if err := c.deleteRemovedGatewayGroups(); err != nil {
+		return errors.Wrap(err, "failed to delete the removed gateway groups")
+	}
+
 	objContext := NewContext(c.context, c.clusterInfo, c.store.Namespace)
 	err := enableRGWDashboard(objContext)
 	if err != nil {
@@ -103,16 +126,20 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 	}
 	c.ownerRef = ref
 
+	if err := c.generateSTSKey(); err != nil {
+		return errors.Wrap(err, "failed to create the sts key")
+	}
+
 	// start a new deployment and scale up
 	desiredRgwInstances := int(c.store.Spec.Gateway.Instances)
 	for i := 0; i < desiredRgwInstances; i++ {
 		var err error
 
 		daemonLetterID := k8sutil.IndexToName(i)
-		// Each rgw is id'ed by <store_name>-<letterID>
-		daemonName := fmt.Sprintf("%s-%s", c.store.Name, daemonLetterID)
-		// resource name is rook-ceph-rgw-<store_name>-<daemon_name>
-		resourceName := fmt.Sprintf("%s-%s-%s", AppName, c.store.Name, daemonLetterID)
+		// Each rgw is id'ed by <store_name>-<letterID>, or <store_name>-<group_name>-<letterID> in a gateway group
+		daemonName := fmt.Sprintf("%s-%s", c.gatewayName(), daemonLetterID)
+		// resource name is rook-ceph-rgw-<daemon_name>
+		resourceName := fmt.Sprintf("%s-%s", AppName, daemonName)
 
 		rgwConfig := &rgwConfig{
 			ResourceName: resourceName,
@@ -143,6 +170,12 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 			}
 		}
 
+		// The credentials of the identity services may change at any time
+		err = c.setAuthFlagsMonConfigStore(rgwConfig.ResourceName)
+		if err != nil {
+			return errors.Wrap(err, "failed to set rgw auth config options")
+		}
+
 		// Create deployment
 		deployment, err := c.createDeployment(rgwConfig)
 		if err != nil {
@@ -180,9 +213,9 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 	}
 
 	// scale down scenario
-	deps, err := k8sutil.GetDeployments(c.context.Clientset, c.store.Namespace, c.storeLabelSelector())
+	deps, err := k8sutil.GetDeployments(c.context.Clientset, c.store.Namespace, c.gatewayLabelSelector())
 	if err != nil {
-		logger.Warningf("could not get deployments for object store %q (matching label selector %q). %v", c.store.Name, c.storeLabelSelector(), err)
+		logger.Warningf("could not get deployments for object store %q (matching label selector %q). %v", c.store.Name, c.gatewayLabelSelector(), err)
 	}
 
 	currentRgwInstances := int(len(deps.Items))
@@ -191,7 +224,7 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 		diffCount := currentRgwInstances - desiredRgwInstances
 		for i := 0; i < diffCount; {
 			depIDToRemove := currentRgwInstances - 1
-			depNameToRemove := fmt.Sprintf("%s-%s-%s", AppName, c.store.Name, k8sutil.IndexToName(depIDToRemove))
+			depNameToRemove := fmt.Sprintf("%s-%s-%s", AppName, c.gatewayName(), k8sutil.IndexToName(depIDToRemove))
 			if err := k8sutil.DeleteDeployment(c.context.Clientset, c.store.Namespace, depNameToRemove); err != nil {
 				logger.Warningf("error during deletion of deployment %q resource. %v", depNameToRemove, err)
 			}
@@ -211,9 +244,9 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 			}
 		}
 		// verify scale down was successful
-		deps, err = k8sutil.GetDeployments(c.context.Clientset, c.store.Namespace, c.storeLabelSelector())
+		deps, err = k8sutil.GetDeployments(c.context.Clientset, c.store.Namespace, c.gatewayLabelSelector())
 		if err != nil {
-			logger.Warningf("could not get deployments for object store %q (matching label selector %q). %v", c.store.Name, c.storeLabelSelector(), err)
+			logger.Warningf("could not get deployments for object store %q (matching label selector %q). %v", c.store.Name, c.gatewayLabelSelector(), err)
 		}
 		currentRgwInstances = len(deps.Items)
 		if currentRgwInstances == desiredRgwInstances {
@@ -221,7 +254,9 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 		}
 	}
 
-	c.deleteLegacyDaemons()
+	if c.gatewayGroup == "" {
+		c.deleteLegacyDaemons()
+	}
 	return nil
 }
 
@@ -284,6 +319,15 @@ func (c *clusterConfig) deleteStore() error {
 				return err
 			}
 		}
+		for _, group := range c.store.Spec.GatewayGroups {
+			groupConfig := c.forGatewayGroup(group)
+			for i := 0; i < int(group.Instances); i++ {
+				depNameToRemove := fmt.Sprintf("%s-%s-%s", AppName, groupConfig.gatewayName(), k8sutil.IndexToName(i))
+				if err := c.deleteRgwCephObjects(depNameToRemove); err != nil {
+					return err
+				}
+			}
+		}
 
 		// Delete the realm and pools
 		objContext, err := NewMultisiteContext(c.context, c.clusterInfo, c.store)
@@ -334,6 +378,75 @@ func (c *clusterConfig) storeLabelSelector() string {
 	return fmt.Sprintf("rook_object_store=%s", c.store.Name)
 }
 
+// gatewayName returns the name the gateways of the config are identified by, <store> for the gateway of the store
+// and <store>-<group> for a gateway group
+func (c *clusterConfig) gatewayName() string {
+	if c.gatewayGroup == "" {
+		return c.store.Name
+	}
+	return fmt.Sprintf("%s-%s", c.store.Name, c.gatewayGroup)
+}
+
+// gatewayLabelSelector selects the deployments of the gateways of the config
+func (c *clusterConfig) gatewayLabelSelector() string {
+	if c.gatewayGroup == "" {
+		return fmt.Sprintf("%s,!%s", c.storeLabelSelector(), gatewayGroupLabel)
+	}
+	return fmt.Sprintf("%s,%s=%s", c.storeLabelSelector(), gatewayGroupLabel, c.gatewayGroup)
+}
+
+// forGatewayGroup returns the config of the gateways of the group, the gateway spec of the store of the
+// returned config being the spec of the group
+func (c *clusterConfig) forGatewayGroup(group cephv1.GatewayGroupSpec) *clusterConfig {
+	groupConfig := *c
+	groupConfig.store = c.store.DeepCopy()
+	groupConfig.store.Spec.Gateway = group.GatewaySpec
+	groupConfig.gatewayGroup = group.Name
+	return &groupConfig
+}
+
+// deleteRemovedGatewayGroups deletes the deployments, keys and services of the gateway groups removed from the spec
+func (c *clusterConfig) deleteRemovedGatewayGroups() error {
+	selector := fmt.Sprintf("%s,%s", c.storeLabelSelector(), gatewayGroupLabel)
+	deps, err := k8sutil.GetDeployments(c.context.Clientset, c.store.Namespace, selector)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the gateway group deployments of object store %q", c.store.Name)
+	}
+
+	groups := map[string]bool{}
+	for _, group := range c.store.Spec.GatewayGroups {
+		groups[group.Name] = true
+	}
+	removed := map[string]bool{}
+	for _, d := range deps.Items {
+		group := d.Labels[gatewayGroupLabel]
+		if groups[group] {
+			continue
+		}
+		logger.Infof("deleting deployment %q of removed gateway group %q in object store %q", d.Name, group, c.store.Name)
+		if err := k8sutil.DeleteDeployment(c.context.Clientset, c.store.Namespace, d.Name); err != nil {
+			return errors.Wrapf(err, "failed to delete deployment %q", d.Name)
+		}
+		secretToRemove := fmt.Sprintf("%s-keyring", d.Name)
+		err = c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Delete(secretToRemove, &metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			logger.Warningf("failed to delete rgw secret %q. %v", secretToRemove, err)
+		}
+		if err := c.deleteRgwCephObjects(d.Name); err != nil {
+			logger.Warningf("%v", err)
+		}
+		removed[group] = true
+	}
+
+	for group := range removed {
+		serviceToRemove := instanceName(fmt.Sprintf("%s-%s", c.store.Name, group))
+		if err := k8sutil.DeleteService(c.context.Clientset, c.store.Namespace, serviceToRemove); err != nil {
+			return errors.Wrapf(err, "failed to delete service %q", serviceToRemove)
+		}
+	}
+	return nil
+}
+
 // Validate the object store arguments
 func (r *ReconcileCephObjectStore) validateStore(s *cephv1.CephObjectStore) error {
 	if s.Name == "" {
@@ -346,6 +459,12 @@ func (r *ReconcileCephObjectStore) validateStore(s *cephv1.CephObjectStore) erro
 	if securePort < 0 || securePort > 65535 {
 		return errors.Errorf("securePort value of %d must be between 0 and 65535", securePort)
 	}
+	if err := validateServiceType(s.Spec.Gateway.ServiceType); err != nil {
+		return err
+	}
+	if err := validateGatewayGroups(s.Spec.GatewayGroups); err != nil {
+		return err
+	}
 
 	// Validate the pool settings, but allow for empty pools specs in case they have already been created
 	// such as by the ceph mgr
@@ -359,6 +478,22 @@ func (r *ReconcileCephObjectStore) validateStore(s *cephv1.CephObjectStore) erro
 			return errors.Wrap(err, "invalid data pool spec")
 		}
 	}
+	if err := validatePlacements(s.Spec); err != nil {
+		return err
+	}
+	if err := validateAuth(s.Spec.Auth); err != nil {
+		return errors.Wrap(err, "invalid auth spec")
+	}
+	if s.Spec.Usage != nil {
+		if _, err := usageCollectionInterval(s.Spec.Usage); err != nil {
+			return err
+		}
+	}
+	for _, placementPool := range placementPools(s.Spec) {
+		if err := pool.ValidatePoolSpec(r.context, r.clusterInfo, &placementPool.spec); err != nil {
+			return errors.Wrapf(err, "invalid data pool spec of storage class %q of placement %q", placementPool.storageClass, placementPool.placement)
+		}
+	}
 
 	// Fail if we detected an external CephCluster CR and the list of endpoints is empty
 	if r.cephClusterSpec.External.Enable && r.clusterInfo.CephCred.Username != cephclient.AdminUsername {
@@ -371,7 +506,101 @@ func (r *ReconcileCephObjectStore) validateStore(s *cephv1.CephObjectStore) erro
 }
 
 func (c *clusterConfig) generateSecretName(id string) string {
-	return fmt.Sprintf("%s-%s-%s-keyring", AppName, c.store.Name, id)
+	return fmt.Sprintf("%s-%s-%s-keyring", AppName, c.gatewayName(), id)
+}
+
+// validateAuth checks the settings of the external identity services, the secrets are checked by the kubelet
+// when the gateways start
+func validateAuth(auth cephv1.ObjectStoreAuthSpec) error {
+	if keystone := auth.Keystone; keystone != nil {
+		if keystone.URL == "" {
+			return errors.New("missing keystone url")
+		}
+		if keystone.ServiceUserSecretName == "" {
+			return errors.New("missing keystone serviceUserSecretName")
+		}
+		if len(keystone.AcceptedRoles) == 0 {
+			return errors.New("missing keystone acceptedRoles")
+		}
+		switch keystone.ImplicitTenants {
+		case "", "true", "false", "s3", "swift":
+		default:
+			return errors.Errorf("invalid keystone implicitTenants %q, must be one of \"true\", \"false\", \"s3\" or \"swift\"", keystone.ImplicitTenants)
+		}
+		if keystone.TokenCacheSize != nil && *keystone.TokenCacheSize < 0 {
+			return errors.Errorf("keystone tokenCacheSize value of %d must not be negative", *keystone.TokenCacheSize)
+		}
+	}
+
+	if ldap := auth.LDAP; ldap != nil {
+		if ldap.URI == "" {
+			return errors.New("missing ldap uri")
+		}
+		if ldap.BindSecretName == "" {
+			return errors.New("missing ldap bindSecretName")
+		}
+		if ldap.SearchDN == "" {
+			return errors.New("missing ldap searchDN")
+		}
+	}
+
+	if sts := auth.STS; sts != nil {
+		if sts.Issuer != "" {
+			if u, err := url.Parse(sts.Issuer); err != nil || u.Scheme != "https" || u.Host == "" {
+				return errors.Errorf("invalid sts issuer %q, must be an https url", sts.Issuer)
+			}
+		}
+		for _, thumbprint := range sts.Thumbprints {
+			if _, err := hex.DecodeString(thumbprint); err != nil || len(thumbprint) != 2*sha1.Size {
+				return errors.Errorf("invalid sts thumbprint %q, must be a SHA-1 fingerprint in hexadecimal", thumbprint)
+			}
+		}
+	}
+	return nil
+}
+
+// validateGatewayGroups checks the gateway groups have unique names and valid ports
+func validateGatewayGroups(groups []cephv1.GatewayGroupSpec) error {
+	names := map[string]bool{}
+	for _, group := range groups {
+		if group.Name == "" {
+			return errors.New("missing gateway group name")
+		}
+		if errs := validation.IsDNS1123Label(group.Name); len(errs) > 0 {
+			return errors.Errorf("invalid gateway group name %q. %s", group.Name, strings.Join(errs, ", "))
+		}
+		if names[group.Name] {
+			return errors.Errorf("duplicate gateway group %q", group.Name)
+		}
+		names[group.Name] = true
+
+		for _, port := range []int32{group.Port, group.SecurePort} {
+			if port < 0 || port > 65535 {
+				return errors.Errorf("port value of %d of gateway group %q must be between 0 and 65535", port, group.Name)
+			}
+		}
+		if group.Port == 0 && group.SecurePort == 0 {
+			return errors.Errorf("missing port or securePort of gateway group %q", group.Name)
+		}
+		if group.SecurePort != 0 && group.SSLCertificateRef == "" {
+			return errors.Errorf("missing sslCertificateRef of gateway group %q with a securePort", group.Name)
+		}
+		if len(group.ExternalRgwEndpoints) > 0 {
+			return errors.Errorf("externalRgwEndpoints are not supported in gateway group %q", group.Name)
+		}
+		if err := validateServiceType(group.ServiceType); err != nil {
+			return errors.Wrapf(err, "invalid gateway group %q", group.Name)
+		}
+	}
+	return nil
+}
+
+func validateServiceType(serviceType v1.ServiceType) error {
+	switch serviceType {
+	case "", v1.ServiceTypeClusterIP, v1.ServiceTypeNodePort, v1.ServiceTypeLoadBalancer:
+		return nil
+	}
+	return errors.Errorf("invalid serviceType %q, must be one of %q, %q or %q", serviceType, v1.ServiceTypeClusterIP, v1.ServiceTypeNodePort, v1.ServiceTypeLoadBalancer)
 }
 
 func emptyPool(pool cephv1.PoolSpec) bool {
@@ -27,10 +27,13 @@ import (
 
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	"github.com/rook/rook/pkg/operator/ceph/config"
 	testop "github.com/rook/rook/pkg/operator/test"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	apps "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	fclient "k8s.io/client-go/kubernetes/fake"
@@ -63,7 +66,7 @@ func TestStartRGW(t *testing.T) {
 	r := &ReconcileCephObjectStore{client: cl, scheme: s}
 
 	// start a basic cluster
-	c := &clusterConfig{context, info, store, version, &cephv1.ClusterSpec{}, &metav1.OwnerReference{}, data, r.client, s}
+	c := &clusterConfig{context, info, store, version, &cephv1.ClusterSpec{}, &metav1.OwnerReference{}, data, r.client, s, ""}
 	err := c.startRGWPods(store.Name, store.Name, store.Name)
 	assert.Nil(t, err)
 
@@ -109,7 +112,7 @@ func TestCreateObjectStore(t *testing.T) {
 	object := []runtime.Object{&cephv1.CephObjectStore{}}
 	cl := fake.NewFakeClientWithScheme(s, object...)
 	r := &ReconcileCephObjectStore{client: cl, scheme: s}
-	c := &clusterConfig{context, info, store, "1.2.3.4", &cephv1.ClusterSpec{}, &metav1.OwnerReference{}, data, r.client, s}
+	c := &clusterConfig{context, info, store, "1.2.3.4", &cephv1.ClusterSpec{}, &metav1.OwnerReference{}, data, r.client, s, ""}
 	err := c.createOrUpdateStore(store.Name, store.Name, store.Name)
 	assert.Nil(t, err)
 }
@@ -137,9 +140,85 @@ func TestGenerateSecretName(t *testing.T) {
 		&metav1.OwnerReference{},
 		&config.DataPathMap{},
 		cl,
-		scheme.Scheme}
+		scheme.Scheme,
+		""}
 	secret := c.generateSecretName("a")
 	assert.Equal(t, "rook-ceph-rgw-default-a-keyring", secret)
+
+	// the keyrings of a gateway group are named after the group
+	secret = c.forGatewayGroup(cephv1.GatewayGroupSpec{Name: "external"}).generateSecretName("a")
+	assert.Equal(t, "rook-ceph-rgw-default-external-a-keyring", secret)
+}
+
+func TestGatewayGroups(t *testing.T) {
+	clientset := testop.New(t, 3)
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command string, outFileArg string, args ...string) (string, error) {
+			if args[0] == "config" {
+				return `{}`, nil
+			}
+			return `{"key":"mysecurekey"}`, nil
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			return `{"id":"test-id"}`, nil
+		},
+	}
+	updateDeploymentAndWait = func(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, deployment *apps.Deployment, daemonType, daemonName string, skipUpgradeChecks, continueUpgradeAfterChecksEvenIfNotHealthy bool) error {
+		return nil
+	}
+	defer func() { updateDeploymentAndWait = mon.UpdateCephDeploymentAndWait }()
+	context := &clusterd.Context{Clientset: clientset, Executor: executor}
+	info := clienttest.CreateTestClusterInfo(1)
+	data := config.NewStatelessDaemonDataPathMap(config.RgwType, "default", "mycluster", "/var/lib/rook/")
+	store := simpleStore()
+	store.Spec.Gateway.Instances = 1
+	store.Spec.GatewayGroups = []cephv1.GatewayGroupSpec{
+		{Name: "external", GatewaySpec: cephv1.GatewaySpec{Instances: 2, SecurePort: 443, SSLCertificateRef: "my-cert", ServiceType: "LoadBalancer"}},
+	}
+	s := scheme.Scheme
+	cl := fake.NewFakeClientWithScheme(s, []runtime.Object{&cephv1.CephObjectStore{}}...)
+	c := &clusterConfig{context, info, store, "v1.1.0", &cephv1.ClusterSpec{}, &metav1.OwnerReference{}, data, cl, s, ""}
+
+	// the gateways of the store and of the group are started
+	err := c.startRGWPods(store.Name, store.Name, store.Name)
+	assert.NoError(t, err)
+	group := c.forGatewayGroup(store.Spec.GatewayGroups[0])
+	_, err = group.reconcileService(group.store)
+	assert.NoError(t, err)
+	err = group.startRGWPods(store.Name, store.Name, store.Name)
+	assert.NoError(t, err)
+
+	deps, err := clientset.AppsV1().Deployments(store.Namespace).List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(deps.Items))
+	d, err := clientset.AppsV1().Deployments(store.Namespace).Get("rook-ceph-rgw-default-external-b", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "external", d.Labels[gatewayGroupLabel])
+	assert.Equal(t, "default", d.Labels["rook_object_store"])
+	assert.Equal(t, "default-external", d.Spec.Selector.MatchLabels["rgw"])
+	assert.Equal(t, "my-cert", d.Spec.Template.Spec.Volumes[len(d.Spec.Template.Spec.Volumes)-1].Secret.SecretName)
+	svc, err := clientset.CoreV1().Services(store.Namespace).Get("rook-ceph-rgw-default-external", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "LoadBalancer", string(svc.Spec.Type))
+	assert.Equal(t, "default-external", svc.Spec.Selector["rgw"])
+
+	// scaling the gateway of the store does not count the gateways of the group
+	err = c.startRGWPods(store.Name, store.Name, store.Name)
+	assert.NoError(t, err)
+	deps, err = clientset.AppsV1().Deployments(store.Namespace).List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(deps.Items))
+
+	// the gateways and the service of a removed group are deleted
+	store.Spec.GatewayGroups = nil
+	err = c.deleteRemovedGatewayGroups()
+	assert.NoError(t, err)
+	deps, err = clientset.AppsV1().Deployments(store.Namespace).List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(deps.Items))
+	assert.Equal(t, "rook-ceph-rgw-default-a", deps.Items[0].Name)
+	_, err = clientset.CoreV1().Services(store.Namespace).Get("rook-ceph-rgw-default-external", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
 }
 
 func TestEmptyPoolSpec(t *testing.T) {
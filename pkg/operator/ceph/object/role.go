@@ -0,0 +1,216 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"syscall"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+const (
+	serviceAccountSubject = "system:serviceaccount:%s:%s"
+	roleEffectDeny        = "Deny"
+	federatedPrincipal    = "Federated"
+)
+
+// ObjectRole is a role of the object store
+type ObjectRole struct {
+	Name string `json:"RoleName"`
+	ARN  string `json:"Arn"`
+	// TrustPolicy is the policy document of the principals allowed to assume the role
+	TrustPolicy string `json:"AssumeRolePolicyDocument"`
+}
+
+// RolePolicy is a trust or a permission policy of a role. Unlike the statements of the bucket policies, the
+// statements of the trust policies have no Resource and the statements of the permission policies have no Principal.
+type RolePolicy struct {
+	Id        string          `json:"Id"`
+	Version   string          `json:"Version"`
+	Statement []RoleStatement `json:"Statement"`
+}
+
+// RoleStatement is a statement of a RolePolicy
+type RoleStatement struct {
+	Sid       string              `json:"Sid"`
+	Effect    effect              `json:"Effect"`
+	Principal map[string][]string `json:"Principal,omitempty"`
+	Action    []action            `json:"Action"`
+	Resource  []string            `json:"Resource,omitempty"`
+	// Condition are the values the keys of the request must match, by condition operator
+	Condition map[string]map[string][]string `json:"Condition,omitempty"`
+}
+
+// RoleTrustPolicy returns the trust policy allowing the service accounts to assume a role with the tokens
+// issued by the OIDC issuer
+func RoleTrustPolicy(issuer string, serviceAccounts []cephv1.ObjectRoleServiceAccount) *RolePolicy {
+	subjects := []string{}
+	for _, serviceAccount := range serviceAccounts {
+		subjects = append(subjects, fmt.Sprintf(serviceAccountSubject, serviceAccount.Namespace, serviceAccount.Name))
+	}
+	statement := RoleStatement{
+		Effect:    effectAllow,
+		Principal: map[string][]string{federatedPrincipal: {OIDCProviderARN(issuer)}},
+		Action:    []action{AssumeRoleWithWebIdentity},
+		Condition: map[string]map[string][]string{
+			"StringEquals": {oidcProviderID(issuer) + ":sub": subjects},
+		},
+	}
+	return &RolePolicy{Version: version, Statement: []RoleStatement{statement}}
+}
+
+// RolePermissionPolicy returns the permission policy of a role on the buckets of the policy spec
+func RolePermissionPolicy(policy cephv1.ObjectRolePolicySpec) *RolePolicy {
+	actions := AllowedActions
+	if len(policy.Actions) > 0 {
+		actions = []action{}
+		for _, a := range policy.Actions {
+			actions = append(actions, action(a))
+		}
+	}
+	// the bucket policy statement builds the ARNs of the buckets and of their content
+	resources := NewPolicyStatement().ForResources(policy.Buckets...).ForSubResources(policy.Buckets...).Resource
+	statement := RoleStatement{
+		Sid:      policy.Name,
+		Effect:   effectAllow,
+		Action:   actions,
+		Resource: resources,
+	}
+	if policy.Effect == roleEffectDeny {
+		statement.Effect = effectDeny
+	}
+	return &RolePolicy{Version: version, Statement: []RoleStatement{statement}}
+}
+
+// CreateOrUpdateRole creates the role, or updates its trust policy if it already exists
+func CreateOrUpdateRole(c *Context, name string, trustPolicy *RolePolicy) (*ObjectRole, error) {
+	trustPolicyDoc, err := json.Marshal(trustPolicy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to serialize the trust policy of role %q", name)
+	}
+
+	output, err := runAdminCommand(c, "role", "get", "--role-name", name)
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); !ok || code != int(syscall.ENOENT) {
+			return nil, errors.Wrapf(err, "failed to get role %q. %s", name, output)
+		}
+		logger.Infof("creating role %q", name)
+		output, err = runAdminCommand(c, "role", "create", "--role-name", name, "--assume-role-policy-doc", string(trustPolicyDoc))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create role %q. %s", name, output)
+		}
+		return decodeRole(output)
+	}
+
+	role, err := decodeRole(output)
+	if err != nil {
+		return nil, err
+	}
+	if role.TrustPolicy != string(trustPolicyDoc) {
+		logger.Infof("updating the trust policy of role %q", name)
+		output, err = runAdminCommand(c, "role", "modify", "--role-name", name, "--assume-role-policy-doc", string(trustPolicyDoc))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to update the trust policy of role %q. %s", name, output)
+		}
+	}
+	return role, nil
+}
+
+// SetRolePolicies sets the permission policies of the role by name, the other policies of the role are deleted
+func SetRolePolicies(c *Context, name string, policies map[string]*RolePolicy) error {
+	current, err := listRolePolicies(c, name)
+	if err != nil {
+		return err
+	}
+	for policyName, policy := range policies {
+		policyDoc, err := json.Marshal(policy)
+		if err != nil {
+			return errors.Wrapf(err, "failed to serialize policy %q of role %q", policyName, name)
+		}
+		output, err := runAdminCommand(c, "role-policy", "put", "--role-name", name, "--policy-name", policyName, "--policy-doc", string(policyDoc))
+		if err != nil {
+			return errors.Wrapf(err, "failed to put policy %q of role %q. %s", policyName, name, output)
+		}
+	}
+	for _, policyName := range current {
+		if _, ok := policies[policyName]; ok {
+			continue
+		}
+		if err := deleteRolePolicy(c, name, policyName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteRole deletes the role and its permission policies, nothing is done if the role does not exist
+func DeleteRole(c *Context, name string) error {
+	policies, err := listRolePolicies(c, name)
+	if err != nil {
+		if code, ok := exec.ExitStatus(errors.Cause(err)); ok && code == int(syscall.ENOENT) {
+			return nil
+		}
+		return err
+	}
+	for _, policyName := range policies {
+		if err := deleteRolePolicy(c, name, policyName); err != nil {
+			return err
+		}
+	}
+	output, err := runAdminCommand(c, "role", "delete", "--role-name", name)
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete role %q. %s", name, output)
+	}
+	logger.Infof("deleted role %q", name)
+	return nil
+}
+
+func listRolePolicies(c *Context, name string) ([]string, error) {
+	output, err := runAdminCommand(c, "role-policy", "list", "--role-name", name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the policies of role %q. %s", name, output)
+	}
+	var policies []string
+	if err := json.Unmarshal([]byte(output), &policies); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the policies of role %q", name)
+	}
+	return policies, nil
+}
+
+func deleteRolePolicy(c *Context, name, policyName string) error {
+	output, err := runAdminCommand(c, "role-policy", "delete", "--role-name", name, "--policy-name", policyName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete policy %q of role %q. %s", policyName, name, output)
+	}
+	logger.Infof("deleted policy %q of role %q", policyName, name)
+	return nil
+}
+
+func decodeRole(output string) (*ObjectRole, error) {
+	var role ObjectRole
+	if err := json.Unmarshal([]byte(output), &role); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse role %q", output)
+	}
+	return &role, nil
+}
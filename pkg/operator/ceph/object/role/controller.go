@@ -0,0 +1,326 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package role to manage the roles the Kubernetes service accounts assume with the rgw STS.
+package role
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-object-role-controller"
+	// the rgw user creating the OIDC provider, the provider is shared by all the roles of the object store
+	stsUserName = "rook-ceph-internal-sts-user"
+	stsUserCaps = "oidc-provider=*"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephObjectRoleKind = reflect.TypeOf(cephv1.CephObjectRole{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephObjectRoleKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileObjectRole reconciles a CephObjectRole object
+type ReconcileObjectRole struct {
+	client      client.Client
+	scheme      *runtime.Scheme
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+}
+
+// Add creates a new CephObjectRole Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context) error {
+	return add(mgr, newReconciler(mgr, context))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Reconciler {
+	// Add the cephv1 scheme to the manager scheme so that the controller knows about it
+	mgrScheme := mgr.GetScheme()
+	if err := cephv1.AddToScheme(mgr.GetScheme()); err != nil {
+		panic(err)
+	}
+
+	return &ReconcileObjectRole{
+		client:  mgr.GetClient(),
+		scheme:  mgrScheme,
+		context: context,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephObjectRole CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephObjectRole{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephObjectRole object and makes changes based on the state read
+// and what is in the CephObjectRole.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileObjectRole) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime loggin interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileObjectRole) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephObjectRole instance
+	role := &cephv1.CephObjectRole{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, role)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephObjectRole resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get CephObjectRole")
+	}
+
+	// The CR was just created, initializing status fields
+	if role.Status == nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, "", "")
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	_, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		// We skip the DeleteRole() function since everything is gone already
+		if !role.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// Remove finalizer
+			err = opcontroller.RemoveFinalizer(r.client, role)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		return reconcileResponse, nil
+	}
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, role)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// Populate clusterInfo during each reconcile
+	r.clusterInfo, _, _, err = mon.LoadClusterInfo(r.context, request.NamespacedName.Namespace)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to populate cluster info")
+	}
+
+	// Populate CephVersion, the OIDC provider is not supported by older versions
+	currentCephVersion, err := cephclient.LeastUptodateDaemonVersion(r.context, r.clusterInfo, opconfig.MonType)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to retrieve current ceph %q version", opconfig.MonType)
+	}
+	r.clusterInfo.CephVersion = currentCephVersion
+
+	store, objContext, err := r.objectStore(role)
+	if err != nil {
+		if !role.GetDeletionTimestamp().IsZero() {
+			// The object store is gone, so are its roles
+			err = opcontroller.RemoveFinalizer(r.client, role)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		logger.Debugf("object store %q not ready, retrying in %q. %v",
+			role.Spec.ObjectStoreName, opcontroller.WaitForRequeueIfCephClusterNotReady.RequeueAfter.String(), err)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, "", "")
+		return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
+	}
+
+	// DELETE: the CR was deleted
+	if !role.GetDeletionTimestamp().IsZero() {
+		logger.Infof("deleting object role %q", role.Name)
+		err = object.DeleteRole(objContext, role.Name)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to delete object role %q", role.Name)
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.client, role)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, nil
+	}
+
+	// validate the role settings
+	err = validateRole(role, store, r.clusterInfo.CephVersion)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, "", err.Error())
+		return reconcile.Result{}, errors.Wrapf(err, "invalid object role CR %q spec", role.Name)
+	}
+
+	// The OIDC provider the role trusts
+	issuer := object.STSIssuer(store.Spec.Auth.STS)
+	err = r.createOIDCProvider(objContext, store)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, "", "")
+		return reconcile.Result{}, errors.Wrapf(err, "failed to create the oidc provider of object store %q", store.Name)
+	}
+
+	// CREATE/UPDATE the role
+	objectRole, err := object.CreateOrUpdateRole(objContext, role.Name, object.RoleTrustPolicy(issuer, role.Spec.ServiceAccounts))
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, "", "")
+		return reconcile.Result{}, errors.Wrapf(err, "failed to create object role %q", role.Name)
+	}
+	err = object.SetRolePolicies(objContext, role.Name, rolePolicies(role))
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, "", "")
+		return reconcile.Result{}, errors.Wrapf(err, "failed to set the policies of object role %q", role.Name)
+	}
+	logger.Infof("created object role %q with arn %q", role.Name, objectRole.ARN)
+
+	// Set Ready status, we are done reconciling
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, objectRole.ARN, "")
+
+	// Return and do not requeue
+	logger.Debug("done reconciling")
+	return reconcile.Result{}, nil
+}
+
+// objectStore returns the object store of the role and its context
+func (r *ReconcileObjectRole) objectStore(role *cephv1.CephObjectRole) (*cephv1.CephObjectStore, *object.Context, error) {
+	store := &cephv1.CephObjectStore{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: role.Spec.ObjectStoreName, Namespace: role.Namespace}, store)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get CephObjectStore %q", role.Spec.ObjectStoreName)
+	}
+	if store.Status == nil || store.Status.Info["endpoint"] == "" {
+		return nil, nil, errors.Errorf("CephObjectStore %q has no endpoint yet", store.Name)
+	}
+
+	objContext, err := object.NewMultisiteContext(r.context, r.clusterInfo, store)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to set multisite on object context for object role")
+	}
+	return store, objContext, nil
+}
+
+// createOIDCProvider creates the OIDC provider of the service account tokens in the object store, authenticated
+// with the sts user
+func (r *ReconcileObjectRole) createOIDCProvider(objContext *object.Context, store *cephv1.CephObjectStore) error {
+	displayName := stsUserName
+	user, rgwerr, err := object.CreateUser(objContext, object.ObjectUser{UserID: stsUserName, DisplayName: &displayName})
+	if err != nil {
+		if rgwerr != object.ErrorCodeFileExists {
+			return errors.Wrapf(err, "failed to create object user %q. error code %d", stsUserName, rgwerr)
+		}
+		user, _, err = object.GetUser(objContext, stsUserName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get object user %q", stsUserName)
+		}
+	}
+	if user.Caps["oidc-provider"] != "*" {
+		if _, err := object.AddUserCaps(objContext, stsUserName, stsUserCaps); err != nil {
+			return errors.Wrapf(err, "failed to add caps to object user %q", stsUserName)
+		}
+	}
+
+	thumbprints, err := object.STSThumbprints(store.Spec.Auth.STS)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the thumbprints of the oidc issuer")
+	}
+	iamAgent, err := object.NewIAMAgent(*user.AccessKey, *user.SecretKey, store.Status.Info["endpoint"], false)
+	if err != nil {
+		return errors.Wrap(err, "failed to create iam agent")
+	}
+	_, err = iamAgent.EnsureOpenIDConnectProvider(object.STSIssuer(store.Spec.Auth.STS), object.STSAudiences(store.Spec.Auth.STS), thumbprints)
+	return err
+}
+
+// updateStatus updates a role with a given status
+func updateStatus(client client.Client, name types.NamespacedName, status, arn, message string) {
+	role := &cephv1.CephObjectRole{}
+	if err := client.Get(context.TODO(), name, role); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephObjectRole resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve object role %q to update status to %q. %v", name, status, err)
+		return
+	}
+	if role.Status == nil {
+		role.Status = &cephv1.ObjectRoleStatus{}
+	}
+
+	role.Status.Phase = status
+	if arn != "" {
+		role.Status.ARN = arn
+	}
+	role.Status.Message = message
+	if err := opcontroller.UpdateStatus(client, role); err != nil {
+		logger.Errorf("failed to set object role %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("object role %q status updated to %q", name, status)
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package role
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os/exec"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/clusterd"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	namespace      = "rook-ceph"
+	userCreateJSON = `{"user_id":"rook-ceph-internal-sts-user","display_name":"rook-ceph-internal-sts-user","keys":[{"user":"rook-ceph-internal-sts-user","access_key":"EOE7FYCNOBZJ5VFV909G","secret_key":"qmIqpWm8HxCzmynCrD6U6vKWi4hnDBndOnmxXNsV"}]}`
+	roleJSON       = `{"RoleId":"b7ea2d47-3ad4-4b1c-8b1d-3f2fb3a3b1c1","RoleName":"my-role","Path":"/","Arn":"arn:aws:iam:::role/my-role","MaxSessionDuration":3600,"AssumeRolePolicyDocument":"{}"}`
+)
+
+func TestValidateRole(t *testing.T) {
+	store := &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "my-store"}}
+	role := &cephv1.CephObjectRole{
+		Spec: cephv1.ObjectRoleSpec{
+			ObjectStoreName: "my-store",
+			ServiceAccounts: []cephv1.ObjectRoleServiceAccount{{Namespace: "default", Name: "my-app"}},
+			Policies:        []cephv1.ObjectRolePolicySpec{{Name: "read", Actions: []string{"s3:GetObject"}, Buckets: []string{"my-bucket"}}},
+		},
+	}
+
+	// the oidc provider requires pacific
+	store.Spec.Auth.STS = &cephv1.STSSpec{}
+	err := validateRole(role, store, cephver.Octopus)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "require ceph pacific")
+
+	// sts not enabled on the store
+	store.Spec.Auth.STS = nil
+	assert.Error(t, validateRole(role, store, cephver.Pacific))
+	store.Spec.Auth.STS = &cephv1.STSSpec{}
+	assert.NoError(t, validateRole(role, store, cephver.Pacific))
+
+	// no service account
+	role.Spec.ServiceAccounts = nil
+	assert.Error(t, validateRole(role, store, cephver.Pacific))
+	role.Spec.ServiceAccounts = []cephv1.ObjectRoleServiceAccount{{Name: "my-app"}}
+	assert.Error(t, validateRole(role, store, cephver.Pacific))
+	role.Spec.ServiceAccounts[0].Namespace = "default"
+	assert.NoError(t, validateRole(role, store, cephver.Pacific))
+
+	// invalid policies
+	role.Spec.Policies = append(role.Spec.Policies, cephv1.ObjectRolePolicySpec{Name: "read", Buckets: []string{"*"}})
+	assert.Error(t, validateRole(role, store, cephver.Pacific))
+	role.Spec.Policies[1].Name = "deny-all"
+	role.Spec.Policies[1].Effect = "Refuse"
+	assert.Error(t, validateRole(role, store, cephver.Pacific))
+	role.Spec.Policies[1].Effect = "Deny"
+	assert.NoError(t, validateRole(role, store, cephver.Pacific))
+	role.Spec.Policies[1].Actions = []string{"iam:CreateRole"}
+	assert.Error(t, validateRole(role, store, cephver.Pacific))
+	role.Spec.Policies[1].Actions = nil
+	role.Spec.Policies[1].Buckets = nil
+	assert.Error(t, validateRole(role, store, cephver.Pacific))
+}
+
+func TestCephObjectRoleController(t *testing.T) {
+	// fake rgw serving the iam api
+	requests := []url.Values{}
+	rgw := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.NoError(t, req.ParseForm())
+		requests = append(requests, req.PostForm)
+		switch req.PostForm.Get("Action") {
+		case "GetOpenIDConnectProvider":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<ErrorResponse><Error><Code>NoSuchEntity</Code><Message>not found</Message></Error></ErrorResponse>`)
+		case "CreateOpenIDConnectProvider":
+			fmt.Fprint(w, `<CreateOpenIDConnectProviderResponse><CreateOpenIDConnectProviderResult><OpenIDConnectProviderArn>arn:aws:iam:::oidc-provider/kubernetes.default.svc</OpenIDConnectProviderArn></CreateOpenIDConnectProviderResult></CreateOpenIDConnectProviderResponse>`)
+		}
+	}))
+	defer rgw.Close()
+
+	role := &cephv1.CephObjectRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-role", Namespace: namespace},
+		TypeMeta:   metav1.TypeMeta{Kind: "CephObjectRole"},
+		Spec: cephv1.ObjectRoleSpec{
+			ObjectStoreName: "my-store",
+			ServiceAccounts: []cephv1.ObjectRoleServiceAccount{{Namespace: "default", Name: "my-app"}},
+			Policies:        []cephv1.ObjectRolePolicySpec{{Name: "read", Actions: []string{"s3:GetObject"}, Buckets: []string{"my-bucket"}}},
+		},
+	}
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+		Status: cephv1.ClusterStatus{
+			Phase:      k8sutil.ReadyStatus,
+			CephStatus: &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+	cephObjectStore := &cephv1.CephObjectStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: namespace},
+		TypeMeta:   metav1.TypeMeta{Kind: "CephObjectStore"},
+		Spec: cephv1.ObjectStoreSpec{
+			Auth: cephv1.ObjectStoreAuthSpec{
+				STS: &cephv1.STSSpec{Thumbprints: []string{"F7D7B3515DD0D319DD219A43A9EA727AD6065287"}},
+			},
+		},
+		Status: &cephv1.ObjectStoreStatus{
+			Info: map[string]string{"endpoint": rgw.URL},
+		},
+	}
+
+	// the exit status of radosgw-admin when the role does not exist
+	notFound := exec.Command("sh", "-c", "exit 2").Run()
+	roleExists := false
+	commands := []string{}
+	monVersion := "ceph version 16.2.1 (afb8bbb2b6e4e4b3e6bd2ec5aadab81c93d86ef7) pacific (stable)"
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "status" {
+				return `{"fsid":"c47cac40-9bee-4d52-823b-ccd803ba5bfe","health":{"checks":{},"status":"HEALTH_OK"},"pgmap":{"num_pgs":100,"pgs_by_state":[{"state_name":"active+clean","count":100}]}}`, nil
+			}
+			if args[0] == "versions" {
+				return `{"mon":{"` + monVersion + `":3}}`, nil
+			}
+			return "", nil
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "user" || args[0] == "caps":
+				return userCreateJSON, nil
+			case args[0] == "role" || args[0] == "role-policy":
+				commands = append(commands, strings.Join(args[:2], " "))
+				if !roleExists && args[1] != "create" {
+					return "", notFound
+				}
+				if args[1] == "create" {
+					roleExists = true
+				}
+				if args[1] == "list" {
+					return `["old"]`, nil
+				}
+				return roleJSON, nil
+			}
+			return "", nil
+		},
+	}
+	clientset := test.New(t, 3)
+	c := &clusterd.Context{
+		Executor:      executor,
+		RookClientset: rookclient.NewSimpleClientset(),
+		Clientset:     clientset,
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+		Data: map[string][]byte{
+			"fsid":         []byte("c47cac40-9bee-4d52-823b-ccd803ba5bfe"),
+			"mon-secret":   []byte("monsecret"),
+			"admin-secret": []byte("adminsecret"),
+		},
+		Type: k8sutil.RookType,
+	}
+	_, err := clientset.CoreV1().Secrets(namespace).Create(secret)
+	assert.NoError(t, err)
+
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephObjectRole{}, &cephv1.CephObjectRoleList{},
+		&cephv1.CephCluster{}, &cephv1.CephClusterList{}, &cephv1.CephObjectStore{}, &cephv1.CephObjectStoreList{})
+	cl := fake.NewFakeClientWithScheme(s, []runtime.Object{role, cephCluster, cephObjectStore}...)
+	r := &ReconcileObjectRole{client: cl, scheme: s, context: c}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "my-role", Namespace: namespace}}
+
+	//
+	// TEST 1: the oidc provider and the role are created
+	//
+	res, err := r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, "CreateOpenIDConnectProvider", requests[1].Get("Action"))
+	assert.Equal(t, "https://kubernetes.default.svc", requests[1].Get("Url"))
+	assert.Equal(t, "sts.amazonaws.com", requests[1].Get("ClientIDList.member.1"))
+	assert.Equal(t, "F7D7B3515DD0D319DD219A43A9EA727AD6065287", requests[1].Get("ThumbprintList.member.1"))
+	// the policy removed from the spec is deleted
+	assert.Equal(t, []string{"role get", "role create", "role-policy list", "role-policy put", "role-policy delete"}, commands)
+	err = cl.Get(context.TODO(), req.NamespacedName, role)
+	assert.NoError(t, err)
+	assert.Equal(t, k8sutil.ReadyStatus, role.Status.Phase)
+	assert.Equal(t, "arn:aws:iam:::role/my-role", role.Status.ARN)
+	assert.Equal(t, 1, len(role.Finalizers))
+
+	//
+	// TEST 2: the oidc provider requires pacific, the role fails validation with a clear message
+	//
+	requests = []url.Values{}
+	commands = []string{}
+	monVersion = "ceph version 15.2.10 (27917a557cca91e4da407489bbaa64ad4352cc02) octopus (stable)"
+	_, err = r.Reconcile(req)
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(requests))
+	assert.Equal(t, 0, len(commands))
+	role = &cephv1.CephObjectRole{}
+	err = cl.Get(context.TODO(), req.NamespacedName, role)
+	assert.NoError(t, err)
+	assert.Equal(t, k8sutil.ReconcileFailedStatus, role.Status.Phase)
+	assert.Contains(t, role.Status.Message, "object roles require ceph pacific or newer, the cluster is running ceph 15.2.10")
+	monVersion = "ceph version 16.2.1 (afb8bbb2b6e4e4b3e6bd2ec5aadab81c93d86ef7) pacific (stable)"
+
+	//
+	// TEST 3: the role and its policies are deleted with the CR
+	//
+	commands = []string{}
+	now := metav1.Now()
+	role.DeletionTimestamp = &now
+	err = cl.Update(context.TODO(), role)
+	assert.NoError(t, err)
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, []string{"role-policy list", "role-policy delete", "role delete"}, commands)
+	deleted := &cephv1.CephObjectRole{}
+	err = cl.Get(context.TODO(), req.NamespacedName, deleted)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(deleted.Finalizers))
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package role
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+)
+
+// oidcProviderMinCephVersion is the first version of the rgw with the IAM API of the OIDC providers the roles trust
+var oidcProviderMinCephVersion = cephver.Pacific
+
+// validateRole validates the role settings
+func validateRole(r *cephv1.CephObjectRole, store *cephv1.CephObjectStore, cephVersion cephver.CephVersion) error {
+	if !cephVersion.IsAtLeast(oidcProviderMinCephVersion) {
+		return errors.Errorf("object roles require ceph pacific or newer, the cluster is running ceph %s", cephVersion.String())
+	}
+	if r.Spec.ObjectStoreName == "" {
+		return errors.New("missing object store name")
+	}
+	if store.Spec.Auth.STS == nil {
+		return errors.Errorf("sts is not enabled on object store %q", store.Name)
+	}
+
+	if len(r.Spec.ServiceAccounts) == 0 {
+		return errors.New("missing service accounts")
+	}
+	for _, serviceAccount := range r.Spec.ServiceAccounts {
+		if serviceAccount.Namespace == "" || serviceAccount.Name == "" {
+			return errors.Errorf("missing namespace or name of service account %q", serviceAccount.Namespace+"/"+serviceAccount.Name)
+		}
+	}
+
+	names := map[string]bool{}
+	for _, policy := range r.Spec.Policies {
+		if policy.Name == "" {
+			return errors.New("missing policy name")
+		}
+		if names[policy.Name] {
+			return errors.Errorf("duplicate policy %q", policy.Name)
+		}
+		names[policy.Name] = true
+		if policy.Effect != "" && policy.Effect != "Allow" && policy.Effect != "Deny" {
+			return errors.Errorf("invalid effect %q of policy %q, expected \"Allow\" or \"Deny\"", policy.Effect, policy.Name)
+		}
+		if len(policy.Buckets) == 0 {
+			return errors.Errorf("missing buckets of policy %q", policy.Name)
+		}
+		for _, action := range policy.Actions {
+			if !strings.HasPrefix(action, "s3:") {
+				return errors.Errorf("invalid action %q of policy %q, expected a \"s3:*\" action", action, policy.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// rolePolicies returns the permission policies of the role by name
+func rolePolicies(r *cephv1.CephObjectRole) map[string]*object.RolePolicy {
+	policies := map[string]*object.RolePolicy{}
+	for _, policy := range r.Spec.Policies {
+		policies[policy.Name] = object.RolePermissionPolicy(policy)
+	}
+	return policies
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleTrustPolicy(t *testing.T) {
+	policy := RoleTrustPolicy("https://kubernetes.default.svc", []cephv1.ObjectRoleServiceAccount{
+		{Namespace: "default", Name: "my-app"},
+		{Namespace: "other", Name: "my-job"},
+	})
+	doc, err := json.Marshal(policy)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Id": "",
+		"Version": "2012-10-17",
+		"Statement": [{
+			"Sid": "",
+			"Effect": "Allow",
+			"Principal": {"Federated": ["arn:aws:iam:::oidc-provider/kubernetes.default.svc"]},
+			"Action": ["sts:AssumeRoleWithWebIdentity"],
+			"Condition": {"StringEquals": {"kubernetes.default.svc:sub": ["system:serviceaccount:default:my-app", "system:serviceaccount:other:my-job"]}}
+		}]
+	}`, string(doc))
+}
+
+func TestRolePermissionPolicy(t *testing.T) {
+	policy := RolePermissionPolicy(cephv1.ObjectRolePolicySpec{Name: "read", Actions: []string{"s3:GetObject", "s3:ListBucket"}, Buckets: []string{"my-bucket"}})
+	doc, err := json.Marshal(policy)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Id": "",
+		"Version": "2012-10-17",
+		"Statement": [{
+			"Sid": "read",
+			"Effect": "Allow",
+			"Action": ["s3:GetObject", "s3:ListBucket"],
+			"Resource": ["arn:aws:s3:::my-bucket", "arn:aws:s3:::my-bucket/*"]
+		}]
+	}`, string(doc))
+
+	// deny the default actions on all the buckets
+	policy = RolePermissionPolicy(cephv1.ObjectRolePolicySpec{Name: "deny", Effect: "Deny", Buckets: []string{"*"}})
+	assert.Equal(t, effectDeny, policy.Statement[0].Effect)
+	assert.Equal(t, AllowedActions, policy.Statement[0].Action)
+	assert.Equal(t, []string{"arn:aws:s3:::*", "arn:aws:s3:::*/*"}, policy.Statement[0].Resource)
+}
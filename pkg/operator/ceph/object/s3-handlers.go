@@ -18,6 +18,8 @@ package object
 
 import (
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
 	"net/http"
 	"strings"
 	"time"
@@ -36,45 +38,70 @@ type S3Agent struct {
 }
 
 func NewS3Agent(accessKey, secretKey, endpoint string, debug bool) (*S3Agent, error) {
+	sess, err := newSession(accessKey, secretKey, endpoint, nil, debug)
+	if err != nil {
+		return nil, err
+	}
+	svc := s3.New(sess)
+	return &S3Agent{
+		Client: svc,
+	}, nil
+}
+
+// newSession returns an aws session to the rgw endpoint, trusting the given PEM certificate in addition
+// to the system ones if the endpoint is https
+func newSession(accessKey, secretKey, endpoint string, tlsCert []byte, debug bool) (*session.Session, error) {
 	const cephRegion = "us-east-1"
 
 	logLevel := aws.LogOff
 	if debug {
 		logLevel = aws.LogDebug
 	}
-	sess, err := session.NewSession(
+	httpClient := &http.Client{
+		Timeout: time.Second * 15,
+	}
+	if len(tlsCert) > 0 {
+		caCertPool, err := x509.SystemCertPool()
+		if err != nil {
+			caCertPool = x509.NewCertPool()
+		}
+		if !caCertPool.AppendCertsFromPEM(tlsCert) {
+			return nil, errors.New("failed to parse the tls certificate of the endpoint")
+		}
+		httpClient.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: caCertPool},
+		}
+	}
+	return session.NewSession(
 		aws.NewConfig().
 			WithRegion(cephRegion).
 			WithCredentials(credentials.NewStaticCredentials(accessKey, secretKey, "")).
 			WithEndpoint(endpoint).
 			WithS3ForcePathStyle(true).
 			WithMaxRetries(5).
-			WithDisableSSL(true).
-			WithHTTPClient(&http.Client{
-				Timeout: time.Second * 15,
-			}).
+			WithDisableSSL(!strings.HasPrefix(endpoint, "https://")).
+			WithHTTPClient(httpClient).
 			WithLogLevel(logLevel),
 	)
-	if err != nil {
-		return nil, err
-	}
-	svc := s3.New(sess)
-	return &S3Agent{
-		Client: svc,
-	}, nil
 }
 
 // CreateBucket creates a bucket with the given name
 func (s *S3Agent) CreateBucketNoInfoLogging(name string) error {
-	return s.createBucket(name, false)
+	return s.createBucket(name, false, "", false)
 }
 
 // CreateBucket creates a bucket with the given name
 func (s *S3Agent) CreateBucket(name string) error {
-	return s.createBucket(name, true)
+	return s.createBucket(name, true, "", false)
+}
+
+// CreateBucketInPlacement creates a bucket with the given name in a placement target, the default placement
+// of the zonegroup if empty, with object lock enabled if requested since it can only be enabled at creation
+func (s *S3Agent) CreateBucketInPlacement(name, placement string, objectLock bool) error {
+	return s.createBucket(name, true, placement, objectLock)
 }
 
-func (s *S3Agent) createBucket(name string, infoLogging bool) error {
+func (s *S3Agent) createBucket(name string, infoLogging bool, placement string, objectLock bool) error {
 	if infoLogging {
 		logger.Infof("creating bucket %q", name)
 	} else {
@@ -83,6 +110,13 @@ func (s *S3Agent) createBucket(name string, infoLogging bool) error {
 	bucketInput := &s3.CreateBucketInput{
 		Bucket: &name,
 	}
+	if placement != "" {
+		// rgw reads the placement target after the colon of the location constraint
+		bucketInput.CreateBucketConfiguration = &s3.CreateBucketConfiguration{LocationConstraint: aws.String(":" + placement)}
+	}
+	if objectLock {
+		bucketInput.ObjectLockEnabledForBucket = aws.Bool(true)
+	}
 	_, err := s.Client.CreateBucket(bucketInput)
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); ok {
@@ -179,3 +213,114 @@ func (s *S3Agent) DeleteObjectInBucket(bucketname string, key string) (bool, err
 	}
 	return true, nil
 }
+
+// PutBucketLifecycle replaces the lifecycle rules of the bucket
+func (s *S3Agent) PutBucketLifecycle(bucketname string, rules []*s3.LifecycleRule) error {
+	_, err := s.Client.PutBucketLifecycleConfiguration(&s3.PutBucketLifecycleConfigurationInput{
+		Bucket:                 aws.String(bucketname),
+		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{Rules: rules},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to put lifecycle configuration on bucket %q", bucketname)
+	}
+	return nil
+}
+
+// DeleteBucketLifecycle removes all the lifecycle rules of the bucket
+func (s *S3Agent) DeleteBucketLifecycle(bucketname string) error {
+	_, err := s.Client.DeleteBucketLifecycle(&s3.DeleteBucketLifecycleInput{
+		Bucket: aws.String(bucketname),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete lifecycle configuration of bucket %q", bucketname)
+	}
+	return nil
+}
+
+// PutBucketVersioning sets the versioning status of the bucket, either "Enabled" or "Suspended"
+func (s *S3Agent) PutBucketVersioning(bucketname string, status string) error {
+	_, err := s.Client.PutBucketVersioning(&s3.PutBucketVersioningInput{
+		Bucket:                  aws.String(bucketname),
+		VersioningConfiguration: &s3.VersioningConfiguration{Status: aws.String(status)},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set versioning of bucket %q to %q", bucketname, status)
+	}
+	return nil
+}
+
+// PutObjectLockRetention sets the default retention of the objects of a bucket created with object lock,
+// an empty mode removes the default retention
+func (s *S3Agent) PutObjectLockRetention(bucketname string, mode string, days int64) error {
+	config := &s3.ObjectLockConfiguration{ObjectLockEnabled: aws.String(s3.ObjectLockEnabledEnabled)}
+	if mode != "" {
+		config.Rule = &s3.ObjectLockRule{
+			DefaultRetention: &s3.DefaultRetention{Mode: aws.String(mode), Days: aws.Int64(days)},
+		}
+	}
+	_, err := s.Client.PutObjectLockConfiguration(&s3.PutObjectLockConfigurationInput{
+		Bucket:                  aws.String(bucketname),
+		ObjectLockConfiguration: config,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to put object lock configuration on bucket %q", bucketname)
+	}
+	return nil
+}
+
+// PutBucketTopicNotification adds the topic notification to the bucket, replacing the notification with the same ID
+func (s *S3Agent) PutBucketTopicNotification(bucketname string, notification *s3.TopicConfiguration) error {
+	config, err := s.Client.GetBucketNotificationConfiguration(&s3.GetBucketNotificationConfigurationRequest{
+		Bucket: aws.String(bucketname),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get notifications of bucket %q", bucketname)
+	}
+
+	config.TopicConfigurations = append(withoutTopicNotification(config.TopicConfigurations, *notification.Id), notification)
+	_, err = s.Client.PutBucketNotificationConfiguration(&s3.PutBucketNotificationConfigurationInput{
+		Bucket:                    aws.String(bucketname),
+		NotificationConfiguration: config,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to put notification %q on bucket %q", *notification.Id, bucketname)
+	}
+	return nil
+}
+
+// DeleteBucketTopicNotification removes the topic notification with the given ID from the bucket
+func (s *S3Agent) DeleteBucketTopicNotification(bucketname string, id string) error {
+	config, err := s.Client.GetBucketNotificationConfiguration(&s3.GetBucketNotificationConfigurationRequest{
+		Bucket: aws.String(bucketname),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchBucket {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get notifications of bucket %q", bucketname)
+	}
+
+	topicConfigurations := withoutTopicNotification(config.TopicConfigurations, id)
+	if len(topicConfigurations) == len(config.TopicConfigurations) {
+		return nil
+	}
+	config.TopicConfigurations = topicConfigurations
+	_, err = s.Client.PutBucketNotificationConfiguration(&s3.PutBucketNotificationConfigurationInput{
+		Bucket:                    aws.String(bucketname),
+		NotificationConfiguration: config,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete notification %q from bucket %q", id, bucketname)
+	}
+	return nil
+}
+
+func withoutTopicNotification(notifications []*s3.TopicConfiguration, id string) []*s3.TopicConfiguration {
+	filtered := []*s3.TopicConfiguration{}
+	for _, notification := range notifications {
+		if aws.StringValue(notification.Id) != id {
+			filtered = append(filtered, notification)
+		}
+	}
+	return filtered
+}
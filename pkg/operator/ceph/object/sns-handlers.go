@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/pkg/errors"
+)
+
+// SNSAgent wraps the sns.SNS structure to manage the bucket notification topics of the rgw
+type SNSAgent struct {
+	Client *sns.SNS
+}
+
+// NewSNSAgent returns an SNS agent to the rgw endpoint, trusting the given PEM certificate if not empty
+func NewSNSAgent(accessKey, secretKey, endpoint string, tlsCert []byte, debug bool) (*SNSAgent, error) {
+	sess, err := newSession(accessKey, secretKey, endpoint, tlsCert, debug)
+	if err != nil {
+		return nil, err
+	}
+	return &SNSAgent{
+		Client: sns.New(sess),
+	}, nil
+}
+
+// CreateTopic creates the topic or updates its attributes if it already exists, and returns its ARN
+func (s *SNSAgent) CreateTopic(name string, attributes map[string]string) (string, error) {
+	output, err := s.Client.CreateTopic(&sns.CreateTopicInput{
+		Name:       aws.String(name),
+		Attributes: aws.StringMap(attributes),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create topic %q", name)
+	}
+	return aws.StringValue(output.TopicArn), nil
+}
+
+// DeleteTopic deletes the topic with the given ARN
+func (s *SNSAgent) DeleteTopic(arn string) error {
+	_, err := s.Client.DeleteTopic(&sns.DeleteTopicInput{
+		TopicArn: aws.String(arn),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == sns.ErrCodeNotFoundException {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete topic %q", arn)
+	}
+	return nil
+}
@@ -47,11 +47,11 @@ func (c *clusterConfig) createDeployment(rgwConfig *rgwConfig) (*apps.Deployment
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      rgwConfig.ResourceName,
 			Namespace: c.store.Namespace,
-			Labels:    getLabels(c.store.Name, c.store.Namespace, true),
+			Labels:    c.gatewayLabels(true),
 		},
 		Spec: apps.DeploymentSpec{
 			Selector: &metav1.LabelSelector{
-				MatchLabels: getLabels(c.store.Name, c.store.Namespace, false),
+				MatchLabels: c.gatewayLabels(false),
 			},
 			Template: pod,
 			Replicas: &replicas,
@@ -105,15 +105,19 @@ func (c *clusterConfig) makeRGWPodSpec(rgwConfig *rgwConfig) (v1.PodTemplateSpec
 		podSpec.Volumes = append(podSpec.Volumes, certVol)
 	}
 
+	// Add the secrets of the external identity services
+	authVolumes, _ := c.authVolumes()
+	podSpec.Volumes = append(podSpec.Volumes, authVolumes...)
+
 	// If host networking is not enabled, preferred pod anti-affinity is added to the rgw daemons
 	preferredDuringScheduling := true
-	labels := getLabels(c.store.Name, c.store.Namespace, false)
+	labels := c.gatewayLabels(false)
 	k8sutil.SetNodeAntiAffinityForPod(&podSpec, c.store.Spec.Gateway.Placement, c.clusterSpec.Network.IsHost(), preferredDuringScheduling, labels, nil)
 
 	podTemplateSpec := v1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   rgwConfig.ResourceName,
-			Labels: getLabels(c.store.Name, c.store.Namespace, true),
+			Labels: c.gatewayLabels(true),
 		},
 		Spec: podSpec,
 	}
@@ -178,6 +182,16 @@ func (c *clusterConfig) makeDaemonContainer(rgwConfig *rgwConfig) v1.Container {
 		container.VolumeMounts = append(container.VolumeMounts, mount)
 	}
 
+	// Authenticate the users against the external identity services
+	_, authMounts := c.authVolumes()
+	container.Args = append(container.Args, c.authFlags()...)
+	container.VolumeMounts = append(container.VolumeMounts, authMounts...)
+
+	// Log the operations of the users for the usage collection
+	if c.store.Spec.Usage != nil {
+		container.Args = append(container.Args, cephconfig.NewFlag("rgw enable usage log", "true"))
+	}
+
 	return container
 }
 
@@ -240,14 +254,15 @@ func (c *clusterConfig) generateLiveProbePort() intstr.IntOrString {
 }
 
 func (c *clusterConfig) generateService(cephObjectStore *cephv1.CephObjectStore) *v1.Service {
-	labels := getLabels(cephObjectStore.Name, cephObjectStore.Namespace, true)
+	labels := c.gatewayLabels(true)
 	svc := &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      instanceName(cephObjectStore.Name),
+			Name:      instanceName(c.gatewayName()),
 			Namespace: cephObjectStore.Namespace,
 			Labels:    labels,
 		},
 	}
+	cephObjectStore.Spec.Gateway.ServiceAnnotations.ApplyToObjectMeta(&svc.ObjectMeta)
 
 	if c.clusterSpec.Network.IsHost() {
 		svc.Spec.ClusterIP = v1.ClusterIPNone
@@ -264,6 +279,7 @@ func (c *clusterConfig) generateService(cephObjectStore *cephv1.CephObjectStore)
 			Selector: labels,
 		}
 	}
+	svc.Spec.Type = cephObjectStore.Spec.Gateway.ServiceType
 	addPort(svc, "http", cephObjectStore.Spec.Gateway.Port, destPort.IntVal)
 	addPort(svc, "https", cephObjectStore.Spec.Gateway.SecurePort, cephObjectStore.Spec.Gateway.SecurePort)
 
@@ -357,3 +373,15 @@ func getLabels(name, namespace string, includeNewLabels bool) map[string]string
 	labels["rook_object_store"] = name
 	return labels
 }
+
+// gatewayLabels returns the labels of the gateways of the config, the gateways of a group are labeled with the
+// group besides the store
+func (c *clusterConfig) gatewayLabels(includeNewLabels bool) map[string]string {
+	labels := getLabels(c.store.Name, c.store.Namespace, includeNewLabels)
+	if c.gatewayGroup != "" {
+		labels = controller.CephDaemonAppLabels(AppName, c.store.Namespace, "rgw", c.gatewayName(), includeNewLabels)
+		labels["rook_object_store"] = c.store.Name
+		labels[gatewayGroupLabel] = c.gatewayGroup
+	}
+	return labels
+}
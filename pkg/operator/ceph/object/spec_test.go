@@ -197,6 +197,93 @@ func TestValidateSpec(t *testing.T) {
 	}
 	err = r.validateStore(s)
 	assert.Nil(t, err)
+
+	// keystone
+	s.Spec.Auth.Keystone = &cephv1.KeystoneSpec{URL: "https://keystone:5000", ServiceUserSecretName: "keystone-admin"}
+	err = r.validateStore(s)
+	assert.Error(t, err)
+	s.Spec.Auth.Keystone.AcceptedRoles = []string{"member"}
+	err = r.validateStore(s)
+	assert.NoError(t, err)
+	s.Spec.Auth.Keystone.ImplicitTenants = "foo"
+	err = r.validateStore(s)
+	assert.Error(t, err)
+	s.Spec.Auth.Keystone.ImplicitTenants = "s3"
+	err = r.validateStore(s)
+	assert.NoError(t, err)
+
+	// ldap
+	s.Spec.Auth.LDAP = &cephv1.LDAPSpec{URI: "ldaps://ldap:636", BindSecretName: "ldap-bind"}
+	err = r.validateStore(s)
+	assert.Error(t, err)
+	s.Spec.Auth.LDAP.SearchDN = "ou=users,dc=example,dc=com"
+	err = r.validateStore(s)
+	assert.NoError(t, err)
+
+	// sts
+	s.Spec.Auth.STS = &cephv1.STSSpec{Issuer: "http://kubernetes.default.svc"}
+	err = r.validateStore(s)
+	assert.Error(t, err)
+	s.Spec.Auth.STS.Issuer = "https://kubernetes.default.svc"
+	err = r.validateStore(s)
+	assert.NoError(t, err)
+	s.Spec.Auth.STS.Thumbprints = []string{"F7D7B3515DD0D319DD219A43A9EA727AD606528"}
+	err = r.validateStore(s)
+	assert.Error(t, err)
+	s.Spec.Auth.STS.Thumbprints = []string{"F7D7B3515DD0D319DD219A43A9EA727AD6065287"}
+	err = r.validateStore(s)
+	assert.NoError(t, err)
+
+	// usage
+	s.Spec.Usage = &cephv1.ObjectStoreUsageSpec{Interval: "1x"}
+	err = r.validateStore(s)
+	assert.Error(t, err)
+	s.Spec.Usage.Interval = "0s"
+	err = r.validateStore(s)
+	assert.Error(t, err)
+	s.Spec.Usage.Interval = "30s"
+	err = r.validateStore(s)
+	assert.Error(t, err)
+	s.Spec.Usage.Interval = "1m"
+	err = r.validateStore(s)
+	assert.NoError(t, err)
+	s.Spec.Usage.Interval = ""
+	err = r.validateStore(s)
+	assert.NoError(t, err)
+
+	// service type
+	s.Spec.Gateway.ServiceType = "ExternalName"
+	err = r.validateStore(s)
+	assert.Error(t, err)
+	s.Spec.Gateway.ServiceType = v1.ServiceTypeNodePort
+	err = r.validateStore(s)
+	assert.NoError(t, err)
+
+	// gateway groups
+	s.Spec.GatewayGroups = []cephv1.GatewayGroupSpec{{Name: "External"}}
+	err = r.validateStore(s)
+	assert.Error(t, err)
+	s.Spec.GatewayGroups[0].Name = "external"
+	err = r.validateStore(s)
+	assert.Error(t, err)
+	s.Spec.GatewayGroups[0].SecurePort = 443
+	err = r.validateStore(s)
+	assert.Error(t, err)
+	s.Spec.GatewayGroups[0].SSLCertificateRef = "my-cert"
+	err = r.validateStore(s)
+	assert.NoError(t, err)
+	s.Spec.GatewayGroups[0].ServiceType = v1.ServiceTypeLoadBalancer
+	err = r.validateStore(s)
+	assert.NoError(t, err)
+	s.Spec.GatewayGroups = append(s.Spec.GatewayGroups, cephv1.GatewayGroupSpec{Name: "external", GatewaySpec: cephv1.GatewaySpec{Port: 80}})
+	err = r.validateStore(s)
+	assert.Error(t, err)
+	s.Spec.GatewayGroups[1].Name = "internal"
+	err = r.validateStore(s)
+	assert.NoError(t, err)
+	s.Spec.GatewayGroups[1].ExternalRgwEndpoints = []v1.EndpointAddress{{IP: "192.168.0.1"}}
+	err = r.validateStore(s)
+	assert.Error(t, err)
 }
 
 func TestGenerateLiveProbe(t *testing.T) {
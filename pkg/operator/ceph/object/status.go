@@ -18,11 +18,15 @@ package object
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -85,14 +89,43 @@ func updateStatusBucket(client client.Client, name types.NamespacedName, phase c
 func buildStatusInfo(cephObjectStore *cephv1.CephObjectStore) map[string]string {
 	m := make(map[string]string)
 
-	if cephObjectStore.Spec.Gateway.SecurePort != 0 && cephObjectStore.Spec.Gateway.Port != 0 {
-		m["secureEndpoint"] = buildDNSEndpoint(BuildDomainName(cephObjectStore.Name, cephObjectStore.Namespace), cephObjectStore.Spec.Gateway.SecurePort, true)
-		m["endpoint"] = buildDNSEndpoint(BuildDomainName(cephObjectStore.Name, cephObjectStore.Namespace), cephObjectStore.Spec.Gateway.Port, false)
-	} else if cephObjectStore.Spec.Gateway.SecurePort != 0 {
-		m["endpoint"] = buildDNSEndpoint(BuildDomainName(cephObjectStore.Name, cephObjectStore.Namespace), cephObjectStore.Spec.Gateway.SecurePort, true)
-	} else {
-		m["endpoint"] = buildDNSEndpoint(BuildDomainName(cephObjectStore.Name, cephObjectStore.Namespace), cephObjectStore.Spec.Gateway.Port, false)
+	addEndpointInfo(m, "", cephObjectStore.Name, cephObjectStore.Namespace, cephObjectStore.Spec.Gateway)
+	// the endpoints of the gateway groups are suffixed with the group, e.g. "endpoint-external"
+	for _, group := range cephObjectStore.Spec.GatewayGroups {
+		addEndpointInfo(m, "-"+group.Name, fmt.Sprintf("%s-%s", cephObjectStore.Name, group.Name), cephObjectStore.Namespace, group.GatewaySpec)
 	}
 
 	return m
 }
+
+func addEndpointInfo(m map[string]string, suffix, name, namespace string, gateway cephv1.GatewaySpec) {
+	if gateway.SecurePort != 0 && gateway.Port != 0 {
+		m["secureEndpoint"+suffix] = buildDNSEndpoint(BuildDomainName(name, namespace), gateway.SecurePort, true)
+		m["endpoint"+suffix] = buildDNSEndpoint(BuildDomainName(name, namespace), gateway.Port, false)
+	} else if gateway.SecurePort != 0 {
+		m["endpoint"+suffix] = buildDNSEndpoint(BuildDomainName(name, namespace), gateway.SecurePort, true)
+	} else {
+		m["endpoint"+suffix] = buildDNSEndpoint(BuildDomainName(name, namespace), gateway.Port, false)
+	}
+}
+
+// TLSEndpoint returns the https endpoint of the object store from its status, and the PEM certificate of the
+// gateway if it has one. An empty endpoint is returned if the store does not listen on a secure port.
+func TLSEndpoint(context *clusterd.Context, store *cephv1.CephObjectStore) (string, []byte, error) {
+	if store.Status == nil {
+		return "", nil, nil
+	}
+	endpoint := store.Status.Info["secureEndpoint"]
+	if endpoint == "" && strings.HasPrefix(store.Status.Info["endpoint"], "https://") {
+		endpoint = store.Status.Info["endpoint"]
+	}
+	if endpoint == "" || store.Spec.Gateway.SSLCertificateRef == "" {
+		return endpoint, nil, nil
+	}
+
+	secret, err := context.Clientset.CoreV1().Secrets(store.Namespace).Get(store.Spec.Gateway.SSLCertificateRef, metav1.GetOptions{})
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to get the tls certificate secret %q of CephObjectStore %q", store.Spec.Gateway.SSLCertificateRef, store.Name)
+	}
+	return endpoint, secret.Data[certKeyName], nil
+}
@@ -20,7 +20,10 @@ import (
 	"testing"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/test"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -57,4 +60,52 @@ func TestBuildStatusInfo(t *testing.T) {
 	assert.NotEmpty(t, statusInfo["secureEndpoint"])
 	assert.Equal(t, "http://rook-ceph-rgw-my-store.rook-ceph.svc:80", statusInfo["endpoint"])
 	assert.Equal(t, "https://rook-ceph-rgw-my-store.rook-ceph.svc:443", statusInfo["secureEndpoint"])
+
+	// Gateway group
+	cephObjectStore.Spec.GatewayGroups = []cephv1.GatewayGroupSpec{{Name: "external", GatewaySpec: cephv1.GatewaySpec{SecurePort: 8443}}}
+
+	statusInfo = buildStatusInfo(cephObjectStore)
+	assert.Equal(t, "http://rook-ceph-rgw-my-store.rook-ceph.svc:80", statusInfo["endpoint"])
+	assert.Equal(t, "https://rook-ceph-rgw-my-store-external.rook-ceph.svc:8443", statusInfo["endpoint-external"])
+	assert.Empty(t, statusInfo["secureEndpoint-external"])
+}
+
+func TestTLSEndpoint(t *testing.T) {
+	clientset := test.New(t, 1)
+	context := &clusterd.Context{Clientset: clientset}
+	store := &cephv1.CephObjectStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "rook-ceph"},
+		Status: &cephv1.ObjectStoreStatus{
+			Info: map[string]string{"endpoint": "http://rook-ceph-rgw-my-store.rook-ceph.svc:80"},
+		},
+	}
+
+	// no secure port
+	endpoint, cert, err := TLSEndpoint(context, store)
+	assert.NoError(t, err)
+	assert.Empty(t, endpoint)
+	assert.Nil(t, cert)
+
+	// both ports, the certificate secret does not exist
+	store.Spec.Gateway.SSLCertificateRef = "my-cert"
+	store.Status.Info["secureEndpoint"] = "https://rook-ceph-rgw-my-store.rook-ceph.svc:443"
+	_, _, err = TLSEndpoint(context, store)
+	assert.Error(t, err)
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cert", Namespace: "rook-ceph"},
+		Data:       map[string][]byte{certKeyName: []byte("my-pem")},
+	}
+	_, err = clientset.CoreV1().Secrets("rook-ceph").Create(secret)
+	assert.NoError(t, err)
+	endpoint, cert, err = TLSEndpoint(context, store)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://rook-ceph-rgw-my-store.rook-ceph.svc:443", endpoint)
+	assert.Equal(t, []byte("my-pem"), cert)
+
+	// secure port only
+	store.Status.Info = map[string]string{"endpoint": "https://rook-ceph-rgw-my-store.rook-ceph.svc:443"}
+	endpoint, _, err = TLSEndpoint(context, store)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://rook-ceph-rgw-my-store.rook-ceph.svc:443", endpoint)
 }
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"crypto/rand"
+	"crypto/sha1" // #nosec G505 the thumbprints of the OIDC providers are SHA-1
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultSTSIssuer   = "https://kubernetes.default.svc"
+	defaultSTSAudience = "sts.amazonaws.com"
+	oidcProviderARN    = "arn:aws:iam:::oidc-provider/%s"
+
+	// the key the session tokens are encrypted with, rgw requires 16 characters
+	stsKeyName      = "key"
+	stsKeyByteCount = 8
+)
+
+// serviceAccountCAFile is the CA of the Kubernetes API server, overridden by the tests
+var serviceAccountCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+
+// STSIssuer returns the URL of the OIDC issuer of the service account tokens
+func STSIssuer(sts *cephv1.STSSpec) string {
+	if sts.Issuer != "" {
+		return sts.Issuer
+	}
+	return defaultSTSIssuer
+}
+
+// STSAudiences returns the audiences the service account tokens must be issued for
+func STSAudiences(sts *cephv1.STSSpec) []string {
+	if len(sts.Audiences) > 0 {
+		return sts.Audiences
+	}
+	return []string{defaultSTSAudience}
+}
+
+// STSThumbprints returns the thumbprints of the certificate of the OIDC issuer, the thumbprints of the
+// certificates of the Kubernetes CA if none are set
+func STSThumbprints(sts *cephv1.STSSpec) ([]string, error) {
+	if len(sts.Thumbprints) > 0 {
+		return sts.Thumbprints, nil
+	}
+	ca, err := ioutil.ReadFile(serviceAccountCAFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the kubernetes CA")
+	}
+	thumbprints := []string{}
+	for block, rest := pem.Decode(ca); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		thumbprint := sha1.Sum(block.Bytes) // #nosec G401 the thumbprints of the OIDC providers are SHA-1
+		thumbprints = append(thumbprints, strings.ToUpper(hex.EncodeToString(thumbprint[:])))
+	}
+	if len(thumbprints) == 0 {
+		return nil, errors.Errorf("no certificate found in the kubernetes CA %q", serviceAccountCAFile)
+	}
+	return thumbprints, nil
+}
+
+// OIDCProviderARN returns the ARN of the OIDC provider of the issuer, the issuer URL without its scheme
+func OIDCProviderARN(issuer string) string {
+	return fmt.Sprintf(oidcProviderARN, oidcProviderID(issuer))
+}
+
+// oidcProviderID returns the identifier of the OIDC provider in the policies, the issuer URL without its scheme
+func oidcProviderID(issuer string) string {
+	if u, err := url.Parse(issuer); err == nil && u.Host != "" {
+		return strings.TrimSuffix(u.Host+u.Path, "/")
+	}
+	return issuer
+}
+
+func (c *clusterConfig) stsKeySecretName() string {
+	return fmt.Sprintf("%s-sts", instanceName(c.store.Name))
+}
+
+// generateSTSKey creates the secret with the key the session tokens are encrypted with. The key is never
+// rotated, the session tokens issued by all the gateways must remain valid.
+func (c *clusterConfig) generateSTSKey() error {
+	if c.store.Spec.Auth.STS == nil {
+		return nil
+	}
+	_, err := c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Get(c.stsKeySecretName(), metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get sts key secret %q", c.stsKeySecretName())
+	}
+
+	key := make([]byte, stsKeyByteCount)
+	if _, err := rand.Read(key); err != nil {
+		return errors.Wrap(err, "failed to generate the sts key")
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            c.stsKeySecretName(),
+			Namespace:       c.store.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*c.ownerRef},
+		},
+		StringData: map[string]string{
+			stsKeyName: hex.EncodeToString(key),
+		},
+		Type: v1.SecretTypeOpaque,
+	}
+	if _, err := c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Create(secret); err != nil {
+		return errors.Wrapf(err, "failed to create sts key secret %q", c.stsKeySecretName())
+	}
+	logger.Infof("created sts key secret %q", c.stsKeySecretName())
+	return nil
+}
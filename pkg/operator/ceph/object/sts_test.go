@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// a self-signed certificate and the SHA-1 fingerprint of its DER encoding
+const (
+	testCA = `-----BEGIN CERTIFICATE-----
+MIIBfzCCASWgAwIBAgIUVURRCH71llx4vE7BKY2LiMWmP5owCgYIKoZIzj0EAwIw
+FTETMBEGA1UEAwwKa3ViZXJuZXRlczAeFw0yNjEwMTgwMjI1MDVaFw0zNjEwMTUw
+MjI1MDVaMBUxEzARBgNVBAMMCmt1YmVybmV0ZXMwWTATBgcqhkjOPQIBBggqhkjO
+PQMBBwNCAAQyi2rBWLt5DyB9CCLVTlwNeiO8LRSz/o6f6c2D17zagRf9zLi0OLaO
+s105zgYzDdYzgLIm873+SjU7ISFPww3Zo1MwUTAdBgNVHQ4EFgQUWF2GDJNCCL9p
+mfKOVBHDgoZfGJwwHwYDVR0jBBgwFoAUWF2GDJNCCL9pmfKOVBHDgoZfGJwwDwYD
+VR0TAQH/BAUwAwEB/zAKBggqhkjOPQQDAgNIADBFAiEAnq9G+5kCUCtQ7y024mAs
+yDuhmcgKCuWf0avQGbj75VkCIG1cg5vmnk3VMppMDrpPTi7s5RvvBoNHYdXDeaOk
+OFeF
+-----END CERTIFICATE-----
+`
+	testCAThumbprint = "3136D39C0B44DBDA329926D9F812DFB0914E260B"
+)
+
+func TestSTSDefaults(t *testing.T) {
+	sts := &cephv1.STSSpec{}
+	assert.Equal(t, "https://kubernetes.default.svc", STSIssuer(sts))
+	assert.Equal(t, []string{"sts.amazonaws.com"}, STSAudiences(sts))
+
+	sts = &cephv1.STSSpec{Issuer: "https://oidc.example.com/cluster1/", Audiences: []string{"my-store"}, Thumbprints: []string{"ABCD"}}
+	assert.Equal(t, "https://oidc.example.com/cluster1/", STSIssuer(sts))
+	assert.Equal(t, []string{"my-store"}, STSAudiences(sts))
+	assert.Equal(t, "arn:aws:iam:::oidc-provider/oidc.example.com/cluster1", OIDCProviderARN(STSIssuer(sts)))
+	thumbprints, err := STSThumbprints(sts)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ABCD"}, thumbprints)
+}
+
+func TestSTSThumbprints(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sts")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer func(caFile string) { serviceAccountCAFile = caFile }(serviceAccountCAFile)
+
+	// no CA
+	serviceAccountCAFile = path.Join(dir, "ca.crt")
+	_, err = STSThumbprints(&cephv1.STSSpec{})
+	assert.Error(t, err)
+
+	// no certificate in the CA
+	assert.NoError(t, ioutil.WriteFile(serviceAccountCAFile, []byte("not a certificate"), 0600))
+	_, err = STSThumbprints(&cephv1.STSSpec{})
+	assert.Error(t, err)
+
+	// the thumbprint of each certificate
+	assert.NoError(t, ioutil.WriteFile(serviceAccountCAFile, []byte(testCA+testCA), 0600))
+	thumbprints, err := STSThumbprints(&cephv1.STSSpec{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{testCAThumbprint, testCAThumbprint}, thumbprints)
+}
+
+func TestGenerateSTSKey(t *testing.T) {
+	clientset := test.New(t, 1)
+	c := &clusterConfig{
+		context:  &clusterd.Context{Clientset: clientset},
+		store:    &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "rook-ceph"}},
+		ownerRef: &metav1.OwnerReference{Name: "my-store"},
+	}
+
+	// sts not enabled
+	assert.NoError(t, c.generateSTSKey())
+	_, err := clientset.CoreV1().Secrets("rook-ceph").Get("rook-ceph-rgw-my-store-sts", metav1.GetOptions{})
+	assert.Error(t, err)
+
+	c.store.Spec.Auth.STS = &cephv1.STSSpec{}
+	assert.NoError(t, c.generateSTSKey())
+	secret, err := clientset.CoreV1().Secrets("rook-ceph").Get("rook-ceph-rgw-my-store-sts", metav1.GetOptions{})
+	assert.NoError(t, err)
+	key := secret.StringData["key"]
+	assert.Equal(t, 16, len(key))
+
+	// the key is not rotated
+	assert.NoError(t, c.generateSTSKey())
+	secret, err = clientset.CoreV1().Secrets("rook-ceph").Get("rook-ceph-rgw-my-store-sts", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, key, secret.StringData["key"])
+}
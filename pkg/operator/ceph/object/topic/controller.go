@@ -0,0 +1,329 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package topic to manage the topics of the rgw bucket notifications.
+package topic
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-bucket-topic-controller"
+	// the rgw user creating the topics, topics are shared by all the users of the object store
+	topicUserName = "rook-ceph-internal-topic-user"
+	// the rgw option accepting the credentials of the topic endpoints over plain http
+	allowCleartextSecretsOption = "rgw_allow_notification_secrets_in_cleartext"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephBucketTopicKind = reflect.TypeOf(cephv1.CephBucketTopic{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephBucketTopicKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileBucketTopic reconciles a CephBucketTopic object
+type ReconcileBucketTopic struct {
+	client      client.Client
+	scheme      *runtime.Scheme
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+}
+
+// Add creates a new CephBucketTopic Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context) error {
+	return add(mgr, newReconciler(mgr, context))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Reconciler {
+	// Add the cephv1 scheme to the manager scheme so that the controller knows about it
+	mgrScheme := mgr.GetScheme()
+	if err := cephv1.AddToScheme(mgr.GetScheme()); err != nil {
+		panic(err)
+	}
+
+	return &ReconcileBucketTopic{
+		client:  mgr.GetClient(),
+		scheme:  mgrScheme,
+		context: context,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephBucketTopic CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephBucketTopic{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephBucketTopic object and makes changes based on the state read
+// and what is in the CephBucketTopic.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileBucketTopic) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime loggin interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileBucketTopic) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephBucketTopic instance
+	topic := &cephv1.CephBucketTopic{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, topic)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketTopic resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get CephBucketTopic")
+	}
+
+	// The CR was just created, initializing status fields
+	if topic.Status == nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, "")
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	_, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		// We skip the deleteTopic() function since everything is gone already
+		if !topic.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// Remove finalizer
+			err = opcontroller.RemoveFinalizer(r.client, topic)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		return reconcileResponse, nil
+	}
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, topic)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// Populate clusterInfo during each reconcile
+	r.clusterInfo, _, _, err = mon.LoadClusterInfo(r.context, request.NamespacedName.Namespace)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to populate cluster info")
+	}
+
+	snsAgent, secure, err := r.newSNSAgent(topic)
+	if err != nil {
+		if !topic.GetDeletionTimestamp().IsZero() {
+			// The object store is gone, so are its topics
+			err = opcontroller.RemoveFinalizer(r.client, topic)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		logger.Debugf("object store %q not ready, retrying in %q. %v",
+			topic.Spec.ObjectStoreName, opcontroller.WaitForRequeueIfCephClusterNotReady.RequeueAfter.String(), err)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, "")
+		return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
+	}
+
+	// DELETE: the CR was deleted
+	if !topic.GetDeletionTimestamp().IsZero() {
+		if topic.Status != nil && topic.Status.ARN != "" {
+			logger.Infof("deleting bucket topic %q", topic.Name)
+			err = snsAgent.DeleteTopic(topic.Status.ARN)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrapf(err, "failed to delete bucket topic %q", topic.Name)
+			}
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.client, topic)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, nil
+	}
+
+	// validate the topic settings
+	err = validateTopic(topic)
+	if err == nil {
+		err = r.validateCredentialsTransport(topic, secure)
+	}
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, "")
+		return reconcile.Result{}, errors.Wrapf(err, "invalid bucket topic CR %q spec", topic.Name)
+	}
+
+	// CREATE/UPDATE the topic
+	attributes, err := r.topicAttributes(topic)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, "")
+		return reconcile.Result{}, errors.Wrapf(err, "failed to build the attributes of bucket topic %q", topic.Name)
+	}
+	arn, err := snsAgent.CreateTopic(topic.Name, attributes)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, "")
+		return reconcile.Result{}, errors.Wrapf(err, "failed to create bucket topic %q", topic.Name)
+	}
+	logger.Infof("created bucket topic %q with arn %q", topic.Name, arn)
+
+	// Set Ready status, we are done reconciling
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, arn)
+
+	// Return and do not requeue
+	logger.Debug("done reconciling")
+	return reconcile.Result{}, nil
+}
+
+// newSNSAgent returns an SNS agent to the object store of the topic, authenticated with the topic user. The
+// https endpoint of the store is preferred, the returned bool is whether the agent connects over TLS.
+func (r *ReconcileBucketTopic) newSNSAgent(topic *cephv1.CephBucketTopic) (*object.SNSAgent, bool, error) {
+	store := &cephv1.CephObjectStore{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: topic.Spec.ObjectStoreName, Namespace: topic.Namespace}, store)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to get CephObjectStore %q", topic.Spec.ObjectStoreName)
+	}
+	if store.Status == nil || store.Status.Info["endpoint"] == "" {
+		return nil, false, errors.Errorf("CephObjectStore %q has no endpoint yet", store.Name)
+	}
+	endpoint, tlsCert, err := object.TLSEndpoint(r.context, store)
+	if err != nil {
+		return nil, false, err
+	}
+	secure := endpoint != ""
+	if !secure {
+		endpoint = store.Status.Info["endpoint"]
+	}
+
+	objContext, err := object.NewMultisiteContext(r.context, r.clusterInfo, store)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to set multisite on object context for bucket topic")
+	}
+
+	displayName := topicUserName
+	user, rgwerr, err := object.CreateUser(objContext, object.ObjectUser{UserID: topicUserName, DisplayName: &displayName})
+	if err != nil {
+		if rgwerr != object.ErrorCodeFileExists {
+			return nil, false, errors.Wrapf(err, "failed to create object user %q. error code %d", topicUserName, rgwerr)
+		}
+		user, _, err = object.GetUser(objContext, topicUserName)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "failed to get object user %q", topicUserName)
+		}
+	}
+
+	agent, err := object.NewSNSAgent(*user.AccessKey, *user.SecretKey, endpoint, tlsCert, false)
+	if err != nil {
+		return nil, false, err
+	}
+	return agent, secure, nil
+}
+
+// validateCredentialsTransport checks that the endpoint credentials of the topic can be sent to the rgw. The rgw
+// rejects topics with credentials received over plain http unless it allows them in cleartext.
+func (r *ReconcileBucketTopic) validateCredentialsTransport(topic *cephv1.CephBucketTopic, secure bool) error {
+	if topic.Spec.Endpoint.AuthSecretName == "" || secure {
+		return nil
+	}
+	allowed, err := cephconfig.GetMonStore(r.context, r.clusterInfo).Get("client.rgw", allowCleartextSecretsOption)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get %q", allowCleartextSecretsOption)
+	}
+	if allowed != "true" {
+		return errors.Errorf("the endpoint credentials of secret %q can only be sent to the rgw over TLS, but CephObjectStore %q has no secure port. "+
+			"set \"gateway.securePort\" on the object store, or set %q to true for \"client.rgw\" in the ceph config database to allow them in cleartext",
+			topic.Spec.Endpoint.AuthSecretName, topic.Spec.ObjectStoreName, allowCleartextSecretsOption)
+	}
+	logger.Warningf("sending the endpoint credentials of bucket topic %q to the rgw in cleartext since %q is set", topic.Name, allowCleartextSecretsOption)
+	return nil
+}
+
+// updateStatus updates a topic with a given status
+func updateStatus(client client.Client, name types.NamespacedName, status, arn string) {
+	topic := &cephv1.CephBucketTopic{}
+	if err := client.Get(context.TODO(), name, topic); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketTopic resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve bucket topic %q to update status to %q. %v", name, status, err)
+		return
+	}
+	if topic.Status == nil {
+		topic.Status = &cephv1.BucketTopicStatus{}
+	}
+
+	topic.Status.Phase = status
+	if arn != "" {
+		topic.Status.ARN = arn
+	}
+	if err := opcontroller.UpdateStatus(client, topic); err != nil {
+		logger.Errorf("failed to set bucket topic %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("bucket topic %q status updated to %q", name, status)
+}
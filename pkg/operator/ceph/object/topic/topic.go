@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topic
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	authUsernameKey = "username"
+	authPasswordKey = "password"
+)
+
+// validateTopic validates the topic settings
+func validateTopic(t *cephv1.CephBucketTopic) error {
+	if t.Spec.ObjectStoreName == "" {
+		return errors.New("missing object store name")
+	}
+
+	endpoints := 0
+	endpoint := t.Spec.Endpoint
+	if endpoint.HTTP != nil {
+		endpoints++
+		if err := validateURI(endpoint.HTTP.URI, "http", "https"); err != nil {
+			return err
+		}
+	}
+	if endpoint.AMQP != nil {
+		endpoints++
+		if err := validateURI(endpoint.AMQP.URI, "amqp", "amqps"); err != nil {
+			return err
+		}
+		if endpoint.AMQP.Exchange == "" {
+			return errors.New("missing amqp exchange")
+		}
+		if err := validateAckLevel(endpoint.AMQP.AckLevel, "none", "broker", "routable"); err != nil {
+			return err
+		}
+	}
+	if endpoint.Kafka != nil {
+		endpoints++
+		if err := validateURI(endpoint.Kafka.URI, "kafka"); err != nil {
+			return err
+		}
+		if err := validateAckLevel(endpoint.Kafka.AckLevel, "none", "broker"); err != nil {
+			return err
+		}
+	}
+	if endpoints != 1 {
+		return errors.Errorf("exactly one of the http, amqp or kafka endpoints must be set, found %d", endpoints)
+	}
+
+	return nil
+}
+
+func validateURI(uri string, schemes ...string) error {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return errors.Wrapf(err, "invalid endpoint uri %q", uri)
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return nil
+		}
+	}
+	return errors.Errorf("invalid scheme %q of endpoint uri %q, expected one of %v", u.Scheme, uri, schemes)
+}
+
+func validateAckLevel(ackLevel string, ackLevels ...string) error {
+	if ackLevel == "" {
+		return nil
+	}
+	for _, level := range ackLevels {
+		if ackLevel == level {
+			return nil
+		}
+	}
+	return errors.Errorf("invalid ack level %q, expected one of %v", ackLevel, ackLevels)
+}
+
+// topicAttributes builds the attributes of the rgw topic from the topic spec
+func (r *ReconcileBucketTopic) topicAttributes(t *cephv1.CephBucketTopic) (map[string]string, error) {
+	attributes := map[string]string{
+		"persistent": strconv.FormatBool(t.Spec.Persistent),
+	}
+	if t.Spec.OpaqueData != "" {
+		attributes["OpaqueData"] = t.Spec.OpaqueData
+	}
+
+	var uri string
+	endpoint := t.Spec.Endpoint
+	switch {
+	case endpoint.HTTP != nil:
+		uri = endpoint.HTTP.URI
+		attributes["verify-ssl"] = strconv.FormatBool(!endpoint.HTTP.DisableVerifySSL)
+	case endpoint.AMQP != nil:
+		uri = endpoint.AMQP.URI
+		attributes["amqp-exchange"] = endpoint.AMQP.Exchange
+		if endpoint.AMQP.AckLevel != "" {
+			attributes["amqp-ack-level"] = endpoint.AMQP.AckLevel
+		}
+		attributes["verify-ssl"] = strconv.FormatBool(!endpoint.AMQP.DisableVerifySSL)
+	case endpoint.Kafka != nil:
+		uri = endpoint.Kafka.URI
+		attributes["use-ssl"] = strconv.FormatBool(endpoint.Kafka.UseSSL)
+		if endpoint.Kafka.AckLevel != "" {
+			attributes["kafka-ack-level"] = endpoint.Kafka.AckLevel
+		}
+		attributes["verify-ssl"] = strconv.FormatBool(!endpoint.Kafka.DisableVerifySSL)
+	}
+
+	if endpoint.AuthSecretName != "" {
+		var err error
+		uri, err = r.withCredentials(uri, t.Namespace, endpoint.AuthSecretName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	attributes["push-endpoint"] = uri
+
+	return attributes, nil
+}
+
+// withCredentials sets the username and password of the secret as the user info of the uri
+func (r *ReconcileBucketTopic) withCredentials(uri, namespace, secretName string) (string, error) {
+	secret, err := r.context.Clientset.CoreV1().Secrets(namespace).Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get endpoint auth secret %q", secretName)
+	}
+	username, ok := secret.Data[authUsernameKey]
+	if !ok {
+		return "", errors.Errorf("endpoint auth secret %q has no %q key", secretName, authUsernameKey)
+	}
+	password, ok := secret.Data[authPasswordKey]
+	if !ok {
+		return "", errors.Errorf("endpoint auth secret %q has no %q key", secretName, authPasswordKey)
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid endpoint uri %q", uri)
+	}
+	u.User = url.UserPassword(string(username), string(password))
+	return u.String(), nil
+}
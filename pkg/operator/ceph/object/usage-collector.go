@@ -0,0 +1,222 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	bktclient "github.com/kube-object-storage/lib-bucket-provisioner/pkg/client/clientset/versioned"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultUsageCollectionInterval = 5 * time.Minute
+	// the collections run radosgw-admin commands over all the buckets and users of the store
+	minUsageCollectionInterval = time.Minute
+	// the annotations of the usage of the bucket of an OBC, the status of the OBCs is owned by the bucket library
+	usageAnnotationPrefix = "ceph.rook.io/usage-"
+)
+
+// usageCollector periodically collects the usage of the buckets and users of an object store
+type usageCollector struct {
+	context        *clusterd.Context
+	objContext     *Context
+	interval       time.Duration
+	client         client.Client
+	bktclient      bktclient.Interface
+	namespacedName types.NamespacedName
+}
+
+// newUsageCollector creates a new usage collector of the object store
+func newUsageCollector(context *clusterd.Context, objContext *Context, client client.Client, bktclient bktclient.Interface, namespacedName types.NamespacedName) *usageCollector {
+	return &usageCollector{
+		context:        context,
+		objContext:     objContext,
+		interval:       defaultUsageCollectionInterval,
+		client:         client,
+		bktclient:      bktclient,
+		namespacedName: namespacedName,
+	}
+}
+
+// collectUsage periodically collects the usage until the object store is deleted
+func (c *usageCollector) collectUsage(stopCh chan struct{}) {
+	if err := c.collect(); err != nil {
+		logger.Warningf("failed to collect the usage of object store %q. %v", c.namespacedName.Name, err)
+	}
+
+	for {
+		select {
+		case <-stopCh:
+			deleteUsageMetrics(c.namespacedName.Namespace, c.namespacedName.Name)
+			logger.Infof("stopping the usage collection of object store %q", c.namespacedName.Name)
+			return
+
+		case <-time.After(c.interval):
+			logger.Debugf("collecting the usage of object store %q", c.namespacedName.Name)
+			if err := c.collect(); err != nil {
+				logger.Warningf("failed to collect the usage of object store %q. %v", c.namespacedName.Name, err)
+			}
+		}
+	}
+}
+
+// usageCollectionInterval returns the interval between the collections of the usage, at least a minute
+func usageCollectionInterval(usage *cephv1.ObjectStoreUsageSpec) (time.Duration, error) {
+	if usage.Interval == "" {
+		return defaultUsageCollectionInterval, nil
+	}
+	interval, err := time.ParseDuration(usage.Interval)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid usage interval %q", usage.Interval)
+	}
+	if interval < minUsageCollectionInterval {
+		return 0, errors.Errorf("usage interval %q must be at least %q", usage.Interval, minUsageCollectionInterval.String())
+	}
+	return interval, nil
+}
+
+func (c *usageCollector) collect() error {
+	// the usage settings are read at each collection to apply their updates
+	store := &cephv1.CephObjectStore{}
+	if err := c.client.Get(context.TODO(), c.namespacedName, store); err != nil {
+		return errors.Wrap(err, "failed to get the object store")
+	}
+	if store.Spec.Usage == nil {
+		deleteUsageMetrics(c.namespacedName.Namespace, c.namespacedName.Name)
+		return nil
+	}
+	// the interval is validated by the reconcile, an invalid update keeps the previous interval
+	if interval, err := usageCollectionInterval(store.Spec.Usage); err != nil {
+		logger.Warningf("invalid usage collection interval of object store %q, collecting every %q. %v", c.namespacedName.Name, c.interval.String(), err)
+	} else {
+		c.interval = interval
+	}
+
+	usage, err := GetUsage(c.objContext)
+	if err != nil {
+		return err
+	}
+	setUsageMetrics(c.namespacedName.Namespace, c.namespacedName.Name, usage)
+	if !store.Spec.Usage.UpdateStatus {
+		return nil
+	}
+
+	if err := c.updateUserStatus(usage); err != nil {
+		return errors.Wrap(err, "failed to report the usage of the users")
+	}
+	if err := c.updateClaimAnnotations(usage); err != nil {
+		return errors.Wrap(err, "failed to report the usage of the buckets")
+	}
+	return nil
+}
+
+// updateUserStatus reports the usage of the users in the status of their CephObjectStoreUser
+func (c *usageCollector) updateUserStatus(usage *ObjectStoreUsage) error {
+	users := &cephv1.CephObjectStoreUserList{}
+	if err := c.client.List(context.TODO(), users, client.InNamespace(c.namespacedName.Namespace)); err != nil {
+		return errors.Wrap(err, "failed to list the object store users")
+	}
+
+	lastCollected := time.Now().UTC().Format(time.RFC3339)
+	for i := range users.Items {
+		user := &users.Items[i]
+		if user.Spec.Store != c.namespacedName.Name || user.Status == nil {
+			continue
+		}
+		userUsage, ok := usage.Users[user.Name]
+		if !ok {
+			userUsage = &Usage{}
+		}
+		user.Status.Usage = &cephv1.ObjectUsageStatus{
+			Size:          userUsage.Size,
+			Objects:       userUsage.Objects,
+			Ops:           userUsage.Ops,
+			SuccessfulOps: userUsage.SuccessfulOps,
+			BytesSent:     userUsage.BytesSent,
+			BytesReceived: userUsage.BytesReceived,
+			LastCollected: lastCollected,
+		}
+		if err := opcontroller.UpdateStatus(c.client, user); err != nil {
+			return errors.Wrapf(err, "failed to update the usage of object store user %q", user.Name)
+		}
+	}
+	return nil
+}
+
+// updateClaimAnnotations reports the usage of the buckets in the annotations of their ObjectBucketClaim
+func (c *usageCollector) updateClaimAnnotations(usage *ObjectStoreUsage) error {
+	provisioner := strings.Replace(GetObjectBucketProvisioner(c.context, c.namespacedName.Namespace), "/", "-", -1)
+	selector := fmt.Sprintf("bucket-provisioner=%s", provisioner)
+	objectBuckets, err := c.bktclient.ObjectbucketV1alpha1().ObjectBuckets().List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return errors.Wrap(err, "failed to list the object buckets")
+	}
+
+	// the buckets of the other object stores of the cluster are provisioned by the same provisioner
+	storeHost := BuildDomainName(c.namespacedName.Name, c.namespacedName.Namespace)
+	for _, ob := range objectBuckets.Items {
+		if ob.Spec.Connection == nil || ob.Spec.Endpoint == nil || ob.Spec.Endpoint.BucketHost != storeHost || ob.Spec.ClaimRef == nil {
+			continue
+		}
+		bucketUsage, ok := usage.Buckets[ob.Spec.Endpoint.BucketName]
+		if !ok {
+			continue
+		}
+
+		claimRef := ob.Spec.ClaimRef
+		obc, err := c.bktclient.ObjectbucketV1alpha1().ObjectBucketClaims(claimRef.Namespace).Get(claimRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get OBC %q in namespace %q", claimRef.Name, claimRef.Namespace)
+		}
+		annotations := map[string]string{
+			usageAnnotationPrefix + "size":           strconv.FormatUint(bucketUsage.Size, 10),
+			usageAnnotationPrefix + "objects":        strconv.FormatUint(bucketUsage.Objects, 10),
+			usageAnnotationPrefix + "ops":            strconv.FormatUint(bucketUsage.Ops, 10),
+			usageAnnotationPrefix + "successful-ops": strconv.FormatUint(bucketUsage.SuccessfulOps, 10),
+			usageAnnotationPrefix + "sent-bytes":     strconv.FormatUint(bucketUsage.BytesSent, 10),
+			usageAnnotationPrefix + "received-bytes": strconv.FormatUint(bucketUsage.BytesReceived, 10),
+		}
+		changed := false
+		if obc.Annotations == nil {
+			obc.Annotations = map[string]string{}
+		}
+		for key, value := range annotations {
+			if obc.Annotations[key] != value {
+				obc.Annotations[key] = value
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if _, err := c.bktclient.ObjectbucketV1alpha1().ObjectBucketClaims(claimRef.Namespace).Update(obc); err != nil {
+			return errors.Wrapf(err, "failed to update the usage of OBC %q in namespace %q", claimRef.Name, claimRef.Namespace)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Usage is the usage of a bucket, or of the buckets owned by a user. The operations and the bytes transferred are
+// the totals of the usage log of the gateways.
+type Usage struct {
+	Size          uint64
+	Objects       uint64
+	Ops           uint64
+	SuccessfulOps uint64
+	BytesSent     uint64
+	BytesReceived uint64
+}
+
+// BucketUsage is the usage of a bucket
+type BucketUsage struct {
+	Owner string
+	Usage
+}
+
+// ObjectStoreUsage is the usage of the buckets and users of an object store
+type ObjectStoreUsage struct {
+	Buckets map[string]*BucketUsage
+	Users   map[string]*Usage
+}
+
+type rgwUsageCounters struct {
+	BytesSent     uint64 `json:"bytes_sent"`
+	BytesReceived uint64 `json:"bytes_received"`
+	Ops           uint64 `json:"ops"`
+	SuccessfulOps uint64 `json:"successful_ops"`
+}
+
+type rgwUsageLog struct {
+	Entries []struct {
+		User    string `json:"user"`
+		Buckets []struct {
+			Bucket     string             `json:"bucket"`
+			Categories []rgwUsageCounters `json:"categories"`
+		} `json:"buckets"`
+	} `json:"entries"`
+	Summary []struct {
+		User  string           `json:"user"`
+		Total rgwUsageCounters `json:"total"`
+	} `json:"summary"`
+}
+
+func (u *Usage) addCounters(counters rgwUsageCounters) {
+	u.Ops += counters.Ops
+	u.SuccessfulOps += counters.SuccessfulOps
+	u.BytesSent += counters.BytesSent
+	u.BytesReceived += counters.BytesReceived
+}
+
+// GetUsage returns the usage of the buckets and users of the object store from the bucket stats and the usage log.
+// The usage log is empty unless the gateways log the usage.
+func GetUsage(c *Context) (*ObjectStoreUsage, error) {
+	result, err := runAdminCommand(c, "bucket", "stats")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the bucket stats. %s", result)
+	}
+	var rgwStats []rgwBucketStats
+	if err := json.Unmarshal([]byte(result), &rgwStats); err != nil {
+		return nil, errors.Wrapf(err, "failed to read buckets stats result=%s", result)
+	}
+
+	usage := &ObjectStoreUsage{Buckets: map[string]*BucketUsage{}, Users: map[string]*Usage{}}
+	userUsage := func(user string) *Usage {
+		if _, ok := usage.Users[user]; !ok {
+			usage.Users[user] = &Usage{}
+		}
+		return usage.Users[user]
+	}
+	for _, rgwStat := range rgwStats {
+		stats := bucketStatsFromRGW(rgwStat)
+		usage.Buckets[rgwStat.Bucket] = &BucketUsage{
+			Owner: rgwStat.Owner,
+			Usage: Usage{Size: stats.Size, Objects: stats.NumberOfObjects},
+		}
+		owner := userUsage(rgwStat.Owner)
+		owner.Size += stats.Size
+		owner.Objects += stats.NumberOfObjects
+	}
+
+	result, err = runAdminCommand(c, "usage", "show")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the usage log. %s", result)
+	}
+	var usageLog rgwUsageLog
+	if err := json.Unmarshal([]byte(result), &usageLog); err != nil {
+		return nil, errors.Wrapf(err, "failed to read usage log result=%s", result)
+	}
+	for _, entry := range usageLog.Entries {
+		for _, bucket := range entry.Buckets {
+			// the operations on no bucket, like listing the buckets, are only counted for the user
+			bucketUsage, ok := usage.Buckets[bucket.Bucket]
+			if !ok {
+				continue
+			}
+			for _, category := range bucket.Categories {
+				bucketUsage.addCounters(category)
+			}
+		}
+	}
+	for _, summary := range usageLog.Summary {
+		userUsage(summary.User).addCounters(summary.Total)
+	}
+
+	return usage, nil
+}
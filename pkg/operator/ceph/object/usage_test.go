@@ -0,0 +1,201 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	bktfake "github.com/kube-object-storage/lib-bucket-provisioner/pkg/client/clientset/versioned/fake"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	bucketStatsJSON = `[
+		{"bucket":"photos","owner":"alice","usage":{"rgw.main":{"size":1000,"num_objects":3},"rgw.multimeta":{"size":0,"num_objects":1}}},
+		{"bucket":"logs","owner":"alice","usage":{"rgw.main":{"size":500,"num_objects":2}}},
+		{"bucket":"backups","owner":"bob","usage":{}}
+	]`
+	usageLogJSON = `{
+		"entries":[
+			{"user":"alice","buckets":[
+				{"bucket":"","categories":[{"category":"list_buckets","bytes_sent":100,"bytes_received":0,"ops":2,"successful_ops":2}]},
+				{"bucket":"photos","categories":[
+					{"category":"put_obj","bytes_sent":0,"bytes_received":1000,"ops":3,"successful_ops":3},
+					{"category":"get_obj","bytes_sent":2000,"bytes_received":0,"ops":2,"successful_ops":1}]}]},
+			{"user":"bob","buckets":[
+				{"bucket":"photos","categories":[{"category":"get_obj","bytes_sent":1000,"bytes_received":0,"ops":1,"successful_ops":1}]}]}
+		],
+		"summary":[
+			{"user":"alice","total":{"bytes_sent":2100,"bytes_received":1000,"ops":7,"successful_ops":6}},
+			{"user":"bob","total":{"bytes_sent":1000,"bytes_received":0,"ops":1,"successful_ops":1}}
+		]
+	}`
+)
+
+func usageExecutor() *exectest.MockExecutor {
+	return &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "bucket" && args[1] == "stats" {
+				return bucketStatsJSON, nil
+			}
+			if args[0] == "usage" && args[1] == "show" {
+				return usageLogJSON, nil
+			}
+			return "", nil
+		},
+	}
+}
+
+func TestGetUsage(t *testing.T) {
+	c := NewContext(&clusterd.Context{Executor: usageExecutor()}, clienttest.CreateTestClusterInfo(1), "my-store")
+
+	usage, err := GetUsage(c)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(usage.Buckets))
+	// the operations of all the users on the bucket are counted
+	assert.Equal(t, BucketUsage{Owner: "alice", Usage: Usage{Size: 1000, Objects: 4, Ops: 6, SuccessfulOps: 5, BytesSent: 3000, BytesReceived: 1000}}, *usage.Buckets["photos"])
+	assert.Equal(t, BucketUsage{Owner: "alice", Usage: Usage{Size: 500, Objects: 2}}, *usage.Buckets["logs"])
+	// the users own the buckets and are accounted their own operations
+	assert.Equal(t, Usage{Size: 1500, Objects: 6, Ops: 7, SuccessfulOps: 6, BytesSent: 2100, BytesReceived: 1000}, *usage.Users["alice"])
+	assert.Equal(t, Usage{Ops: 1, SuccessfulOps: 1, BytesSent: 1000}, *usage.Users["bob"])
+}
+
+func TestSetUsageMetrics(t *testing.T) {
+	usage := &ObjectStoreUsage{
+		Buckets: map[string]*BucketUsage{
+			"photos": {Owner: "alice", Usage: Usage{Size: 1000, Objects: 4}},
+			"logs":   {Owner: "alice", Usage: Usage{Size: 500, Objects: 2}},
+		},
+		Users: map[string]*Usage{"alice": {Size: 1500, Objects: 6, Ops: 7}},
+	}
+	setUsageMetrics("rook-ceph", "my-store", usage)
+	expected := `
+# HELP rook_ceph_object_bucket_size_bytes Size of the objects of the bucket
+# TYPE rook_ceph_object_bucket_size_bytes gauge
+rook_ceph_object_bucket_size_bytes{bucket="logs",namespace="rook-ceph",object_store="my-store",owner="alice"} 500
+rook_ceph_object_bucket_size_bytes{bucket="photos",namespace="rook-ceph",object_store="my-store",owner="alice"} 1000
+# HELP rook_ceph_object_user_ops_total Number of operations on the buckets owned by the user in the usage log
+# TYPE rook_ceph_object_user_ops_total counter
+rook_ceph_object_user_ops_total{namespace="rook-ceph",object_store="my-store",user="alice"} 7
+`
+	assert.NoError(t, testutil.CollectAndCompare(usageMetricsCollector{}, strings.NewReader(expected),
+		"rook_ceph_object_bucket_size_bytes", "rook_ceph_object_user_ops_total"))
+	// 6 metrics for each bucket and user
+	assert.Equal(t, 18, testutil.CollectAndCount(usageMetricsCollector{}))
+
+	// the metrics of a deleted bucket are removed
+	delete(usage.Buckets, "logs")
+	setUsageMetrics("rook-ceph", "my-store", usage)
+	assert.Equal(t, 12, testutil.CollectAndCount(usageMetricsCollector{}))
+
+	// the metrics of a deleted store are removed
+	deleteUsageMetrics("rook-ceph", "my-store")
+	assert.Equal(t, 0, testutil.CollectAndCount(usageMetricsCollector{}))
+}
+
+func TestCollectUsage(t *testing.T) {
+	namespace := "rook-ceph"
+	store := &cephv1.CephObjectStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: namespace},
+		Spec: cephv1.ObjectStoreSpec{
+			Usage: &cephv1.ObjectStoreUsageSpec{Interval: "1h", UpdateStatus: true},
+		},
+	}
+	user := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice", Namespace: namespace},
+		Spec:       cephv1.ObjectStoreUserSpec{Store: "my-store"},
+		Status:     &cephv1.ObjectStoreUserStatus{Phase: k8sutil.ReadyStatus},
+	}
+	otherUser := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "bob", Namespace: namespace},
+		Spec:       cephv1.ObjectStoreUserSpec{Store: "other-store"},
+		Status:     &cephv1.ObjectStoreUserStatus{Phase: k8sutil.ReadyStatus},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephObjectStore{}, &cephv1.CephObjectStoreList{},
+		&cephv1.CephObjectStoreUser{}, &cephv1.CephObjectStoreUserList{})
+	cl := fake.NewFakeClientWithScheme(s, []runtime.Object{store, user, otherUser}...)
+
+	ob := &bktv1alpha1.ObjectBucket{
+		ObjectMeta: metav1.ObjectMeta{Name: "obc-default-photos", Labels: map[string]string{"bucket-provisioner": "ceph.rook.io-bucket"}},
+		Spec: bktv1alpha1.ObjectBucketSpec{
+			ClaimRef: &v1.ObjectReference{Namespace: "default", Name: "photos"},
+			Connection: &bktv1alpha1.Connection{
+				Endpoint: &bktv1alpha1.Endpoint{BucketHost: BuildDomainName("my-store", namespace), BucketName: "photos"},
+			},
+		},
+	}
+	obc := &bktv1alpha1.ObjectBucketClaim{ObjectMeta: metav1.ObjectMeta{Name: "photos", Namespace: "default"}}
+	bktclient := bktfake.NewSimpleClientset(ob, obc)
+
+	clientset := testop.New(t, 1)
+	c := &clusterd.Context{Executor: usageExecutor(), Clientset: clientset}
+	objContext := NewContext(c, clienttest.CreateTestClusterInfo(1), "my-store")
+	collector := newUsageCollector(c, objContext, cl, bktclient, types.NamespacedName{Name: "my-store", Namespace: namespace})
+
+	err := collector.collect()
+	assert.NoError(t, err)
+	assert.Equal(t, "1h0m0s", collector.interval.String())
+	expected := `
+# HELP rook_ceph_object_user_size_bytes Size of the objects of the buckets owned by the user
+# TYPE rook_ceph_object_user_size_bytes gauge
+rook_ceph_object_user_size_bytes{namespace="rook-ceph",object_store="my-store",user="alice"} 1500
+rook_ceph_object_user_size_bytes{namespace="rook-ceph",object_store="my-store",user="bob"} 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(usageMetricsCollector{}, strings.NewReader(expected), "rook_ceph_object_user_size_bytes"))
+
+	// the usage of the users of the store is reported in their status
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "alice", Namespace: namespace}, user)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1500), user.Status.Usage.Size)
+	assert.Equal(t, uint64(7), user.Status.Usage.Ops)
+	assert.NotEmpty(t, user.Status.Usage.LastCollected)
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "bob", Namespace: namespace}, otherUser)
+	assert.NoError(t, err)
+	assert.Nil(t, otherUser.Status.Usage)
+
+	// the usage of the bucket is reported in the annotations of the claim
+	obc, err = bktclient.ObjectbucketV1alpha1().ObjectBucketClaims("default").Get("photos", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "1000", obc.Annotations["ceph.rook.io/usage-size"])
+	assert.Equal(t, "4", obc.Annotations["ceph.rook.io/usage-objects"])
+	assert.Equal(t, "6", obc.Annotations["ceph.rook.io/usage-ops"])
+
+	// the metrics are removed when the usage collection is disabled
+	store.Spec.Usage = nil
+	err = cl.Update(context.TODO(), store)
+	assert.NoError(t, err)
+	err = collector.collect()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, testutil.CollectAndCount(usageMetricsCollector{}))
+}
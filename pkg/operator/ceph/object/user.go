@@ -43,6 +43,18 @@ type ObjectUser struct {
 	AccessKey   *string `json:"accessKey"`
 	SecretKey   *string `json:"secretKey"`
 	SystemUser  bool    `json:"systemuser"`
+	// Keys are all the S3 keys of the user, AccessKey and SecretKey being the first one
+	Keys []ObjectUserKey `json:"keys,omitempty"`
+	// Caps are the admin capabilities of the user by type
+	Caps map[string]string `json:"caps,omitempty"`
+	// QuotaEnabled is whether the user quota is enabled
+	QuotaEnabled bool `json:"quotaEnabled,omitempty"`
+}
+
+// ObjectUserKey is an S3 key pair of an object store user
+type ObjectUserKey struct {
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
 }
 
 // ListUsers lists the object pool users.
@@ -68,6 +80,13 @@ type rgwUserInfo struct {
 		AccessKey string `json:"access_key"`
 		SecretKey string `json:"secret_key"`
 	}
+	Caps []struct {
+		Type string `json:"type"`
+		Perm string `json:"perm"`
+	}
+	UserQuota struct {
+		Enabled bool `json:"enabled"`
+	} `json:"user_quota"`
 }
 
 func decodeUser(data string) (*ObjectUser, int, error) {
@@ -85,6 +104,14 @@ func decodeUser(data string) (*ObjectUser, int, error) {
 	} else {
 		return nil, RGWErrorBadData, errors.New("AccessKey and SecretKey are missing")
 	}
+	for _, key := range user.Keys {
+		rookUser.Keys = append(rookUser.Keys, ObjectUserKey{AccessKey: key.AccessKey, SecretKey: key.SecretKey})
+	}
+	rookUser.Caps = map[string]string{}
+	for _, cap := range user.Caps {
+		rookUser.Caps[cap.Type] = cap.Perm
+	}
+	rookUser.QuotaEnabled = user.UserQuota.Enabled
 
 	return &rookUser, RGWErrorNone, nil
 }
@@ -257,3 +284,54 @@ func SetQuotaUserMaxSize(c *Context, id string, maxsize string) (string, error)
 	}
 	return result, err
 }
+
+// DisableUserQuota will disable the quota defined for a user
+func DisableUserQuota(c *Context, id string) (string, error) {
+	logger.Debugf("Disabling user quota for %q", id)
+	args := []string{"quota", "disable", "--quota-scope", "user", "--uid", id}
+	result, err := runAdminCommand(c, args...)
+	if err != nil {
+		err = errors.Wrap(err, "failed to disable quota for the user")
+	}
+	return result, err
+}
+
+// AddUserCaps adds the admin capabilities to a user, e.g. "users=read;buckets=*"
+func AddUserCaps(c *Context, id string, caps string) (string, error) {
+	logger.Infof("adding caps %q to user %q", caps, id)
+	result, err := runAdminCommand(c, "caps", "add", "--uid", id, "--caps", caps)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to add caps %q to user %q", caps, id)
+	}
+	return result, err
+}
+
+// RemoveUserCaps removes the admin capabilities from a user, e.g. "users=read;buckets=*"
+func RemoveUserCaps(c *Context, id string, caps string) (string, error) {
+	logger.Infof("removing caps %q from user %q", caps, id)
+	result, err := runAdminCommand(c, "caps", "rm", "--uid", id, "--caps", caps)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to remove caps %q from user %q", caps, id)
+	}
+	return result, err
+}
+
+// CreateUserKey generates a new S3 key pair for the user and returns the user with all its keys
+func CreateUserKey(c *Context, id string) (*ObjectUser, int, error) {
+	logger.Infof("generating a new key for user %q", id)
+	result, err := runAdminCommand(c, "key", "create", "--uid", id, "--key-type", "s3", "--gen-access-key", "--gen-secret")
+	if err != nil {
+		return nil, RGWErrorUnknown, errors.Wrapf(err, "failed to create key for user %q", id)
+	}
+	return decodeUser(result)
+}
+
+// DeleteUserKey removes the S3 key pair with the given access key from the user
+func DeleteUserKey(c *Context, id string, accessKey string) (string, error) {
+	logger.Infof("removing key %q of user %q", accessKey, id)
+	result, err := runAdminCommand(c, "key", "rm", "--uid", id, "--key-type", "s3", "--access-key", accessKey)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to remove key %q of user %q", accessKey, id)
+	}
+	return result, err
+}
@@ -71,6 +71,7 @@ type ReconcileObjectStoreUser struct {
 	userConfig      object.ObjectUser
 	cephClusterSpec *cephv1.ClusterSpec
 	clusterInfo     *cephclient.ClusterInfo
+	keyRotation     *cephv1.ObjectUserKeyRotationStatus
 }
 
 // Add creates a new CephObjectStoreUser Controller and adds it to the Manager. The Manager will set fields on the Controller
@@ -149,7 +150,7 @@ func (r *ReconcileObjectStoreUser) reconcile(request reconcile.Request) (reconci
 
 	// The CR was just created, initializing status fields
 	if cephObjectStoreUser.Status == nil {
-		updateStatus(r.client, request.NamespacedName, k8sutil.Created)
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, nil)
 	}
 
 	// Make sure a CephCluster is present otherwise do nothing
@@ -202,7 +203,7 @@ func (r *ReconcileObjectStoreUser) reconcile(request reconcile.Request) (reconci
 		}
 		logger.Debugf("ObjectStore resource not ready in namespace %q, retrying in %q. %v",
 			request.NamespacedName.Namespace, opcontroller.WaitForRequeueIfCephClusterNotReady.RequeueAfter.String(), err)
-		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, nil)
 		return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
 	}
 
@@ -231,67 +232,96 @@ func (r *ReconcileObjectStoreUser) reconcile(request reconcile.Request) (reconci
 	// validate the user settings
 	err = r.validateUser(cephObjectStoreUser)
 	if err != nil {
-		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, nil)
 		return reconcile.Result{}, errors.Wrapf(err, "invalid pool CR %q spec", cephObjectStoreUser.Name)
 	}
 
 	// CREATE/UPDATE CEPH USER
 	reconcileResponse, err = r.reconcileCephUser(cephObjectStoreUser)
 	if err != nil {
-		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, nil)
 		return reconcileResponse, err
 	}
 
 	// CREATE/UPDATE KUBERNETES SECRET
 	reconcileResponse, err = r.reconcileCephUserSecret(cephObjectStoreUser)
 	if err != nil {
-		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, r.keyRotation)
 		return reconcileResponse, err
 	}
 
+	// REMOVE THE KEYS AT THE END OF THEIR GRACE PERIOD
+	reconcileResponse, err = r.retireKeys(cephObjectStoreUser)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, r.keyRotation)
+		return reconcileResponse, errors.Wrapf(err, "failed to remove the previous keys of ceph object user %q", cephObjectStoreUser.Name)
+	}
+
 	// Set Ready status, we are done reconciling
-	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, r.keyRotation)
 
-	// Return and do not requeue
+	// Return and only requeue if keys are retiring
 	logger.Debug("done reconciling")
-	return reconcile.Result{}, nil
+	return reconcileResponse, nil
 }
 
 func (r *ReconcileObjectStoreUser) reconcileCephUser(cephObjectStoreUser *cephv1.CephObjectStoreUser) (reconcile.Result, error) {
-	err := r.createorUpdateCephUser(cephObjectStoreUser)
+	created, err := r.createorUpdateCephUser(cephObjectStoreUser)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "failed to create/update object store user %q", cephObjectStoreUser.Name)
 	}
 
+	err = r.reconcileQuotas(cephObjectStoreUser)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to set quotas of object store user %q", cephObjectStoreUser.Name)
+	}
+
+	err = r.reconcileCaps(cephObjectStoreUser)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to set capabilities of object store user %q", cephObjectStoreUser.Name)
+	}
+
+	err = r.rotateKeys(cephObjectStoreUser, created)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile keys of object store user %q", cephObjectStoreUser.Name)
+	}
+
 	return reconcile.Result{}, nil
 }
 
-func (r *ReconcileObjectStoreUser) createorUpdateCephUser(u *cephv1.CephObjectStoreUser) error {
+// createorUpdateCephUser creates the ceph user or updates it if it exists, returns whether it was created
+func (r *ReconcileObjectStoreUser) createorUpdateCephUser(u *cephv1.CephObjectStoreUser) (bool, error) {
 	logger.Infof("creating ceph object user %q in namespace %q", u.Name, u.Namespace)
 	user, rgwerr, err := object.CreateUser(r.objContext, r.userConfig)
 	if err != nil {
 		if rgwerr == object.ErrorCodeFileExists {
 			objectUser, _, err := object.UpdateUser(r.objContext, r.userConfig)
 			if err != nil {
-				return errors.Wrapf(err, "failed to get details from ceph object user %q", objectUser.UserID)
+				return false, errors.Wrapf(err, "failed to get details from ceph object user %q", u.Name)
 			}
 
 			// Set access and secret key
 			r.userConfig.AccessKey = objectUser.AccessKey
 			r.userConfig.SecretKey = objectUser.SecretKey
+			r.userConfig.Keys = objectUser.Keys
+			r.userConfig.Caps = objectUser.Caps
+			r.userConfig.QuotaEnabled = objectUser.QuotaEnabled
 			logger.Debugf("ceph object user %q updated with display name %q", u.Name, *objectUser.DisplayName)
 
-			return nil
+			return false, nil
 		}
-		return errors.Wrapf(err, "failed to create ceph object user %q. error code %d", u.Name, rgwerr)
+		return false, errors.Wrapf(err, "failed to create ceph object user %q. error code %d", u.Name, rgwerr)
 	}
 
 	// Set access and secret key
 	r.userConfig.AccessKey = user.AccessKey
 	r.userConfig.SecretKey = user.SecretKey
+	r.userConfig.Keys = user.Keys
+	r.userConfig.Caps = user.Caps
+	r.userConfig.QuotaEnabled = user.QuotaEnabled
 
 	logger.Infof("created ceph object user %q", u.Name)
-	return nil
+	return true, nil
 }
 
 func (r *ReconcileObjectStoreUser) initializeObjectStoreContext(u *cephv1.CephObjectStoreUser) error {
@@ -480,6 +510,15 @@ func (r *ReconcileObjectStoreUser) validateUser(u *cephv1.CephObjectStoreUser) e
 			return errors.New("missing store")
 		}
 	}
+	if err := validateQuotas(u.Spec.Quotas); err != nil {
+		return err
+	}
+	if err := validateCaps(u.Spec.Capabilities); err != nil {
+		return err
+	}
+	if _, err := gracePeriod(u.Spec.KeyRotation); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -487,8 +526,8 @@ func labelsForRgw(name string) map[string]string {
 	return map[string]string{"rgw": name, k8sutil.AppAttr: appName}
 }
 
-// updateStatus updates an object with a given status
-func updateStatus(client client.Client, name types.NamespacedName, status string) {
+// updateStatus updates an object with a given status, the key rotation status is left unchanged if nil
+func updateStatus(client client.Client, name types.NamespacedName, status string, keyRotation *cephv1.ObjectUserKeyRotationStatus) {
 	user := &cephv1.CephObjectStoreUser{}
 	if err := client.Get(context.TODO(), name, user); err != nil {
 		if kerrors.IsNotFound(err) {
@@ -506,9 +545,30 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 	if user.Status.Phase == k8sutil.ReadyStatus {
 		user.Status.Info = generateStatusInfo(user)
 	}
+	if keyRotation != nil {
+		user.Status.KeyRotation = keyRotation
+	}
 	if err := opcontroller.UpdateStatus(client, user); err != nil {
 		logger.Errorf("failed to set object store user %q status to %q. %v", name, status, err)
 		return
 	}
 	logger.Debugf("object store user %q status updated to %q", name, status)
 }
+
+// updateKeyRotationStatus records the key rotation state in the status of the user, unlike updateStatus it fails if
+// the status cannot be updated
+func updateKeyRotationStatus(client client.Client, name types.NamespacedName, keyRotation *cephv1.ObjectUserKeyRotationStatus) error {
+	user := &cephv1.CephObjectStoreUser{}
+	if err := client.Get(context.TODO(), name, user); err != nil {
+		return errors.Wrapf(err, "failed to retrieve object store user %q to update its key rotation status", name)
+	}
+	if user.Status == nil {
+		user.Status = &cephv1.ObjectStoreUserStatus{}
+	}
+
+	user.Status.KeyRotation = keyRotation
+	if err := opcontroller.UpdateStatus(client, user); err != nil {
+		return errors.Wrapf(err, "failed to update the key rotation status of object store user %q", name)
+	}
+	return nil
+}
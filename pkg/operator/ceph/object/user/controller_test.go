@@ -19,7 +19,9 @@ package objectuser
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/coreos/pkg/capnslog"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -28,10 +30,13 @@ import (
 	"github.com/rook/rook/pkg/operator/test"
 
 	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephobject "github.com/rook/rook/pkg/operator/ceph/object"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -311,3 +316,189 @@ func TestBuildUpdateStatusInfo(t *testing.T) {
 	assert.NotEmpty(t, statusInfo["secretName"])
 	assert.Equal(t, "rook-ceph-object-user-my-store-my-user", statusInfo["secretName"])
 }
+
+func TestValidateUserSettings(t *testing.T) {
+	r := &ReconcileObjectStoreUser{cephClusterSpec: &cephv1.ClusterSpec{}}
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       cephv1.ObjectStoreUserSpec{Store: store},
+	}
+	assert.NoError(t, r.validateUser(u))
+
+	maxBuckets := 10
+	maxObjects := int64(1000)
+	maxSize := resource.MustParse("10Gi")
+	u.Spec.Quotas = &cephv1.ObjectUserQuotaSpec{MaxBuckets: &maxBuckets, MaxObjects: &maxObjects, MaxSize: &maxSize}
+	u.Spec.Capabilities = &cephv1.ObjectUserCapSpec{Users: "read", Buckets: "*"}
+	u.Spec.KeyRotation = &cephv1.ObjectUserKeyRotationSpec{Trigger: "2020-10-01", GracePeriod: "24h"}
+	assert.NoError(t, r.validateUser(u))
+
+	u.Spec.Capabilities.Usage = "everything"
+	assert.Error(t, r.validateUser(u))
+	u.Spec.Capabilities.Usage = ""
+
+	negative := -1
+	u.Spec.Quotas.MaxBuckets = &negative
+	assert.Error(t, r.validateUser(u))
+	u.Spec.Quotas.MaxBuckets = &maxBuckets
+
+	u.Spec.KeyRotation.GracePeriod = "one day"
+	assert.Error(t, r.validateUser(u))
+}
+
+func TestReconcileQuotasAndCaps(t *testing.T) {
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			commands = append(commands, strings.Join(args[:6], " "))
+			return "", nil
+		},
+	}
+	c := &clusterd.Context{Executor: executor}
+	r := &ReconcileObjectStoreUser{context: c, objContext: cephobject.NewContext(c, cephclient.AdminClusterInfo(namespace), "")}
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       cephv1.ObjectStoreUserSpec{Store: store},
+	}
+
+	// nothing is managed
+	assert.NoError(t, r.reconcileQuotas(u))
+	assert.NoError(t, r.reconcileCaps(u))
+	assert.Equal(t, 0, len(commands))
+
+	// the max size is set and the max objects removed
+	maxSize := resource.MustParse("1Ki")
+	u.Spec.Quotas = &cephv1.ObjectUserQuotaSpec{MaxSize: &maxSize}
+	assert.NoError(t, r.reconcileQuotas(u))
+	assert.Equal(t, []string{
+		"quota set --uid my-user --quota-scope user",
+		"quota set --uid my-user --quota-scope user",
+		"quota enable --quota-scope user --uid my-user",
+	}, commands)
+
+	// the quotas removed from the spec are disabled
+	commands = []string{}
+	u.Spec.Quotas = nil
+	r.userConfig.QuotaEnabled = true
+	assert.NoError(t, r.reconcileQuotas(u))
+	assert.Equal(t, []string{"quota disable --quota-scope user --uid my-user"}, commands)
+
+	// the usage cap is added, the users cap is changed and the metadata cap removed
+	commands = []string{}
+	r.userConfig.Caps = map[string]string{"users": "read", "buckets": "*", "metadata": "write"}
+	u.Spec.Capabilities = &cephv1.ObjectUserCapSpec{Users: "*", Buckets: "*", Usage: "read"}
+	assert.NoError(t, r.reconcileCaps(u))
+	assert.ElementsMatch(t, []string{
+		"caps rm --uid my-user --caps users=read",
+		"caps add --uid my-user --caps users=*",
+		"caps add --uid my-user --caps usage=read",
+		"caps rm --uid my-user --caps metadata=write",
+	}, commands)
+}
+
+func TestRotateKeys(t *testing.T) {
+	now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	commands := []string{}
+	keyCreateJSON := `{"user_id":"my-user","display_name":"my-user","keys":[{"user":"my-user","access_key":"NEW","secret_key":"new-secret"},{"user":"my-user","access_key":"OLD","secret_key":"old-secret"}],"caps":[]}`
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			commands = append(commands, strings.Join(args[:2], " "))
+			if args[0] == "key" && args[1] == "create" {
+				return keyCreateJSON, nil
+			}
+			return "", nil
+		},
+	}
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       cephv1.ObjectStoreUserSpec{Store: store},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephObjectStoreUser{}, &cephv1.CephObjectStoreUserList{})
+	cl := fake.NewFakeClientWithScheme(s, u.DeepCopy())
+	c := &clusterd.Context{Executor: executor}
+	r := &ReconcileObjectStoreUser{client: cl, context: c, objContext: cephobject.NewContext(c, cephclient.AdminClusterInfo(namespace), "")}
+	r.userConfig.Keys = []cephobject.ObjectUserKey{{AccessKey: "OLD", SecretKey: "old-secret"}}
+	// the access keys recorded in the status, the retiring ones last
+	recorded := func() []string {
+		user := &cephv1.CephObjectStoreUser{}
+		assert.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, user))
+		if user.Status == nil || user.Status.KeyRotation == nil {
+			return nil
+		}
+		keys := []string{user.Status.KeyRotation.AccessKey}
+		for _, key := range user.Status.KeyRotation.RetiringKeys {
+			keys = append(keys, key.AccessKey)
+		}
+		return keys
+	}
+
+	// the keys are not tracked without rotation
+	assert.NoError(t, r.rotateKeys(u, false))
+	assert.Nil(t, r.keyRotation)
+
+	// a created user keeps its first key
+	u.Spec.KeyRotation = &cephv1.ObjectUserKeyRotationSpec{Trigger: "1", GracePeriod: "1h"}
+	assert.NoError(t, r.rotateKeys(u, true))
+	assert.Equal(t, &cephv1.ObjectUserKeyRotationStatus{Trigger: "1", AccessKey: "OLD"}, r.keyRotation)
+	assert.Equal(t, 0, len(commands))
+
+	// the trigger changed, a new key is generated and the old key is retired after the grace period
+	u.Status = &cephv1.ObjectStoreUserStatus{KeyRotation: r.keyRotation}
+	u.Spec.KeyRotation.Trigger = "2"
+	assert.NoError(t, r.rotateKeys(u, false))
+	assert.Equal(t, []string{"key create"}, commands)
+	assert.Equal(t, "2", r.keyRotation.Trigger)
+	assert.Equal(t, "NEW", r.keyRotation.AccessKey)
+	assert.Equal(t, "NEW", *r.userConfig.AccessKey)
+	assert.Equal(t, "new-secret", *r.userConfig.SecretKey)
+	assert.Equal(t, []cephv1.RetiringObjectUserKey{{AccessKey: "OLD", RetireTime: metav1.NewTime(now.Add(time.Hour))}}, r.keyRotation.RetiringKeys)
+	// the rotation is recorded in the status right away
+	assert.Equal(t, []string{"NEW", "OLD"}, recorded())
+
+	// the old key is kept during the grace period
+	commands = []string{}
+	result, err := r.retireKeys(u)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, result.RequeueAfter)
+	assert.Equal(t, 1, len(r.keyRotation.RetiringKeys))
+	assert.Equal(t, 0, len(commands))
+
+	// the old key is removed after the grace period
+	now = now.Add(2 * time.Hour)
+	result, err = r.retireKeys(u)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), result.RequeueAfter)
+	assert.Equal(t, 0, len(r.keyRotation.RetiringKeys))
+	assert.Equal(t, []string{"key rm"}, commands)
+
+	// the same trigger does not rotate the keys again, the new key remains selected
+	commands = []string{}
+	u.Status.KeyRotation = r.keyRotation
+	r.userConfig.Keys = []cephobject.ObjectUserKey{{AccessKey: "ANOTHER", SecretKey: "another-secret"}, {AccessKey: "NEW", SecretKey: "new-secret"}}
+	assert.NoError(t, r.rotateKeys(u, false))
+	assert.Equal(t, 0, len(commands))
+	assert.Equal(t, "NEW", *r.userConfig.AccessKey)
+
+	// the rotation cannot be recorded, the reconcile fails
+	u.Status.KeyRotation = r.keyRotation
+	u.Spec.KeyRotation.Trigger = "3"
+	r.userConfig.Keys = []cephobject.ObjectUserKey{{AccessKey: "NEW", SecretKey: "new-secret"}}
+	keyCreateJSON = `{"user_id":"my-user","display_name":"my-user","keys":[{"user":"my-user","access_key":"NEW","secret_key":"new-secret"},{"user":"my-user","access_key":"NEWER","secret_key":"newer-secret"}],"caps":[]}`
+	r.client = fake.NewFakeClientWithScheme(s)
+	assert.Error(t, r.rotateKeys(u, false))
+	assert.Equal(t, []string{"key create"}, commands)
+
+	// the retry does not create another key, the key created by the failed rotation is the new key
+	commands = []string{}
+	r.client = cl
+	r.userConfig.Keys = []cephobject.ObjectUserKey{{AccessKey: "NEW", SecretKey: "new-secret"}, {AccessKey: "NEWER", SecretKey: "newer-secret"}}
+	assert.NoError(t, r.rotateKeys(u, false))
+	assert.Equal(t, 0, len(commands))
+	assert.Equal(t, "NEWER", *r.userConfig.AccessKey)
+	assert.Equal(t, []cephv1.RetiringObjectUserKey{{AccessKey: "NEW", RetireTime: metav1.NewTime(now.Add(time.Hour))}}, r.keyRotation.RetiringKeys)
+	assert.Equal(t, []string{"NEWER", "NEW"}, recorded())
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// timeNow is overridden by the tests
+var timeNow = time.Now
+
+func gracePeriod(rotation *cephv1.ObjectUserKeyRotationSpec) (time.Duration, error) {
+	if rotation == nil || rotation.GracePeriod == "" {
+		return 0, nil
+	}
+	period, err := time.ParseDuration(rotation.GracePeriod)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid key rotation grace period %q", rotation.GracePeriod)
+	}
+	if period < 0 {
+		return 0, errors.Errorf("invalid key rotation grace period %q, must not be negative", rotation.GracePeriod)
+	}
+	return period, nil
+}
+
+// rotateKeys generates a new key pair when the rotation trigger of the user changed and selects the key
+// stored in the user secret. The keys of a user created in this reconcile are not rotated.
+func (r *ReconcileObjectStoreUser) rotateKeys(u *cephv1.CephObjectStoreUser, created bool) error {
+	// the keys are tracked once the rotation is configured
+	if u.Spec.KeyRotation == nil && (u.Status == nil || u.Status.KeyRotation == nil) {
+		r.keyRotation = nil
+		return nil
+	}
+
+	state := &cephv1.ObjectUserKeyRotationStatus{}
+	if u.Status != nil && u.Status.KeyRotation != nil {
+		state = u.Status.KeyRotation.DeepCopy()
+	}
+	trigger := ""
+	if u.Spec.KeyRotation != nil {
+		trigger = u.Spec.KeyRotation.Trigger
+	}
+
+	if !created && trigger != "" && trigger != state.Trigger {
+		period, err := gracePeriod(u.Spec.KeyRotation)
+		if err != nil {
+			return err
+		}
+		previousKeys := r.userConfig.Keys
+		// a key unknown to the status was created by a rotation that could not be recorded, it is the new key
+		newKey := unrecordedUserKey(state, previousKeys)
+		if newKey == nil {
+			user, _, err := object.CreateUserKey(r.objContext, u.Name)
+			if err != nil {
+				return errors.Wrapf(err, "failed to rotate the keys of ceph object user %q", u.Name)
+			}
+			newKey = newUserKey(previousKeys, user.Keys)
+			if newKey == nil {
+				return errors.Errorf("failed to find the new key of ceph object user %q", u.Name)
+			}
+			r.userConfig.Keys = user.Keys
+		}
+
+		// all the previous keys are retired, the keys already retiring keep their retire time
+		retireTime := metav1.NewTime(timeNow().Add(period))
+		for _, key := range previousKeys {
+			if key.AccessKey != newKey.AccessKey && !isRetiring(state, key.AccessKey) {
+				state.RetiringKeys = append(state.RetiringKeys, cephv1.RetiringObjectUserKey{AccessKey: key.AccessKey, RetireTime: retireTime})
+			}
+		}
+		state.AccessKey = newKey.AccessKey
+		state.Trigger = trigger
+
+		// the rotation is recorded before the secret is updated, otherwise the next reconcile would rotate again
+		name := types.NamespacedName{Name: u.Name, Namespace: u.Namespace}
+		if err := updateKeyRotationStatus(r.client, name, state); err != nil {
+			return err
+		}
+		logger.Infof("rotated the keys of ceph object user %q, the previous keys are removed after %s", u.Name, period.String())
+	}
+	state.Trigger = trigger
+
+	// select the key stored in the secret, the first key of the user if it is unknown
+	current := findUserKey(r.userConfig.Keys, state.AccessKey)
+	if current == nil {
+		if len(r.userConfig.Keys) == 0 {
+			return errors.Errorf("ceph object user %q has no key", u.Name)
+		}
+		current = &r.userConfig.Keys[0]
+		state.AccessKey = current.AccessKey
+	}
+	r.userConfig.AccessKey = &current.AccessKey
+	r.userConfig.SecretKey = &current.SecretKey
+
+	r.keyRotation = state
+	return nil
+}
+
+// retireKeys removes the previous keys of the user whose grace period ended and returns when to check
+// the keys still retiring
+func (r *ReconcileObjectStoreUser) retireKeys(u *cephv1.CephObjectStoreUser) (reconcile.Result, error) {
+	if r.keyRotation == nil {
+		return reconcile.Result{}, nil
+	}
+
+	now := timeNow()
+	result := reconcile.Result{}
+	retiring := []cephv1.RetiringObjectUserKey{}
+	for i, key := range r.keyRotation.RetiringKeys {
+		// never remove the key stored in the secret
+		if key.AccessKey == r.keyRotation.AccessKey || findUserKey(r.userConfig.Keys, key.AccessKey) == nil {
+			continue
+		}
+		if wait := key.RetireTime.Time.Sub(now); wait > 0 {
+			retiring = append(retiring, key)
+			if result.RequeueAfter == 0 || wait < result.RequeueAfter {
+				result.RequeueAfter = wait
+			}
+			continue
+		}
+		if _, err := object.DeleteUserKey(r.objContext, u.Name, key.AccessKey); err != nil {
+			// keep the keys not removed yet
+			r.keyRotation.RetiringKeys = append(retiring, r.keyRotation.RetiringKeys[i:]...)
+			return reconcile.Result{}, err
+		}
+	}
+	r.keyRotation.RetiringKeys = retiring
+	return result, nil
+}
+
+// unrecordedUserKey returns the key of the user neither stored in the secret nor retiring. The keys are only unknown
+// to the status once it tracks them, when the status update of a rotation failed after the key was created.
+func unrecordedUserKey(state *cephv1.ObjectUserKeyRotationStatus, keys []object.ObjectUserKey) *object.ObjectUserKey {
+	if state.AccessKey == "" {
+		return nil
+	}
+	for i := range keys {
+		if keys[i].AccessKey != state.AccessKey && !isRetiring(state, keys[i].AccessKey) {
+			return &keys[i]
+		}
+	}
+	return nil
+}
+
+func newUserKey(previous, keys []object.ObjectUserKey) *object.ObjectUserKey {
+	for i := range keys {
+		if findUserKey(previous, keys[i].AccessKey) == nil {
+			return &keys[i]
+		}
+	}
+	return nil
+}
+
+func findUserKey(keys []object.ObjectUserKey, accessKey string) *object.ObjectUserKey {
+	if accessKey == "" {
+		return nil
+	}
+	for i := range keys {
+		if keys[i].AccessKey == accessKey {
+			return &keys[i]
+		}
+	}
+	return nil
+}
+
+func isRetiring(state *cephv1.ObjectUserKeyRotationStatus, accessKey string) bool {
+	for _, key := range state.RetiringKeys {
+		if key.AccessKey == accessKey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+)
+
+// the quota value removing a limit
+const noQuota = "-1"
+
+// validCapPerms are the permissions a capability can be set to
+var validCapPerms = map[string]bool{"": true, "read": true, "write": true, "*": true}
+
+// capsByType returns the capabilities of the user keyed by their radosgw type
+func capsByType(caps *cephv1.ObjectUserCapSpec) map[string]string {
+	return map[string]string{
+		"users":    caps.Users,
+		"buckets":  caps.Buckets,
+		"usage":    caps.Usage,
+		"metadata": caps.Metadata,
+	}
+}
+
+func validateCaps(caps *cephv1.ObjectUserCapSpec) error {
+	if caps == nil {
+		return nil
+	}
+	for capType, perm := range capsByType(caps) {
+		if !validCapPerms[perm] {
+			return errors.Errorf("invalid %q capability %q, must be one of \"read\", \"write\" or \"*\"", capType, perm)
+		}
+	}
+	return nil
+}
+
+func validateQuotas(quotas *cephv1.ObjectUserQuotaSpec) error {
+	if quotas == nil {
+		return nil
+	}
+	if quotas.MaxBuckets != nil && *quotas.MaxBuckets < 0 {
+		return errors.Errorf("invalid max buckets %d, must not be negative", *quotas.MaxBuckets)
+	}
+	if quotas.MaxSize != nil && quotas.MaxSize.Sign() < 0 {
+		return errors.Errorf("invalid max size %q, must not be negative", quotas.MaxSize.String())
+	}
+	if quotas.MaxObjects != nil && *quotas.MaxObjects < 0 {
+		return errors.Errorf("invalid max objects %d, must not be negative", *quotas.MaxObjects)
+	}
+	return nil
+}
+
+// reconcileQuotas sets the quotas of the user, the limits not set in the spec are removed. The user quota is
+// disabled once the quotas are removed from the spec.
+func (r *ReconcileObjectStoreUser) reconcileQuotas(u *cephv1.CephObjectStoreUser) error {
+	quotas := u.Spec.Quotas
+	if quotas == nil {
+		if r.userConfig.QuotaEnabled {
+			if _, err := object.DisableUserQuota(r.objContext, u.Name); err != nil {
+				return err
+			}
+			logger.Infof("disabled the quotas of ceph object user %q", u.Name)
+		}
+		return nil
+	}
+
+	if quotas.MaxBuckets != nil {
+		if _, err := object.SetQuotaUserBucketMax(r.objContext, u.Name, *quotas.MaxBuckets); err != nil {
+			return err
+		}
+	}
+	maxSize := noQuota
+	if quotas.MaxSize != nil {
+		maxSize = strconv.FormatInt(quotas.MaxSize.Value(), 10)
+	}
+	if _, err := object.SetQuotaUserMaxSize(r.objContext, u.Name, maxSize); err != nil {
+		return err
+	}
+	maxObjects := noQuota
+	if quotas.MaxObjects != nil {
+		maxObjects = strconv.FormatInt(*quotas.MaxObjects, 10)
+	}
+	if _, err := object.SetQuotaUserObjectMax(r.objContext, u.Name, maxObjects); err != nil {
+		return err
+	}
+	if _, err := object.EnableUserQuota(r.objContext, u.Name); err != nil {
+		return err
+	}
+
+	logger.Debugf("quotas of ceph object user %q set to max size %s and max objects %s", u.Name, maxSize, maxObjects)
+	return nil
+}
+
+// reconcileCaps sets the admin capabilities of the user, the capabilities not set in the spec are removed
+func (r *ReconcileObjectStoreUser) reconcileCaps(u *cephv1.CephObjectStoreUser) error {
+	if u.Spec.Capabilities == nil {
+		return nil
+	}
+
+	for capType, perm := range capsByType(u.Spec.Capabilities) {
+		current := r.userConfig.Caps[capType]
+		if current == perm {
+			continue
+		}
+		// adding a capability merges it with the current one, so the current one is removed first
+		if current != "" {
+			if _, err := object.RemoveUserCaps(r.objContext, u.Name, fmt.Sprintf("%s=%s", capType, current)); err != nil {
+				return err
+			}
+		}
+		if perm != "" {
+			if _, err := object.AddUserCaps(r.objContext, u.Name, fmt.Sprintf("%s=%s", capType, perm)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
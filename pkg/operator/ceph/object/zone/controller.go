@@ -70,6 +70,8 @@ type ReconcileObjectZone struct {
 	scheme      *runtime.Scheme
 	context     *clusterd.Context
 	clusterInfo *cephclient.ClusterInfo
+	// the stop channels of the sync status checkers, by zone
+	syncCheckers map[string]chan struct{}
 }
 
 // Add creates a new CephObjectZone Controller and adds it to the Manager. The Manager will set fields on the Controller
@@ -86,9 +88,10 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Rec
 		panic(err)
 	}
 	return &ReconcileObjectZone{
-		client:  mgr.GetClient(),
-		scheme:  mgrScheme,
-		context: context,
+		client:       mgr.GetClient(),
+		scheme:       mgrScheme,
+		context:      context,
+		syncCheckers: make(map[string]chan struct{}),
 	}
 }
 
@@ -130,6 +133,7 @@ func (r *ReconcileObjectZone) reconcile(request reconcile.Request) (reconcile.Re
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			logger.Debug("CephObjectZone resource not found. Ignoring since object must be deleted.")
+			r.stopSyncStatusChecker(request.NamespacedName)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -156,6 +160,8 @@ func (r *ReconcileObjectZone) reconcile(request reconcile.Request) (reconcile.Re
 	// DELETE: the CR was deleted
 	if !cephObjectZone.GetDeletionTimestamp().IsZero() {
 		logger.Debugf("deleting zone CR %q", cephObjectZone.Name)
+		r.stopSyncStatusChecker(request.NamespacedName)
+		deleteSyncMetrics(cephObjectZone.Namespace, cephObjectZone.Name)
 
 		// Return and do not requeue. Successful deletion.
 		return reconcile.Result{}, nil
@@ -195,14 +201,50 @@ func (r *ReconcileObjectZone) reconcile(request reconcile.Request) (reconcile.Re
 		return r.setFailedStatus(request.NamespacedName, "failed to create ceph zone", err)
 	}
 
+	// Promote the zone to master of its zone group
+	if cephObjectZone.Spec.Master {
+		err = r.promoteCephZone(cephObjectZone, realmName)
+		if err != nil {
+			return r.setFailedStatus(request.NamespacedName, "failed to promote ceph zone", err)
+		}
+	}
+
 	// Set Ready status, we are done reconciling
 	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
 
+	// The sync status is refreshed periodically apart from the reconcile
+	r.startSyncStatusChecker(request.NamespacedName)
+
 	// Return and do not requeue
 	logger.Debug("zone done reconciling")
 	return reconcile.Result{}, nil
 }
 
+// startSyncStatusChecker starts the go routine refreshing the sync status of the zone if not running yet
+func (r *ReconcileObjectZone) startSyncStatusChecker(name types.NamespacedName) {
+	if r.syncCheckers == nil {
+		r.syncCheckers = make(map[string]chan struct{})
+	}
+	if _, ok := r.syncCheckers[name.String()]; ok {
+		logger.Debugf("sync status checker of zone %q already running", name.Name)
+		return
+	}
+
+	stopCh := make(chan struct{})
+	r.syncCheckers[name.String()] = stopCh
+	checker := newSyncStatusChecker(r.context, r.clusterInfo, r.client, name)
+	logger.Infof("starting the sync status checks of zone %q", name.Name)
+	go checker.checkSyncStatus(stopCh)
+}
+
+// stopSyncStatusChecker stops the go routine refreshing the sync status of the zone if running
+func (r *ReconcileObjectZone) stopSyncStatusChecker(name types.NamespacedName) {
+	if stopCh, ok := r.syncCheckers[name.String()]; ok {
+		close(stopCh)
+		delete(r.syncCheckers, name.String())
+	}
+}
+
 func (r *ReconcileObjectZone) createCephZone(zone *cephv1.CephObjectZone, realmName string) (reconcile.Result, error) {
 	logger.Infof("creating object zone %q in zonegroup %q in realm %q", zone.Name, zone.Spec.ZoneGroup, realmName)
 
@@ -253,6 +295,30 @@ func (r *ReconcileObjectZone) createCephZone(zone *cephv1.CephObjectZone, realmN
 	return reconcile.Result{}, nil
 }
 
+func (r *ReconcileObjectZone) promoteCephZone(zone *cephv1.CephObjectZone, realmName string) error {
+	objContext := object.NewContext(r.context, r.clusterInfo, zone.Name)
+	promoted, err := object.PromoteZone(objContext, realmName, zone.Spec.ZoneGroup, zone.Name)
+	if err != nil {
+		return err
+	}
+	name := types.NamespacedName{Name: zone.Name, Namespace: zone.Namespace}
+	if promoted {
+		// the zone is master from now on, record the restart so it is retried if it fails
+		if err := object.SetGatewayRestartPending(r.client, name, &cephv1.CephObjectZone{}, true); err != nil {
+			logger.Errorf("failed to record the pending gateway restart of zone %q. %v", zone.Name, err)
+		}
+	} else if !object.IsGatewayRestartPending(zone) {
+		logger.Debugf("ceph zone %q is already the master zone", zone.Name)
+		return nil
+	}
+
+	// the gateways only load the new period when restarted
+	if err := object.RestartZoneGateways(r.client, r.context, zone.Namespace, []string{zone.Name}); err != nil {
+		return err
+	}
+	return object.SetGatewayRestartPending(r.client, name, &cephv1.CephObjectZone{}, false)
+}
+
 func (r *ReconcileObjectZone) createPoolsAndZone(objContext *object.Context, zone *cephv1.CephObjectZone, realmName string, zoneIsMaster bool) error {
 	// create pools for zone
 	logger.Debugf("creating pools ceph zone %q", zone.Name)
@@ -356,7 +422,7 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 		return
 	}
 	if objectZone.Status == nil {
-		objectZone.Status = &cephv1.Status{}
+		objectZone.Status = &cephv1.ObjectZoneStatus{}
 	}
 
 	objectZone.Status.Phase = status
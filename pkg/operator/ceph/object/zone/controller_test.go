@@ -22,12 +22,14 @@ import (
 	"testing"
 
 	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
 	"github.com/rook/rook/pkg/operator/test"
 
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/object"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
@@ -36,6 +38,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -358,3 +361,74 @@ func TestCephObjectZoneController(t *testing.T) {
 	err = r.client.Get(context.TODO(), req.NamespacedName, objectZone)
 	assert.NoError(t, err)
 }
+
+func TestPromoteCephZone(t *testing.T) {
+	defer func(probe func(string) bool) { object.EndpointAnswers = probe }(object.EndpointAnswers)
+	object.EndpointAnswers = func(endpoint string) bool { return false }
+	namespace := "rook-ceph"
+	name := types.NamespacedName{Name: "zone-b", Namespace: namespace}
+
+	masterZoneID := "zone-1"
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "zonegroup" && args[1] == "get":
+				return `{"id":"zg-1","name":"zonegroup-a","master_zone":"` + masterZoneID + `","zones":[
+					{"id":"zone-1","name":"zone-a","endpoints":["http://zone-a.example.com:80"]},
+					{"id":"zone-2","name":"zone-b","endpoints":["http://zone-b.example.com:80"]}]}`, nil
+			case args[0] == "zone" && args[1] == "get":
+				return `{"id":"zone-2"}`, nil
+			case args[0] == "zone" && args[1] == "modify":
+				masterZoneID = "zone-2"
+			}
+			return "", nil
+		},
+	}
+	clientset := test.New(t, 1)
+	restartFails := true
+	restarts := 0
+	clientset.PrependReactor("delete-collection", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		restarts++
+		if restartFails {
+			return true, nil, errors.New("failed to delete pods")
+		}
+		return true, nil, nil
+	})
+	c := &clusterd.Context{Executor: executor, Clientset: clientset}
+
+	zone := &cephv1.CephObjectZone{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: namespace},
+		Spec:       cephv1.ObjectZoneSpec{ZoneGroup: "zonegroup-a", Master: true},
+	}
+	store := &cephv1.CephObjectStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "store-b", Namespace: namespace},
+		Spec:       cephv1.ObjectStoreSpec{Zone: cephv1.ZoneSpec{Name: name.Name}},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephObjectZone{}, &cephv1.CephObjectZoneList{}, &cephv1.CephObjectStore{}, &cephv1.CephObjectStoreList{})
+	cl := fake.NewFakeClientWithScheme(s, zone, store)
+	r := &ReconcileObjectZone{client: cl, scheme: s, context: c, clusterInfo: cephclient.AdminClusterInfo(namespace)}
+
+	// the zone is promoted but the restart of the gateways fails, it stays pending
+	err := r.promoteCephZone(zone, "realm-a")
+	assert.Error(t, err)
+	assert.Equal(t, "zone-2", masterZoneID)
+	assert.Equal(t, 1, restarts)
+	zone = &cephv1.CephObjectZone{}
+	assert.NoError(t, cl.Get(context.TODO(), name, zone))
+	assert.True(t, object.IsGatewayRestartPending(zone))
+
+	// the zone already is the master but the restart is retried
+	restartFails = false
+	err = r.promoteCephZone(zone, "realm-a")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, restarts)
+	zone = &cephv1.CephObjectZone{}
+	assert.NoError(t, cl.Get(context.TODO(), name, zone))
+	assert.False(t, object.IsGatewayRestartPending(zone))
+
+	// nothing left to do
+	err = r.promoteCephZone(zone, "realm-a")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, restarts)
+}
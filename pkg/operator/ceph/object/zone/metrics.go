@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zone
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricsPrefix = "rook_ceph_object_zone_"
+
+var (
+	zoneLabels       = []string{"namespace", "zone"}
+	dataSourceLabels = []string{"namespace", "zone", "source_zone"}
+
+	metadataCaughtUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricsPrefix + "metadata_sync_caught_up",
+		Help: "Whether the metadata of the zone is in sync with the master zone",
+	}, zoneLabels)
+	metadataShardsBehind = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricsPrefix + "metadata_sync_shards_behind",
+		Help: "Number of metadata log shards with changes not applied to the zone",
+	}, zoneLabels)
+	metadataLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricsPrefix + "metadata_sync_lag_seconds",
+		Help: "Age of the oldest metadata change not applied to the zone",
+	}, zoneLabels)
+	dataCaughtUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricsPrefix + "data_sync_caught_up",
+		Help: "Whether the data of the zone is in sync with the source zone",
+	}, dataSourceLabels)
+	dataShardsBehind = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricsPrefix + "data_sync_shards_behind",
+		Help: "Number of data log shards of the source zone with changes not applied to the zone",
+	}, dataSourceLabels)
+	dataRecoveringShards = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricsPrefix + "data_sync_recovering_shards",
+		Help: "Number of data log shards of the source zone recovering from sync errors",
+	}, dataSourceLabels)
+	dataLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricsPrefix + "data_sync_lag_seconds",
+		Help: "Age of the oldest data change of the source zone not applied to the zone",
+	}, dataSourceLabels)
+	bucketsBehind = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricsPrefix + "buckets_sync_behind",
+		Help: "Number of buckets with changes of the source zone not applied to the zone",
+	}, dataSourceLabels)
+
+	// the source zones reported for each zone, to remove the metrics of the sources gone
+	reportedSources     = map[string][]string{}
+	reportedSourcesLock sync.Mutex
+)
+
+func init() {
+	metrics.Registry.MustRegister(metadataCaughtUp, metadataShardsBehind, metadataLag,
+		dataCaughtUp, dataShardsBehind, dataRecoveringShards, dataLag, bucketsBehind)
+}
+
+// setSyncMetrics exports the sync status of a zone
+func setSyncMetrics(namespace, zone string, syncStatus *cephv1.ObjectZoneSyncStatus) {
+	metadataCaughtUp.WithLabelValues(namespace, zone).Set(boolValue(syncStatus.Metadata.CaughtUp))
+	metadataShardsBehind.WithLabelValues(namespace, zone).Set(float64(syncStatus.Metadata.ShardsBehind))
+	metadataLag.WithLabelValues(namespace, zone).Set(float64(syncStatus.Metadata.LagSeconds))
+
+	sources := []string{}
+	for _, source := range syncStatus.DataSources {
+		dataCaughtUp.WithLabelValues(namespace, zone, source.Zone).Set(boolValue(source.CaughtUp))
+		dataShardsBehind.WithLabelValues(namespace, zone, source.Zone).Set(float64(source.ShardsBehind))
+		dataRecoveringShards.WithLabelValues(namespace, zone, source.Zone).Set(float64(source.RecoveringShards))
+		dataLag.WithLabelValues(namespace, zone, source.Zone).Set(float64(source.LagSeconds))
+		bucketsBehind.WithLabelValues(namespace, zone, source.Zone).Set(float64(source.BucketsBehind))
+		sources = append(sources, source.Zone)
+	}
+
+	reportedSourcesLock.Lock()
+	defer reportedSourcesLock.Unlock()
+	key := namespace + "/" + zone
+	for _, previous := range reportedSources[key] {
+		if !contains(sources, previous) {
+			deleteDataSourceMetrics(namespace, zone, previous)
+		}
+	}
+	reportedSources[key] = sources
+}
+
+// deleteSyncMetrics removes the metrics of a deleted zone
+func deleteSyncMetrics(namespace, zone string) {
+	metadataCaughtUp.DeleteLabelValues(namespace, zone)
+	metadataShardsBehind.DeleteLabelValues(namespace, zone)
+	metadataLag.DeleteLabelValues(namespace, zone)
+
+	reportedSourcesLock.Lock()
+	defer reportedSourcesLock.Unlock()
+	key := namespace + "/" + zone
+	for _, source := range reportedSources[key] {
+		deleteDataSourceMetrics(namespace, zone, source)
+	}
+	delete(reportedSources, key)
+}
+
+func deleteDataSourceMetrics(namespace, zone, source string) {
+	dataCaughtUp.DeleteLabelValues(namespace, zone, source)
+	dataShardsBehind.DeleteLabelValues(namespace, zone, source)
+	dataRecoveringShards.DeleteLabelValues(namespace, zone, source)
+	dataLag.DeleteLabelValues(namespace, zone, source)
+	bucketsBehind.DeleteLabelValues(namespace, zone, source)
+}
+
+func boolValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,361 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zone
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	oldestChangePrefix = "oldest incremental change not applied:"
+
+	syncReasonCaughtUp     = "CaughtUp"
+	syncReasonBehind       = "Behind"
+	syncReasonZoneIsMaster = "ZoneIsMaster"
+	syncReasonNoSource     = "NoSourceZone"
+
+	// maxBucketsSyncStatus is the maximum number of buckets whose sync status is queried at each check
+	maxBucketsSyncStatus = 100
+)
+
+var (
+	// syncStatusInterval is how often the sync status of the zones is refreshed
+	syncStatusInterval = time.Minute
+
+	// timeNow is overridden by the tests
+	timeNow = time.Now
+
+	dataSyncSourceRegex   = regexp.MustCompile(`^data sync source: (\S+)(?: \((.*)\))?`)
+	bucketSyncSourceRegex = regexp.MustCompile(`^source zone (\S+)(?: \((.*)\))?`)
+	shardsBehindRegex     = regexp.MustCompile(`is behind on (\d+) shards?`)
+	recoveringShardsRegex = regexp.MustCompile(`^(\d+) shards? (?:are|is) recovering`)
+
+	// the layouts of the times printed by the different ceph versions
+	syncTimeLayouts = []string{
+		"2006-01-02T15:04:05.999999999-0700",
+		"2006-01-02 15:04:05.999999999Z",
+		"2006-01-02 15:04:05.999999999",
+	}
+)
+
+// syncStatusChecker periodically refreshes the sync status of a zone, apart from the reconcile of the zone
+type syncStatusChecker struct {
+	context        *clusterd.Context
+	clusterInfo    *cephclient.ClusterInfo
+	client         client.Client
+	namespacedName types.NamespacedName
+	interval       time.Duration
+}
+
+func newSyncStatusChecker(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, client client.Client, namespacedName types.NamespacedName) *syncStatusChecker {
+	return &syncStatusChecker{
+		context:        context,
+		clusterInfo:    clusterInfo,
+		client:         client,
+		namespacedName: namespacedName,
+		interval:       syncStatusInterval,
+	}
+}
+
+// checkSyncStatus refreshes the sync status of the zone until the stop channel is closed
+func (c *syncStatusChecker) checkSyncStatus(stopCh chan struct{}) {
+	c.checkSyncStatusOnce()
+
+	for {
+		select {
+		case <-stopCh:
+			logger.Infof("stopping the sync status checks of zone %q", c.namespacedName.Name)
+			return
+
+		case <-time.After(c.interval):
+			c.checkSyncStatusOnce()
+		}
+	}
+}
+
+// checkSyncStatusOnce refreshes the sync status of the zone, a failure is only logged until the next check
+func (c *syncStatusChecker) checkSyncStatusOnce() {
+	zone := &cephv1.CephObjectZone{}
+	if err := c.client.Get(context.TODO(), c.namespacedName, zone); err != nil {
+		logger.Debugf("failed to get object zone %q to refresh its sync status. %v", c.namespacedName, err)
+		return
+	}
+	zoneGroup := &cephv1.CephObjectZoneGroup{}
+	if err := c.client.Get(context.TODO(), types.NamespacedName{Name: zone.Spec.ZoneGroup, Namespace: zone.Namespace}, zoneGroup); err != nil {
+		logger.Debugf("failed to get the zone group of object zone %q to refresh its sync status. %v", c.namespacedName, err)
+		return
+	}
+
+	if err := c.refreshSyncStatus(zone, zoneGroup.Spec.Realm); err != nil {
+		logger.Warningf("failed to refresh the sync status of zone %q. %v", zone.Name, err)
+	}
+}
+
+// refreshSyncStatus collects the sync status of the zone, updates its status and its metrics
+func (c *syncStatusChecker) refreshSyncStatus(zone *cephv1.CephObjectZone, realmName string) error {
+	objContext := object.NewContext(c.context, c.clusterInfo, zone.Name)
+	multisiteArgs := []string{
+		fmt.Sprintf("--rgw-realm=%s", realmName),
+		fmt.Sprintf("--rgw-zonegroup=%s", zone.Spec.ZoneGroup),
+		fmt.Sprintf("--rgw-zone=%s", zone.Name),
+	}
+
+	output, err := object.RunAdminCommandNoMultisite(objContext, append([]string{"sync", "status"}, multisiteArgs...)...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the sync status of zone %q. %s", zone.Name, output)
+	}
+	syncStatus, err := parseSyncStatus(output, timeNow())
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the sync status of zone %q", zone.Name)
+	}
+
+	// the buckets can only be behind a source zone with data log shards behind
+	if hasDataShardsBehind(syncStatus) {
+		if err := countBucketsBehind(objContext, multisiteArgs, syncStatus); err != nil {
+			return errors.Wrapf(err, "failed to get the bucket sync status of zone %q", zone.Name)
+		}
+	}
+
+	setSyncMetrics(zone.Namespace, zone.Name, syncStatus)
+	updateSyncStatus(c.client, types.NamespacedName{Name: zone.Name, Namespace: zone.Namespace}, syncStatus)
+	return nil
+}
+
+// parseSyncStatus parses the output of `radosgw-admin sync status`
+func parseSyncStatus(output string, now time.Time) (*cephv1.ObjectZoneSyncStatus, error) {
+	syncStatus := &cephv1.ObjectZoneSyncStatus{LastChecked: metav1.NewTime(now)}
+	foundMetadata := false
+
+	// the progress the following lines apply to, the metadata or a data source
+	var progress *cephv1.ObjectSyncProgress
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "metadata sync"):
+			foundMetadata = true
+			progress = &syncStatus.Metadata
+			progress.State = strings.TrimSpace(strings.TrimPrefix(line, "metadata sync"))
+			// the master zone is the source of the metadata
+			progress.CaughtUp = strings.Contains(progress.State, "zone is master")
+
+		case dataSyncSourceRegex.MatchString(line):
+			match := dataSyncSourceRegex.FindStringSubmatch(line)
+			syncStatus.DataSources = append(syncStatus.DataSources, cephv1.ObjectDataSyncSource{Zone: zoneName(match)})
+			progress = &syncStatus.DataSources[len(syncStatus.DataSources)-1].ObjectSyncProgress
+
+		case progress == nil || line == "":
+			continue
+
+		case strings.Contains(line, "is caught up with"):
+			progress.CaughtUp = true
+
+		case shardsBehindRegex.MatchString(line):
+			progress.ShardsBehind, _ = strconv.Atoi(shardsBehindRegex.FindStringSubmatch(line)[1])
+
+		case recoveringShardsRegex.MatchString(line):
+			progress.RecoveringShards, _ = strconv.Atoi(recoveringShardsRegex.FindStringSubmatch(line)[1])
+
+		case strings.HasPrefix(line, oldestChangePrefix):
+			if oldest, ok := parseSyncTime(strings.TrimSpace(strings.TrimPrefix(line, oldestChangePrefix))); ok {
+				progress.OldestChange = &metav1.Time{Time: oldest}
+			}
+
+		case strings.HasPrefix(line, "failed to"):
+			progress.State = line
+
+		case progress.State == "" && !strings.Contains(line, ":"):
+			// the state of a data source is on its own line
+			progress.State = line
+		}
+	}
+	if !foundMetadata {
+		return nil, errors.Errorf("no metadata sync found in %q", output)
+	}
+
+	setLag(&syncStatus.Metadata, now)
+	for i := range syncStatus.DataSources {
+		setLag(&syncStatus.DataSources[i].ObjectSyncProgress, now)
+	}
+	return syncStatus, nil
+}
+
+// zoneName returns the name of a zone matched as "<id> (<name>)", the id if the name is not known
+func zoneName(match []string) string {
+	if match[2] != "" {
+		return match[2]
+	}
+	return match[1]
+}
+
+func parseSyncTime(value string) (time.Time, bool) {
+	for _, layout := range syncTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	logger.Debugf("unknown sync time format %q", value)
+	return time.Time{}, false
+}
+
+func setLag(progress *cephv1.ObjectSyncProgress, now time.Time) {
+	progress.LagSeconds = 0
+	if progress.CaughtUp || progress.OldestChange == nil {
+		return
+	}
+	if lag := now.Sub(progress.OldestChange.Time); lag > 0 {
+		progress.LagSeconds = int64(lag.Seconds())
+	}
+}
+
+// hasDataShardsBehind returns whether the zone is behind a source zone on its data log shards
+func hasDataShardsBehind(syncStatus *cephv1.ObjectZoneSyncStatus) bool {
+	for _, source := range syncStatus.DataSources {
+		if source.ShardsBehind > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// countBucketsBehind counts the buckets of the zone with changes from each source zone not applied yet. Only the
+// first buckets are queried, one radosgw-admin command each, so the counts are a lower bound on large zones.
+func countBucketsBehind(objContext *object.Context, multisiteArgs []string, syncStatus *cephv1.ObjectZoneSyncStatus) error {
+	output, err := object.RunAdminCommandNoMultisite(objContext, append([]string{"bucket", "list"}, multisiteArgs...)...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list buckets. %s", output)
+	}
+	var buckets []string
+	if err := json.Unmarshal([]byte(output), &buckets); err != nil {
+		return errors.Wrapf(err, "failed to parse the bucket list %q", output)
+	}
+	if len(buckets) > maxBucketsSyncStatus {
+		logger.Debugf("checking the sync status of the first %d buckets out of %d", maxBucketsSyncStatus, len(buckets))
+		buckets = buckets[:maxBucketsSyncStatus]
+	}
+
+	behind := map[string]int{}
+	for _, bucket := range buckets {
+		args := append([]string{"bucket", "sync", "status", fmt.Sprintf("--bucket=%s", bucket)}, multisiteArgs...)
+		output, err := object.RunAdminCommandNoMultisite(objContext, args...)
+		if err != nil {
+			// the bucket may have been removed since it was listed
+			logger.Debugf("failed to get the sync status of bucket %q. %v", bucket, err)
+			continue
+		}
+		for _, source := range bucketSourcesBehind(output) {
+			behind[source]++
+		}
+	}
+
+	for i := range syncStatus.DataSources {
+		syncStatus.DataSources[i].BucketsBehind = behind[syncStatus.DataSources[i].Zone]
+	}
+	return nil
+}
+
+// bucketSourcesBehind parses the output of `radosgw-admin bucket sync status` and returns the source zones
+// the bucket is behind
+func bucketSourcesBehind(output string) []string {
+	sources := []string{}
+	source := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if match := bucketSyncSourceRegex.FindStringSubmatch(line); match != nil {
+			source = zoneName(match)
+			continue
+		}
+		if source != "" && strings.Contains(line, "is behind") {
+			sources = append(sources, source)
+			source = ""
+		}
+	}
+	return sources
+}
+
+// syncConditions returns the conditions of the zone matching its sync status
+func syncConditions(syncStatus *cephv1.ObjectZoneSyncStatus) []cephv1.Condition {
+	metadata := cephv1.Condition{Type: cephv1.ConditionMetadataSynced, Status: v1.ConditionTrue, Reason: syncReasonCaughtUp}
+	switch {
+	case strings.Contains(syncStatus.Metadata.State, "zone is master"):
+		metadata.Reason = syncReasonZoneIsMaster
+	case !syncStatus.Metadata.CaughtUp:
+		metadata.Status = v1.ConditionFalse
+		metadata.Reason = syncReasonBehind
+		metadata.Message = fmt.Sprintf("metadata is behind on %d shards", syncStatus.Metadata.ShardsBehind)
+	}
+
+	data := cephv1.Condition{Type: cephv1.ConditionDataSynced, Status: v1.ConditionTrue, Reason: syncReasonCaughtUp}
+	if len(syncStatus.DataSources) == 0 {
+		data.Reason = syncReasonNoSource
+	}
+	behind := []string{}
+	for _, source := range syncStatus.DataSources {
+		if !source.CaughtUp || source.BucketsBehind > 0 {
+			behind = append(behind, fmt.Sprintf("%s (%d shards, %d buckets)", source.Zone, source.ShardsBehind, source.BucketsBehind))
+		}
+	}
+	if len(behind) > 0 {
+		data.Status = v1.ConditionFalse
+		data.Reason = syncReasonBehind
+		data.Message = fmt.Sprintf("data is behind source zones %s", strings.Join(behind, ", "))
+	}
+	return []cephv1.Condition{metadata, data}
+}
+
+// updateSyncStatus updates the sync status and the sync conditions of a zone
+func updateSyncStatus(client client.Client, name types.NamespacedName, syncStatus *cephv1.ObjectZoneSyncStatus) {
+	objectZone := &cephv1.CephObjectZone{}
+	if err := client.Get(context.TODO(), name, objectZone); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephObjectZone resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve object zone %q to update its sync status. %v", name, err)
+		return
+	}
+	if objectZone.Status == nil {
+		objectZone.Status = &cephv1.ObjectZoneStatus{}
+	}
+
+	objectZone.Status.SyncStatus = syncStatus
+	for _, condition := range syncConditions(syncStatus) {
+		opcontroller.SetCondition(&objectZone.Status.Conditions, condition)
+	}
+	if err := opcontroller.UpdateStatus(client, objectZone); err != nil {
+		logger.Errorf("failed to update the sync status of object zone %q. %v", name, err)
+		return
+	}
+	logger.Debugf("object zone %q sync status updated", name)
+}
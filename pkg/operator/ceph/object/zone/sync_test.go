@@ -0,0 +1,195 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zone
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	masterSyncStatus = `          realm 237e6250-5f7d-4b85-9359-8cb2b1848507 (realm-a)
+      zonegroup fd8ff110-d3fd-49b4-b24f-f6cd3dddfedf (zonegroup-a)
+           zone 6cb39d2c-3005-49da-9be3-c1a92a97d28a (zone-a)
+  metadata sync no sync (zone is master)
+      data sync source: b1abbebb-e8ae-4c3b-880e-b009728bad53 (zone-b)
+                        syncing
+                        full sync: 0/128 shards
+                        incremental sync: 128/128 shards
+                        data is caught up with source
+`
+	secondarySyncStatus = `          realm 237e6250-5f7d-4b85-9359-8cb2b1848507 (realm-a)
+      zonegroup fd8ff110-d3fd-49b4-b24f-f6cd3dddfedf (zonegroup-a)
+           zone b1abbebb-e8ae-4c3b-880e-b009728bad53 (zone-b)
+  metadata sync syncing
+                full sync: 0/64 shards
+                incremental sync: 64/64 shards
+                metadata is behind on 1 shards
+                behind shards: [26]
+                oldest incremental change not applied: 2020-10-01T11:58:00.123456+0000
+      data sync source: 6cb39d2c-3005-49da-9be3-c1a92a97d28a (zone-a)
+                        syncing
+                        full sync: 0/128 shards
+                        incremental sync: 128/128 shards
+                        data is behind on 2 shards
+                        behind shards: [12,95]
+                        oldest incremental change not applied: 2020-10-01 11:50:00.000000Z
+                        3 shards are recovering
+                        recovering shards: [1,2,3]
+      data sync source: 8a2c4e6f-1b3d-4f5a-9c7e-0d1f2a3b4c5d
+                        failed to retrieve sync info: (5) Input/output error
+`
+)
+
+func TestParseSyncStatus(t *testing.T) {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	syncStatus, err := parseSyncStatus(masterSyncStatus, now)
+	assert.NoError(t, err)
+	assert.Equal(t, now, syncStatus.LastChecked.Time)
+	assert.Equal(t, "no sync (zone is master)", syncStatus.Metadata.State)
+	assert.True(t, syncStatus.Metadata.CaughtUp)
+	assert.Equal(t, 1, len(syncStatus.DataSources))
+	assert.Equal(t, "zone-b", syncStatus.DataSources[0].Zone)
+	assert.Equal(t, "syncing", syncStatus.DataSources[0].State)
+	assert.True(t, syncStatus.DataSources[0].CaughtUp)
+	assert.Equal(t, int64(0), syncStatus.DataSources[0].LagSeconds)
+
+	syncStatus, err = parseSyncStatus(secondarySyncStatus, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "syncing", syncStatus.Metadata.State)
+	assert.False(t, syncStatus.Metadata.CaughtUp)
+	assert.Equal(t, 1, syncStatus.Metadata.ShardsBehind)
+	assert.Equal(t, int64(119), syncStatus.Metadata.LagSeconds)
+	assert.Equal(t, 2, len(syncStatus.DataSources))
+	source := syncStatus.DataSources[0]
+	assert.Equal(t, "zone-a", source.Zone)
+	assert.False(t, source.CaughtUp)
+	assert.Equal(t, 2, source.ShardsBehind)
+	assert.Equal(t, 3, source.RecoveringShards)
+	assert.Equal(t, int64(600), source.LagSeconds)
+	// the name of the zone is not known
+	source = syncStatus.DataSources[1]
+	assert.Equal(t, "8a2c4e6f-1b3d-4f5a-9c7e-0d1f2a3b4c5d", source.Zone)
+	assert.Equal(t, "failed to retrieve sync info: (5) Input/output error", source.State)
+	assert.False(t, source.CaughtUp)
+
+	_, err = parseSyncStatus("", now)
+	assert.Error(t, err)
+}
+
+func TestBucketSourcesBehind(t *testing.T) {
+	output := `          realm 237e6250-5f7d-4b85-9359-8cb2b1848507 (realm-a)
+      zonegroup fd8ff110-d3fd-49b4-b24f-f6cd3dddfedf (zonegroup-a)
+           zone b1abbebb-e8ae-4c3b-880e-b009728bad53 (zone-b)
+         bucket :photos[6cb39d2c-3005-49da-9be3-c1a92a97d28a.4137.1])
+
+    source zone 6cb39d2c-3005-49da-9be3-c1a92a97d28a (zone-a)
+                full sync: 0/11 shards
+                incremental sync: 11/11 shards
+                bucket is behind on 2 shards
+                behind shards: [3,7]
+    source zone 8a2c4e6f-1b3d-4f5a-9c7e-0d1f2a3b4c5d (zone-c)
+                full sync: 0/11 shards
+                incremental sync: 11/11 shards
+                bucket is caught up with source
+`
+	assert.Equal(t, []string{"zone-a"}, bucketSourcesBehind(output))
+	assert.Equal(t, []string{}, bucketSourcesBehind(""))
+}
+
+func TestRefreshSyncStatusBuckets(t *testing.T) {
+	buckets := []string{"photos", "logs"}
+	bucketStatusCalls := 0
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "bucket" && args[1] == "list" {
+				output, err := json.Marshal(buckets)
+				return string(output), err
+			}
+			if args[0] == "bucket" && args[1] == "sync" {
+				bucketStatusCalls++
+				if args[3] == "--bucket=photos" {
+					return "source zone 6cb39d2c (zone-a)\n  bucket is behind on 1 shards\n", nil
+				}
+			}
+			return "source zone 6cb39d2c (zone-a)\n  bucket is caught up with source\n", nil
+		},
+	}
+	objContext := object.NewContext(&clusterd.Context{Executor: executor}, cephclient.AdminClusterInfo("mycluster"), "zone-b")
+	syncStatus, err := parseSyncStatus(masterSyncStatus, time.Now())
+	assert.NoError(t, err)
+	syncStatus.DataSources[0].Zone = "zone-a"
+
+	// the buckets are only queried when the data is behind
+	assert.False(t, hasDataShardsBehind(syncStatus))
+	syncStatus.DataSources[0].ShardsBehind = 1
+	assert.True(t, hasDataShardsBehind(syncStatus))
+
+	assert.NoError(t, countBucketsBehind(objContext, []string{"--rgw-zone=zone-b"}, syncStatus))
+	assert.Equal(t, 1, syncStatus.DataSources[0].BucketsBehind)
+	assert.Equal(t, 2, bucketStatusCalls)
+
+	// the number of buckets queried is capped
+	buckets = []string{}
+	for i := 0; i < maxBucketsSyncStatus+20; i++ {
+		buckets = append(buckets, fmt.Sprintf("bucket-%d", i))
+	}
+	bucketStatusCalls = 0
+	assert.NoError(t, countBucketsBehind(objContext, []string{"--rgw-zone=zone-b"}, syncStatus))
+	assert.Equal(t, 0, syncStatus.DataSources[0].BucketsBehind)
+	assert.Equal(t, maxBucketsSyncStatus, bucketStatusCalls)
+}
+
+func TestSyncConditions(t *testing.T) {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	syncStatus, _ := parseSyncStatus(masterSyncStatus, now)
+	conditions := syncConditions(syncStatus)
+	assert.Equal(t, cephv1.ConditionMetadataSynced, conditions[0].Type)
+	assert.Equal(t, v1.ConditionTrue, conditions[0].Status)
+	assert.Equal(t, syncReasonZoneIsMaster, conditions[0].Reason)
+	assert.Equal(t, cephv1.ConditionDataSynced, conditions[1].Type)
+	assert.Equal(t, v1.ConditionTrue, conditions[1].Status)
+
+	// the data is caught up but a bucket is behind
+	syncStatus.DataSources[0].BucketsBehind = 1
+	conditions = syncConditions(syncStatus)
+	assert.Equal(t, v1.ConditionFalse, conditions[1].Status)
+	assert.Equal(t, "data is behind source zones zone-b (0 shards, 1 buckets)", conditions[1].Message)
+
+	syncStatus, _ = parseSyncStatus(secondarySyncStatus, now)
+	conditions = syncConditions(syncStatus)
+	assert.Equal(t, v1.ConditionFalse, conditions[0].Status)
+	assert.Equal(t, syncReasonBehind, conditions[0].Reason)
+	assert.Equal(t, v1.ConditionFalse, conditions[1].Status)
+
+	// no other zone
+	conditions = syncConditions(&cephv1.ObjectZoneSyncStatus{Metadata: cephv1.ObjectSyncProgress{State: "no sync (zone is master)", CaughtUp: true}})
+	assert.Equal(t, v1.ConditionTrue, conditions[1].Status)
+	assert.Equal(t, syncReasonNoSource, conditions[1].Reason)
+}
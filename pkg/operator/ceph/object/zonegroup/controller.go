@@ -193,6 +193,14 @@ func (r *ReconcileObjectZoneGroup) reconcile(request reconcile.Request) (reconci
 		return r.setFailedStatus(request.NamespacedName, "failed to create ceph zone group", err)
 	}
 
+	// Promote the zone group to master of its realm
+	if cephObjectZoneGroup.Spec.Master {
+		err = r.promoteCephZoneGroup(cephObjectZoneGroup)
+		if err != nil {
+			return r.setFailedStatus(request.NamespacedName, "failed to promote ceph zone group", err)
+		}
+	}
+
 	// Set Ready status, we are done reconciling
 	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
 
@@ -260,6 +268,40 @@ func (r *ReconcileObjectZoneGroup) createCephZoneGroup(zoneGroup *cephv1.CephObj
 	return reconcile.Result{}, nil
 }
 
+func (r *ReconcileObjectZoneGroup) promoteCephZoneGroup(zoneGroup *cephv1.CephObjectZoneGroup) error {
+	objContext := object.NewContext(r.context, r.clusterInfo, zoneGroup.Name)
+	promoted, err := object.PromoteZoneGroup(objContext, zoneGroup.Spec.Realm, zoneGroup.Name)
+	if err != nil {
+		return err
+	}
+	name := types.NamespacedName{Name: zoneGroup.Name, Namespace: zoneGroup.Namespace}
+	if promoted {
+		// the zone group is master from now on, record the restart so it is retried if it fails
+		if err := object.SetGatewayRestartPending(r.client, name, &cephv1.CephObjectZoneGroup{}, true); err != nil {
+			logger.Errorf("failed to record the pending gateway restart of zone group %q. %v", zoneGroup.Name, err)
+		}
+	} else if !object.IsGatewayRestartPending(zoneGroup) {
+		logger.Debugf("ceph zone group %q is already the master zone group", zoneGroup.Name)
+		return nil
+	}
+
+	// the gateways of all the zones of the zone group only load the new period when restarted
+	zones := &cephv1.CephObjectZoneList{}
+	if err := r.client.List(context.TODO(), zones, client.InNamespace(zoneGroup.Namespace)); err != nil {
+		return errors.Wrap(err, "failed to list object zones")
+	}
+	zoneNames := []string{}
+	for _, zone := range zones.Items {
+		if zone.Spec.ZoneGroup == zoneGroup.Name {
+			zoneNames = append(zoneNames, zone.Name)
+		}
+	}
+	if err := object.RestartZoneGateways(r.client, r.context, zoneGroup.Namespace, zoneNames); err != nil {
+		return err
+	}
+	return object.SetGatewayRestartPending(r.client, name, &cephv1.CephObjectZoneGroup{}, false)
+}
+
 func (r *ReconcileObjectZoneGroup) reconcileObjectRealm(zoneGroup *cephv1.CephObjectZoneGroup) (reconcile.Result, error) {
 	// Verify the object realm API object actually exists
 	cephObjectRealm := &cephv1.CephObjectRealm{}
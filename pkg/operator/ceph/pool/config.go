@@ -66,6 +66,57 @@ func (r *ReconcileCephBlockPool) createBootstrapPeerSecret(cephBlockPool *cephv1
 	return reconcile.Result{}, nil
 }
 
+func (r *ReconcileCephBlockPool) reconcileAddBoostrapPeer(cephBlockPool *cephv1.CephBlockPool) (reconcile.Result, error) {
+	if !cephBlockPool.Spec.Mirroring.HasPeers() {
+		return reconcile.Result{}, nil
+	}
+
+	// List all the peers secret, we can have more than one peer we might want to configure
+	// For each, get the Kubernetes Secret and import the "peer token" in the pool so that we can configure the mirroring
+	for _, peerSecret := range cephBlockPool.Spec.Mirroring.Peers.SecretNames {
+		logger.Debugf("fetching bootstrap peer kubernetes secret %q", peerSecret)
+		s, err := r.context.Clientset.CoreV1().Secrets(cephBlockPool.Namespace).Get(peerSecret, metav1.GetOptions{})
+		// We don't care about IsNotFound here, we still need to fail
+		if err != nil {
+			return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to fetch kubernetes secret %q bootstrap peer", peerSecret)
+		}
+
+		// Validate peer secret content
+		err = validatePeerToken(s.Data)
+		if err != nil {
+			return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to validate rbd-mirror bootstrap peer secret %q data", peerSecret)
+		}
+
+		// The direction of the pool spec takes precedence over the one of the secret
+		direction := cephBlockPool.Spec.Mirroring.Direction
+		if direction == "" {
+			direction = string(s.Data["direction"])
+		}
+
+		// Import bootstrap peer
+		err = cephclient.ImportRBDMirrorBootstrapPeer(r.context, r.clusterInfo, cephBlockPool.Name, direction, s.Data["token"])
+		if err != nil {
+			return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to import bootstrap peer token")
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func validatePeerToken(data map[string][]byte) error {
+	if len(data) == 0 {
+		return errors.Errorf("failed to lookup 'data' secret field (empty)")
+	}
+
+	// Lookup Secret keys and content
+	k, ok := data["token"]
+	if !ok || len(k) == 0 {
+		return errors.Errorf("failed to lookup %q key in secret bootstrap peer (missing or empty)", "token")
+	}
+
+	return nil
+}
+
 // GenerateBootstrapPeerSecret generates a Kubernetes Secret for the mirror bootstrap peer token
 func GenerateBootstrapPeerSecret(name, namespace string, token []byte) *corev1.Secret {
 	s := &corev1.Secret{
@@ -20,8 +20,14 @@ package pool
 import (
 	"testing"
 
+	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -38,3 +44,74 @@ func TestGenerateStatusInfo(t *testing.T) {
 	assert.NotEmpty(t, secretName)
 	assert.Equal(t, "pool-peer-token-foo", secretName)
 }
+
+func TestValidatePeerToken(t *testing.T) {
+	// empty secret data
+	err := validatePeerToken(map[string][]byte{})
+	assert.Error(t, err)
+
+	// missing token
+	err = validatePeerToken(map[string][]byte{"pool": []byte("foo")})
+	assert.Error(t, err)
+
+	// success
+	err = validatePeerToken(map[string][]byte{"token": []byte("bar")})
+	assert.NoError(t, err)
+}
+
+func TestReconcileAddBoostrapPeer(t *testing.T) {
+	namespace := "rook-ceph"
+	direction := ""
+	imported := 0
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "mirror" && args[1] == "pool" && args[2] == "peer" && args[3] == "bootstrap" && args[4] == "import" {
+				assert.Equal(t, "foo", args[5])
+				if direction != "" {
+					assert.Equal(t, "--direction", args[7])
+					assert.Equal(t, direction, args[8])
+				} else {
+					assert.NotEqual(t, "--direction", args[7])
+				}
+				imported++
+				return "", nil
+			}
+			return "", errors.New("unknown command")
+		},
+	}
+	c := &clusterd.Context{Executor: executor, Clientset: testop.New(t, 1)}
+	r := &ReconcileCephBlockPool{context: c, clusterInfo: cephclient.AdminClusterInfo(namespace)}
+	p := &cephv1.CephBlockPool{
+		ObjectMeta: v1.ObjectMeta{Name: "foo", Namespace: namespace},
+		Spec:       cephv1.PoolSpec{Mirroring: cephv1.MirroringSpec{Enabled: true, Mode: "image"}},
+	}
+
+	// no peers
+	_, err := r.reconcileAddBoostrapPeer(p)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, imported)
+
+	// the peer secret does not exist
+	p.Spec.Mirroring.Peers = &cephv1.MirroringPeerSpec{SecretNames: []string{"pool-peer-token-foo-site-b"}}
+	_, err = r.reconcileAddBoostrapPeer(p)
+	assert.Error(t, err)
+
+	// the peer secret copied from the other cluster, the direction of the secret is used
+	peerSecret := &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: "pool-peer-token-foo-site-b", Namespace: namespace},
+		Data:       map[string][]byte{"token": []byte("bar"), "pool": []byte("foo"), "direction": []byte("rx-only")},
+	}
+	_, err = c.Clientset.CoreV1().Secrets(namespace).Create(peerSecret)
+	assert.NoError(t, err)
+	direction = "rx-only"
+	_, err = r.reconcileAddBoostrapPeer(p)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, imported)
+
+	// the direction of the pool takes precedence
+	p.Spec.Mirroring.Direction = "rx-tx"
+	direction = "rx-tx"
+	_, err = r.reconcileAddBoostrapPeer(p)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, imported)
+}
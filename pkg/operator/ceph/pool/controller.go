@@ -248,10 +248,16 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 	// CREATE/UPDATE
 	reconcileResponse, err = r.reconcileCreatePool(clusterInfo, cephBlockPool)
 	if err != nil {
-		updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure, nil)
+		updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure, appliedQuotaStatusInfo(cephBlockPool))
 		return reconcileResponse, errors.Wrapf(err, "failed to create pool %q.", cephBlockPool.GetName())
 	}
 
+	// the quotas of the spec are applied with the pool properties, the quotas removed from the spec are reset
+	if err := removeQuotas(r.context, clusterInfo, cephBlockPool); err != nil {
+		updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure, appliedQuotaStatusInfo(cephBlockPool))
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to remove quotas of pool %q.", cephBlockPool.GetName())
+	}
+
 	// enable/disable RBD stats collection based on cephBlockPool spec
 	if err := configureRBDStats(r.context, clusterInfo); err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to enable/disable stats collection for pool(s)")
@@ -259,36 +265,55 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 
 	// ADD PEERS
 	logger.Debug("reconciling create rbd mirror peer configuration")
+	var statusInfo map[string]string
 	if cephBlockPool.Spec.Mirroring.Enabled {
 		// Always create a bootstrap peer token in case another cluster wants to add us as a peer
 		reconcileResponse, err = r.createBootstrapPeerSecret(cephBlockPool, request.NamespacedName)
 		if err != nil {
-			updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure, nil)
+			updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure, appliedQuotaStatusInfo(cephBlockPool))
 			return reconcileResponse, errors.Wrapf(err, "failed to create rbd-mirror bootstrap peer for pool %q.", cephBlockPool.GetName())
 		}
 
-		// Check if rbd-mirror CR and daemons are running
-		logger.Debug("listing rbd-mirror CR")
-		// Run the goroutine to update the mirroring status
-		if !cephBlockPool.Spec.StatusCheck.Mirror.Disabled {
-			// Start monitoring object store
-			if r.blockPoolChannels[cephBlockPool.Name].monitoringRunning {
-				logger.Debug("external rgw endpoint monitoring go routine already running!")
-			} else {
-				checker := newMirrorChecker(r.context, r.client, r.clusterInfo, request.NamespacedName, &cephBlockPool.Spec.StatusCheck, cephBlockPool.Name)
-				go checker.checkMirroring(r.blockPoolChannels[cephBlockPool.Name].stopChan)
-			}
+		// Add the peers of the pool, if any
+		reconcileResponse, err = r.reconcileAddBoostrapPeer(cephBlockPool)
+		if err != nil {
+			updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure, appliedQuotaStatusInfo(cephBlockPool))
+			return reconcileResponse, errors.Wrapf(err, "failed to add rbd-mirror bootstrap peers to pool %q.", cephBlockPool.GetName())
 		}
 
-		// Set Ready status, we are done reconciling
-		updateStatus(r.client, request.NamespacedName, cephv1.ConditionReady, generateStatusInfo(cephBlockPool))
+		statusInfo = generateStatusInfo(cephBlockPool)
+	}
 
-		// If not mirrored there is no Status Info field to fulfil
-	} else {
-		// Set Ready status, we are done reconciling
-		updateStatus(r.client, request.NamespacedName, cephv1.ConditionReady, nil)
+	// Run the goroutine to update the mirroring status and the quota usage
+	if (cephBlockPool.Spec.Mirroring.Enabled && !cephBlockPool.Spec.StatusCheck.Mirror.Disabled) || cephBlockPool.Spec.Quotas.IsEnabled() {
+		if r.blockPoolChannels[cephBlockPool.Name].monitoringRunning {
+			logger.Debug("pool status monitoring go routine already running!")
+		} else {
+			r.blockPoolChannels[cephBlockPool.Name].monitoringRunning = true
+			checker := newMirrorChecker(r.context, r.client, r.clusterInfo, request.NamespacedName, &cephBlockPool.Spec.StatusCheck, cephBlockPool.Name)
+			go checker.checkMirroring(r.blockPoolChannels[cephBlockPool.Name].stopChan)
+		}
 	}
 
+	// REPORT QUOTA USAGE
+	if cephBlockPool.Spec.Quotas.IsEnabled() {
+		quotaInfo, err := generateQuotaStatusInfo(r.context, clusterInfo, cephBlockPool)
+		if err != nil {
+			// the quotas are applied, only the usage report is missing
+			logger.Warningf("failed to report quota usage of pool %q. %v", cephBlockPool.Name, err)
+			quotaInfo = quotaLimitsInfo(cephBlockPool.Spec.Quotas)
+		}
+		if statusInfo == nil {
+			statusInfo = make(map[string]string)
+		}
+		for k, v := range quotaInfo {
+			statusInfo[k] = v
+		}
+	}
+
+	// Set Ready status, we are done reconciling
+	updateStatus(r.client, request.NamespacedName, cephv1.ConditionReady, statusInfo)
+
 	// Return and do not requeue
 	logger.Debug("done reconciling")
 	return reconcile.Result{}, nil
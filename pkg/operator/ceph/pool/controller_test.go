@@ -121,6 +121,36 @@ func TestValidatePool(t *testing.T) {
 	p.Spec.CompressionMode = "passive"
 	err = ValidatePool(context, clusterInfo, &p)
 	assert.Nil(t, err)
+
+	// succeed with snapshot schedules and image mirroring
+	p = cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "mypool", Namespace: clusterInfo.Namespace}}
+	p.Spec.Mirroring.Enabled = true
+	p.Spec.Mirroring.Mode = "image"
+	p.Spec.Mirroring.SnapshotSchedules = []cephv1.SnapshotScheduleSpec{{Interval: "24h"}}
+	err = ValidatePool(context, clusterInfo, &p)
+	assert.NoError(t, err)
+
+	// fail with snapshot schedules and pool mirroring
+	p.Spec.Mirroring.Mode = "pool"
+	err = ValidatePool(context, clusterInfo, &p)
+	assert.Error(t, err)
+
+	// fail with a snapshot schedule without interval
+	p.Spec.Mirroring.Mode = "image"
+	p.Spec.Mirroring.SnapshotSchedules = []cephv1.SnapshotScheduleSpec{{StartTime: "14:00:00-05:00"}}
+	err = ValidatePool(context, clusterInfo, &p)
+	assert.Error(t, err)
+
+	// succeed with a valid mirroring direction
+	p.Spec.Mirroring.SnapshotSchedules = nil
+	p.Spec.Mirroring.Direction = "rx-only"
+	err = ValidatePool(context, clusterInfo, &p)
+	assert.NoError(t, err)
+
+	// fail with an unknown mirroring direction
+	p.Spec.Mirroring.Direction = "tx-only"
+	err = ValidatePool(context, clusterInfo, &p)
+	assert.Error(t, err)
 }
 
 func TestValidateCrushProperties(t *testing.T) {
@@ -452,6 +482,9 @@ func TestCephBlockPoolController(t *testing.T) {
 			if args[0] == "mirror" && args[1] == "pool" && args[2] == "peer" && args[3] == "bootstrap" && args[4] == "create" {
 				return `eyJmc2lkIjoiYzZiMDg3ZjItNzgyOS00ZGJiLWJjZmMtNTNkYzM0ZTBiMzVkIiwiY2xpZW50X2lkIjoicmJkLW1pcnJvci1wZWVyIiwia2V5IjoiQVFBV1lsWmZVQ1Q2RGhBQVBtVnAwbGtubDA5YVZWS3lyRVV1NEE9PSIsIm1vbl9ob3N0IjoiW3YyOjE5Mi4xNjguMTExLjEwOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTA6Njc4OV0sW3YyOjE5Mi4xNjguMTExLjEyOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTI6Njc4OV0sW3YyOjE5Mi4xNjguMTExLjExOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTE6Njc4OV0ifQ==`, nil
 			}
+			if args[0] == "mirror" && args[1] == "snapshot" && args[2] == "schedule" && args[3] == "ls" {
+				return `[]`, nil
+			}
 			return "", nil
 		},
 	}
@@ -17,6 +17,7 @@ limitations under the License.
 package pool
 
 import (
+	"context"
 	"time"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -64,14 +65,10 @@ func newMirrorChecker(context *clusterd.Context, client client.Client, clusterIn
 	return c
 }
 
-// checkMirroring periodically checks the health of the cluster
+// checkMirroring periodically checks the mirroring health and the quota usage of the pool
 func (c *mirrorChecker) checkMirroring(stopCh chan struct{}) {
-	// check the mirroring health immediately before starting the loop
-	err := c.checkMirroringHealth()
-	if err != nil {
-		c.updateStatusMirroring(nil, nil, err.Error())
-		logger.Debugf("failed to check pool mirroring status for ceph block pool %q. %v", c.namespacedName.Name, err)
-	}
+	// check the pool status immediately before starting the loop
+	c.checkPoolStatus()
 
 	for {
 		select {
@@ -81,12 +78,34 @@ func (c *mirrorChecker) checkMirroring(stopCh chan struct{}) {
 
 		case <-time.After(c.interval):
 			logger.Debugf("checking pool mirroring status %q", c.namespacedName.Name)
-			err := c.checkMirroringHealth()
-			if err != nil {
-				c.updateStatusMirroring(nil, nil, err.Error())
-				logger.Debugf("failed to check pool mirroring status for ceph block pool %q. %v", c.namespacedName.Name, err)
-			}
+			c.checkPoolStatus()
+		}
+	}
+}
+
+// checkPoolStatus refreshes the mirroring status and the quota usage of the pool if they are enabled in its spec
+func (c *mirrorChecker) checkPoolStatus() {
+	blockPool := &cephv1.CephBlockPool{}
+	if err := c.client.Get(context.TODO(), c.namespacedName, blockPool); err != nil {
+		logger.Debugf("failed to get ceph block pool %q to check its status. %v", c.namespacedName.Name, err)
+		return
+	}
+
+	if blockPool.Spec.Mirroring.Enabled && !blockPool.Spec.StatusCheck.Mirror.Disabled {
+		err := c.checkMirroringHealth()
+		if err != nil {
+			c.updateStatusMirroring(nil, nil, err.Error())
+			logger.Debugf("failed to check pool mirroring status for ceph block pool %q. %v", c.namespacedName.Name, err)
+		}
+	}
+
+	if blockPool.Spec.Quotas.IsEnabled() {
+		quotaInfo, err := generateQuotaStatusInfo(c.context, c.clusterInfo, blockPool)
+		if err != nil {
+			logger.Debugf("failed to check the quota usage of ceph block pool %q. %v", c.namespacedName.Name, err)
+			return
 		}
+		c.updateStatusQuotas(quotaInfo)
 	}
 }
 
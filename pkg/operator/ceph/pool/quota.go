@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	quotaMaxBytesKey        = "quotaMaxBytes"
+	quotaMaxObjectsKey      = "quotaMaxObjects"
+	usedBytesKey            = "usedBytes"
+	usedObjectsKey          = "usedObjects"
+	provisionedBytesKey     = "provisionedBytes"
+	quotaBytesUsedPercent   = "quotaBytesUsedPercent"
+	quotaObjectsUsedPercent = "quotaObjectsUsedPercent"
+)
+
+// the quotas applied to the pool are recorded by the reconcile, a quota removed from the spec is only removed from the
+// pool if it was applied before, the quotas set by hand are left untouched
+var quotaLimitKeys = []string{quotaMaxBytesKey, quotaMaxObjectsKey}
+
+// the usage of the pool is refreshed by the pool status checker
+var quotaUsageKeys = []string{usedBytesKey, usedObjectsKey, provisionedBytesKey, quotaBytesUsedPercent, quotaObjectsUsedPercent}
+
+// quotaLimitsInfo returns the quotas of the spec as recorded in the status info
+func quotaLimitsInfo(quotas cephv1.QuotaSpec) map[string]string {
+	m := make(map[string]string)
+	if quotas.MaxBytes != nil {
+		m[quotaMaxBytesKey] = strconv.FormatInt(quotas.MaxBytes.Value(), 10)
+	}
+	if quotas.MaxObjects != nil {
+		m[quotaMaxObjectsKey] = strconv.FormatInt(quotas.MaxObjects.Value(), 10)
+	}
+	return m
+}
+
+// appliedQuotaStatusInfo returns the quotas recorded in the status info by the last reconcile, they are kept in the
+// status when the reconcile fails
+func appliedQuotaStatusInfo(p *cephv1.CephBlockPool) map[string]string {
+	if p.Status == nil {
+		return nil
+	}
+	var m map[string]string
+	for _, key := range quotaLimitKeys {
+		if value, ok := p.Status.Info[key]; ok {
+			if m == nil {
+				m = make(map[string]string)
+			}
+			m[key] = value
+		}
+	}
+	return m
+}
+
+// removeQuotas removes from the pool the quotas applied by a previous reconcile that are not in the spec anymore
+func removeQuotas(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, p *cephv1.CephBlockPool) error {
+	applied := appliedQuotaStatusInfo(p)
+	zero := resource.MustParse("0")
+	removed := cephv1.QuotaSpec{}
+	if _, ok := applied[quotaMaxBytesKey]; ok && p.Spec.Quotas.MaxBytes == nil {
+		removed.MaxBytes = &zero
+	}
+	if _, ok := applied[quotaMaxObjectsKey]; ok && p.Spec.Quotas.MaxObjects == nil {
+		removed.MaxObjects = &zero
+	}
+	if !removed.IsEnabled() {
+		return nil
+	}
+
+	logger.Infof("removing the quotas removed from the spec of pool %q", p.Name)
+	return cephclient.SetPoolQuota(context, clusterInfo, p.Name, removed)
+}
+
+// generateQuotaStatusInfo reports the usage of the pool against its quotas
+func generateQuotaStatusInfo(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, p *cephv1.CephBlockPool) (map[string]string, error) {
+	stats, err := cephclient.GetPoolStats(context, clusterInfo)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get usage of pool %q", p.Name)
+	}
+
+	var usedBytes, usedObjects int64
+	found := false
+	for _, pool := range stats.Pools {
+		if pool.Name == p.Name {
+			usedBytes = int64(pool.Stats.Stored)
+			usedObjects = int64(pool.Stats.Objects)
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errors.Errorf("pool %q not found in pool stats", p.Name)
+	}
+
+	m := quotaLimitsInfo(p.Spec.Quotas)
+	m[usedBytesKey] = strconv.FormatInt(usedBytes, 10)
+	m[usedObjectsKey] = strconv.FormatInt(usedObjects, 10)
+	if p.Spec.Quotas.MaxBytes != nil {
+		maxBytes := p.Spec.Quotas.MaxBytes.Value()
+		if maxBytes > 0 {
+			m[quotaBytesUsedPercent] = strconv.FormatInt(usedBytes*100/maxBytes, 10)
+		}
+	}
+	if p.Spec.Quotas.MaxObjects != nil {
+		maxObjects := p.Spec.Quotas.MaxObjects.Value()
+		if maxObjects > 0 {
+			m[quotaObjectsUsedPercent] = strconv.FormatInt(usedObjects*100/maxObjects, 10)
+		}
+	}
+
+	// the images can be thin provisioned beyond the bytes quota
+	rbdStats, err := cephclient.GetPoolStatistics(context, clusterInfo, p.Name)
+	if err != nil {
+		logger.Warningf("failed to get provisioned bytes of pool %q. %v", p.Name, err)
+	} else {
+		m[provisionedBytesKey] = strconv.Itoa(rbdStats.Images.ProvisionedBytes)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGenerateQuotaStatusInfo(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "df" {
+				return `{"pools":[{"name":"replicapool","id":1,"stats":{"stored":536870912,"objects":2500,"bytes_used":1610612736}}]}`, nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "pool" && args[1] == "stats" {
+				return `{"images":{"count":2,"provisioned_bytes":4294967296,"snap_count":0}}`, nil
+			}
+			return "", errors.Errorf("unexpected rbd command %q", args)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := cephclient.AdminClusterInfo("rook-ceph")
+
+	maxBytes := resource.MustParse("1Gi")
+	maxObjects := resource.MustParse("10k")
+	p := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: "rook-ceph"},
+		Spec:       cephv1.PoolSpec{Quotas: cephv1.QuotaSpec{MaxBytes: &maxBytes, MaxObjects: &maxObjects}},
+	}
+
+	info, err := generateQuotaStatusInfo(context, clusterInfo, p)
+	assert.NoError(t, err)
+	assert.Equal(t, "1073741824", info[quotaMaxBytesKey])
+	assert.Equal(t, "10000", info[quotaMaxObjectsKey])
+	assert.Equal(t, "536870912", info[usedBytesKey])
+	assert.Equal(t, "2500", info[usedObjectsKey])
+	assert.Equal(t, "50", info[quotaBytesUsedPercent])
+	assert.Equal(t, "25", info[quotaObjectsUsedPercent])
+	assert.Equal(t, "4294967296", info[provisionedBytesKey])
+
+	// a zero quota is reported without usage percentage
+	zero := resource.MustParse("0")
+	p.Spec.Quotas = cephv1.QuotaSpec{MaxObjects: &zero}
+	info, err = generateQuotaStatusInfo(context, clusterInfo, p)
+	assert.NoError(t, err)
+	assert.Equal(t, "0", info[quotaMaxObjectsKey])
+	assert.NotContains(t, info, quotaObjectsUsedPercent)
+	assert.NotContains(t, info, quotaMaxBytesKey)
+
+	// the pool does not exist yet
+	p.Name = "foo"
+	_, err = generateQuotaStatusInfo(context, clusterInfo, p)
+	assert.Error(t, err)
+}
+
+func TestCheckPoolStatusQuotas(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "df" {
+				return `{"pools":[{"name":"replicapool","id":1,"stats":{"stored":536870912,"objects":2500,"bytes_used":1610612736}}]}`, nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "pool" && args[1] == "stats" {
+				return `{"images":{"count":2,"provisioned_bytes":4294967296,"snap_count":0}}`, nil
+			}
+			return "", errors.Errorf("unexpected rbd command %q", args)
+		},
+	}
+	maxBytes := resource.MustParse("1Gi")
+	p := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: "rook-ceph"},
+		Spec:       cephv1.PoolSpec{Quotas: cephv1.QuotaSpec{MaxBytes: &maxBytes}},
+		Status: &cephv1.CephBlockPoolStatus{
+			Phase: cephv1.ConditionReady,
+			// the max objects quota was removed from the spec since the last reconcile
+			Info: map[string]string{quotaMaxObjectsKey: "10000", quotaObjectsUsedPercent: "0", "foo": "bar"},
+		},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephBlockPool{}, &cephv1.CephBlockPoolList{})
+	cl := fake.NewFakeClientWithScheme(s, p)
+	name := types.NamespacedName{Name: "replicapool", Namespace: "rook-ceph"}
+	spec := &cephv1.MirrorHealthCheckSpec{}
+	checker := newMirrorChecker(&clusterd.Context{Executor: executor}, cl, cephclient.AdminClusterInfo("rook-ceph"), name, spec, "replicapool")
+
+	// the mirroring is not checked since it is not enabled
+	checker.checkPoolStatus()
+	p = &cephv1.CephBlockPool{}
+	err := cl.Get(context.TODO(), name, p)
+	assert.NoError(t, err)
+	assert.Equal(t, "50", p.Status.Info[quotaBytesUsedPercent])
+	assert.NotContains(t, p.Status.Info, quotaObjectsUsedPercent)
+	// the quotas applied to the pool are only recorded by the reconcile
+	assert.NotContains(t, p.Status.Info, quotaMaxBytesKey)
+	assert.Equal(t, "10000", p.Status.Info[quotaMaxObjectsKey])
+	assert.Equal(t, "bar", p.Status.Info["foo"])
+}
+
+func TestRemoveQuotas(t *testing.T) {
+	quotas := map[string]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "osd" && args[2] == "set-quota" {
+				quotas[args[4]] = args[5]
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := cephclient.AdminClusterInfo("rook-ceph")
+	maxBytes := resource.MustParse("1Gi")
+	p := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: "rook-ceph"},
+		Spec:       cephv1.PoolSpec{Quotas: cephv1.QuotaSpec{MaxBytes: &maxBytes}},
+	}
+
+	// no quota was applied before
+	err := removeQuotas(context, clusterInfo, p)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(quotas))
+
+	// the quota still in the spec is kept
+	p.Status = &cephv1.CephBlockPoolStatus{Info: map[string]string{quotaMaxBytesKey: "1073741824", usedBytesKey: "0"}}
+	err = removeQuotas(context, clusterInfo, p)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(quotas))
+
+	// only the quotas applied before and removed from the spec are reset
+	p.Status.Info[quotaMaxObjectsKey] = "10000"
+	p.Spec.Quotas = cephv1.QuotaSpec{}
+	err = removeQuotas(context, clusterInfo, p)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"max_bytes": "0", "max_objects": "0"}, quotas)
+	assert.Equal(t, map[string]string{quotaMaxBytesKey: "1073741824", quotaMaxObjectsKey: "10000"}, appliedQuotaStatusInfo(p))
+}
@@ -89,6 +89,7 @@ func toCustomResourceStatus(currentStatus *cephv1.MirroringStatusSpec, mirroring
 		}
 		mirroringStatusSpec.LastChecked = time.Now().UTC().Format(time.RFC3339)
 		mirroringStatusSpec.Summary["summary"] = mirroringStatus.Summary
+		mirroringStatusSpec.ImageStates, mirroringStatusSpec.FailedImages, mirroringStatusSpec.MaxReplicationLagSeconds = toImagesStatus(mirroringStatus.Images)
 	}
 
 	// Always display the details, typically an error
@@ -115,3 +116,71 @@ func toCustomResourceStatus(currentStatus *cephv1.MirroringStatusSpec, mirroring
 
 	return mirroringStatusSpec, mirroringInfoSpec
 }
+
+// maxFailedImagesStatus is the largest number of images in error listed in the status, the status of a pool with many
+// images must stay small
+const maxFailedImagesStatus = 10
+
+// toImagesStatus counts the mirrored images by state and returns the status of the images in error and the largest
+// replication lag, if any
+func toImagesStatus(images []cephclient.ImageMirroringStatus) (map[string]int, []cephv1.ImageMirroringStatusSpec, *int64) {
+	var maxLag *int64
+	var imageStates map[string]int
+	var failedImages []cephv1.ImageMirroringStatusSpec
+	for i := range images {
+		if imageStates == nil {
+			imageStates = map[string]int{}
+		}
+		imageStates[images[i].State]++
+
+		status := cephv1.ImageMirroringStatusSpec{
+			Name:        images[i].Name,
+			State:       images[i].State,
+			Description: images[i].Description,
+			LastUpdate:  images[i].LastUpdate,
+		}
+		if lag, ok := images[i].ReplicationLag(); ok {
+			status.ReplicationLagSeconds = &lag
+			if maxLag == nil || lag > *maxLag {
+				maxLag = &lag
+			}
+		}
+		if images[i].IsFailed() && len(failedImages) < maxFailedImagesStatus {
+			failedImages = append(failedImages, status)
+		}
+	}
+
+	return imageStates, failedImages, maxLag
+}
+
+// updateStatusQuotas refreshes the quota usage in the status info of the pool
+func (c *mirrorChecker) updateStatusQuotas(quotaInfo map[string]string) {
+	blockPool := &cephv1.CephBlockPool{}
+	if err := c.client.Get(context.TODO(), c.namespacedName, blockPool); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBlockPool resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph block pool %q to update quota usage. %v", c.namespacedName.Name, err)
+		return
+	}
+	if blockPool.Status == nil {
+		blockPool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+	if blockPool.Status.Info == nil {
+		blockPool.Status.Info = make(map[string]string)
+	}
+	// only the usage is refreshed, the quotas applied to the pool are recorded by the reconcile
+	for _, key := range quotaUsageKeys {
+		delete(blockPool.Status.Info, key)
+		if value, ok := quotaInfo[key]; ok {
+			blockPool.Status.Info[key] = value
+		}
+	}
+	if err := opcontroller.UpdateStatus(c.client, blockPool); err != nil {
+		logger.Errorf("failed to set ceph block pool %q quota usage. %v", c.namespacedName.Name, err)
+		return
+	}
+
+	logger.Debugf("ceph block pool %q quota usage updated", c.namespacedName.Name)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"fmt"
+	"testing"
+
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToImagesStatus(t *testing.T) {
+	// no images
+	states, failed, maxLag := toImagesStatus(nil)
+	assert.Nil(t, states)
+	assert.Nil(t, failed)
+	assert.Nil(t, maxLag)
+
+	states, failed, maxLag = toImagesStatus([]cephclient.ImageMirroringStatus{
+		{Name: "img1", State: "up+replaying", Description: `replaying, {"local_snapshot_timestamp":100,"remote_snapshot_timestamp":160}`},
+		{Name: "img2", State: "up+replaying", Description: `replaying, {"local_snapshot_timestamp":100,"remote_snapshot_timestamp":400}`},
+		{Name: "img3", State: "up+stopped", Description: "local image is primary", LastUpdate: "2020-11-23 17:40:05"},
+		{Name: "img4", State: "up+error", Description: "split-brain detected", LastUpdate: "2020-11-23 17:40:05"},
+		{Name: "img5", State: "up+stopped", PeerSites: []cephclient.PeerSiteMirrorStatus{{SiteName: "site-b", State: "up+error"}}},
+	})
+	assert.Equal(t, map[string]int{"up+replaying": 2, "up+stopped": 2, "up+error": 1}, states)
+	assert.Equal(t, 2, len(failed))
+	assert.Equal(t, "img4", failed[0].Name)
+	assert.Equal(t, "split-brain detected", failed[0].Description)
+	assert.Equal(t, "2020-11-23 17:40:05", failed[0].LastUpdate)
+	assert.Equal(t, "img5", failed[1].Name)
+	assert.Equal(t, int64(300), *maxLag)
+
+	// the failed images are capped
+	images := []cephclient.ImageMirroringStatus{}
+	for i := 0; i < 2*maxFailedImagesStatus; i++ {
+		images = append(images, cephclient.ImageMirroringStatus{Name: fmt.Sprintf("img%d", i), State: "up+error"})
+	}
+	states, failed, _ = toImagesStatus(images)
+	assert.Equal(t, 2*maxFailedImagesStatus, states["up+error"])
+	assert.Equal(t, maxFailedImagesStatus, len(failed))
+}
@@ -106,6 +106,22 @@ func ValidatePoolSpec(context *clusterd.Context, clusterInfo *client.ClusterInfo
 		default:
 			return errors.Errorf("unrecognized mirroring mode %q. only 'image and 'pool' are supported", p.Mirroring.Mode)
 		}
+
+		if len(p.Mirroring.SnapshotSchedules) > 0 && p.Mirroring.Mode != "image" {
+			return errors.New("snapshot schedules are only supported with the 'image' mirroring mode")
+		}
+		for _, schedule := range p.Mirroring.SnapshotSchedules {
+			if schedule.Interval == "" {
+				return errors.New("missing interval of snapshot schedule")
+			}
+		}
+
+		switch p.Mirroring.Direction {
+		case "", "rx-only", "rx-tx":
+			break
+		default:
+			return errors.Errorf("unrecognized mirroring direction %q. only 'rx-only' and 'rx-tx' are supported", p.Mirroring.Direction)
+		}
 	}
 
 	return nil
@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sutil
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// ExecInPod runs a command in a container of the first running pod with the label selector and returns its
+// standard output
+func ExecInPod(context *clusterd.Context, namespace, labelSelector, containerName string, command []string) ([]byte, error) {
+	pods, err := context.Clientset.CoreV1().Pods(namespace).List(metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list pods with label %q", labelSelector)
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+
+		req := context.Clientset.CoreV1().RESTClient().Post().
+			Resource("pods").
+			Namespace(namespace).
+			Name(pod.Name).
+			SubResource("exec").
+			VersionedParams(&v1.PodExecOptions{
+				Container: containerName,
+				Command:   command,
+				Stdout:    true,
+				Stderr:    true,
+			}, scheme.ParameterCodec)
+		executor, err := remotecommand.NewSPDYExecutor(context.KubeConfig, "POST", req.URL())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create executor for pod %q", pod.Name)
+		}
+
+		var stdout, stderr bytes.Buffer
+		err = executor.Stream(remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to run %q in pod %q. %s", command, pod.Name, stderr.String())
+		}
+		return stdout.Bytes(), nil
+	}
+
+	return nil, errors.Errorf("did not find any running pod with label %q", labelSelector)
+}
@@ -59,6 +59,16 @@ func UpdateService(
 	serviceDefinition.Spec.ClusterIP = existing.Spec.ClusterIP
 	// ResourceVersion required to update services in k8s v1 API to prevent race conditions
 	serviceDefinition.ResourceVersion = existing.ResourceVersion
+	// The node ports are allocated when the service is created, keep them unless they are set
+	if serviceDefinition.Spec.Type == v1.ServiceTypeNodePort || serviceDefinition.Spec.Type == v1.ServiceTypeLoadBalancer {
+		for i, port := range serviceDefinition.Spec.Ports {
+			for _, existingPort := range existing.Spec.Ports {
+				if port.NodePort == 0 && port.Name == existingPort.Name {
+					serviceDefinition.Spec.Ports[i].NodePort = existingPort.NodePort
+				}
+			}
+		}
+	}
 	return clientset.CoreV1().Services(namespace).Update(serviceDefinition)
 }
 
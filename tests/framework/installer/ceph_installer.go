@@ -598,9 +598,15 @@ func (h *CephInstaller) UninstallRookFromMultipleNS(systemNamespace string, name
 		"cephobjectrealms.ceph.rook.io",
 		"cephobjectzonegroups.ceph.rook.io",
 		"cephobjectzones.ceph.rook.io",
+		"cephbuckettopics.ceph.rook.io",
+		"cephbucketnotifications.ceph.rook.io",
+		"cephobjectroles.ceph.rook.io",
 		"cephfilesystems.ceph.rook.io",
+		"cephfilesystemsubvolumegroups.ceph.rook.io",
+		"cephfilesystemmirrors.ceph.rook.io",
 		"cephnfses.ceph.rook.io",
 		"cephclients.ceph.rook.io",
+		"cephosdremovals.ceph.rook.io",
 		"volumes.rook.io",
 		"objectbuckets.objectbucket.io",
 		"objectbucketclaims.objectbucket.io",
@@ -157,6 +157,31 @@ spec:
                   maximum: 9
                   minimum: 0
                   type: integer
+                failureDomainLabel:
+                  type: string
+                zones:
+                  items:
+                    properties:
+                      name:
+                        type: string
+                    type: object
+                  type: array
+                stretchCluster:
+                  properties:
+                    failureDomainLabel:
+                      type: string
+                    subFailureDomain:
+                      type: string
+                    zones:
+                      items:
+                        properties:
+                          name:
+                            type: string
+                          arbiter:
+                            type: boolean
+                        type: object
+                      type: array
+                  type: object
                 volumeClaimTemplate: {}
             mgr:
               properties:
@@ -252,6 +277,12 @@ spec:
                   type: integer
             removeOSDsIfOutAndSafeToRemove:
               type: boolean
+            cephConfig:
+              type: object
+              additionalProperties:
+                type: object
+                additionalProperties:
+                  type: string
             external:
               properties:
                 enable:
@@ -272,6 +303,19 @@ spec:
                     iteration:
                       type: integer
                       format: int32
+            security:
+              properties:
+                kms:
+                  properties:
+                    connectionDetails: {}
+                    tokenSecretName:
+                      type: string
+                keyRotation:
+                  properties:
+                    enabled:
+                      type: boolean
+                    period:
+                      type: string
             placement: {}
             resources: {}
   additionalPrinterColumns:
@@ -294,6 +338,10 @@ spec:
       type: string
       description: Ceph Health
       JSONPath: .status.ceph.health
+    - name: Used
+      type: integer
+      description: Percentage of the raw capacity used
+      JSONPath: .status.ceph.capacity.usedPercent
   subresources:
     status: {}
 ---
@@ -396,6 +444,44 @@ spec:
                     type: object
             preservePoolsOnDelete:
               type: boolean
+            mirroring:
+              properties:
+                enabled:
+                  type: boolean
+                peers:
+                  properties:
+                    secretNames:
+                      type: array
+                directories:
+                  type: array
+                  items:
+                    type: string
+                snapshotSchedules:
+                  type: array
+                  items:
+                    properties:
+                      path:
+                        type: string
+                      interval:
+                        type: string
+                      startTime:
+                        type: string
+                snapshotRetention:
+                  type: array
+                  items:
+                    properties:
+                      path:
+                        type: string
+                      duration:
+                        type: string
+            statusCheck:
+              properties:
+                mirror:
+                  properties:
+                    disabled:
+                      type: boolean
+                    interval:
+                      type: string
   additionalPrinterColumns:
     - name: ActiveMDS
       type: string
@@ -409,6 +495,81 @@ spec:
 ---
 apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
+metadata:
+  name: cephfilesystemsubvolumegroups.ceph.rook.io
+spec:
+  group: ceph.rook.io
+  names:
+    kind: CephFilesystemSubVolumeGroup
+    listKind: CephFilesystemSubVolumeGroupList
+    plural: cephfilesystemsubvolumegroups
+    singular: cephfilesystemsubvolumegroup
+  scope: Namespaced
+  version: v1
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          properties:
+            filesystemName:
+              type: string
+            quota: {}
+            dataPoolName:
+              type: string
+            pinning:
+              properties:
+                export:
+                  type: integer
+                  minimum: -1
+                distributed:
+                  type: integer
+                  minimum: 0
+                  maximum: 1
+                random:
+                  type: number
+                  minimum: 0
+                  maximum: 1
+          required:
+          - filesystemName
+  additionalPrinterColumns:
+    - name: Filesystem
+      type: string
+      description: Name of the CephFilesystem
+      JSONPath: .spec.filesystemName
+    - name: Phase
+      type: string
+      JSONPath: .status.phase
+    - name: Age
+      type: date
+      JSONPath: .metadata.creationTimestamp
+  subresources:
+    status: {}
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: cephfilesystemmirrors.ceph.rook.io
+spec:
+  group: ceph.rook.io
+  names:
+    kind: CephFilesystemMirror
+    listKind: CephFilesystemMirrorList
+    plural: cephfilesystemmirrors
+    singular: cephfilesystemmirror
+  scope: Namespaced
+  version: v1
+  additionalPrinterColumns:
+    - name: Phase
+      type: string
+      JSONPath: .status.phase
+    - name: Age
+      type: date
+      JSONPath: .metadata.creationTimestamp
+  subresources:
+    status: {}
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
 metadata:
   name: cephnfses.ceph.rook.io
 spec:
@@ -621,6 +782,230 @@ spec:
 ---
 apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
+metadata:
+  name: cephbuckettopics.ceph.rook.io
+spec:
+  group: ceph.rook.io
+  names:
+    kind: CephBucketTopic
+    listKind: CephBucketTopicList
+    plural: cephbuckettopics
+    singular: cephbuckettopic
+  scope: Namespaced
+  version: v1
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          properties:
+            objectStoreName:
+              type: string
+            opaqueData:
+              type: string
+            persistent:
+              type: boolean
+            endpoint:
+              properties:
+                http:
+                  properties:
+                    uri:
+                      type: string
+                      pattern: ^https?://
+                    disableVerifySSL:
+                      type: boolean
+                  required:
+                  - uri
+                amqp:
+                  properties:
+                    uri:
+                      type: string
+                      pattern: ^amqps?://
+                    exchange:
+                      type: string
+                    ackLevel:
+                      type: string
+                      enum:
+                      - none
+                      - broker
+                      - routable
+                    disableVerifySSL:
+                      type: boolean
+                  required:
+                  - uri
+                  - exchange
+                kafka:
+                  properties:
+                    uri:
+                      type: string
+                      pattern: ^kafka://
+                    useSSL:
+                      type: boolean
+                    ackLevel:
+                      type: string
+                      enum:
+                      - none
+                      - broker
+                    disableVerifySSL:
+                      type: boolean
+                  required:
+                  - uri
+                authSecretName:
+                  type: string
+          required:
+          - objectStoreName
+          - endpoint
+  additionalPrinterColumns:
+    - name: ObjectStore
+      type: string
+      JSONPath: .spec.objectStoreName
+    - name: Phase
+      type: string
+      JSONPath: .status.phase
+    - name: Age
+      type: date
+      JSONPath: .metadata.creationTimestamp
+  subresources:
+    status: {}
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: cephbucketnotifications.ceph.rook.io
+spec:
+  group: ceph.rook.io
+  names:
+    kind: CephBucketNotification
+    listKind: CephBucketNotificationList
+    plural: cephbucketnotifications
+    singular: cephbucketnotification
+  scope: Namespaced
+  version: v1
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          properties:
+            topic:
+              type: string
+            events:
+              type: array
+              items:
+                type: string
+                enum:
+                - s3:ObjectCreated:*
+                - s3:ObjectCreated:Put
+                - s3:ObjectCreated:Post
+                - s3:ObjectCreated:Copy
+                - s3:ObjectCreated:CompleteMultipartUpload
+                - s3:ObjectRemoved:*
+                - s3:ObjectRemoved:Delete
+                - s3:ObjectRemoved:DeleteMarkerCreated
+            filter:
+              properties:
+                keyFilters:
+                  type: array
+                  items:
+                    properties:
+                      name:
+                        type: string
+                        enum:
+                        - prefix
+                        - suffix
+                        - regex
+                      value:
+                        type: string
+                    required:
+                    - name
+                    - value
+          required:
+          - topic
+  additionalPrinterColumns:
+    - name: Topic
+      type: string
+      JSONPath: .spec.topic
+    - name: Phase
+      type: string
+      JSONPath: .status.phase
+    - name: Age
+      type: date
+      JSONPath: .metadata.creationTimestamp
+  subresources:
+    status: {}
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: cephobjectroles.ceph.rook.io
+spec:
+  group: ceph.rook.io
+  names:
+    kind: CephObjectRole
+    listKind: CephObjectRoleList
+    plural: cephobjectroles
+    singular: cephobjectrole
+  scope: Namespaced
+  version: v1
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          properties:
+            objectStoreName:
+              type: string
+            serviceAccounts:
+              type: array
+              minItems: 1
+              items:
+                properties:
+                  namespace:
+                    type: string
+                  name:
+                    type: string
+                required:
+                - namespace
+                - name
+            policies:
+              type: array
+              items:
+                properties:
+                  name:
+                    type: string
+                  effect:
+                    type: string
+                    enum:
+                    - Allow
+                    - Deny
+                  actions:
+                    type: array
+                    items:
+                      type: string
+                      pattern: ^s3:
+                  buckets:
+                    type: array
+                    minItems: 1
+                    items:
+                      type: string
+                required:
+                - name
+                - buckets
+          required:
+          - objectStoreName
+          - serviceAccounts
+  additionalPrinterColumns:
+    - name: ObjectStore
+      type: string
+      JSONPath: .spec.objectStoreName
+    - name: Phase
+      type: string
+      JSONPath: .status.phase
+    - name: Age
+      type: date
+      JSONPath: .metadata.creationTimestamp
+  subresources:
+    status: {}
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
 metadata:
   name: cephblockpools.ceph.rook.io
 spec:
@@ -684,6 +1069,30 @@ spec:
                   enum:
                   - image
                   - pool
+                snapshotSchedules:
+                  type: array
+                  items:
+                    properties:
+                      interval:
+                        type: string
+                      startTime:
+                        type: string
+                peers:
+                  properties:
+                    secretNames:
+                      type: array
+                      items:
+                        type: string
+                direction:
+                  type: string
+                  enum:
+                  - ""
+                  - rx-only
+                  - rx-tx
+            quotas:
+              properties:
+                maxBytes: {}
+                maxObjects: {}
   subresources:
     status: {}
 ---
@@ -780,6 +1189,59 @@ spec:
 ---
 apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
+metadata:
+  name: cephosdremovals.ceph.rook.io
+spec:
+  group: ceph.rook.io
+  names:
+    kind: CephOSDRemoval
+    listKind: CephOSDRemovalList
+    plural: cephosdremovals
+    singular: cephosdremoval
+  scope: Namespaced
+  version: v1
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          properties:
+            osdIDs:
+              type: array
+              minItems: 1
+              items:
+                type: integer
+                minimum: 0
+            forceRemoval:
+              type: boolean
+            preservePVC:
+              type: boolean
+            replace:
+              type: boolean
+            sanitizeDisks:
+              properties:
+                method:
+                  type: string
+                  pattern: ^(complete|quick)$
+                dataSource:
+                  type: string
+                  pattern: ^(zero|random)$
+                iteration:
+                  type: integer
+                  format: int32
+          required:
+          - osdIDs
+  additionalPrinterColumns:
+    - name: Phase
+      type: string
+      JSONPath: .status.phase
+    - name: Age
+      type: date
+      JSONPath: .metadata.creationTimestamp
+  subresources:
+    status: {}
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
 metadata:
   name: cephrbdmirrors.ceph.rook.io
 spec:
@@ -958,6 +1420,7 @@ rules:
   - secrets
   - pods
   - pods/log
+  - pods/exec
   - services
   - configmaps
   verbs: